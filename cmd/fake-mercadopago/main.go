@@ -0,0 +1,31 @@
+// Command fake-mercadopago serves an in-memory Mercado Pago API for running the payment loop offline.
+// Point MERCADO_PAGO_BASEURL at it and settle QR codes with POST /fake/qrs/{externalReference}/{outcome}.
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/abattassini/tc-fiap-payment/internal/fakemercadopago"
+)
+
+func main() {
+	port := os.Getenv("FAKE_MERCADO_PAGO_PORT")
+	if port == "" {
+		port = "8090"
+	}
+
+	server := fakemercadopago.NewServer(&fakemercadopago.Config{
+		ClientId:      os.Getenv("MERCADO_PAGO_CLIENT_ID"),
+		PosId:         os.Getenv("MERCADO_PAGO_POS_ID"),
+		Token:         os.Getenv("MERCADO_PAGO_ACCESS_TOKEN"),
+		WebhookURL:    os.Getenv("MERCADO_PAGO_WEBHOOK_CALLBACK_URL"),
+		WebhookSecret: os.Getenv("MERCADO_PAGO_WEBHOOK_SECRET"),
+	}, &http.Client{})
+
+	log.Printf("Fake Mercado Pago listening on :%s", port)
+	if err := http.ListenAndServe(":"+port, server); err != nil {
+		log.Fatalf("Error while serving fake Mercado Pago: %v", err)
+	}
+}
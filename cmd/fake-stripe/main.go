@@ -0,0 +1,35 @@
+// Command fake-stripe serves an in-memory Stripe API for running card payments offline. Point
+// STRIPE_BASEURL at it and pay with the Stripe test payment methods, e.g. pm_card_visa.
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/abattassini/tc-fiap-payment/internal/fakestripe"
+)
+
+func main() {
+	port := os.Getenv("FAKE_STRIPE_PORT")
+	if port == "" {
+		port = "8091"
+	}
+
+	// Stripe webhook endpoints are configured on the Stripe dashboard rather than per payment
+	webhookURL := os.Getenv("FAKE_STRIPE_WEBHOOK_URL")
+	if webhookURL == "" {
+		webhookURL = "http://localhost:8082/payment/webhooks/stripe"
+	}
+
+	server := fakestripe.NewServer(&fakestripe.Config{
+		SecretKey:     os.Getenv("STRIPE_SECRET_KEY"),
+		WebhookURL:    webhookURL,
+		WebhookSecret: os.Getenv("STRIPE_WEBHOOK_SECRET"),
+	}, &http.Client{})
+
+	log.Printf("Fake Stripe listening on :%s", port)
+	if err := http.ListenAndServe(":"+port, server); err != nil {
+		log.Fatalf("Error while serving fake Stripe: %v", err)
+	}
+}
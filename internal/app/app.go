@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
@@ -11,18 +12,34 @@ import (
 	"go.uber.org/fx"
 
 	paymentController "github.com/abattassini/tc-fiap-payment/internal/payment/controller"
+	paymentEntities "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	paymentRepositories "github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
 	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
 	paymentApiController "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/controller"
+	paymentMiddleware "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
 	paymentClients "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/clients"
 	paymentGatewaysImpl "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	paymentJobs "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/jobs"
 	paymentPersistence "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
 	paymentPresenter "github.com/abattassini/tc-fiap-payment/internal/payment/presenter"
 	paymentUseCasesAdd "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/addPayment"
+	paymentUseCasesCancel "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/cancelPayment"
+	paymentUseCasesCapture "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/capturePayment"
+	paymentUseCasesDispatchOutbox "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/dispatchOutbox"
+	paymentUseCasesExpirePayments "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/expirePayments"
 	paymentUseCasesGet "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPayment"
+	paymentUseCasesGetHistory "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPaymentHistory"
 	paymentUseCasesGetStatus "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPaymentStatus"
+	paymentUseCasesGetSplit "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getSplitPayment"
 	paymentUseCasesHandleWebhook "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/handleWebhook"
+	paymentUseCasesListDisputes "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listDisputes"
+	paymentUseCasesListOutboxMessages "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listOutboxMessages"
+	paymentUseCasesListPaymentAttempts "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listPaymentAttempts"
+	paymentUseCasesListWebhookNotifications "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listWebhookNotifications"
+	paymentUseCasesRefund "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/refundPayment"
+	paymentUseCasesRetryOutboxMessage "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/retryOutboxMessage"
 	paymentUseCasesUpdate "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/updatePayment"
+	paymentUseCasesVoidAuthorizations "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/voidAuthorizations"
 
 	"github.com/abattassini/tc-fiap-payment/pkg/rest"
 	"github.com/abattassini/tc-fiap-payment/pkg/storage/postgres"
@@ -33,35 +50,90 @@ func InitializeApp() *fx.App {
 		fx.Provide(
 			postgres.NewPostgresDB,
 			fx.Annotate(paymentPersistence.NewPaymentRepositoryImpl, fx.As(new(paymentRepositories.PaymentRepository))),
+			fx.Annotate(paymentPersistence.NewWebhookNotificationRepositoryImpl, fx.As(new(paymentRepositories.WebhookNotificationRepository))),
+			fx.Annotate(paymentPersistence.NewOutboxRepositoryImpl, fx.As(new(paymentRepositories.OutboxRepository))),
+			fx.Annotate(paymentPersistence.NewRefundRepositoryImpl, fx.As(new(paymentRepositories.RefundRepository))),
+			fx.Annotate(paymentPersistence.NewDisputeRepositoryImpl, fx.As(new(paymentRepositories.DisputeRepository))),
+			fx.Annotate(paymentPersistence.NewIdempotencyKeyRepositoryImpl, fx.As(new(paymentRepositories.IdempotencyKeyRepository))),
 			fx.Annotate(paymentPresenter.NewPaymentPresenterImpl, fx.As(new(paymentPresenter.PaymentPresenter))),
 			fx.Annotate(paymentController.NewPaymentControllerImpl, fx.As(new(paymentController.PaymentController))),
 			fx.Annotate(paymentController.NewPaymentWebhookControllerImpl, fx.As(new(paymentController.PaymentWebhookController))),
+			fx.Annotate(paymentController.NewOutboxControllerImpl, fx.As(new(paymentController.OutboxController))),
+			fx.Annotate(paymentController.NewDisputeControllerImpl, fx.As(new(paymentController.DisputeController))),
 			fx.Annotate(paymentUseCasesAdd.NewAddPaymentUseCaseImpl, fx.As(new(paymentUseCasesAdd.AddPaymentUseCase))),
+			fx.Annotate(paymentUseCasesAdd.NewAddSplitPaymentUseCaseImpl, fx.As(new(paymentUseCasesAdd.AddSplitPaymentUseCase))),
 			fx.Annotate(paymentUseCasesGet.NewGetPaymentUseCaseImpl, fx.As(new(paymentUseCasesGet.GetPaymentUseCase))),
+			fx.Annotate(paymentUseCasesGetSplit.NewGetSplitPaymentUseCaseImpl, fx.As(new(paymentUseCasesGetSplit.GetSplitPaymentUseCase))),
 			fx.Annotate(paymentUseCasesGetStatus.NewGetPaymentStatusUseCaseImpl, fx.As(new(paymentUseCasesGetStatus.GetPaymentStatusUseCase))),
+			fx.Annotate(paymentUseCasesGetHistory.NewGetPaymentHistoryUseCaseImpl, fx.As(new(paymentUseCasesGetHistory.GetPaymentHistoryUseCase))),
+			fx.Annotate(paymentUseCasesListPaymentAttempts.NewListPaymentAttemptsUseCaseImpl, fx.As(new(paymentUseCasesListPaymentAttempts.ListPaymentAttemptsUseCase))),
+			fx.Annotate(paymentUseCasesCancel.NewCancelPaymentUseCaseImpl, fx.As(new(paymentUseCasesCancel.CancelPaymentUseCase))),
+			fx.Annotate(paymentUseCasesRefund.NewRefundPaymentUseCaseImpl, fx.As(new(paymentUseCasesRefund.RefundPaymentUseCase))),
+			fx.Annotate(paymentUseCasesCapture.NewCapturePaymentUseCaseImpl, fx.As(new(paymentUseCasesCapture.CapturePaymentUseCase))),
 			fx.Annotate(paymentUseCasesUpdate.NewUpdatePaymentUseCaseImpl, fx.As(new(paymentUseCasesUpdate.UpdatePaymentUseCase))),
 			fx.Annotate(paymentUseCasesHandleWebhook.NewHandleWebhookUseCaseImpl, fx.As(new(paymentUseCasesHandleWebhook.HandleWebhookUseCase))),
-			func() (paymentGateways.MercadoPagoGateway, error) {
-				return paymentGatewaysImpl.NewMercadoPagoGatewayImpl()
-			},
+			fx.Annotate(paymentUseCasesListWebhookNotifications.NewListWebhookNotificationsUseCaseImpl, fx.As(new(paymentUseCasesListWebhookNotifications.ListWebhookNotificationsUseCase))),
+			fx.Annotate(paymentUseCasesDispatchOutbox.NewDispatchOutboxUseCaseImpl, fx.As(new(paymentUseCasesDispatchOutbox.DispatchOutboxUseCase))),
+			fx.Annotate(paymentUseCasesExpirePayments.NewExpirePaymentsUseCaseImpl, fx.As(new(paymentUseCasesExpirePayments.ExpirePaymentsUseCase))),
+			fx.Annotate(paymentUseCasesVoidAuthorizations.NewVoidAuthorizationsUseCaseImpl, fx.As(new(paymentUseCasesVoidAuthorizations.VoidAuthorizationsUseCase))),
+			fx.Annotate(paymentUseCasesListDisputes.NewListDisputesUseCaseImpl, fx.As(new(paymentUseCasesListDisputes.ListDisputesUseCase))),
+			fx.Annotate(paymentUseCasesListOutboxMessages.NewListOutboxMessagesUseCaseImpl, fx.As(new(paymentUseCasesListOutboxMessages.ListOutboxMessagesUseCase))),
+			fx.Annotate(paymentUseCasesRetryOutboxMessage.NewRetryOutboxMessageUseCaseImpl, fx.As(new(paymentUseCasesRetryOutboxMessage.RetryOutboxMessageUseCase))),
+			fx.Annotate(paymentGatewaysImpl.NewMercadoPagoGatewayImpl, fx.As(new(paymentGateways.MercadoPagoGateway))),
+			paymentGatewaysImpl.NewMercadoPagoPaymentGateway,
+			registerPaymentGateway[*paymentGatewaysImpl.MercadoPagoPaymentGateway](paymentEntities.PaymentTypeQRCode),
+			fx.Annotate(paymentGatewaysImpl.NewStripeGatewayImpl, fx.As(new(paymentGateways.StripeGateway))),
+			paymentGatewaysImpl.NewStripePaymentGateway,
+			registerPaymentGateway[*paymentGatewaysImpl.StripePaymentGateway](paymentEntities.PaymentTypeCard),
+			fx.Annotate(paymentGatewaysImpl.NewPixGatewayImpl, fx.As(new(paymentGateways.PixGateway))),
+			paymentGatewaysImpl.NewPixPaymentGateway,
+			registerPaymentGateway[*paymentGatewaysImpl.PixPaymentGateway](paymentEntities.PaymentTypePix),
+			fx.Annotate(paymentGateways.NewPaymentGatewayRegistry, fx.ParamTags(`group:"payment_gateways"`)),
 			func() rest.HTTPClient {
 				return &http.Client{}
 			},
-			func(httpClient rest.HTTPClient) paymentClients.OrderClient {
+			func(httpClient rest.HTTPClient) (paymentClients.OrderClient, error) {
 				return paymentClients.NewOrderClient(httpClient)
 			},
 			chi.NewRouter,
+			paymentMiddleware.NewMercadoPagoSignatureVerifier,
+			paymentMiddleware.NewStripeSignatureVerifier,
+			paymentMiddleware.NewIdempotencyKeyHandler,
+			paymentJobs.NewOutboxDispatcher,
+			paymentJobs.NewPaymentExpirySweeper,
+			paymentJobs.NewAuthorizationVoidSweeper,
 			func(
 				paymentController paymentController.PaymentController,
-				paymentWebhookController paymentController.PaymentWebhookController) []rest.Controller {
+				paymentWebhookController paymentController.PaymentWebhookController,
+				outboxController paymentController.OutboxController,
+				disputeController paymentController.DisputeController,
+				signatureVerifier *paymentMiddleware.MercadoPagoSignatureVerifier,
+				stripeSignatureVerifier *paymentMiddleware.StripeSignatureVerifier,
+				idempotencyKeyHandler *paymentMiddleware.IdempotencyKeyHandler) []rest.Controller {
 				return []rest.Controller{
-					paymentApiController.NewPaymentApiController(paymentController),
-					paymentApiController.NewPaymentWebhookApiController(paymentWebhookController),
+					paymentApiController.NewPaymentApiController(paymentController, idempotencyKeyHandler),
+					paymentApiController.NewPaymentWebhookApiController(paymentWebhookController, signatureVerifier, stripeSignatureVerifier),
+					paymentApiController.NewOutboxApiController(outboxController),
+					paymentApiController.NewDisputeApiController(disputeController),
 				}
 			},
 		),
 		fx.Invoke(registerRoutes),
 		fx.Invoke(startHTTPServer),
+		fx.Invoke(startDebugServer),
+		fx.Invoke(startOutboxDispatcher),
+		fx.Invoke(startPaymentExpirySweeper),
+		fx.Invoke(startAuthorizationVoidSweeper),
+	)
+}
+
+// registerPaymentGateway makes the gateway adapter of type T charge payments of the given type.
+func registerPaymentGateway[T paymentGateways.PaymentGateway](paymentType string) any {
+	return fx.Annotate(
+		func(gateway T) paymentGateways.PaymentGatewayRegistration {
+			return paymentGateways.PaymentGatewayRegistration{PaymentType: paymentType, Gateway: gateway}
+		},
+		fx.ResultTags(`group:"payment_gateways"`),
 	)
 }
 
@@ -95,3 +167,72 @@ func startHTTPServer(lc fx.Lifecycle, r *chi.Mux) {
 		},
 	})
 }
+
+// startDebugServer serves the expvar counters at /debug/vars on an internal listener, kept off the
+// public router.
+func startDebugServer(lc fx.Lifecycle) {
+	addr := os.Getenv("DEBUG_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:8083"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				log.Printf("Starting debug server on %s", addr)
+				if err := http.ListenAndServe(addr, mux); err != nil {
+					log.Fatalf("Failed to start debug server: %v", err)
+				}
+			}()
+			return nil
+		},
+	})
+}
+
+func startOutboxDispatcher(lc fx.Lifecycle, dispatcher *paymentJobs.OutboxDispatcher) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Println("Starting order status outbox dispatcher")
+			dispatcher.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Println("Stopping order status outbox dispatcher")
+			dispatcher.Stop()
+			return nil
+		},
+	})
+}
+
+func startPaymentExpirySweeper(lc fx.Lifecycle, sweeper *paymentJobs.PaymentExpirySweeper) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Println("Starting payment expiry sweeper")
+			sweeper.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Println("Stopping payment expiry sweeper")
+			sweeper.Stop()
+			return nil
+		},
+	})
+}
+
+func startAuthorizationVoidSweeper(lc fx.Lifecycle, sweeper *paymentJobs.AuthorizationVoidSweeper) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Println("Starting authorization void sweeper")
+			sweeper.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Println("Stopping authorization void sweeper")
+			sweeper.Stop()
+			return nil
+		},
+	})
+}
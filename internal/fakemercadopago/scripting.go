@@ -0,0 +1,142 @@
+package fakemercadopago
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+// Outcomes accepted by POST /fake/qrs/{externalReference}/{outcome}.
+const (
+	// OutcomeApprove pays the QR order and clears the point of sale.
+	OutcomeApprove = "approve"
+	// OutcomeDecline records a rejected payment attempt; the QR order stays payable.
+	OutcomeDecline = "decline"
+	// OutcomeExpire makes the QR order unpayable without creating a payment, like an elapsed expiration_date.
+	OutcomeExpire = "expire"
+)
+
+// SettlementResponseDto reports what a scripted outcome did, including the webhook delivery.
+type SettlementResponseDto struct {
+	Order        QROrder                            `json:"order"`
+	Payment      *dto.MercadoPagoPaymentResponseDto `json:"payment,omitempty"`
+	Notification *NotificationResult                `json:"notification,omitempty"`
+}
+
+func (s *Server) listQROrders(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	orders := make([]QROrder, 0, len(s.orders))
+	for _, order := range s.orders {
+		orders = append(orders, *order)
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(orders, func(a, b QROrder) int {
+		return strings.Compare(a.InStoreOrderId, b.InStoreOrderId)
+	})
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (s *Server) settleQROrder(w http.ResponseWriter, r *http.Request) {
+	externalReference, outcome := chi.URLParam(r, "externalReference"), chi.URLParam(r, "outcome")
+
+	s.mu.Lock()
+	order, exists := s.orders[externalReference]
+	if !exists {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("QR order %q not found", externalReference))
+		return
+	}
+
+	s.expireIfDue(order)
+	if order.Status != QROrderStatusOpen {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, "order_not_open", fmt.Sprintf("QR order %q is %s", externalReference, order.Status))
+		return
+	}
+
+	var settled *payment
+	switch outcome {
+	case OutcomeApprove:
+		settled = s.addPayment(order, "approved", "accredited")
+		order.Status = QROrderStatusPaid
+		s.clearPointOfSale(order)
+	case OutcomeDecline:
+		settled = s.addPayment(order, "rejected", "cc_rejected_other_reason")
+	case OutcomeExpire:
+		order.Status = QROrderStatusExpired
+		s.clearPointOfSale(order)
+	default:
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "bad_request",
+			fmt.Sprintf("unknown outcome %q, expected %s, %s or %s", outcome, OutcomeApprove, OutcomeDecline, OutcomeExpire))
+		return
+	}
+
+	response := SettlementResponseDto{Order: *order}
+	var notification *notification
+	if settled != nil {
+		paymentResponse := settled.MercadoPagoPaymentResponseDto
+		response.Payment = &paymentResponse
+		notification = s.newNotification(settled)
+	}
+	s.mu.Unlock()
+
+	// The webhook is delivered before answering so that scripts see how the service reacted to it
+	if notification != nil {
+		result := s.deliver(notification)
+		response.Notification = &result
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// renotify delivers the latest state of a payment again, like a Mercado Pago retry.
+func (s *Server) renotify(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	payment, err := s.findPayment(chi.URLParam(r, "paymentId"))
+	if err != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "not_found", err.Error())
+		return
+	}
+	notification := s.lastNotification(payment)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.deliver(notification))
+}
+
+// addPayment must be called with the lock held.
+func (s *Server) addPayment(order *QROrder, status string, statusDetail string) *payment {
+	settled := &payment{
+		MercadoPagoPaymentResponseDto: dto.MercadoPagoPaymentResponseDto{
+			Id:                s.nextId(),
+			Status:            status,
+			StatusDetail:      statusDetail,
+			ExternalReference: order.ExternalReference,
+			TransactionAmount: order.TotalAmount,
+		},
+		notificationURL: order.NotificationURL,
+	}
+	s.payments[settled.Id] = settled
+	order.PaymentIds = append(order.PaymentIds, settled.Id)
+	return settled
+}
+
+// expireIfDue must be called with the lock held.
+func (s *Server) expireIfDue(order *QROrder) {
+	if order.Status == QROrderStatusOpen && order.ExpiresAt != nil && !s.now().Before(*order.ExpiresAt) {
+		order.Status = QROrderStatusExpired
+		s.clearPointOfSale(order)
+	}
+}
+
+// clearPointOfSale must be called with the lock held.
+func (s *Server) clearPointOfSale(order *QROrder) {
+	if s.posOrders[order.PosId] == order {
+		delete(s.posOrders, order.PosId)
+	}
+}
@@ -0,0 +1,354 @@
+// Package fakemercadopago is an in-memory stand-in for the parts of the Mercado Pago API the payment
+// service uses: in-store QR orders, payment lookup, point of sale cleanup and refunds. Payments are
+// settled through scripting endpoints under /fake, which also fire signed webhooks back to the
+// service, so the whole payment loop can run offline.
+package fakemercadopago
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/abattassini/tc-fiap-payment/pkg/rest"
+	"github.com/go-chi/chi/v5"
+)
+
+type Config struct {
+	// ClientId and PosId restrict the in-store endpoints to one point of sale; empty accepts any.
+	ClientId string
+	PosId    string
+	// Token is the expected bearer token; empty accepts any.
+	Token string
+	// WebhookURL receives the notifications of QR orders created without a notification_url.
+	WebhookURL string
+	// WebhookSecret signs the x-signature header of the notifications.
+	WebhookSecret string
+}
+
+// QROrderStatus is the state of an in-store QR order on the fake point of sale.
+type QROrderStatus string
+
+const (
+	QROrderStatusOpen      QROrderStatus = "open"
+	QROrderStatusPaid      QROrderStatus = "paid"
+	QROrderStatusCancelled QROrderStatus = "cancelled"
+	QROrderStatusExpired   QROrderStatus = "expired"
+)
+
+type QROrder struct {
+	InStoreOrderId    string        `json:"in_store_order_id"`
+	ExternalReference string        `json:"external_reference"`
+	PosId             string        `json:"pos_id"`
+	TotalAmount       money.Amount  `json:"total_amount"`
+	NotificationURL   string        `json:"notification_url"`
+	ExpiresAt         *time.Time    `json:"expires_at,omitempty"`
+	Status            QROrderStatus `json:"status"`
+	QRData            string        `json:"qr_data"`
+	PaymentIds        []int64       `json:"payment_ids"`
+}
+
+type payment struct {
+	dto.MercadoPagoPaymentResponseDto
+	refunds         []dto.MercadoPagoRefundResponseDto
+	notificationURL string
+	// lastNotificationId is reused when the latest notification is delivered again.
+	lastNotificationId string
+}
+
+func (p *payment) refundedAmount() money.Amount {
+	var refunded money.Amount
+	for _, refund := range p.refunds {
+		refunded += refund.Amount
+	}
+	return refunded
+}
+
+// Server implements the fake Mercado Pago API as an http.Handler.
+type Server struct {
+	config *Config
+	client rest.HTTPClient
+	now    func() time.Time
+	router chi.Router
+
+	mu sync.Mutex
+	// orders holds every QR order by external reference; posOrders the one shown on each point of sale.
+	orders        map[string]*QROrder
+	posOrders     map[string]*QROrder
+	payments      map[int64]*payment
+	refundsByKey  map[string]dto.MercadoPagoRefundResponseDto
+	lastId        int64
+	notifications int64
+}
+
+func NewServer(config *Config, client rest.HTTPClient) *Server {
+	s := &Server{
+		config:       config,
+		client:       client,
+		now:          time.Now,
+		orders:       make(map[string]*QROrder),
+		posOrders:    make(map[string]*QROrder),
+		payments:     make(map[int64]*payment),
+		refundsByKey: make(map[string]dto.MercadoPagoRefundResponseDto),
+		lastId:       1000000000,
+	}
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(s.authenticate)
+		r.Post("/instore/orders/qr/seller/collectors/{clientId}/pos/{posId}/qrs", s.createQROrder)
+		r.Get("/instore/qr/seller/collectors/{clientId}/pos/{posId}/orders", s.getPOSOrder)
+		r.Delete("/instore/qr/seller/collectors/{clientId}/pos/{posId}/orders", s.deletePOSOrder)
+		r.Get("/v1/payments/{paymentId}", s.getPayment)
+		r.Post("/v1/payments/{paymentId}/refunds", s.refundPayment)
+	})
+	r.Get("/fake/qrs", s.listQROrders)
+	r.Post("/fake/qrs/{externalReference}/{outcome}", s.settleQROrder)
+	r.Post("/fake/payments/{paymentId}/notify", s.renotify)
+	s.router = r
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || token == "" || (s.config.Token != "" && token != s.config.Token) {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "invalid access token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) checkPointOfSale(w http.ResponseWriter, r *http.Request) (string, bool) {
+	clientId, posId := chi.URLParam(r, "clientId"), chi.URLParam(r, "posId")
+	if (s.config.ClientId != "" && clientId != s.config.ClientId) || (s.config.PosId != "" && posId != s.config.PosId) {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("point of sale %s/%s not found", clientId, posId))
+		return "", false
+	}
+	return posId, true
+}
+
+func (s *Server) createQROrder(w http.ResponseWriter, r *http.Request) {
+	posId, ok := s.checkPointOfSale(w, r)
+	if !ok {
+		return
+	}
+
+	var request dto.CreateQRCodeDTO
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid request payload")
+		return
+	}
+	if err := validateQROrder(request); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	order := &QROrder{
+		ExternalReference: request.ExternalReference,
+		PosId:             posId,
+		TotalAmount:       request.TotalAmount,
+		NotificationURL:   request.NotificationURL,
+		Status:            QROrderStatusOpen,
+	}
+	if request.ExpirationDate != "" {
+		expiresAt, err := time.Parse(dto.MercadoPagoTimeLayout, request.ExpirationDate)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid expiration_date")
+			return
+		}
+		order.ExpiresAt = &expiresAt
+	}
+
+	s.mu.Lock()
+	order.InStoreOrderId = fmt.Sprintf("in-store-order-%d", s.nextId())
+	order.QRData = qrData(order)
+	if previous := s.posOrders[posId]; previous != nil && previous.Status == QROrderStatusOpen {
+		// A point of sale shows a single QR code, so a new order replaces the open one
+		previous.Status = QROrderStatusCancelled
+	}
+	s.orders[order.ExternalReference] = order
+	s.posOrders[posId] = order
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, dto.QRCodeResponseDto{QRData: order.QRData, InStoreOrderId: order.InStoreOrderId})
+}
+
+func validateQROrder(request dto.CreateQRCodeDTO) error {
+	if request.ExternalReference == "" {
+		return fmt.Errorf("external_reference is required")
+	}
+	if request.TotalAmount <= 0 {
+		return fmt.Errorf("total_amount must be positive")
+	}
+	var itemsTotal money.Amount
+	for _, item := range request.Items {
+		itemsTotal += item.TotalAmount
+	}
+	if itemsTotal != request.TotalAmount {
+		return fmt.Errorf("items total %s does not match total_amount %s", itemsTotal, request.TotalAmount)
+	}
+	return nil
+}
+
+func (s *Server) getPOSOrder(w http.ResponseWriter, r *http.Request) {
+	posId, ok := s.checkPointOfSale(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	var response *dto.MercadoPagoInStoreOrderResponseDto
+	if order := s.posOrders[posId]; order != nil {
+		response = &dto.MercadoPagoInStoreOrderResponseDto{
+			InStoreOrderId:    order.InStoreOrderId,
+			ExternalReference: order.ExternalReference,
+		}
+	}
+	s.mu.Unlock()
+
+	if response == nil {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("point of sale %s has no order", posId))
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) deletePOSOrder(w http.ResponseWriter, r *http.Request) {
+	posId, ok := s.checkPointOfSale(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if order := s.posOrders[posId]; order != nil {
+		if order.Status == QROrderStatusOpen {
+			order.Status = QROrderStatusCancelled
+		}
+		delete(s.posOrders, posId)
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getPayment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	payment, err := s.findPayment(chi.URLParam(r, "paymentId"))
+	var response dto.MercadoPagoPaymentResponseDto
+	if err == nil {
+		response = payment.MercadoPagoPaymentResponseDto
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, "not_found", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) refundPayment(w http.ResponseWriter, r *http.Request) {
+	var request dto.MercadoPagoRefundRequestDto
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid request payload")
+			return
+		}
+	}
+	idempotencyKey := r.Header.Get("X-Idempotency-Key")
+
+	s.mu.Lock()
+	payment, err := s.findPayment(chi.URLParam(r, "paymentId"))
+	if err != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "not_found", err.Error())
+		return
+	}
+
+	key := strconv.FormatInt(payment.Id, 10) + "/" + idempotencyKey
+	if refund, replayed := s.refundsByKey[key]; replayed && idempotencyKey != "" {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusCreated, refund)
+		return
+	}
+
+	refundable := payment.TransactionAmount - payment.refundedAmount()
+	amount := request.Amount
+	if amount == 0 {
+		amount = refundable
+	}
+	if payment.Status != "approved" || amount <= 0 || amount > refundable {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "invalid_refund", fmt.Sprintf("cannot refund %s of payment %d, refundable %s", amount, payment.Id, refundable))
+		return
+	}
+
+	refund := dto.MercadoPagoRefundResponseDto{Id: s.nextId(), PaymentId: payment.Id, Amount: amount, Status: "approved"}
+	payment.refunds = append(payment.refunds, refund)
+	if amount == refundable {
+		payment.Status, payment.StatusDetail = "refunded", "refunded"
+	} else {
+		payment.Status, payment.StatusDetail = "approved", "partially_refunded"
+	}
+	if idempotencyKey != "" {
+		s.refundsByKey[key] = refund
+	}
+	notification := s.newNotification(payment)
+	s.mu.Unlock()
+
+	// Mercado Pago reports the payment change of a refund like any other
+	go s.deliver(notification)
+
+	writeJSON(w, http.StatusCreated, refund)
+}
+
+// findPayment must be called with the lock held.
+func (s *Server) findPayment(rawId string) (*payment, error) {
+	id, err := strconv.ParseInt(rawId, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("payment %q not found", rawId)
+	}
+	payment, exists := s.payments[id]
+	if !exists {
+		return nil, fmt.Errorf("payment %d not found", id)
+	}
+	return payment, nil
+}
+
+// nextId must be called with the lock held.
+func (s *Server) nextId() int64 {
+	s.lastId++
+	return s.lastId
+}
+
+func qrData(order *QROrder) string {
+	return fmt.Sprintf("00020101021243650016COM.MERCADOLIBRE0201306%02d%s5204970053039865802BR5909FAKE MP6009SAO PAULO62070503***6304FAKE",
+		len(order.InStoreOrderId), order.InStoreOrderId)
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, errorResponse{Error: code, Message: message, Status: status})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,284 @@
+package fakemercadopago_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/fakemercadopago"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const webhookSecret = "fake-secret"
+
+type receivedNotification struct {
+	request      dto.MercadoPagoWebhookNotificationRequestDTO
+	dataId       string
+	signatureErr error
+}
+
+type FakeMercadoPagoTestSuite struct {
+	suite.Suite
+	fake          *httptest.Server
+	webhook       *httptest.Server
+	notifications chan receivedNotification
+	gateway       *gateways.MercadoPagoGatewayImpl
+}
+
+func (suite *FakeMercadoPagoTestSuite) SetupTest() {
+	verifier, err := middleware.NewMercadoPagoSignatureVerifierWithConfig(&middleware.MercadoPagoSignatureConfig{
+		Secret: webhookSecret,
+		Mode:   middleware.SignatureModeEnforce,
+	})
+	suite.Require().NoError(err)
+
+	suite.notifications = make(chan receivedNotification, 10)
+	suite.webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received := receivedNotification{dataId: r.URL.Query().Get("data.id"), signatureErr: verifier.Verify(r)}
+		json.NewDecoder(r.Body).Decode(&received.request)
+		suite.notifications <- received
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	suite.fake = httptest.NewServer(fakemercadopago.NewServer(&fakemercadopago.Config{
+		ClientId:      "client123",
+		PosId:         "pos123",
+		Token:         "test_token",
+		WebhookURL:    suite.webhook.URL + "/payment/webhooks/notify",
+		WebhookSecret: webhookSecret,
+	}, &http.Client{}))
+
+	suite.T().Setenv("MERCADO_PAGO_BASEURL", suite.fake.URL)
+	suite.T().Setenv("MERCADO_PAGO_ACCESS_TOKEN", "test_token")
+	suite.T().Setenv("MERCADO_PAGO_CLIENT_ID", "client123")
+	suite.T().Setenv("MERCADO_PAGO_POS_ID", "pos123")
+	suite.gateway, err = gateways.NewMercadoPagoGatewayImplWithClient(&http.Client{})
+	suite.Require().NoError(err)
+}
+
+func (suite *FakeMercadoPagoTestSuite) TearDownTest() {
+	suite.fake.Close()
+	suite.webhook.Close()
+}
+
+func TestFakeMercadoPagoTestSuite(t *testing.T) {
+	suite.Run(t, new(FakeMercadoPagoTestSuite))
+}
+
+func newQRCode(externalReference string, total string) dto.CreateQRCodeDTO {
+	amount := money.MustParse(total)
+	return dto.CreateQRCodeDTO{
+		ExternalReference: externalReference,
+		Title:             "Fiap",
+		TotalAmount:       amount,
+		Items:             []dto.Item{{Title: "Produto", UnitPrice: amount, Quantity: 1, TotalAmount: amount}},
+	}
+}
+
+func (suite *FakeMercadoPagoTestSuite) settle(externalReference string, outcome string) (int, fakemercadopago.SettlementResponseDto) {
+	resp, err := http.Post(suite.fake.URL+"/fake/qrs/"+externalReference+"/"+outcome, "application/json", nil)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var settlement fakemercadopago.SettlementResponseDto
+	json.NewDecoder(resp.Body).Decode(&settlement)
+	return resp.StatusCode, settlement
+}
+
+func (suite *FakeMercadoPagoTestSuite) nextNotification() receivedNotification {
+	select {
+	case notification := <-suite.notifications:
+		return notification
+	case <-time.After(5 * time.Second):
+		suite.FailNow("no webhook notification received")
+		return receivedNotification{}
+	}
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_ApprovedQRCode_ShouldNotifyAndBeRefundable() {
+	ctx := context.Background()
+
+	// GIVEN a QR code created through the real gateway
+	qrCode, err := suite.gateway.GenerateQRCode(ctx, newQRCode("order-1", "50.00"))
+	suite.Require().NoError(err)
+	assert.NotEmpty(suite.T(), qrCode.QRData)
+	assert.NotEmpty(suite.T(), qrCode.InStoreOrderId)
+
+	// WHEN the customer pays it
+	status, settlement := suite.settle("order-1", fakemercadopago.OutcomeApprove)
+
+	// THEN a signed payment notification should be delivered
+	assert.Equal(suite.T(), http.StatusOK, status)
+	suite.Require().NotNil(settlement.Payment)
+	assert.Equal(suite.T(), fakemercadopago.QROrderStatusPaid, settlement.Order.Status)
+	assert.Equal(suite.T(), http.StatusOK, settlement.Notification.StatusCode)
+
+	paymentId := strconv.FormatInt(settlement.Payment.Id, 10)
+	notification := suite.nextNotification()
+	assert.NoError(suite.T(), notification.signatureErr)
+	assert.Equal(suite.T(), paymentId, notification.dataId)
+	assert.Equal(suite.T(), "payment", notification.request.Topic)
+	assert.Equal(suite.T(), paymentId, notification.request.Resource)
+
+	// AND the payment should be approved when looked up
+	payment, err := suite.gateway.GetPayment(ctx, paymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "approved", payment.Status)
+	assert.Equal(suite.T(), "order-1", payment.ExternalReference)
+	assert.Equal(suite.T(), money.MustParse("50.00"), payment.TransactionAmount)
+
+	// AND refunds should be applied once per idempotency key
+	refund, err := suite.gateway.Refund(ctx, paymentId, money.MustParse("20.00"), "refund-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "approved", refund.Status)
+
+	replayed, err := suite.gateway.Refund(ctx, paymentId, money.MustParse("20.00"), "refund-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), refund.Id, replayed.Id)
+
+	payment, err = suite.gateway.GetPayment(ctx, paymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "partially_refunded", payment.StatusDetail)
+
+	_, err = suite.gateway.Refund(ctx, paymentId, money.MustParse("30.01"), "refund-2")
+	assert.Error(suite.T(), err)
+
+	_, err = suite.gateway.Refund(ctx, paymentId, 0, "refund-3")
+	suite.Require().NoError(err)
+	payment, err = suite.gateway.GetPayment(ctx, paymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "refunded", payment.Status)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_DeclinedQRCode_ShouldStayPayable() {
+	// GIVEN a QR code
+	_, err := suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-2", "10.00"))
+	suite.Require().NoError(err)
+
+	// WHEN the first attempt is declined
+	status, settlement := suite.settle("order-2", fakemercadopago.OutcomeDecline)
+
+	// THEN a rejected payment should be reported and the QR code still be payable
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Equal(suite.T(), "rejected", settlement.Payment.Status)
+	assert.Equal(suite.T(), fakemercadopago.QROrderStatusOpen, settlement.Order.Status)
+	assert.NoError(suite.T(), suite.nextNotification().signatureErr)
+
+	status, settlement = suite.settle("order-2", fakemercadopago.OutcomeApprove)
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Len(suite.T(), settlement.Order.PaymentIds, 2)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_ExpiredQRCode_ShouldNotBePayable() {
+	// GIVEN a QR code whose expiration date has passed and one expired by script
+	expired := newQRCode("order-3", "10.00")
+	expired.ExpirationDate = time.Now().Add(-time.Minute).Format(dto.MercadoPagoTimeLayout)
+	_, err := suite.gateway.GenerateQRCode(context.Background(), expired)
+	suite.Require().NoError(err)
+
+	_, err = suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-4", "10.00"))
+	suite.Require().NoError(err)
+	status, settlement := suite.settle("order-4", fakemercadopago.OutcomeExpire)
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Equal(suite.T(), fakemercadopago.QROrderStatusExpired, settlement.Order.Status)
+	assert.Nil(suite.T(), settlement.Notification)
+
+	// WHEN trying to pay them
+	expiredStatus, _ := suite.settle("order-3", fakemercadopago.OutcomeApprove)
+	scriptedStatus, _ := suite.settle("order-4", fakemercadopago.OutcomeApprove)
+
+	// THEN both should be rejected
+	assert.Equal(suite.T(), http.StatusConflict, expiredStatus)
+	assert.Equal(suite.T(), http.StatusConflict, scriptedStatus)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_DeleteInStoreOrder_ShouldCancelOpenQRCode() {
+	// GIVEN a QR code on the point of sale
+	_, err := suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-5", "10.00"))
+	suite.Require().NoError(err)
+
+	// WHEN the point of sale is cleared
+	err = suite.gateway.DeleteInStoreOrder(context.Background())
+
+	// THEN the QR code should no longer be payable
+	assert.NoError(suite.T(), err)
+	status, _ := suite.settle("order-5", fakemercadopago.OutcomeApprove)
+	assert.Equal(suite.T(), http.StatusConflict, status)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_GetInStoreOrder_ShouldReturnLatestQRCode() {
+	// GIVEN two QR codes generated one after the other
+	_, err := suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-9", "10.00"))
+	suite.Require().NoError(err)
+	latest, err := suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-10", "20.00"))
+	suite.Require().NoError(err)
+
+	// WHEN getting the order on the point of sale
+	order, err := suite.gateway.GetInStoreOrder(context.Background())
+
+	// THEN it should be the latest one
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), latest.InStoreOrderId, order.InStoreOrderId)
+	assert.Equal(suite.T(), "order-10", order.ExternalReference)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_Renotify_ShouldRedeliverSameNotification() {
+	// GIVEN a paid QR code
+	_, err := suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-6", "10.00"))
+	suite.Require().NoError(err)
+	_, settlement := suite.settle("order-6", fakemercadopago.OutcomeApprove)
+	first := suite.nextNotification()
+
+	// WHEN its notification is delivered again
+	resp, err := http.Post(suite.fake.URL+"/fake/payments/"+strconv.FormatInt(settlement.Payment.Id, 10)+"/notify", "application/json", nil)
+	suite.Require().NoError(err)
+	resp.Body.Close()
+
+	// THEN the service should receive the same notification id
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), first.request.Id, suite.nextNotification().request.Id)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_WithInvalidToken_ShouldReturn401() {
+	// GIVEN a request with the wrong access token
+	req := httptest.NewRequest(http.MethodGet, suite.fake.URL+"/v1/payments/1", nil)
+	req.RequestURI = ""
+	req.Header.Set("Authorization", "Bearer wrong")
+
+	// WHEN calling the fake
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	resp.Body.Close()
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_WithUnknownPayment_ShouldReturnError() {
+	// WHEN looking up a payment that was never made
+	_, err := suite.gateway.GetPayment(context.Background(), "999")
+
+	// THEN the gateway should report it
+	assert.ErrorContains(suite.T(), err, "404")
+}
+
+func (suite *FakeMercadoPagoTestSuite) Test_WithUnknownOutcome_ShouldReturn400() {
+	// GIVEN a QR code
+	_, err := suite.gateway.GenerateQRCode(context.Background(), newQRCode("order-7", "10.00"))
+	suite.Require().NoError(err)
+
+	// WHEN scripting an unknown outcome
+	status, _ := suite.settle("order-7", "chargeback")
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+}
@@ -0,0 +1,115 @@
+package fakemercadopago
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+)
+
+const paymentTopic = "payment"
+
+type notification struct {
+	id        string
+	paymentId string
+	url       string
+}
+
+// NotificationResult is the outcome of a webhook delivery.
+type NotificationResult struct {
+	NotificationId string `json:"notification_id"`
+	URL            string `json:"url,omitempty"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// newNotification must be called with the lock held.
+func (s *Server) newNotification(payment *payment) *notification {
+	s.notifications++
+	payment.lastNotificationId = strconv.FormatInt(s.notifications, 10)
+	return s.lastNotification(payment)
+}
+
+// lastNotification must be called with the lock held.
+func (s *Server) lastNotification(payment *payment) *notification {
+	if payment.lastNotificationId == "" {
+		return s.newNotification(payment)
+	}
+
+	webhookURL := payment.notificationURL
+	if webhookURL == "" {
+		webhookURL = s.config.WebhookURL
+	}
+	return &notification{
+		id:        payment.lastNotificationId,
+		paymentId: strconv.FormatInt(payment.Id, 10),
+		url:       webhookURL,
+	}
+}
+
+// deliver posts the notification the way Mercado Pago does: the payment id in the data.id query
+// parameter, an x-request-id, and an x-signature computed with the webhook secret.
+func (s *Server) deliver(notification *notification) NotificationResult {
+	result := NotificationResult{NotificationId: notification.id, URL: notification.url}
+	if notification.url == "" {
+		result.Error = "no webhook URL configured"
+		return result
+	}
+
+	req, err := s.buildNotificationRequest(notification)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		println("ERROR: Failed to deliver webhook notification", notification.id+":", err.Error())
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	return result
+}
+
+func (s *Server) buildNotificationRequest(notification *notification) (*http.Request, error) {
+	endpoint, err := url.Parse(notification.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("data.id", notification.paymentId)
+	query.Set("type", paymentTopic)
+	endpoint.RawQuery = query.Encode()
+
+	payload, err := json.Marshal(dto.MercadoPagoWebhookNotificationRequestDTO{
+		Id:       notification.id,
+		Topic:    paymentTopic,
+		Resource: notification.paymentId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint.String(), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	s.mu.Lock()
+	requestId := fmt.Sprintf("fake-request-%d", s.nextId())
+	s.mu.Unlock()
+	ts := strconv.FormatInt(s.now().UnixMilli(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-request-id", requestId)
+	req.Header.Set("x-signature", fmt.Sprintf("ts=%s,v1=%s", ts, middleware.Sign(s.config.WebhookSecret, notification.paymentId, requestId, ts)))
+	return req, nil
+}
@@ -0,0 +1,434 @@
+// Package fakestripe is an in-memory stand-in for the parts of the Stripe API the payment service
+// uses for card payments: payment intents (create, confirm, capture, cancel) and refunds. Every
+// change fires a signed event at the configured webhook URL, so the card payment loop can run
+// offline. Cards are the Stripe test payment methods: pm_card_visa is approved and any payment
+// method containing "Declined", like pm_card_chargeDeclined, is declined.
+package fakestripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/rest"
+	"github.com/go-chi/chi/v5"
+)
+
+type Config struct {
+	// SecretKey is the expected bearer token; empty accepts any.
+	SecretKey string
+	// WebhookURL receives the events; events are dropped when it is empty.
+	WebhookURL string
+	// WebhookSecret signs the Stripe-Signature header of the events.
+	WebhookSecret string
+	// RetryDelay is the wait before redelivering an event the webhook did not accept; defaults to a second.
+	RetryDelay time.Duration
+}
+
+// Charge is the card charge behind a confirmed payment intent.
+type Charge struct {
+	Id             string `json:"id"`
+	Object         string `json:"object"`
+	Amount         int64  `json:"amount"`
+	AmountCaptured int64  `json:"amount_captured"`
+	AmountRefunded int64  `json:"amount_refunded"`
+	Captured       bool   `json:"captured"`
+	Refunded       bool   `json:"refunded"`
+	Status         string `json:"status"`
+	PaymentIntent  string `json:"payment_intent"`
+}
+
+type paymentIntent struct {
+	dto.StripePaymentIntentDto
+	charge *Charge
+	// lastEvent is delivered again by POST /fake/payment_intents/{id}/notify.
+	lastEvent *event
+}
+
+// paymentIntentResponse renders the latest charge as its id unless it was expanded, like Stripe does.
+type paymentIntentResponse struct {
+	dto.StripePaymentIntentDto
+	LatestCharge any `json:"latest_charge"`
+}
+
+func (p *paymentIntent) response(expandCharge bool) paymentIntentResponse {
+	response := paymentIntentResponse{StripePaymentIntentDto: p.StripePaymentIntentDto}
+	if p.charge != nil {
+		if expandCharge {
+			charge := *p.charge
+			response.LatestCharge = &charge
+		} else {
+			response.LatestCharge = p.charge.Id
+		}
+	}
+	return response
+}
+
+// Server implements the fake Stripe API as an http.Handler.
+type Server struct {
+	config *Config
+	client rest.HTTPClient
+	now    func() time.Time
+	router chi.Router
+
+	mu           sync.Mutex
+	intents      map[string]*paymentIntent
+	refundsByKey map[string]dto.StripeRefundDto
+	lastId       int64
+}
+
+func NewServer(config *Config, client rest.HTTPClient) *Server {
+	s := &Server{
+		config:       config,
+		client:       client,
+		now:          time.Now,
+		intents:      make(map[string]*paymentIntent),
+		refundsByKey: make(map[string]dto.StripeRefundDto),
+	}
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(s.authenticate)
+		r.Post("/v1/payment_intents", s.createPaymentIntent)
+		r.Get("/v1/payment_intents/{id}", s.getPaymentIntent)
+		r.Post("/v1/payment_intents/{id}/confirm", s.confirmPaymentIntent)
+		r.Post("/v1/payment_intents/{id}/capture", s.capturePaymentIntent)
+		r.Post("/v1/payment_intents/{id}/cancel", s.cancelPaymentIntent)
+		r.Post("/v1/refunds", s.createRefund)
+	})
+	r.Get("/fake/payment_intents", s.listPaymentIntents)
+	r.Post("/fake/payment_intents/{id}/notify", s.renotify)
+	s.router = r
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || key == "" || (s.config.SecretKey != "" && key != s.config.SecretKey) {
+			writeError(w, http.StatusUnauthorized, "invalid_request_error", "", "Invalid API Key provided")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) createPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", "invalid form body")
+		return
+	}
+	amount, err := strconv.ParseInt(r.PostForm.Get("amount"), 10, 64)
+	if err != nil || amount <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid_integer", "amount must be a positive integer")
+		return
+	}
+	currency := r.PostForm.Get("currency")
+	if currency == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", "currency is required")
+		return
+	}
+	captureMethod := r.PostForm.Get("capture_method")
+	if captureMethod == "" {
+		captureMethod = "automatic"
+	}
+	if captureMethod != "automatic" && captureMethod != "manual" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", "capture_method must be automatic or manual")
+		return
+	}
+
+	metadata := make(map[string]string)
+	for key, values := range r.PostForm {
+		if name, ok := strings.CutPrefix(key, "metadata["); ok && strings.HasSuffix(name, "]") {
+			metadata[strings.TrimSuffix(name, "]")] = values[0]
+		}
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("pi_fake_%d", s.nextId())
+	intent := &paymentIntent{StripePaymentIntentDto: dto.StripePaymentIntentDto{
+		Id:            id,
+		Object:        "payment_intent",
+		Amount:        amount,
+		Currency:      currency,
+		Status:        "requires_payment_method",
+		CaptureMethod: captureMethod,
+		ClientSecret:  fmt.Sprintf("%s_secret_%d", id, s.nextId()),
+		Metadata:      metadata,
+	}}
+	s.intents[id] = intent
+	response := intent.response(false)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	expand := false
+	for _, field := range r.URL.Query()["expand[]"] {
+		expand = expand || field == "latest_charge"
+	}
+
+	s.mu.Lock()
+	intent, exists := s.intents[chi.URLParam(r, "id")]
+	var response paymentIntentResponse
+	if exists {
+		response = intent.response(expand)
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		writeMissing(w, chi.URLParam(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) confirmPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", "invalid form body")
+		return
+	}
+	paymentMethod := r.PostForm.Get("payment_method")
+	if !strings.HasPrefix(paymentMethod, "pm_") {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing", fmt.Sprintf("No such PaymentMethod: '%s'", paymentMethod))
+		return
+	}
+
+	s.mu.Lock()
+	intent, exists := s.intents[chi.URLParam(r, "id")]
+	if !exists {
+		s.mu.Unlock()
+		writeMissing(w, chi.URLParam(r, "id"))
+		return
+	}
+	if intent.Status != "requires_payment_method" && intent.Status != "requires_confirmation" {
+		s.mu.Unlock()
+		writeUnexpectedState(w, intent)
+		return
+	}
+
+	if strings.Contains(paymentMethod, "Declined") {
+		// The intent waits for another payment method, with the decline recorded on it
+		intent.Status = "requires_payment_method"
+		intent.LastPaymentError = &dto.StripeErrorDto{Type: "card_error", Code: "card_declined", Message: "Your card was declined."}
+		event := s.newEvent(intent, "payment_intent.payment_failed")
+		declined := intent.StripePaymentIntentDto
+		s.mu.Unlock()
+
+		go s.deliver(event)
+		writeJSON(w, http.StatusPaymentRequired, dto.StripeErrorResponseDto{Error: dto.StripeErrorDto{
+			Type:          "card_error",
+			Code:          "card_declined",
+			Message:       "Your card was declined.",
+			PaymentIntent: &declined,
+		}})
+		return
+	}
+
+	intent.LastPaymentError = nil
+	intent.charge = &Charge{
+		Id:            fmt.Sprintf("ch_fake_%d", s.nextId()),
+		Object:        "charge",
+		Amount:        intent.Amount,
+		Status:        "succeeded",
+		PaymentIntent: intent.Id,
+	}
+	var event *event
+	if intent.CaptureMethod == "manual" {
+		intent.Status = "requires_capture"
+		intent.AmountCapturable = intent.Amount
+		event = s.newEvent(intent, "payment_intent.amount_capturable_updated")
+	} else {
+		s.capture(intent, intent.Amount)
+		event = s.newEvent(intent, "payment_intent.succeeded")
+	}
+	response := intent.response(false)
+	s.mu.Unlock()
+
+	go s.deliver(event)
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) capturePaymentIntent(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", "invalid form body")
+		return
+	}
+
+	s.mu.Lock()
+	intent, exists := s.intents[chi.URLParam(r, "id")]
+	if !exists {
+		s.mu.Unlock()
+		writeMissing(w, chi.URLParam(r, "id"))
+		return
+	}
+	if intent.Status != "requires_capture" {
+		s.mu.Unlock()
+		writeUnexpectedState(w, intent)
+		return
+	}
+
+	amount := intent.AmountCapturable
+	if value := r.PostForm.Get("amount_to_capture"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 || parsed > intent.AmountCapturable {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "amount_too_large",
+				fmt.Sprintf("amount_to_capture must be between 1 and %d", intent.AmountCapturable))
+			return
+		}
+		amount = parsed
+	}
+
+	s.capture(intent, amount)
+	event := s.newEvent(intent, "payment_intent.succeeded")
+	response := intent.response(false)
+	s.mu.Unlock()
+
+	go s.deliver(event)
+	writeJSON(w, http.StatusOK, response)
+}
+
+// capture must be called with the lock held. The uncaptured rest of the authorization is released.
+func (s *Server) capture(intent *paymentIntent, amount int64) {
+	intent.Status = "succeeded"
+	intent.AmountCapturable = 0
+	intent.AmountReceived = amount
+	intent.charge.AmountCaptured = amount
+	intent.charge.Captured = true
+}
+
+func (s *Server) cancelPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	intent, exists := s.intents[chi.URLParam(r, "id")]
+	if !exists {
+		s.mu.Unlock()
+		writeMissing(w, chi.URLParam(r, "id"))
+		return
+	}
+	if intent.Status == "succeeded" || intent.Status == "canceled" {
+		s.mu.Unlock()
+		writeUnexpectedState(w, intent)
+		return
+	}
+
+	intent.Status = "canceled"
+	intent.AmountCapturable = 0
+	intent.CancellationReason = "requested_by_customer"
+	event := s.newEvent(intent, "payment_intent.canceled")
+	response := intent.response(false)
+	s.mu.Unlock()
+
+	go s.deliver(event)
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) createRefund(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", "invalid form body")
+		return
+	}
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+
+	s.mu.Lock()
+	if refund, replayed := s.refundsByKey[idempotencyKey]; replayed && idempotencyKey != "" {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, refund)
+		return
+	}
+
+	intent, exists := s.intents[r.PostForm.Get("payment_intent")]
+	if !exists {
+		s.mu.Unlock()
+		writeMissing(w, r.PostForm.Get("payment_intent"))
+		return
+	}
+	if intent.Status != "succeeded" {
+		s.mu.Unlock()
+		writeUnexpectedState(w, intent)
+		return
+	}
+
+	refundable := intent.charge.AmountCaptured - intent.charge.AmountRefunded
+	amount := refundable
+	if value := r.PostForm.Get("amount"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid_integer", "amount must be an integer")
+			return
+		}
+		amount = parsed
+	}
+	if amount <= 0 || amount > refundable {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "amount_too_large",
+			fmt.Sprintf("Refund amount (%d) is greater than the unrefunded amount on the charge (%d)", amount, refundable))
+		return
+	}
+
+	refund := dto.StripeRefundDto{
+		Id:            fmt.Sprintf("re_fake_%d", s.nextId()),
+		Amount:        amount,
+		PaymentIntent: intent.Id,
+		Status:        "succeeded",
+	}
+	intent.charge.AmountRefunded += amount
+	intent.charge.Refunded = intent.charge.AmountRefunded == intent.charge.AmountCaptured
+	if idempotencyKey != "" {
+		s.refundsByKey[idempotencyKey] = refund
+	}
+	event := s.newChargeEvent(intent, "charge.refunded")
+	s.mu.Unlock()
+
+	go s.deliver(event)
+	writeJSON(w, http.StatusOK, refund)
+}
+
+func (s *Server) listPaymentIntents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	intents := make([]paymentIntentResponse, 0, len(s.intents))
+	for i := int64(1); i <= s.lastId; i++ {
+		if intent, exists := s.intents[fmt.Sprintf("pi_fake_%d", i)]; exists {
+			intents = append(intents, intent.response(true))
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, intents)
+}
+
+// nextId must be called with the lock held.
+func (s *Server) nextId() int64 {
+	s.lastId++
+	return s.lastId
+}
+
+func writeMissing(w http.ResponseWriter, id string) {
+	writeError(w, http.StatusNotFound, "invalid_request_error", "resource_missing", fmt.Sprintf("No such payment_intent: '%s'", id))
+}
+
+func writeUnexpectedState(w http.ResponseWriter, intent *paymentIntent) {
+	writeError(w, http.StatusBadRequest, "invalid_request_error", "payment_intent_unexpected_state",
+		fmt.Sprintf("This PaymentIntent's status is %s.", intent.Status))
+}
+
+func writeError(w http.ResponseWriter, status int, errorType string, code string, message string) {
+	writeJSON(w, status, dto.StripeErrorResponseDto{Error: dto.StripeErrorDto{Type: errorType, Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,251 @@
+package fakestripe_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/fakestripe"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const webhookSecret = "whsec_fake"
+
+type receivedEvent struct {
+	event        dto.StripeEventDto
+	signatureErr error
+}
+
+type FakeStripeTestSuite struct {
+	suite.Suite
+	fake    *httptest.Server
+	webhook *httptest.Server
+	events  chan receivedEvent
+	// rejections is how many deliveries the webhook answers with 500 before accepting them.
+	rejections     atomic.Int32
+	stripeGateway  *gateways.StripeGatewayImpl
+	paymentGateway *gateways.StripePaymentGateway
+}
+
+func (suite *FakeStripeTestSuite) SetupTest() {
+	verifier, err := middleware.NewStripeSignatureVerifierWithConfig(&middleware.StripeSignatureConfig{
+		Secret:    webhookSecret,
+		Mode:      middleware.SignatureModeEnforce,
+		Tolerance: time.Minute,
+	})
+	suite.Require().NoError(err)
+
+	suite.rejections.Store(0)
+	suite.events = make(chan receivedEvent, 10)
+	suite.webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if suite.rejections.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received := receivedEvent{signatureErr: verifier.Verify(r)}
+		json.NewDecoder(r.Body).Decode(&received.event)
+		suite.events <- received
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	suite.fake = httptest.NewServer(fakestripe.NewServer(&fakestripe.Config{
+		SecretKey:     "sk_test_fake",
+		WebhookURL:    suite.webhook.URL + "/payment/webhooks/stripe",
+		WebhookSecret: webhookSecret,
+		RetryDelay:    10 * time.Millisecond,
+	}, &http.Client{}))
+
+	suite.T().Setenv("STRIPE_BASEURL", suite.fake.URL)
+	suite.T().Setenv("STRIPE_SECRET_KEY", "sk_test_fake")
+	suite.stripeGateway, err = gateways.NewStripeGatewayImplWithClient(&http.Client{})
+	suite.Require().NoError(err)
+	suite.paymentGateway = gateways.NewStripePaymentGateway(suite.stripeGateway)
+}
+
+func (suite *FakeStripeTestSuite) TearDownTest() {
+	suite.fake.Close()
+	suite.webhook.Close()
+}
+
+func TestFakeStripeTestSuite(t *testing.T) {
+	suite.Run(t, new(FakeStripeTestSuite))
+}
+
+func newChargeRequest(paymentMethod string) paymentGateways.ChargeRequest {
+	return paymentGateways.ChargeRequest{
+		ExternalReference: "order-1",
+		Total:             money.New(money.MustParse("50.00"), money.BRL),
+		PaymentMethod:     paymentMethod,
+	}
+}
+
+func (suite *FakeStripeTestSuite) nextEvent() receivedEvent {
+	select {
+	case event := <-suite.events:
+		return event
+	case <-time.After(5 * time.Second):
+		suite.FailNow("no Stripe event received")
+		return receivedEvent{}
+	}
+}
+
+func (suite *FakeStripeTestSuite) Test_ApprovedCard_ShouldNotifyAndBeRefundable() {
+	ctx := context.Background()
+
+	// GIVEN a card charge confirmed with an approved test card
+	charge, err := suite.paymentGateway.CreateCharge(ctx, newChargeRequest("pm_card_visa"))
+	suite.Require().NoError(err)
+	assert.NotEmpty(suite.T(), charge.ClientSecret)
+
+	// THEN a signed event should be delivered for the authorization
+	event := suite.nextEvent()
+	assert.NoError(suite.T(), event.signatureErr)
+	assert.Equal(suite.T(), "payment_intent.amount_capturable_updated", event.event.Type)
+	assert.Equal(suite.T(), charge.ProviderPaymentId, event.event.PaymentIntentId())
+
+	// AND the payment should be authorized when looked up
+	status, err := suite.paymentGateway.GetChargeStatus(ctx, charge.ProviderPaymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entities.PaymentStatusAuthorized, status.Status)
+	assert.Equal(suite.T(), "order-1", status.ExternalReference)
+
+	// AND capturing it should approve the captured amount
+	payment := &entities.Payment{ProviderPaymentId: charge.ProviderPaymentId}
+	suite.Require().NoError(suite.paymentGateway.CaptureCharge(ctx, payment, money.MustParse("50.00")))
+	assert.Equal(suite.T(), "payment_intent.succeeded", suite.nextEvent().event.Type)
+
+	status, err = suite.paymentGateway.GetChargeStatus(ctx, charge.ProviderPaymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, status.Status)
+	assert.Equal(suite.T(), money.MustParse("50.00"), status.CapturedAmount)
+
+	// AND refunds should be applied once per idempotency key
+	refund, err := suite.paymentGateway.RefundCharge(ctx, payment, money.MustParse("20.00"), "refund-1")
+	suite.Require().NoError(err)
+	replayed, err := suite.paymentGateway.RefundCharge(ctx, payment, money.MustParse("20.00"), "refund-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), refund.ProviderRefundId, replayed.ProviderRefundId)
+	assert.Equal(suite.T(), entities.RefundStatusApproved, refund.Status)
+
+	event = suite.nextEvent()
+	assert.Equal(suite.T(), "charge.refunded", event.event.Type)
+	assert.Equal(suite.T(), charge.ProviderPaymentId, event.event.PaymentIntentId())
+
+	status, err = suite.paymentGateway.GetChargeStatus(ctx, charge.ProviderPaymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entities.PaymentStatusPartiallyRefunded, status.Status)
+
+	// AND refunding more than what is left should fail
+	_, err = suite.paymentGateway.RefundCharge(ctx, payment, money.MustParse("40.00"), "refund-2")
+	assert.ErrorContains(suite.T(), err, "400")
+}
+
+func (suite *FakeStripeTestSuite) Test_DeclinedCard_ShouldNotifyPaymentFailure() {
+	ctx := context.Background()
+
+	// GIVEN a card charge confirmed with a declined test card
+	charge, err := suite.paymentGateway.CreateCharge(ctx, newChargeRequest("pm_card_chargeDeclined"))
+
+	// THEN the charge should still be created, the decline being an outcome of the payment
+	suite.Require().NoError(err)
+	event := suite.nextEvent()
+	assert.Equal(suite.T(), "payment_intent.payment_failed", event.event.Type)
+
+	status, err := suite.paymentGateway.GetChargeStatus(ctx, charge.ProviderPaymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entities.PaymentStatusDeclined, status.Status)
+}
+
+func (suite *FakeStripeTestSuite) Test_ManualCapture_ShouldAuthorizeThenCapture() {
+	ctx := context.Background()
+
+	// GIVEN an authorized intent with manual capture
+	intent, err := suite.stripeGateway.CreatePaymentIntent(ctx, dto.StripeCreatePaymentIntentDto{
+		Amount:        5000,
+		Currency:      "brl",
+		CaptureMethod: "manual",
+	})
+	suite.Require().NoError(err)
+	intent, err = suite.stripeGateway.ConfirmPaymentIntent(ctx, intent.Id, "pm_card_visa")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "requires_capture", intent.Status)
+	assert.Equal(suite.T(), int64(5000), intent.AmountCapturable)
+	assert.Equal(suite.T(), "payment_intent.amount_capturable_updated", suite.nextEvent().event.Type)
+
+	// WHEN capturing part of it
+	intent, err = suite.stripeGateway.CapturePaymentIntent(ctx, intent.Id, 4000)
+
+	// THEN only the captured amount should be received
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "succeeded", intent.Status)
+	assert.Equal(suite.T(), int64(4000), intent.AmountReceived)
+	assert.Equal(suite.T(), "payment_intent.succeeded", suite.nextEvent().event.Type)
+}
+
+func (suite *FakeStripeTestSuite) Test_Cancel_ShouldCancelOnlyOnce() {
+	ctx := context.Background()
+
+	// GIVEN an unconfirmed card charge
+	charge, err := suite.paymentGateway.CreateCharge(ctx, newChargeRequest(""))
+	suite.Require().NoError(err)
+	payment := &entities.Payment{ProviderPaymentId: charge.ProviderPaymentId}
+
+	// WHEN cancelling it
+	err = suite.paymentGateway.CancelCharge(ctx, payment)
+
+	// THEN it should be cancelled and notified
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "payment_intent.canceled", suite.nextEvent().event.Type)
+	status, err := suite.paymentGateway.GetChargeStatus(ctx, charge.ProviderPaymentId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entities.PaymentStatusCancelled, status.Status)
+
+	// AND cancelling again should fail
+	assert.Error(suite.T(), suite.paymentGateway.CancelCharge(ctx, payment))
+}
+
+func (suite *FakeStripeTestSuite) Test_RejectedEvent_ShouldBeRetried() {
+	// GIVEN a webhook failing the first delivery
+	suite.rejections.Store(1)
+	charge, err := suite.paymentGateway.CreateCharge(context.Background(), newChargeRequest("pm_card_visa"))
+	suite.Require().NoError(err)
+
+	// WHEN the event is retried
+	event := suite.nextEvent()
+
+	// THEN it should eventually be accepted
+	assert.Equal(suite.T(), charge.ProviderPaymentId, event.event.PaymentIntentId())
+
+	// AND it can be delivered again on demand
+	resp, err := http.Post(suite.fake.URL+"/fake/payment_intents/"+charge.ProviderPaymentId+"/notify", "application/json", nil)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	var result fakestripe.NotificationResult
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(suite.T(), http.StatusOK, result.StatusCode)
+	assert.Equal(suite.T(), event.event.Id, suite.nextEvent().event.Id)
+}
+
+func (suite *FakeStripeTestSuite) Test_WrongSecretKey_ShouldBeRejected() {
+	// GIVEN a gateway using another secret key
+	suite.T().Setenv("STRIPE_SECRET_KEY", "sk_test_other")
+	gateway, err := gateways.NewStripeGatewayImplWithClient(&http.Client{})
+	suite.Require().NoError(err)
+
+	// WHEN creating an intent
+	_, err = gateway.CreatePaymentIntent(context.Background(), dto.StripeCreatePaymentIntentDto{Amount: 100, Currency: "brl"})
+
+	// THEN it should be unauthorized
+	assert.ErrorContains(suite.T(), err, "401")
+}
@@ -0,0 +1,139 @@
+package fakestripe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// deliveryAttempts is how many times an event is posted before giving up, standing in for the
+// retries Stripe keeps doing for days.
+const deliveryAttempts = 3
+
+type event struct {
+	Id      string `json:"id"`
+	Object  string `json:"object"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object any `json:"object"`
+	} `json:"data"`
+}
+
+// NotificationResult is the outcome of an event delivery.
+type NotificationResult struct {
+	EventId    string `json:"event_id"`
+	URL        string `json:"url,omitempty"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// newEvent must be called with the lock held. The event carries a snapshot of the intent.
+func (s *Server) newEvent(intent *paymentIntent, eventType string) *event {
+	return s.recordEvent(intent, eventType, intent.response(false))
+}
+
+// newChargeEvent must be called with the lock held. The event carries a snapshot of the charge.
+func (s *Server) newChargeEvent(intent *paymentIntent, eventType string) *event {
+	charge := *intent.charge
+	return s.recordEvent(intent, eventType, &charge)
+}
+
+func (s *Server) recordEvent(intent *paymentIntent, eventType string, object any) *event {
+	e := &event{
+		Id:      fmt.Sprintf("evt_fake_%d", s.nextId()),
+		Object:  "event",
+		Type:    eventType,
+		Created: s.now().Unix(),
+	}
+	e.Data.Object = object
+	intent.lastEvent = e
+	return e
+}
+
+// deliver posts the event the way Stripe does, signed in the Stripe-Signature header, retrying
+// while the webhook does not answer with a 2xx.
+func (s *Server) deliver(e *event) NotificationResult {
+	result := NotificationResult{EventId: e.Id, URL: s.config.WebhookURL}
+	if s.config.WebhookURL == "" {
+		result.Error = "no webhook URL configured"
+		return result
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	retryDelay := s.config.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = time.Second
+	}
+
+	for result.Attempts < deliveryAttempts {
+		if result.Attempts > 0 {
+			time.Sleep(retryDelay)
+		}
+		result.Attempts++
+		result.StatusCode, result.Error = 0, ""
+
+		statusCode, err := s.post(payload)
+		if err != nil {
+			println("ERROR: Failed to deliver Stripe event", e.Id+":", err.Error())
+			result.Error = err.Error()
+			continue
+		}
+		result.StatusCode = statusCode
+		if statusCode >= 200 && statusCode < 300 {
+			break
+		}
+	}
+	return result
+}
+
+func (s *Server) post(payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, s.config.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Stripe-Signature", fmt.Sprintf("t=%s,v1=%s", ts, middleware.SignStripePayload(s.config.WebhookSecret, ts, payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// renotify delivers the latest event of a payment intent again and reports how it went.
+func (s *Server) renotify(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	intent, exists := s.intents[chi.URLParam(r, "id")]
+	var e *event
+	if exists {
+		e = intent.lastEvent
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		writeMissing(w, chi.URLParam(r, "id"))
+		return
+	}
+	if e == nil {
+		writeError(w, http.StatusConflict, "invalid_request_error", "no_event", "the payment intent has no event yet")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.deliver(e))
+}
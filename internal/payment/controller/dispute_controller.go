@@ -0,0 +1,7 @@
+package controller
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+
+type DisputeController interface {
+	ListDisputes(listRequest *dto.ListDisputesRequestDto) ([]*dto.DisputeResponseDto, error)
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	paymentPresenter "github.com/abattassini/tc-fiap-payment/internal/payment/presenter"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	listDisputesUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listDisputes"
+)
+
+var (
+	_ DisputeController = (*DisputeControllerImpl)(nil)
+)
+
+type DisputeControllerImpl struct {
+	presenter           paymentPresenter.PaymentPresenter
+	listDisputesUseCase listDisputesUseCase.ListDisputesUseCase
+}
+
+func NewDisputeControllerImpl(
+	presenter paymentPresenter.PaymentPresenter,
+	listDisputesUseCase listDisputesUseCase.ListDisputesUseCase) *DisputeControllerImpl {
+	return &DisputeControllerImpl{
+		presenter:           presenter,
+		listDisputesUseCase: listDisputesUseCase,
+	}
+}
+
+func (c *DisputeControllerImpl) ListDisputes(listRequest *dto.ListDisputesRequestDto) ([]*dto.DisputeResponseDto, error) {
+	disputes, err := c.listDisputesUseCase.Execute(
+		commands.NewListDisputesCommand(
+			listRequest.OrderId,
+			listRequest.Status,
+			listRequest.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentDisputes(disputes), nil
+}
@@ -0,0 +1,72 @@
+package controller_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockPresenter "github.com/abattassini/tc-fiap-payment/mocks/payment/presenter"
+	mockListDisputes "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/listDisputes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DisputeControllerTestSuite struct {
+	suite.Suite
+	mockPresenter           *mockPresenter.MockPaymentPresenter
+	mockListDisputesUseCase *mockListDisputes.MockListDisputesUseCase
+	controller              controller.DisputeController
+}
+
+func (suite *DisputeControllerTestSuite) SetupTest() {
+	suite.mockPresenter = mockPresenter.NewMockPaymentPresenter(suite.T())
+	suite.mockListDisputesUseCase = mockListDisputes.NewMockListDisputesUseCase(suite.T())
+	suite.controller = controller.NewDisputeControllerImpl(suite.mockPresenter, suite.mockListDisputesUseCase)
+}
+
+func TestDisputeControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(DisputeControllerTestSuite))
+}
+
+func (suite *DisputeControllerTestSuite) Test_ListDisputes_WithFilter_ShouldReturnPresentedDisputes() {
+	// GIVEN open disputes
+	disputes := []*entities.Dispute{{ID: 1, OrderId: 1, Status: entities.DisputeStatusOpen}}
+	expected := []*dto.DisputeResponseDto{{ID: 1, OrderId: 1, Status: "open"}}
+
+	suite.mockListDisputesUseCase.EXPECT().
+		Execute(commands.NewListDisputesCommand(0, "open", 5)).
+		Return(disputes, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentDisputes(disputes).
+		Return(expected).
+		Once()
+
+	// WHEN listing disputes
+	result, err := suite.controller.ListDisputes(&dto.ListDisputesRequestDto{Status: "open", Limit: 5})
+
+	// THEN the presented disputes should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *DisputeControllerTestSuite) Test_ListDisputes_WithError_ShouldReturnError() {
+	// GIVEN a failing use case
+	expectedError := errors.New("database error")
+
+	suite.mockListDisputesUseCase.EXPECT().
+		Execute(commands.NewListDisputesCommand(0, "", 0)).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN listing disputes
+	result, err := suite.controller.ListDisputes(&dto.ListDisputesRequestDto{})
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
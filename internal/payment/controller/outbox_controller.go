@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+
+type OutboxController interface {
+	ListMessages(listRequest *dto.ListOutboxMessagesRequestDto) ([]*dto.OutboxMessageResponseDto, error)
+	RetryMessage(id uint) (*dto.OutboxMessageResponseDto, error)
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	paymentPresenter "github.com/abattassini/tc-fiap-payment/internal/payment/presenter"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	listOutboxMessagesUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listOutboxMessages"
+	retryOutboxMessageUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/retryOutboxMessage"
+)
+
+var (
+	_ OutboxController = (*OutboxControllerImpl)(nil)
+)
+
+type OutboxControllerImpl struct {
+	presenter                 paymentPresenter.PaymentPresenter
+	listOutboxMessagesUseCase listOutboxMessagesUseCase.ListOutboxMessagesUseCase
+	retryOutboxMessageUseCase retryOutboxMessageUseCase.RetryOutboxMessageUseCase
+}
+
+func NewOutboxControllerImpl(
+	presenter paymentPresenter.PaymentPresenter,
+	listOutboxMessagesUseCase listOutboxMessagesUseCase.ListOutboxMessagesUseCase,
+	retryOutboxMessageUseCase retryOutboxMessageUseCase.RetryOutboxMessageUseCase) *OutboxControllerImpl {
+	return &OutboxControllerImpl{
+		presenter:                 presenter,
+		listOutboxMessagesUseCase: listOutboxMessagesUseCase,
+		retryOutboxMessageUseCase: retryOutboxMessageUseCase,
+	}
+}
+
+func (c *OutboxControllerImpl) ListMessages(listRequest *dto.ListOutboxMessagesRequestDto) ([]*dto.OutboxMessageResponseDto, error) {
+	messages, err := c.listOutboxMessagesUseCase.Execute(
+		commands.NewListOutboxMessagesCommand(
+			listRequest.OrderId,
+			listRequest.Status,
+			listRequest.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentOutboxMessages(messages), nil
+}
+
+func (c *OutboxControllerImpl) RetryMessage(id uint) (*dto.OutboxMessageResponseDto, error) {
+	message, err := c.retryOutboxMessageUseCase.Execute(commands.NewRetryOutboxMessageCommand(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentOutboxMessage(message), nil
+}
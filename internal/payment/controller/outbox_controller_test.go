@@ -0,0 +1,117 @@
+package controller_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockPresenter "github.com/abattassini/tc-fiap-payment/mocks/payment/presenter"
+	mockListOutboxMessages "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/listOutboxMessages"
+	mockRetryOutboxMessage "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/retryOutboxMessage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxControllerTestSuite struct {
+	suite.Suite
+	mockPresenter                 *mockPresenter.MockPaymentPresenter
+	mockListOutboxMessagesUseCase *mockListOutboxMessages.MockListOutboxMessagesUseCase
+	mockRetryOutboxMessageUseCase *mockRetryOutboxMessage.MockRetryOutboxMessageUseCase
+	controller                    controller.OutboxController
+}
+
+func (suite *OutboxControllerTestSuite) SetupTest() {
+	suite.mockPresenter = mockPresenter.NewMockPaymentPresenter(suite.T())
+	suite.mockListOutboxMessagesUseCase = mockListOutboxMessages.NewMockListOutboxMessagesUseCase(suite.T())
+	suite.mockRetryOutboxMessageUseCase = mockRetryOutboxMessage.NewMockRetryOutboxMessageUseCase(suite.T())
+	suite.controller = controller.NewOutboxControllerImpl(
+		suite.mockPresenter,
+		suite.mockListOutboxMessagesUseCase,
+		suite.mockRetryOutboxMessageUseCase,
+	)
+}
+
+func TestOutboxControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxControllerTestSuite))
+}
+
+func (suite *OutboxControllerTestSuite) Test_ListMessages_WithFilter_ShouldReturnPresentedMessages() {
+	// GIVEN failed messages for an order
+	messages := []*entities.OutboxMessage{entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)}
+	expected := []*dto.OutboxMessageResponseDto{{ID: 1, OrderId: 1, Status: "failed"}}
+
+	suite.mockListOutboxMessagesUseCase.EXPECT().
+		Execute(commands.NewListOutboxMessagesCommand(1, "failed", 5)).
+		Return(messages, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentOutboxMessages(messages).
+		Return(expected).
+		Once()
+
+	// WHEN listing messages
+	result, err := suite.controller.ListMessages(&dto.ListOutboxMessagesRequestDto{OrderId: 1, Status: "failed", Limit: 5})
+
+	// THEN the presented messages should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *OutboxControllerTestSuite) Test_RetryMessage_ShouldReturnPresentedMessage() {
+	// GIVEN a retryable message
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	expected := &dto.OutboxMessageResponseDto{ID: 7, OrderId: 1, Status: "pending"}
+
+	suite.mockRetryOutboxMessageUseCase.EXPECT().
+		Execute(commands.NewRetryOutboxMessageCommand(7)).
+		Return(message, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentOutboxMessage(message).
+		Return(expected).
+		Once()
+
+	// WHEN retrying it
+	result, err := suite.controller.RetryMessage(7)
+
+	// THEN the presented message should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *OutboxControllerTestSuite) Test_RetryMessage_WithError_ShouldReturnError() {
+	// GIVEN a delivered message
+	suite.mockRetryOutboxMessageUseCase.EXPECT().
+		Execute(commands.NewRetryOutboxMessageCommand(7)).
+		Return(nil, entities.ErrOutboxMessageDelivered).
+		Once()
+
+	// WHEN retrying it
+	result, err := suite.controller.RetryMessage(7)
+
+	// THEN error should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrOutboxMessageDelivered)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *OutboxControllerTestSuite) Test_ListMessages_WithError_ShouldReturnError() {
+	// GIVEN a failing use case
+	expectedError := errors.New("database error")
+
+	suite.mockListOutboxMessagesUseCase.EXPECT().
+		Execute(commands.NewListOutboxMessagesCommand(0, "", 0)).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN listing messages
+	result, err := suite.controller.ListMessages(&dto.ListOutboxMessagesRequestDto{})
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
@@ -6,7 +6,15 @@ import (
 
 type PaymentController interface {
 	CreatePayment(addPaymentRequest *dto.AddPaymentRequestDto) (string, error)
+	CreateSplitPayment(addPaymentRequest *dto.AddPaymentRequestDto) ([]*dto.PaymentLegCodeResponseDto, error)
 	GetPaymentStatusByOrderId(orderId uint) (string, error)
 	GetPaymentByOrderId(orderId uint) (*dto.GetPaymentResponseDto, error)
+	GetPaymentAttemptsByOrderId(orderId uint) ([]*dto.PaymentAttemptResponseDto, error)
+	GetPaymentHistoryByOrderId(orderId uint) ([]*dto.PaymentStatusChangeResponseDto, error)
 	UpdatePaymentStatus(orderId uint, status string) error
+	// CancelPayment, RefundPayment and CapturePayment act on the given leg of an order paid in split
+	// legs, and on its payment when leg is zero.
+	CancelPayment(orderId uint, leg int) (*dto.GetPaymentResponseDto, error)
+	RefundPayment(orderId uint, leg int, refundRequest *dto.RefundPaymentRequestDto) (*dto.RefundResponseDto, error)
+	CapturePayment(orderId uint, leg int, captureRequest *dto.CapturePaymentRequestDto) (*dto.GetPaymentResponseDto, error)
 }
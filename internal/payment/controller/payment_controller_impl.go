@@ -1,12 +1,19 @@
 package controller
 
 import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	paymentPresenter "github.com/abattassini/tc-fiap-payment/internal/payment/presenter"
 	addPayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/addPayment"
+	cancelpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/cancelPayment"
+	capturepayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/capturePayment"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	getpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPayment"
+	getpaymenthistory "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPaymentHistory"
 	getpaymentstatus "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPaymentStatus"
+	getsplitpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getSplitPayment"
+	listpaymentattempts "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listPaymentAttempts"
+	refundpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/refundPayment"
 	updatepayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/updatePayment"
 )
 
@@ -15,11 +22,18 @@ var (
 )
 
 type PaymentControllerImpl struct {
-	presenter               paymentPresenter.PaymentPresenter
-	getPaymentUseCase       getpayment.GetPaymentUseCase
-	getPaymentStatusUseCase getpaymentstatus.GetPaymentStatusUseCase
-	updatePaymentUseCase    updatepayment.UpdatePaymentUseCase
-	addPaymentUseCase       addPayment.AddPaymentUseCase
+	presenter                  paymentPresenter.PaymentPresenter
+	getPaymentUseCase          getpayment.GetPaymentUseCase
+	getPaymentStatusUseCase    getpaymentstatus.GetPaymentStatusUseCase
+	updatePaymentUseCase       updatepayment.UpdatePaymentUseCase
+	addPaymentUseCase          addPayment.AddPaymentUseCase
+	listPaymentAttemptsUseCase listpaymentattempts.ListPaymentAttemptsUseCase
+	getPaymentHistoryUseCase   getpaymenthistory.GetPaymentHistoryUseCase
+	cancelPaymentUseCase       cancelpayment.CancelPaymentUseCase
+	refundPaymentUseCase       refundpayment.RefundPaymentUseCase
+	capturePaymentUseCase      capturepayment.CapturePaymentUseCase
+	addSplitPaymentUseCase     addPayment.AddSplitPaymentUseCase
+	getSplitPaymentUseCase     getsplitpayment.GetSplitPaymentUseCase
 }
 
 func NewPaymentControllerImpl(
@@ -27,22 +41,40 @@ func NewPaymentControllerImpl(
 	getPaymentUseCase getpayment.GetPaymentUseCase,
 	getPaymentStatusUseCase getpaymentstatus.GetPaymentStatusUseCase,
 	updatePaymentUseCase updatepayment.UpdatePaymentUseCase,
-	addPaymentUseCase addPayment.AddPaymentUseCase) *PaymentControllerImpl {
+	addPaymentUseCase addPayment.AddPaymentUseCase,
+	listPaymentAttemptsUseCase listpaymentattempts.ListPaymentAttemptsUseCase,
+	getPaymentHistoryUseCase getpaymenthistory.GetPaymentHistoryUseCase,
+	cancelPaymentUseCase cancelpayment.CancelPaymentUseCase,
+	refundPaymentUseCase refundpayment.RefundPaymentUseCase,
+	capturePaymentUseCase capturepayment.CapturePaymentUseCase,
+	addSplitPaymentUseCase addPayment.AddSplitPaymentUseCase,
+	getSplitPaymentUseCase getsplitpayment.GetSplitPaymentUseCase) *PaymentControllerImpl {
 	return &PaymentControllerImpl{
-		presenter:               presenter,
-		getPaymentUseCase:       getPaymentUseCase,
-		getPaymentStatusUseCase: getPaymentStatusUseCase,
-		updatePaymentUseCase:    updatePaymentUseCase,
-		addPaymentUseCase:       addPaymentUseCase,
+		presenter:                  presenter,
+		getPaymentUseCase:          getPaymentUseCase,
+		getPaymentStatusUseCase:    getPaymentStatusUseCase,
+		updatePaymentUseCase:       updatePaymentUseCase,
+		addPaymentUseCase:          addPaymentUseCase,
+		listPaymentAttemptsUseCase: listPaymentAttemptsUseCase,
+		getPaymentHistoryUseCase:   getPaymentHistoryUseCase,
+		cancelPaymentUseCase:       cancelPaymentUseCase,
+		refundPaymentUseCase:       refundPaymentUseCase,
+		capturePaymentUseCase:      capturePaymentUseCase,
+		addSplitPaymentUseCase:     addSplitPaymentUseCase,
+		getSplitPaymentUseCase:     getSplitPaymentUseCase,
 	}
 }
 
 func (c *PaymentControllerImpl) CreatePayment(addPaymentRequest *dto.AddPaymentRequestDto) (string, error) {
-	qrCode, err := c.addPaymentUseCase.Execute(
-		commands.NewAddPaymentCommand(
-			addPaymentRequest.OrderId,
-			addPaymentRequest.Total,
-			addPaymentRequest.Type))
+	addPayment := commands.NewAddPaymentCommand(
+		addPaymentRequest.OrderId,
+		addPaymentRequest.Total,
+		addPaymentRequest.Type,
+		addPaymentRequest.Regenerate)
+	addPayment.PaymentMethod = addPaymentRequest.PaymentMethod
+	addPayment.Tip = tipFromRequest(addPaymentRequest.Tip)
+
+	qrCode, err := c.addPaymentUseCase.Execute(addPayment)
 	if err != nil {
 		return "", err
 	}
@@ -50,6 +82,34 @@ func (c *PaymentControllerImpl) CreatePayment(addPaymentRequest *dto.AddPaymentR
 	return qrCode, nil
 }
 
+func (c *PaymentControllerImpl) CreateSplitPayment(addPaymentRequest *dto.AddPaymentRequestDto) ([]*dto.PaymentLegCodeResponseDto, error) {
+	legs := make([]commands.SplitPaymentLeg, 0, len(addPaymentRequest.Legs))
+	for _, leg := range addPaymentRequest.Legs {
+		legs = append(legs, commands.SplitPaymentLeg{Type: leg.Type, Amount: leg.Amount, PaymentMethod: leg.PaymentMethod})
+	}
+
+	addSplitPayment := commands.NewAddSplitPaymentCommand(
+		addPaymentRequest.OrderId,
+		addPaymentRequest.Total,
+		legs,
+		addPaymentRequest.Regenerate)
+	addSplitPayment.Tip = tipFromRequest(addPaymentRequest.Tip)
+
+	split, err := c.addSplitPaymentUseCase.Execute(addSplitPayment)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentSplitPaymentCodes(split), nil
+}
+
+func tipFromRequest(tip *dto.TipRequestDto) commands.Tip {
+	if tip == nil {
+		return commands.Tip{}
+	}
+	return commands.Tip{Amount: tip.Amount, Percentage: tip.Percentage}
+}
+
 func (c *PaymentControllerImpl) GetPaymentStatusByOrderId(orderId uint) (string, error) {
 	status, err := c.getPaymentStatusUseCase.Execute(commands.NewGetPaymentStatusCommand(orderId))
 	if err != nil {
@@ -64,14 +124,81 @@ func (c *PaymentControllerImpl) GetPaymentByOrderId(orderId uint) (*dto.GetPayme
 	if err != nil {
 		return nil, err
 	}
+	if !payment.IsSplitLeg() {
+		return c.presenter.Present(payment), nil
+	}
 
-	return c.presenter.Present(payment), nil
+	split, err := c.getSplitPaymentUseCase.Execute(commands.NewGetSplitPaymentCommand(orderId))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentSplitPayment(split), nil
+}
+
+func (c *PaymentControllerImpl) GetPaymentAttemptsByOrderId(orderId uint) ([]*dto.PaymentAttemptResponseDto, error) {
+	payments, err := c.listPaymentAttemptsUseCase.Execute(commands.NewListPaymentAttemptsCommand(orderId))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentPaymentAttempts(payments), nil
+}
+
+func (c *PaymentControllerImpl) GetPaymentHistoryByOrderId(orderId uint) ([]*dto.PaymentStatusChangeResponseDto, error) {
+	changes, err := c.getPaymentHistoryUseCase.Execute(commands.NewGetPaymentHistoryCommand(orderId))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentPaymentStatusHistory(changes), nil
 }
 
 func (c *PaymentControllerImpl) UpdatePaymentStatus(orderId uint, status string) error {
-	err := c.updatePaymentUseCase.Execute(commands.NewUpdatePaymentStatusCommand(orderId, status))
+	paymentStatus, err := entities.ParsePaymentStatus(status)
+	if err != nil {
+		return err
+	}
+
+	err = c.updatePaymentUseCase.Execute(commands.NewUpdatePaymentStatusCommand(orderId, paymentStatus, entities.PaymentStatusChangeSourceAPI, ""))
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (c *PaymentControllerImpl) CancelPayment(orderId uint, leg int) (*dto.GetPaymentResponseDto, error) {
+	cancelPayment := commands.NewCancelPaymentCommand(orderId)
+	cancelPayment.Leg = leg
+
+	payment, err := c.cancelPaymentUseCase.Execute(cancelPayment)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.Present(payment), nil
+}
+
+func (c *PaymentControllerImpl) RefundPayment(orderId uint, leg int, refundRequest *dto.RefundPaymentRequestDto) (*dto.RefundResponseDto, error) {
+	refundPayment := commands.NewRefundPaymentCommand(orderId, refundRequest.Amount, refundRequest.Reason)
+	refundPayment.Leg = leg
+
+	refund, err := c.refundPaymentUseCase.Execute(refundPayment)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentRefund(refund), nil
+}
+
+func (c *PaymentControllerImpl) CapturePayment(orderId uint, leg int, captureRequest *dto.CapturePaymentRequestDto) (*dto.GetPaymentResponseDto, error) {
+	capturePayment := commands.NewCapturePaymentCommand(orderId, captureRequest.Amount)
+	capturePayment.Leg = leg
+
+	payment, err := c.capturePaymentUseCase.Execute(capturePayment)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.Present(payment), nil
+}
@@ -7,11 +7,19 @@ import (
 	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	mockPresenter "github.com/abattassini/tc-fiap-payment/mocks/payment/presenter"
 	mockAddPayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/addPayment"
+	mockCancelPayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/cancelPayment"
+	mockCapturePayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/capturePayment"
 	mockGetPayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/getPayment"
+	mockGetPaymentHistory "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/getPaymentHistory"
 	mockGetPaymentStatus "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/getPaymentStatus"
+	mockGetSplitPayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/getSplitPayment"
+	mockListPaymentAttempts "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/listPaymentAttempts"
+	mockRefundPayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/refundPayment"
 	mockUpdatePayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/updatePayment"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -24,6 +32,13 @@ type PaymentControllerTestSuite struct {
 	mockGetPaymentStatusUseCase *mockGetPaymentStatus.MockGetPaymentStatusUseCase
 	mockUpdatePaymentUseCase    *mockUpdatePayment.MockUpdatePaymentUseCase
 	mockAddPaymentUseCase       *mockAddPayment.MockAddPaymentUseCase
+	mockListAttemptsUseCase     *mockListPaymentAttempts.MockListPaymentAttemptsUseCase
+	mockGetHistoryUseCase       *mockGetPaymentHistory.MockGetPaymentHistoryUseCase
+	mockCancelPaymentUseCase    *mockCancelPayment.MockCancelPaymentUseCase
+	mockRefundPaymentUseCase    *mockRefundPayment.MockRefundPaymentUseCase
+	mockCapturePaymentUseCase   *mockCapturePayment.MockCapturePaymentUseCase
+	mockAddSplitPaymentUseCase  *mockAddPayment.MockAddSplitPaymentUseCase
+	mockGetSplitPaymentUseCase  *mockGetSplitPayment.MockGetSplitPaymentUseCase
 	controller                  controller.PaymentController
 }
 
@@ -33,12 +48,26 @@ func (suite *PaymentControllerTestSuite) SetupTest() {
 	suite.mockGetPaymentStatusUseCase = mockGetPaymentStatus.NewMockGetPaymentStatusUseCase(suite.T())
 	suite.mockUpdatePaymentUseCase = mockUpdatePayment.NewMockUpdatePaymentUseCase(suite.T())
 	suite.mockAddPaymentUseCase = mockAddPayment.NewMockAddPaymentUseCase(suite.T())
+	suite.mockListAttemptsUseCase = mockListPaymentAttempts.NewMockListPaymentAttemptsUseCase(suite.T())
+	suite.mockGetHistoryUseCase = mockGetPaymentHistory.NewMockGetPaymentHistoryUseCase(suite.T())
+	suite.mockCancelPaymentUseCase = mockCancelPayment.NewMockCancelPaymentUseCase(suite.T())
+	suite.mockRefundPaymentUseCase = mockRefundPayment.NewMockRefundPaymentUseCase(suite.T())
+	suite.mockCapturePaymentUseCase = mockCapturePayment.NewMockCapturePaymentUseCase(suite.T())
+	suite.mockAddSplitPaymentUseCase = mockAddPayment.NewMockAddSplitPaymentUseCase(suite.T())
+	suite.mockGetSplitPaymentUseCase = mockGetSplitPayment.NewMockGetSplitPaymentUseCase(suite.T())
 	suite.controller = controller.NewPaymentControllerImpl(
 		suite.mockPresenter,
 		suite.mockGetPaymentUseCase,
 		suite.mockGetPaymentStatusUseCase,
 		suite.mockUpdatePaymentUseCase,
 		suite.mockAddPaymentUseCase,
+		suite.mockListAttemptsUseCase,
+		suite.mockGetHistoryUseCase,
+		suite.mockCancelPaymentUseCase,
+		suite.mockRefundPaymentUseCase,
+		suite.mockCapturePaymentUseCase,
+		suite.mockAddSplitPaymentUseCase,
+		suite.mockGetSplitPaymentUseCase,
 	)
 }
 
@@ -50,7 +79,7 @@ func (suite *PaymentControllerTestSuite) Test_CreatePayment_WithValidRequest_Sho
 	// GIVEN a valid payment request
 	request := &dto.AddPaymentRequestDto{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
@@ -72,11 +101,35 @@ func (suite *PaymentControllerTestSuite) Test_CreatePayment_WithValidRequest_Sho
 	suite.mockAddPaymentUseCase.AssertExpectations(suite.T())
 }
 
+func (suite *PaymentControllerTestSuite) Test_CreatePayment_WithTip_ShouldPassItToUseCase() {
+	// GIVEN a payment request with a tip percentage
+	request := &dto.AddPaymentRequestDto{
+		OrderId: 1,
+		Total:   money.MustParse("100.00"),
+		Type:    "QRCode",
+		Tip:     &dto.TipRequestDto{Percentage: money.MustParse("10")},
+	}
+
+	suite.mockAddPaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.AddPaymentCommand) bool {
+			return command.Tip == commands.Tip{Percentage: money.MustParse("10")}
+		})).
+		Return("qr-data", nil).
+		Once()
+
+	// WHEN creating payment
+	qrCode, err := suite.controller.CreatePayment(request)
+
+	// THEN the tip should reach the use case
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "qr-data", qrCode)
+}
+
 func (suite *PaymentControllerTestSuite) Test_CreatePayment_WithError_ShouldReturnError() {
 	// GIVEN a payment request
 	request := &dto.AddPaymentRequestDto{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
@@ -142,7 +195,7 @@ func (suite *PaymentControllerTestSuite) Test_GetPaymentByOrderId_WithValidOrder
 	payment := &entities.Payment{
 		ID:      1,
 		OrderId: orderId,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Status:  "Approved",
 		Type:    "QRCode",
 	}
@@ -150,7 +203,7 @@ func (suite *PaymentControllerTestSuite) Test_GetPaymentByOrderId_WithValidOrder
 	expectedResponse := &dto.GetPaymentResponseDto{
 		ID:      1,
 		OrderId: orderId,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Status:  "Approved",
 		Type:    "QRCode",
 	}
@@ -232,3 +285,286 @@ func (suite *PaymentControllerTestSuite) Test_UpdatePaymentStatus_WithError_Shou
 	assert.Equal(suite.T(), expectedError, err)
 	suite.mockUpdatePaymentUseCase.AssertExpectations(suite.T())
 }
+
+func (suite *PaymentControllerTestSuite) Test_UpdatePaymentStatus_WithUnknownStatus_ShouldReturnError() {
+	// GIVEN an order ID and an unknown status
+	orderId := uint(1)
+	status := "Paid"
+
+	// WHEN updating payment status
+	err := suite.controller.UpdatePaymentStatus(orderId, status)
+
+	// THEN error should be returned without calling the use case
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "unknown payment status")
+}
+
+func (suite *PaymentControllerTestSuite) Test_GetPaymentAttemptsByOrderId_ShouldPresentAttempts() {
+	// GIVEN an order with two attempts
+	payments := []*entities.Payment{
+		{ID: 1, OrderId: 1, Status: entities.PaymentStatusExpired},
+		{ID: 2, OrderId: 1, Status: entities.PaymentStatusPending, Active: true},
+	}
+	expected := []*dto.PaymentAttemptResponseDto{
+		{Attempt: 1, ID: 1, Status: "expired"},
+		{Attempt: 2, ID: 2, Status: "pending", Active: true},
+	}
+
+	suite.mockListAttemptsUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.ListPaymentAttemptsCommand) bool {
+			return cmd.OrderId == 1
+		})).
+		Return(payments, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentPaymentAttempts(payments).
+		Return(expected).
+		Once()
+
+	// WHEN getting the attempts
+	result, err := suite.controller.GetPaymentAttemptsByOrderId(1)
+
+	// THEN the presented attempts should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_GetPaymentAttemptsByOrderId_WithError_ShouldReturnError() {
+	// GIVEN a failing use case
+	expectedError := errors.New("database error")
+
+	suite.mockListAttemptsUseCase.EXPECT().
+		Execute(mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN getting the attempts
+	result, err := suite.controller.GetPaymentAttemptsByOrderId(1)
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_GetPaymentHistoryByOrderId_ShouldPresentStatusChanges() {
+	// GIVEN an order with a recorded status change
+	changes := []*entities.PaymentStatusChange{
+		{ID: 1, PaymentId: 1, OrderId: 1, FromStatus: entities.PaymentStatusPending, ToStatus: entities.PaymentStatusApproved},
+	}
+	expected := []*dto.PaymentStatusChangeResponseDto{
+		{ID: 1, PaymentId: 1, FromStatus: "pending", ToStatus: "approved"},
+	}
+
+	suite.mockGetHistoryUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.GetPaymentHistoryCommand) bool {
+			return cmd.OrderId == 1
+		})).
+		Return(changes, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentPaymentStatusHistory(changes).
+		Return(expected).
+		Once()
+
+	// WHEN getting the history
+	result, err := suite.controller.GetPaymentHistoryByOrderId(1)
+
+	// THEN the presented history should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_UpdatePaymentStatus_ShouldRecordApiAsSource() {
+	// GIVEN a status update requested through the API
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
+			return cmd.OrderId == 1 &&
+				cmd.Status == entities.PaymentStatusCancelled &&
+				cmd.Source == entities.PaymentStatusChangeSourceAPI
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN updating the status
+	err := suite.controller.UpdatePaymentStatus(1, "cancelled")
+
+	// THEN the change should be attributed to the API
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PaymentControllerTestSuite) Test_CancelPayment_ShouldPresentCancelledPayment() {
+	// GIVEN a payment that gets cancelled
+	payment := &entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusCancelled}
+	expected := &dto.GetPaymentResponseDto{ID: 1, OrderId: 1, Status: "cancelled"}
+
+	suite.mockCancelPaymentUseCase.EXPECT().
+		Execute(commands.NewCancelPaymentCommand(1)).
+		Return(payment, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		Present(payment).
+		Return(expected).
+		Once()
+
+	// WHEN cancelling
+	result, err := suite.controller.CancelPayment(1, 0)
+
+	// THEN the presented payment should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_CancelPayment_WithError_ShouldReturnError() {
+	// GIVEN a cancellation that fails
+	expectedError := errors.New("mercado pago unavailable")
+
+	suite.mockCancelPaymentUseCase.EXPECT().
+		Execute(commands.NewCancelPaymentCommand(1)).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN cancelling
+	result, err := suite.controller.CancelPayment(1, 0)
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_RefundPayment_ShouldPresentRefund() {
+	// GIVEN a partial refund request
+	request := &dto.RefundPaymentRequestDto{Amount: money.MustParse("10.00"), Reason: "missing item"}
+	refund := &entities.Refund{ID: 1, PaymentId: 1, OrderId: 1, Amount: request.Amount, Status: entities.RefundStatusApproved}
+	expected := &dto.RefundResponseDto{ID: 1, PaymentId: 1, Amount: request.Amount, Status: "approved"}
+
+	suite.mockRefundPaymentUseCase.EXPECT().
+		Execute(commands.NewRefundPaymentCommand(1, request.Amount, "missing item")).
+		Return(refund, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentRefund(refund).
+		Return(expected).
+		Once()
+
+	// WHEN refunding
+	result, err := suite.controller.RefundPayment(1, 0, request)
+
+	// THEN the presented refund should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_RefundPayment_WithError_ShouldReturnError() {
+	// GIVEN a refund that fails
+	expectedError := entities.ErrRefundNotAllowed
+
+	suite.mockRefundPaymentUseCase.EXPECT().
+		Execute(commands.NewRefundPaymentCommand(1, 0, "")).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN refunding
+	result, err := suite.controller.RefundPayment(1, 0, &dto.RefundPaymentRequestDto{})
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_CapturePayment_ShouldPresentCapturedPayment() {
+	// GIVEN a partial capture request
+	request := &dto.CapturePaymentRequestDto{Amount: money.MustParse("42.00")}
+	payment := &entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusApproved, CapturedTotal: request.Amount}
+	expected := &dto.GetPaymentResponseDto{ID: 1, OrderId: 1, Status: "approved"}
+
+	suite.mockCapturePaymentUseCase.EXPECT().
+		Execute(commands.NewCapturePaymentCommand(1, request.Amount)).
+		Return(payment, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		Present(payment).
+		Return(expected).
+		Once()
+
+	// WHEN capturing
+	result, err := suite.controller.CapturePayment(1, 0, request)
+
+	// THEN the presented payment should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_CapturePayment_WithError_ShouldReturnError() {
+	// GIVEN a capture that is not allowed
+	suite.mockCapturePaymentUseCase.EXPECT().
+		Execute(commands.NewCapturePaymentCommand(1, 0)).
+		Return(nil, entities.ErrCaptureNotAllowed).
+		Once()
+
+	// WHEN capturing
+	result, err := suite.controller.CapturePayment(1, 0, &dto.CapturePaymentRequestDto{})
+
+	// THEN the error should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrCaptureNotAllowed)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *PaymentControllerTestSuite) Test_GetPaymentByOrderId_WithSplitPayment_ShouldPresentAllLegs() {
+	// GIVEN an order paid in split legs
+	leg := &entities.Payment{ID: 1, OrderId: 1, SplitId: "split", Leg: 1, Status: entities.PaymentStatusApproved}
+	split := &entities.SplitPayment{OrderId: 1, SplitId: "split", Legs: []*entities.Payment{leg}}
+	expected := &dto.GetPaymentResponseDto{OrderId: 1, Type: entities.PaymentTypeSplit}
+
+	suite.mockGetPaymentUseCase.EXPECT().Execute(mock.Anything).Return(leg, nil).Once()
+	suite.mockGetSplitPaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.GetSplitPaymentCommand) bool { return command.OrderId == 1 })).
+		Return(split, nil).
+		Once()
+	suite.mockPresenter.EXPECT().PresentSplitPayment(split).Return(expected).Once()
+
+	// WHEN getting the payment of the order
+	result, err := suite.controller.GetPaymentByOrderId(1)
+
+	// THEN the split payment should be presented instead of a single leg
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+	suite.mockPresenter.AssertNotCalled(suite.T(), "Present", mock.Anything)
+}
+
+func (suite *PaymentControllerTestSuite) Test_CreateSplitPayment_ShouldPassLegsToUseCase() {
+	// GIVEN a request splitting the order in two
+	request := &dto.AddPaymentRequestDto{
+		OrderId:    1,
+		Total:      money.MustParse("100.00"),
+		Regenerate: true,
+		Legs: []dto.PaymentLegRequestDto{
+			{Type: entities.PaymentTypeQRCode, Amount: money.MustParse("60.00")},
+			{Type: entities.PaymentTypeCard, Amount: money.MustParse("40.00"), PaymentMethod: "pm_card_visa"},
+		},
+	}
+	split := &entities.SplitPayment{OrderId: 1}
+	codes := []*dto.PaymentLegCodeResponseDto{{Leg: 1}, {Leg: 2}}
+
+	suite.mockAddSplitPaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.AddSplitPaymentCommand) bool {
+			return command.OrderId == 1 && command.Regenerate && len(command.Legs) == 2 &&
+				command.Legs[1].Type == entities.PaymentTypeCard &&
+				command.Legs[1].Amount == money.MustParse("40.00") &&
+				command.Legs[1].PaymentMethod == "pm_card_visa"
+		})).
+		Return(split, nil).
+		Once()
+	suite.mockPresenter.EXPECT().PresentSplitPaymentCodes(split).Return(codes).Once()
+
+	// WHEN creating the split payment
+	result, err := suite.controller.CreateSplitPayment(request)
+
+	// THEN the payment codes of the legs should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), codes, result)
+}
@@ -4,4 +4,7 @@ import "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/a
 
 type PaymentWebhookController interface {
 	HandleWebhook(mercadoPagoWebhookRequest *dto.MercadoPagoWebhookNotificationRequestDTO) error
+	HandleStripeEvent(stripeEvent *dto.StripeEventDto) error
+	HandlePixNotification(pixNotification *dto.PixWebhookDto) error
+	ListNotifications(listRequest *dto.ListWebhookNotificationsRequestDto) ([]*dto.WebhookNotificationResponseDto, error)
 }
@@ -1,9 +1,14 @@
 package controller
 
 import (
+	"errors"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	paymentPresenter "github.com/abattassini/tc-fiap-payment/internal/payment/presenter"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	handleWebhookUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/handleWebhook"
+	listWebhookNotificationsUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listWebhookNotifications"
 )
 
 var (
@@ -11,28 +16,85 @@ var (
 )
 
 type PaymentWebhookControllerImpl struct {
-	handleWebhookUseCase handleWebhookUseCase.HandleWebhookUseCase
+	presenter                       paymentPresenter.PaymentPresenter
+	handleWebhookUseCase            handleWebhookUseCase.HandleWebhookUseCase
+	listWebhookNotificationsUseCase listWebhookNotificationsUseCase.ListWebhookNotificationsUseCase
 }
 
-func NewPaymentWebhookControllerImpl(handleWebhookUseCase handleWebhookUseCase.HandleWebhookUseCase) *PaymentWebhookControllerImpl {
-	return &PaymentWebhookControllerImpl{handleWebhookUseCase: handleWebhookUseCase}
+func NewPaymentWebhookControllerImpl(
+	presenter paymentPresenter.PaymentPresenter,
+	handleWebhookUseCase handleWebhookUseCase.HandleWebhookUseCase,
+	listWebhookNotificationsUseCase listWebhookNotificationsUseCase.ListWebhookNotificationsUseCase) *PaymentWebhookControllerImpl {
+	return &PaymentWebhookControllerImpl{
+		presenter:                       presenter,
+		handleWebhookUseCase:            handleWebhookUseCase,
+		listWebhookNotificationsUseCase: listWebhookNotificationsUseCase,
+	}
 }
 
 func (c *PaymentWebhookControllerImpl) HandleWebhook(mercadoPagoWebhookRequest *dto.MercadoPagoWebhookNotificationRequestDTO) error {
-	status := GetStatusFromString(mercadoPagoWebhookRequest.Topic)
+	command := commands.HandleWebhookCommand{
+		Id:       mercadoPagoWebhookRequest.Id,
+		Topic:    mercadoPagoWebhookRequest.Topic,
+		Resource: mercadoPagoWebhookRequest.Resource,
+	}
+	return c.handleWebhookUseCase.Execute(command)
+}
 
+// HandleStripeEvent records the event under its id and type, with the payment intent it is about as
+// the resource.
+func (c *PaymentWebhookControllerImpl) HandleStripeEvent(stripeEvent *dto.StripeEventDto) error {
 	command := commands.HandleWebhookCommand{
-		Id:     mercadoPagoWebhookRequest.Id,
-		Status: status,
+		Provider: entities.PaymentProviderStripe,
+		Id:       stripeEvent.Id,
+		Topic:    stripeEvent.Type,
+		Resource: stripeEvent.PaymentIntentId(),
 	}
 	return c.handleWebhookUseCase.Execute(command)
 }
 
-func GetStatusFromString(status string) string {
-	switch status {
-	case "payment.created", "payment.updated":
-		return "Approved"
-	default:
-		return "Declined"
+// HandlePixNotification records each received PIX under its end to end id and each refund under its
+// own id, with the charge they are about as the resource. Every entry is handled even when an earlier
+// one fails, since the PSP retries the whole notification.
+func (c *PaymentWebhookControllerImpl) HandlePixNotification(pixNotification *dto.PixWebhookDto) error {
+	var errs []error
+	for _, pix := range pixNotification.Pix {
+		command := commands.HandleWebhookCommand{
+			Provider: entities.PaymentProviderPix,
+			Id:       pix.EndToEndId,
+			Topic:    "pix",
+			Resource: pix.Txid,
+		}
+		errs = append(errs, c.handleWebhookUseCase.Execute(command))
+
+		for _, devolucao := range pix.Devolucoes {
+			refundId := devolucao.RtrId
+			if refundId == "" {
+				refundId = devolucao.Id
+			}
+			command := commands.HandleWebhookCommand{
+				Provider: entities.PaymentProviderPix,
+				Id:       refundId,
+				Topic:    "pix.devolucao",
+				Resource: pix.Txid,
+			}
+			errs = append(errs, c.handleWebhookUseCase.Execute(command))
+		}
 	}
+	return errors.Join(errs...)
+}
+
+func (c *PaymentWebhookControllerImpl) ListNotifications(listRequest *dto.ListWebhookNotificationsRequestDto) ([]*dto.WebhookNotificationResponseDto, error) {
+	notifications, err := c.listWebhookNotificationsUseCase.Execute(
+		commands.NewListWebhookNotificationsCommand(
+			listRequest.ProviderId,
+			listRequest.Topic,
+			listRequest.Resource,
+			listRequest.Outcome,
+			listRequest.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.presenter.PresentWebhookNotifications(notifications), nil
 }
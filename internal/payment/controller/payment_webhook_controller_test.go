@@ -5,8 +5,12 @@ import (
 	"testing"
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockPresenter "github.com/abattassini/tc-fiap-payment/mocks/payment/presenter"
 	mockHandleWebhook "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/handleWebhook"
+	mockListWebhookNotifications "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/listWebhookNotifications"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -14,70 +18,63 @@ import (
 
 type PaymentWebhookControllerTestSuite struct {
 	suite.Suite
-	mockHandleWebhookUseCase *mockHandleWebhook.MockHandleWebhookUseCase
-	controller               controller.PaymentWebhookController
+	mockPresenter                       *mockPresenter.MockPaymentPresenter
+	mockHandleWebhookUseCase            *mockHandleWebhook.MockHandleWebhookUseCase
+	mockListWebhookNotificationsUseCase *mockListWebhookNotifications.MockListWebhookNotificationsUseCase
+	controller                          controller.PaymentWebhookController
 }
 
 func (suite *PaymentWebhookControllerTestSuite) SetupTest() {
+	suite.mockPresenter = mockPresenter.NewMockPaymentPresenter(suite.T())
 	suite.mockHandleWebhookUseCase = mockHandleWebhook.NewMockHandleWebhookUseCase(suite.T())
-	suite.controller = controller.NewPaymentWebhookControllerImpl(suite.mockHandleWebhookUseCase)
+	suite.mockListWebhookNotificationsUseCase = mockListWebhookNotifications.NewMockListWebhookNotificationsUseCase(suite.T())
+	suite.controller = controller.NewPaymentWebhookControllerImpl(
+		suite.mockPresenter,
+		suite.mockHandleWebhookUseCase,
+		suite.mockListWebhookNotificationsUseCase,
+	)
 }
 
 func TestPaymentWebhookControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(PaymentWebhookControllerTestSuite))
 }
 
-func (suite *PaymentWebhookControllerTestSuite) Test_HandleWebhook_WithPaymentCreated_ShouldProcessAsApproved() {
-	// GIVEN a payment.created webhook
+func (suite *PaymentWebhookControllerTestSuite) Test_HandleWebhook_WithPaymentNotification_ShouldForwardResource() {
+	// GIVEN a payment webhook
 	request := &dto.MercadoPagoWebhookNotificationRequestDTO{
-		Id:    "1",
-		Topic: "payment.created",
-	}
-
-	suite.mockHandleWebhookUseCase.EXPECT().
-		Execute(mock.MatchedBy(func(cmd interface{}) bool {
-			return true
-		})).
-		Return(nil).
-		Once()
-
-	// WHEN handling webhook
-	err := suite.controller.HandleWebhook(request)
-
-	// THEN operation should succeed
-	assert.NoError(suite.T(), err)
-	suite.mockHandleWebhookUseCase.AssertExpectations(suite.T())
-}
-
-func (suite *PaymentWebhookControllerTestSuite) Test_HandleWebhook_WithPaymentUpdated_ShouldProcessAsApproved() {
-	// GIVEN a payment.updated webhook
-	request := &dto.MercadoPagoWebhookNotificationRequestDTO{
-		Id:    "1",
-		Topic: "payment.updated",
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "https://api.mercadopago.com/v1/payments/987",
 	}
 
 	suite.mockHandleWebhookUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(commands.HandleWebhookCommand{
+			Id:       "987",
+			Topic:    "payment",
+			Resource: "https://api.mercadopago.com/v1/payments/987",
+		}).
 		Return(nil).
 		Once()
 
 	// WHEN handling webhook
 	err := suite.controller.HandleWebhook(request)
 
-	// THEN operation should succeed
+	// THEN the notification should be forwarded without inferring any status
 	assert.NoError(suite.T(), err)
 	suite.mockHandleWebhookUseCase.AssertExpectations(suite.T())
 }
 
-func (suite *PaymentWebhookControllerTestSuite) Test_HandleWebhook_WithOtherTopic_ShouldProcessAsDeclined() {
-	// GIVEN a webhook with other topic
+func (suite *PaymentWebhookControllerTestSuite) Test_HandleWebhook_WithMerchantOrderNotification_ShouldForwardResource() {
+	// GIVEN a merchant order webhook
 	request := &dto.MercadoPagoWebhookNotificationRequestDTO{
-		Id:    "1",
-		Topic: "payment.failed",
+		Topic:    "merchant_order",
+		Resource: "https://api.mercadolibre.com/merchant_orders/555",
 	}
 
 	suite.mockHandleWebhookUseCase.EXPECT().
-		Execute(mock.Anything).
+		Execute(mock.MatchedBy(func(cmd commands.HandleWebhookCommand) bool {
+			return cmd.Topic == "merchant_order" && cmd.Resource == request.Resource
+		})).
 		Return(nil).
 		Once()
 
@@ -112,26 +109,106 @@ func (suite *PaymentWebhookControllerTestSuite) Test_HandleWebhook_WithError_Sho
 	suite.mockHandleWebhookUseCase.AssertExpectations(suite.T())
 }
 
-func (suite *PaymentWebhookControllerTestSuite) Test_GetStatusFromString_WithPaymentCreated_ShouldReturnApproved() {
-	// WHEN getting status from payment.created
-	status := controller.GetStatusFromString("payment.created")
+func (suite *PaymentWebhookControllerTestSuite) Test_HandleStripeEvent_ShouldForwardPaymentIntent() {
+	// GIVEN a Stripe event about a charge of a payment intent
+	event := &dto.StripeEventDto{Id: "evt_1", Type: "charge.refunded"}
+	event.Data.Object = dto.StripeEventObjectDto{Id: "ch_1", Object: "charge", PaymentIntent: "pi_1"}
+
+	suite.mockHandleWebhookUseCase.EXPECT().
+		Execute(commands.HandleWebhookCommand{
+			Provider: entities.PaymentProviderStripe,
+			Id:       "evt_1",
+			Topic:    "charge.refunded",
+			Resource: "pi_1",
+		}).
+		Return(nil).
+		Once()
+
+	// WHEN handling the event
+	err := suite.controller.HandleStripeEvent(event)
+
+	// THEN the payment intent should be forwarded as the resource
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PaymentWebhookControllerTestSuite) Test_HandlePixNotification_ShouldForwardPaymentsAndRefunds() {
+	// GIVEN a PIX notification with a refunded payment and a failing payment
+	notification := &dto.PixWebhookDto{Pix: []dto.PixDto{
+		{
+			EndToEndId: "E1",
+			Txid:       "txid1",
+			Devolucoes: []dto.PixDevolucaoDto{{Id: "refund1", RtrId: "D1", Status: "DEVOLVIDO"}},
+		},
+		{EndToEndId: "E2", Txid: "txid2"},
+	}}
+	expectedError := errors.New("payment not found")
 
-	// THEN should return Approved
-	assert.Equal(suite.T(), "Approved", status)
+	suite.mockHandleWebhookUseCase.EXPECT().
+		Execute(commands.HandleWebhookCommand{Provider: entities.PaymentProviderPix, Id: "E1", Topic: "pix", Resource: "txid1"}).
+		Return(nil).
+		Once()
+	suite.mockHandleWebhookUseCase.EXPECT().
+		Execute(commands.HandleWebhookCommand{Provider: entities.PaymentProviderPix, Id: "D1", Topic: "pix.devolucao", Resource: "txid1"}).
+		Return(nil).
+		Once()
+	suite.mockHandleWebhookUseCase.EXPECT().
+		Execute(commands.HandleWebhookCommand{Provider: entities.PaymentProviderPix, Id: "E2", Topic: "pix", Resource: "txid2"}).
+		Return(expectedError).
+		Once()
+
+	// WHEN handling the notification
+	err := suite.controller.HandlePixNotification(notification)
+
+	// THEN every entry should be forwarded with the charge as the resource, and the failure reported
+	assert.ErrorIs(suite.T(), err, expectedError)
 }
 
-func (suite *PaymentWebhookControllerTestSuite) Test_GetStatusFromString_WithPaymentUpdated_ShouldReturnApproved() {
-	// WHEN getting status from payment.updated
-	status := controller.GetStatusFromString("payment.updated")
+func (suite *PaymentWebhookControllerTestSuite) Test_ListNotifications_WithFilter_ShouldReturnPresentedNotifications() {
+	// GIVEN a filter and stored notifications
+	request := &dto.ListWebhookNotificationsRequestDto{
+		Resource: "987",
+		Outcome:  "failed",
+		Limit:    10,
+	}
+
+	notifications := []*entities.WebhookNotification{
+		{ID: 1, ProviderId: "987", Topic: "payment", Resource: "987", Outcome: entities.WebhookOutcomeFailed},
+	}
+	expected := []*dto.WebhookNotificationResponseDto{
+		{ID: 1, ProviderId: "987", Topic: "payment", Resource: "987", Outcome: "failed"},
+	}
+
+	suite.mockListWebhookNotificationsUseCase.EXPECT().
+		Execute(commands.NewListWebhookNotificationsCommand("", "", "987", "failed", 10)).
+		Return(notifications, nil).
+		Once()
+
+	suite.mockPresenter.EXPECT().
+		PresentWebhookNotifications(notifications).
+		Return(expected).
+		Once()
+
+	// WHEN listing notifications
+	result, err := suite.controller.ListNotifications(request)
 
-	// THEN should return Approved
-	assert.Equal(suite.T(), "Approved", status)
+	// THEN the presented notifications should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
 }
 
-func (suite *PaymentWebhookControllerTestSuite) Test_GetStatusFromString_WithOtherTopic_ShouldReturnDeclined() {
-	// WHEN getting status from other topic
-	status := controller.GetStatusFromString("payment.failed")
+func (suite *PaymentWebhookControllerTestSuite) Test_ListNotifications_WithError_ShouldReturnError() {
+	// GIVEN a failing use case
+	expectedError := errors.New("database error")
+
+	suite.mockListWebhookNotificationsUseCase.EXPECT().
+		Execute(mock.Anything).
+		Return(nil, expectedError).
+		Once()
 
-	// THEN should return Declined
-	assert.Equal(suite.T(), "Declined", status)
+	// WHEN listing notifications
+	result, err := suite.controller.ListNotifications(&dto.ListWebhookNotificationsRequestDto{})
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
 }
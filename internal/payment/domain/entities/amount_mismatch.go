@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+var ErrAmountMismatch = errors.New("payment amount does not match the order")
+
+// PaymentAmountMismatchError is returned when the amount requested by the client differs from the
+// order total known by the Order Service.
+type PaymentAmountMismatchError struct {
+	Requested  money.Amount
+	OrderTotal money.Amount
+}
+
+func (e *PaymentAmountMismatchError) Error() string {
+	return fmt.Sprintf("%s: requested %s, order total is %s", ErrAmountMismatch, e.Requested, e.OrderTotal)
+}
+
+func (e *PaymentAmountMismatchError) Is(target error) bool {
+	return target == ErrAmountMismatch
+}
+
+// OrderTotalMismatchError is returned when the order total differs from the sum of its product lines.
+type OrderTotalMismatchError struct {
+	OrderTotal money.Amount
+	ItemsTotal money.Amount
+}
+
+func (e *OrderTotalMismatchError) Error() string {
+	return fmt.Sprintf("%s: order total %s differs from the sum of its items %s", ErrAmountMismatch, e.OrderTotal, e.ItemsTotal)
+}
+
+func (e *OrderTotalMismatchError) Is(target error) bool {
+	return target == ErrAmountMismatch
+}
+
+// SplitAmountMismatchError is returned when the legs of a split payment do not add up to what the order
+// charges: its total plus service fees and tip.
+type SplitAmountMismatchError struct {
+	LegsTotal money.Amount
+	Charged   money.Amount
+}
+
+func (e *SplitAmountMismatchError) Error() string {
+	return fmt.Sprintf("%s: legs add up to %s, the order charges %s", ErrAmountMismatch, e.LegsTotal, e.Charged)
+}
+
+func (e *SplitAmountMismatchError) Is(target error) bool {
+	return target == ErrAmountMismatch
+}
@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+type DisputeType string
+
+const (
+	DisputeTypeChargeback DisputeType = "chargeback"
+	DisputeTypeClaim      DisputeType = "claim"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen   DisputeStatus = "open"
+	DisputeStatusClosed DisputeStatus = "closed"
+)
+
+type DisputeOutcome string
+
+const (
+	// DisputeOutcomeWon means the merchant keeps the money.
+	DisputeOutcomeWon DisputeOutcome = "won"
+	// DisputeOutcomeLost means the money went back to the customer.
+	DisputeOutcomeLost DisputeOutcome = "lost"
+)
+
+// Dispute is a chargeback or claim opened by the customer against a paid payment. Provider, Type and
+// ProviderDisputeId identify it, so repeated notifications land on the same row.
+type Dispute struct {
+	ID                uint      `gorm:"primaryKey"`
+	CreatedAt         time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt         time.Time
+	PaymentId         uint           `gorm:"index;not null"`
+	OrderId           uint           `gorm:"index;not null"`
+	Provider          string         `gorm:"size:32;uniqueIndex:idx_disputes_provider_dispute;not null"`
+	Type              DisputeType    `gorm:"uniqueIndex:idx_disputes_provider_dispute;not null"`
+	ProviderDisputeId string         `gorm:"uniqueIndex:idx_disputes_provider_dispute;not null"`
+	Amount            money.Amount   `gorm:"type:numeric(12,2);not null"`
+	Currency          money.Currency `gorm:"size:3;not null;default:BRL"`
+	Status            DisputeStatus  `gorm:"index;not null"`
+	Outcome           DisputeOutcome
+	Reason            string
+	// PaymentStatus is the status the payment had before it was disputed: its status when the dispute was
+	// opened, or the one recorded by a dispute already open on it then.
+	PaymentStatus PaymentStatus `gorm:"not null"`
+	OpenedAt      time.Time     `gorm:"not null"`
+	ClosedAt      *time.Time
+}
+
+func (Dispute) TableName() string {
+	return "disputes"
+}
+
+func NewDispute(payment *Payment, disputeType DisputeType, providerDisputeId string, amount money.Amount, reason string, now time.Time) *Dispute {
+	return &Dispute{
+		PaymentId:         payment.ID,
+		OrderId:           payment.OrderId,
+		Provider:          payment.Provider,
+		Type:              disputeType,
+		ProviderDisputeId: providerDisputeId,
+		Amount:            amount,
+		Currency:          payment.Currency,
+		Status:            DisputeStatusOpen,
+		Reason:            reason,
+		PaymentStatus:     payment.Status,
+		OpenedAt:          now,
+	}
+}
+
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeStatusOpen
+}
+
+func (d *Dispute) Close(outcome DisputeOutcome, now time.Time) {
+	d.Status = DisputeStatusClosed
+	d.Outcome = outcome
+	d.ClosedAt = &now
+}
+
+// ResolvedPaymentStatus is the status the disputed payment moves to once the dispute is closed.
+func (d *Dispute) ResolvedPaymentStatus() PaymentStatus {
+	if d.Outcome == DisputeOutcomeWon {
+		return d.PaymentStatus
+	}
+	return PaymentStatusRefunded
+}
@@ -0,0 +1,45 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDispute_ShouldRememberPaymentStatus(t *testing.T) {
+	// GIVEN a partially refunded payment
+	payment := &entities.Payment{ID: 1, OrderId: 2, Provider: entities.PaymentProviderMercadoPago, Currency: money.BRL, Status: entities.PaymentStatusPartiallyRefunded}
+
+	// WHEN a dispute is opened
+	dispute := entities.NewDispute(payment, entities.DisputeTypeChargeback, "cb-1", money.MustParse("10.00"), "fraud", time.Now())
+
+	// THEN it should be open and remember the status to restore
+	assert.True(t, dispute.IsOpen())
+	assert.Equal(t, entities.PaymentStatusPartiallyRefunded, dispute.PaymentStatus)
+	assert.Equal(t, uint(2), dispute.OrderId)
+}
+
+func TestDispute_ResolvedPaymentStatus(t *testing.T) {
+	tests := []struct {
+		outcome  entities.DisputeOutcome
+		expected entities.PaymentStatus
+	}{
+		{entities.DisputeOutcomeWon, entities.PaymentStatusApproved},
+		{entities.DisputeOutcomeLost, entities.PaymentStatusRefunded},
+	}
+
+	for _, tt := range tests {
+		// GIVEN a dispute opened on an approved payment
+		dispute := &entities.Dispute{Status: entities.DisputeStatusOpen, PaymentStatus: entities.PaymentStatusApproved}
+
+		// WHEN it is closed
+		dispute.Close(tt.outcome, time.Now())
+
+		// THEN the payment status should follow the outcome
+		assert.False(t, dispute.IsOpen())
+		assert.Equal(t, tt.expected, dispute.ResolvedPaymentStatus(), tt.outcome)
+	}
+}
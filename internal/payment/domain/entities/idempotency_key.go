@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// IdempotencyKey stores the response of a request sent with an Idempotency-Key header, so that
+// retries of the same request get the original response instead of repeating its side effects.
+type IdempotencyKey struct {
+	ID                  uint      `gorm:"primaryKey"`
+	Key                 string    `gorm:"uniqueIndex;not null"`
+	RequestFingerprint  string    `gorm:"not null"`
+	CreatedAt           time.Time `gorm:"index;not null"`
+	CompletedAt         *time.Time
+	ResponseStatus      int
+	ResponseContentType string
+	ResponseBody        []byte
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted reports whether the original request finished and its response was recorded.
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
+
+func (k *IdempotencyKey) IsExpired(ttl time.Duration, now time.Time) bool {
+	return now.After(k.CreatedAt.Add(ttl))
+}
+
+// IsAbandoned reports whether the request that claimed the key has run for longer than timeout without
+// completing, e.g. because its replica died.
+func (k *IdempotencyKey) IsAbandoned(timeout time.Duration, now time.Time) bool {
+	return !k.IsCompleted() && now.After(k.CreatedAt.Add(timeout))
+}
+
+func (k *IdempotencyKey) Complete(status int, contentType string, body []byte) {
+	now := time.Now()
+	k.CompletedAt = &now
+	k.ResponseStatus = status
+	k.ResponseContentType = contentType
+	k.ResponseBody = body
+}
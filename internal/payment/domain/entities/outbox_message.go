@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// Order status updates sent to the Order Service. They are stored in the outbox, so their values must not
+// change; the order client translates them to the status codes of the Order Service contract, which are
+// configured with ORDER_STATUS_PREPARING and ORDER_STATUS_CANCELLED.
+const (
+	// OrderStatusPreparing moves an order whose payment was approved to the kitchen.
+	OrderStatusPreparing = 2
+	// OrderStatusCancelled cancels an order that was not paid, e.g. an expired QR code or a voided
+	// card authorization.
+	OrderStatusCancelled = 5
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusDelivered OutboxStatus = "delivered"
+	OutboxStatusFailed    OutboxStatus = "failed"
+)
+
+var ErrOutboxMessageDelivered = errors.New("outbox message already delivered")
+
+// OutboxMessage is an order status update waiting to be delivered to the Order Service. It is
+// written in the same transaction as the payment change that caused it, and delivered afterwards
+// by the outbox dispatcher.
+type OutboxMessage struct {
+	ID            uint         `gorm:"primaryKey"`
+	CreatedAt     time.Time    `gorm:"default:current_timestamp"`
+	OrderId       uint         `gorm:"index;not null"`
+	OrderStatus   int          `gorm:"not null"`
+	Status        OutboxStatus `gorm:"index:idx_order_status_outbox_due,priority:1;not null"`
+	NextAttemptAt time.Time    `gorm:"index:idx_order_status_outbox_due,priority:2;not null"`
+	Attempts      uint         `gorm:"not null;default:0"`
+	LastError     string
+	DeliveredAt   *time.Time
+	// ClaimedUntil keeps a message picked by the dispatcher of one replica out of the dispatchers of the
+	// others until then; nil when no dispatcher holds it.
+	ClaimedUntil *time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "order_status_outbox"
+}
+
+func NewOrderStatusOutboxMessage(orderId uint, orderStatus int) *OutboxMessage {
+	return &OutboxMessage{
+		OrderId:       orderId,
+		OrderStatus:   orderStatus,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+func (m *OutboxMessage) MarkDelivered() {
+	now := time.Now()
+	m.Attempts++
+	m.Status = OutboxStatusDelivered
+	m.DeliveredAt = &now
+	m.LastError = ""
+	m.ClaimedUntil = nil
+}
+
+// MarkAttemptFailed records a failed delivery. The message is scheduled again at nextAttemptAt
+// until maxAttempts is reached, after which it stays failed until retried manually, holding back the
+// later updates of its order.
+func (m *OutboxMessage) MarkAttemptFailed(err error, nextAttemptAt time.Time, maxAttempts uint) {
+	m.Attempts++
+	m.LastError = err.Error()
+	m.ClaimedUntil = nil
+	if m.Attempts >= maxAttempts {
+		m.Status = OutboxStatusFailed
+		return
+	}
+	m.NextAttemptAt = nextAttemptAt
+}
+
+// Retry schedules the message for immediate delivery with a fresh attempt budget.
+func (m *OutboxMessage) Retry() error {
+	if m.Status == OutboxStatusDelivered {
+		return ErrOutboxMessageDelivered
+	}
+	m.Status = OutboxStatusPending
+	m.Attempts = 0
+	m.NextAttemptAt = time.Now()
+	m.ClaimedUntil = nil
+	return nil
+}
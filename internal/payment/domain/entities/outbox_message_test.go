@@ -0,0 +1,86 @@
+package entities_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrderStatusOutboxMessage_ShouldBeDueImmediately(t *testing.T) {
+	// WHEN creating an order status update
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+
+	// THEN it should be pending and due now
+	assert.Equal(t, entities.OutboxStatusPending, message.Status)
+	assert.Equal(t, uint(1), message.OrderId)
+	assert.Equal(t, entities.OrderStatusPreparing, message.OrderStatus)
+	assert.False(t, message.NextAttemptAt.After(time.Now()))
+}
+
+func TestOutboxMessage_MarkAttemptFailed_ShouldRescheduleUntilMaxAttempts(t *testing.T) {
+	// GIVEN a pending message allowed two attempts
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	nextAttemptAt := time.Now().Add(time.Minute)
+
+	// WHEN the first attempt fails
+	message.MarkAttemptFailed(errors.New("order service unavailable"), nextAttemptAt, 2)
+
+	// THEN it should be rescheduled
+	assert.Equal(t, entities.OutboxStatusPending, message.Status)
+	assert.Equal(t, uint(1), message.Attempts)
+	assert.Equal(t, nextAttemptAt, message.NextAttemptAt)
+	assert.Equal(t, "order service unavailable", message.LastError)
+
+	// WHEN the second attempt fails
+	message.MarkAttemptFailed(errors.New("order service unavailable"), nextAttemptAt, 2)
+
+	// THEN it should give up
+	assert.Equal(t, entities.OutboxStatusFailed, message.Status)
+	assert.Equal(t, uint(2), message.Attempts)
+}
+
+func TestOutboxMessage_MarkDelivered(t *testing.T) {
+	// GIVEN a message with a previous failure
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.MarkAttemptFailed(errors.New("timeout"), time.Now(), 5)
+
+	// WHEN it is delivered
+	message.MarkDelivered()
+
+	// THEN it should be delivered and the error cleared
+	assert.Equal(t, entities.OutboxStatusDelivered, message.Status)
+	assert.Equal(t, uint(2), message.Attempts)
+	assert.NotNil(t, message.DeliveredAt)
+	assert.Empty(t, message.LastError)
+}
+
+func TestOutboxMessage_Retry(t *testing.T) {
+	// GIVEN a message that exhausted its attempts
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.MarkAttemptFailed(errors.New("timeout"), time.Now().Add(time.Hour), 1)
+
+	// WHEN retrying it
+	err := message.Retry()
+
+	// THEN it should be pending again with a fresh attempt budget
+	assert.NoError(t, err)
+	assert.Equal(t, entities.OutboxStatusPending, message.Status)
+	assert.Zero(t, message.Attempts)
+	assert.False(t, message.NextAttemptAt.After(time.Now()))
+}
+
+func TestOutboxMessage_Retry_WhenDelivered_ShouldFail(t *testing.T) {
+	// GIVEN a delivered message
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.MarkDelivered()
+
+	// WHEN retrying it
+	err := message.Retry()
+
+	// THEN it should be rejected
+	assert.ErrorIs(t, err, entities.ErrOutboxMessageDelivered)
+	assert.Equal(t, entities.OutboxStatusDelivered, message.Status)
+}
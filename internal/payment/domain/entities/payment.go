@@ -1,16 +1,212 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+var (
+	ErrActivePaymentExists      = errors.New("order already has an active payment")
+	ErrPaymentNotFound          = errors.New("payment not found")
+	ErrUnsupportedPaymentType   = errors.New("unsupported payment type")
+	ErrCaptureNotAllowed        = errors.New("payment cannot be captured")
+	ErrCaptureExceedsAuthorized = errors.New("capture exceeds the authorized amount")
+	ErrInvalidSplitPayment      = errors.New("invalid split payment")
+	ErrPaymentLegRequired       = errors.New("order is paid in split legs, a leg must be given")
+	ErrInvalidTip               = errors.New("invalid tip")
+)
+
+// CaptureExceedsAuthorizedError is returned when a capture asks for more than the authorization holds.
+type CaptureExceedsAuthorizedError struct {
+	Requested  money.Amount
+	Authorized money.Amount
+}
+
+func (e *CaptureExceedsAuthorizedError) Error() string {
+	return fmt.Sprintf("%s: requested %s, authorized %s", ErrCaptureExceedsAuthorized, e.Requested, e.Authorized)
+}
+
+func (e *CaptureExceedsAuthorizedError) Is(target error) bool {
+	return target == ErrCaptureExceedsAuthorized
+}
+
+const (
+	PaymentProviderMercadoPago = "mercadopago"
+	PaymentProviderStripe      = "stripe"
+	PaymentProviderPix         = "pix"
+)
+
+const (
+	// PaymentTypeQRCode is the in-store QR code payment, charged through Mercado Pago.
+	PaymentTypeQRCode = "qrcode"
+	// PaymentTypeCard is a card payment, charged through a Stripe-compatible provider.
+	PaymentTypeCard = "card"
+	// PaymentTypePix is a PIX instant payment, charged through the PSP receiving our PIX payments.
+	PaymentTypePix = "pix"
+	// PaymentTypeSplit is how an order paid in several legs is presented; each leg has the type it was
+	// charged with.
+	PaymentTypeSplit = "split"
+)
 
 type Payment struct {
 	ID        uint      `gorm:"primaryKey"`
 	CreatedAt time.Time `gorm:"default:current_timestamp"`
-	OrderId   uint      `gorm:"index;not null"`
-	Total     float32   `gorm:"not null"`
-	Type      string    `gorm:"not null"`
-	Status    string    `gorm:"not null"`
+	UpdatedAt time.Time
+	OrderId   uint           `gorm:"index;not null;uniqueIndex:idx_payment_active_order_leg,priority:1,where:active;uniqueIndex:idx_payment_active_order_slot,where:active AND leg <= 1"`
+	Total     money.Amount   `gorm:"type:numeric(12,2);not null"`
+	Currency  money.Currency `gorm:"size:3;not null;default:BRL"`
+	Type      string         `gorm:"not null"`
+	Status    PaymentStatus  `gorm:"not null"`
+	// Active mirrors !Status.IsTerminal() so the database can enforce a single active payment per order,
+	// or per leg of an order paid in split legs. A payment of the whole order (leg 0) and the first leg of
+	// a split share a slot, since every new split issues its first leg: an order is never paid at once and
+	// in split legs at the same time.
+	Active bool `gorm:"not null;default:false"`
+	// SplitId groups the legs of an order paid with several payments, and Leg numbers them from 1; both
+	// are empty for an order paid at once.
+	SplitId string `gorm:"size:32;index"`
+	Leg     int    `gorm:"not null;default:0;uniqueIndex:idx_payment_active_order_leg,priority:2"`
+	// Provider references, used to reconcile the payment with the provider. ProviderOrderId is the
+	// in-store order behind the QR code; ProviderPaymentId is only known once the provider notifies us.
+	Provider          string `gorm:"size:32;index:idx_payment_provider_payment,priority:1"`
+	ProviderOrderId   string
+	ExternalReference string `gorm:"index"`
+	QRData            string
+	// ClientSecret lets the client confirm a card payment with the provider; it is never listed back.
+	ClientSecret string
+	// Txid identifies the charge of a PIX payment at the PSP; the BR Code to pay it is kept in QRData.
+	Txid              string `gorm:"size:35;index"`
+	ProviderPaymentId string `gorm:"index:idx_payment_provider_payment,priority:2"`
+	// ExpiresAt is when the QR code stops being payable; nil when it does not expire.
+	ExpiresAt *time.Time `gorm:"index"`
+	// AuthorizedAt is when the card was authorized; uncaptured authorizations are voided after a while.
+	AuthorizedAt *time.Time `gorm:"index"`
+	// ClaimedUntil keeps a payment picked by the sweep of one replica out of the sweeps of the others
+	// until then; nil when no sweep holds it.
+	ClaimedUntil *time.Time
+	// CapturedTotal is the part of an authorization that was captured; zero for payments approved without
+	// a separate capture.
+	CapturedTotal money.Amount `gorm:"type:numeric(12,2);not null;default:0"`
+	// RefundedTotal is the sum of the refunds the provider accepted, kept up to date as they are settled.
+	RefundedTotal money.Amount `gorm:"type:numeric(12,2);not null;default:0"`
+	// TipAmount and ServiceFeeAmount are the parts of Total charged as a tip and as service fees on top
+	// of the order items, kept apart for reporting.
+	TipAmount        money.Amount `gorm:"type:numeric(12,2);not null;default:0"`
+	ServiceFeeAmount money.Amount `gorm:"type:numeric(12,2);not null;default:0"`
+	FailureReason    string
 }
 
 func (Payment) TableName() string {
 	return "payment"
 }
+
+func NewPayment(orderId uint, total money.Money, paymentType string) *Payment {
+	return &Payment{
+		OrderId:  orderId,
+		Total:    total.Amount,
+		Currency: total.Currency,
+		Type:     paymentType,
+		Status:   PaymentStatusPending,
+		Active:   true,
+	}
+}
+
+// NewFailedPayment records a payment attempt that the provider rejected, so that it can be audited
+// without ever becoming the active payment of the order.
+func NewFailedPayment(orderId uint, total money.Money, paymentType string, reason string) *Payment {
+	return &Payment{
+		OrderId:       orderId,
+		Total:         total.Amount,
+		Currency:      total.Currency,
+		Type:          paymentType,
+		Status:        PaymentStatusFailed,
+		FailureReason: reason,
+	}
+}
+
+// CapturedAmount is the amount collected from the customer, the most that can be refunded. Payments
+// approved without a separate capture collected their whole total.
+func (p *Payment) CapturedAmount() money.Amount {
+	if p.CapturedTotal > 0 {
+		return p.CapturedTotal
+	}
+	return p.Total
+}
+
+// RefundedStatus is the status of a refundable payment once refunded has been given back: refunded when
+// nothing of the captured amount is left, partially refunded otherwise.
+func (p *Payment) RefundedStatus(refunded money.Amount) PaymentStatus {
+	if refunded >= p.CapturedAmount() {
+		return PaymentStatusRefunded
+	}
+	return PaymentStatusPartiallyRefunded
+}
+
+// Revenue is what the payment collected for the order and its service fees and kept after refunds. Tips
+// are charged along with the order but belong to the staff, so revenue reports leave them out. It is
+// never negative, e.g. when a partial capture left less than the tip.
+func (p *Payment) Revenue() money.Amount {
+	return max(p.CapturedAmount()-p.RefundedTotal-p.TipAmount, 0)
+}
+
+// NewPaymentLeg creates a pending leg of a split payment, charging part of the order.
+func NewPaymentLeg(orderId uint, splitId string, leg int, amount money.Money, paymentType string) *Payment {
+	payment := NewPayment(orderId, amount, paymentType)
+	payment.SplitId = splitId
+	payment.Leg = leg
+	return payment
+}
+
+// OrderExternalReference is the reference sent to the provider to identify the order of a payment.
+func OrderExternalReference(orderId uint) string {
+	return fmt.Sprintf("order-%d", orderId)
+}
+
+// LegExternalReference identifies a leg of a split payment at its provider, so that each leg is
+// reconciled on its own.
+func LegExternalReference(orderId uint, leg int) string {
+	return fmt.Sprintf("order-%d-leg-%d", orderId, leg)
+}
+
+// IsSplitLeg reports whether the payment charges only part of its order, as a leg of a split payment.
+func (p *Payment) IsSplitLeg() bool {
+	return p.Leg > 0
+}
+
+// PaymentCode is what the client needs to pay: the QR code, or the client secret of a card payment.
+func (p *Payment) PaymentCode() string {
+	if p.QRData != "" {
+		return p.QRData
+	}
+	return p.ClientSecret
+}
+
+func (p *Payment) Money() money.Money {
+	return money.New(p.Total, p.Currency)
+}
+
+// IsExpired reports whether the payment is still pending after its QR code expired.
+func (p *Payment) IsExpired(now time.Time) bool {
+	return p.Status == PaymentStatusPending && p.ExpiresAt != nil && !now.Before(*p.ExpiresAt)
+}
+
+// TransitionTo moves the payment to the given status, enforcing the status transition table.
+// Re-applying the current status is a no-op so that repeated notifications are harmless.
+func (p *Payment) TransitionTo(status PaymentStatus) error {
+	if p.Status == status {
+		return nil
+	}
+	if !p.Status.CanTransitionTo(status) {
+		return &InvalidStatusTransitionError{From: p.Status, To: status}
+	}
+	p.Status = status
+	p.Active = !status.IsTerminal()
+	if status == PaymentStatusAuthorized {
+		authorizedAt := time.Now()
+		p.AuthorizedAt = &authorizedAt
+	}
+	return nil
+}
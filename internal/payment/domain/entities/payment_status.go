@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending PaymentStatus = "pending"
+	// PaymentStatusAuthorized marks a card payment whose amount is held on the card, waiting to be captured.
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusApproved   PaymentStatus = "approved"
+	PaymentStatusDeclined   PaymentStatus = "declined"
+	PaymentStatusCancelled  PaymentStatus = "cancelled"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	// PaymentStatusPartiallyRefunded marks an approved payment of which part was given back.
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusExpired           PaymentStatus = "expired"
+	// PaymentStatusDisputed marks a paid payment under a chargeback or claim.
+	PaymentStatusDisputed PaymentStatus = "disputed"
+	// PaymentStatusFailed marks a payment that could not be created on the provider.
+	PaymentStatusFailed PaymentStatus = "failed"
+)
+
+// paymentStatusTransitions lists, for each status, the statuses a payment may move to.
+// Statuses without an entry are terminal.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending: {
+		PaymentStatusAuthorized,
+		PaymentStatusApproved,
+		PaymentStatusDeclined,
+		PaymentStatusCancelled,
+		PaymentStatusExpired,
+	},
+	// Capturing approves the payment; cancelling it, or voiding it once the capture window is over,
+	// releases the hold.
+	PaymentStatusAuthorized: {
+		PaymentStatusApproved,
+		PaymentStatusCancelled,
+	},
+	PaymentStatusApproved: {
+		PaymentStatusRefunded,
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusDisputed,
+	},
+	PaymentStatusPartiallyRefunded: {
+		PaymentStatusRefunded,
+		PaymentStatusDisputed,
+	},
+	// A won dispute restores the status the payment had when it was opened; a lost one refunds it.
+	PaymentStatusDisputed: {
+		PaymentStatusApproved,
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusRefunded,
+	},
+}
+
+var ErrInvalidStatusTransition = errors.New("invalid payment status transition")
+
+type InvalidStatusTransitionError struct {
+	From PaymentStatus
+	To   PaymentStatus
+}
+
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: from %q to %q", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+func (e *InvalidStatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+// PaymentStatuses lists every known payment status.
+func PaymentStatuses() []PaymentStatus {
+	return []PaymentStatus{
+		PaymentStatusPending,
+		PaymentStatusAuthorized,
+		PaymentStatusApproved,
+		PaymentStatusDeclined,
+		PaymentStatusCancelled,
+		PaymentStatusRefunded,
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusExpired,
+		PaymentStatusDisputed,
+		PaymentStatusFailed,
+	}
+}
+
+func ParsePaymentStatus(status string) (PaymentStatus, error) {
+	parsed := PaymentStatus(strings.ToLower(strings.TrimSpace(status)))
+	if !parsed.IsValid() {
+		return "", fmt.Errorf("unknown payment status %q", status)
+	}
+	return parsed, nil
+}
+
+func (s PaymentStatus) IsValid() bool {
+	return slices.Contains(PaymentStatuses(), s)
+}
+
+func (s PaymentStatus) IsTerminal() bool {
+	return len(paymentStatusTransitions[s]) == 0
+}
+
+// IsRefundable reports whether money can still be given back for a payment in this status.
+func (s PaymentStatus) IsRefundable() bool {
+	return s == PaymentStatusApproved || s == PaymentStatusPartiallyRefunded
+}
+
+// IsPaidAfterClosing reports whether a provider reporting the payment as reported collected money for a
+// payment that was already cancelled or expired here, which has to be given back.
+func (s PaymentStatus) IsPaidAfterClosing(reported PaymentStatus) bool {
+	return (s == PaymentStatusCancelled || s == PaymentStatusExpired) &&
+		(reported == PaymentStatusAuthorized || reported == PaymentStatusApproved)
+}
+
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
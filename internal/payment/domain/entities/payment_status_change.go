@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+type PaymentStatusChangeSource string
+
+const (
+	PaymentStatusChangeSourceWebhook        PaymentStatusChangeSource = "webhook"
+	PaymentStatusChangeSourceAPI            PaymentStatusChangeSource = "api"
+	PaymentStatusChangeSourceReconciliation PaymentStatusChangeSource = "reconciliation"
+	PaymentStatusChangeSourceAdmin          PaymentStatusChangeSource = "admin"
+)
+
+// PaymentStatusChange is an audit record of a payment moving between statuses. NotificationId holds
+// the provider notification that triggered the change, when it came from a webhook.
+type PaymentStatusChange struct {
+	ID             uint                      `gorm:"primaryKey"`
+	PaymentId      uint                      `gorm:"index;not null"`
+	OrderId        uint                      `gorm:"index;not null"`
+	FromStatus     PaymentStatus             `gorm:"not null"`
+	ToStatus       PaymentStatus             `gorm:"not null"`
+	Source         PaymentStatusChangeSource `gorm:"not null"`
+	NotificationId string
+	CreatedAt      time.Time `gorm:"not null"`
+}
+
+func (PaymentStatusChange) TableName() string {
+	return "payment_status_history"
+}
+
+// NewPaymentStatusChange records the move of the payment from the given status to its current one.
+func NewPaymentStatusChange(payment *Payment, from PaymentStatus, source PaymentStatusChangeSource, notificationId string) *PaymentStatusChange {
+	return &PaymentStatusChange{
+		PaymentId:      payment.ID,
+		OrderId:        payment.OrderId,
+		FromStatus:     from,
+		ToStatus:       payment.Status,
+		Source:         source,
+		NotificationId: notificationId,
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,180 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayment_TransitionTo_FromPendingToApproved_ShouldSucceed(t *testing.T) {
+	// GIVEN a pending payment
+	payment := &entities.Payment{Status: entities.PaymentStatusPending}
+
+	// WHEN approving it
+	err := payment.TransitionTo(entities.PaymentStatusApproved)
+
+	// THEN the status should change
+	assert.NoError(t, err)
+	assert.Equal(t, entities.PaymentStatusApproved, payment.Status)
+}
+
+func TestPayment_TransitionTo_FromApprovedToDeclined_ShouldFail(t *testing.T) {
+	// GIVEN an approved payment
+	payment := &entities.Payment{Status: entities.PaymentStatusApproved}
+
+	// WHEN a late declined status arrives
+	err := payment.TransitionTo(entities.PaymentStatusDeclined)
+
+	// THEN the transition should be rejected
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusTransition)
+	assert.Equal(t, entities.PaymentStatusApproved, payment.Status)
+
+	var transitionErr *entities.InvalidStatusTransitionError
+	assert.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, entities.PaymentStatusApproved, transitionErr.From)
+	assert.Equal(t, entities.PaymentStatusDeclined, transitionErr.To)
+}
+
+func TestPayment_TransitionTo_SameStatus_ShouldBeNoOp(t *testing.T) {
+	// GIVEN an approved payment
+	payment := &entities.Payment{Status: entities.PaymentStatusApproved}
+
+	// WHEN the same status is applied again
+	err := payment.TransitionTo(entities.PaymentStatusApproved)
+
+	// THEN nothing should happen
+	assert.NoError(t, err)
+	assert.Equal(t, entities.PaymentStatusApproved, payment.Status)
+}
+
+func TestPayment_TransitionTo_FromTerminalStatus_ShouldFail(t *testing.T) {
+	// GIVEN a declined payment
+	payment := &entities.Payment{Status: entities.PaymentStatusDeclined}
+
+	// WHEN trying to approve it
+	err := payment.TransitionTo(entities.PaymentStatusApproved)
+
+	// THEN the transition should be rejected
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusTransition)
+	assert.True(t, entities.PaymentStatusDeclined.IsTerminal())
+}
+
+func TestParsePaymentStatus(t *testing.T) {
+	// GIVEN legacy capitalized and typed statuses
+	cases := map[string]entities.PaymentStatus{
+		"Approved":  entities.PaymentStatusApproved,
+		"declined":  entities.PaymentStatusDeclined,
+		" pending ": entities.PaymentStatusPending,
+	}
+
+	for input, expected := range cases {
+		// WHEN parsing
+		status, err := entities.ParsePaymentStatus(input)
+
+		// THEN the typed status should be returned
+		assert.NoError(t, err)
+		assert.Equal(t, expected, status)
+	}
+
+	_, err := entities.ParsePaymentStatus("paid")
+	assert.Error(t, err)
+}
+
+func TestNewPayment_ShouldBePendingAndActive(t *testing.T) {
+	// WHEN creating a payment
+	payment := entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode")
+
+	// THEN it should be the active pending payment of the order
+	assert.Equal(t, entities.PaymentStatusPending, payment.Status)
+	assert.True(t, payment.Active)
+}
+
+func TestNewFailedPayment_ShouldBeTerminalAndInactive(t *testing.T) {
+	// WHEN recording a payment the provider rejected
+	payment := entities.NewFailedPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode", "gateway timeout")
+
+	// THEN it should be terminal and never active
+	assert.Equal(t, entities.PaymentStatusFailed, payment.Status)
+	assert.False(t, payment.Active)
+	assert.True(t, payment.Status.IsTerminal())
+	assert.Equal(t, "gateway timeout", payment.FailureReason)
+}
+
+func TestPayment_IsExpired(t *testing.T) {
+	// GIVEN a pending payment whose QR code expires now
+	now := time.Now()
+	payment := entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode")
+	payment.ExpiresAt = &now
+
+	// THEN it should be expired from its expiration date on, and only while pending
+	assert.False(t, payment.IsExpired(now.Add(-time.Second)))
+	assert.True(t, payment.IsExpired(now))
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusApproved))
+	assert.False(t, payment.IsExpired(now))
+}
+
+func TestPayment_Revenue_ShouldLeaveTipOut(t *testing.T) {
+	// GIVEN a payment of 100.00 in items, a 10.00 service fee and a 5.50 tip
+	payment := entities.NewPayment(1, money.New(money.MustParse("115.50"), money.BRL), entities.PaymentTypeQRCode)
+	payment.ServiceFeeAmount = money.MustParse("10.00")
+	payment.TipAmount = money.MustParse("5.50")
+
+	// THEN the tip should be charged but not count as revenue
+	assert.Equal(t, money.MustParse("110.00"), payment.Revenue())
+
+	// WHEN only part of it is captured
+	payment.CapturedTotal = money.MustParse("105.50")
+
+	// THEN revenue should follow what was collected
+	assert.Equal(t, money.MustParse("100.00"), payment.Revenue())
+
+	// WHEN part of it is refunded
+	payment.RefundedTotal = money.MustParse("30.00")
+
+	// THEN revenue should leave the refund out
+	assert.Equal(t, money.MustParse("70.00"), payment.Revenue())
+}
+
+func TestPayment_Revenue_WithCaptureBelowTip_ShouldBeZero(t *testing.T) {
+	// GIVEN a payment with a 5.50 tip of which only 3.00 was captured
+	payment := entities.NewPayment(1, money.New(money.MustParse("115.50"), money.BRL), entities.PaymentTypeCard)
+	payment.TipAmount = money.MustParse("5.50")
+	payment.CapturedTotal = money.MustParse("3.00")
+
+	// THEN revenue should not be negative
+	assert.Zero(t, payment.Revenue())
+}
+
+func TestPayment_TransitionTo_TerminalStatus_ShouldDeactivate(t *testing.T) {
+	// GIVEN an active payment
+	payment := entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode")
+
+	// WHEN it is approved, it should stay active
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusApproved))
+	assert.True(t, payment.Active)
+
+	// WHEN it is refunded, it should no longer be active
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusRefunded))
+	assert.False(t, payment.Active)
+}
+
+func TestNewPaymentStatusChange_ShouldRecordTransition(t *testing.T) {
+	// GIVEN a payment that was just approved
+	payment := &entities.Payment{ID: 2, OrderId: 1, Status: entities.PaymentStatusPending}
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusApproved))
+
+	// WHEN recording the change
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "987")
+
+	// THEN it should link the payment and both statuses
+	assert.Equal(t, uint(2), change.PaymentId)
+	assert.Equal(t, uint(1), change.OrderId)
+	assert.Equal(t, entities.PaymentStatusPending, change.FromStatus)
+	assert.Equal(t, entities.PaymentStatusApproved, change.ToStatus)
+	assert.Equal(t, entities.PaymentStatusChangeSourceWebhook, change.Source)
+	assert.Equal(t, "987", change.NotificationId)
+	assert.False(t, change.CreatedAt.IsZero())
+}
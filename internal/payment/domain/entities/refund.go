@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending  RefundStatus = "pending"
+	RefundStatusApproved RefundStatus = "approved"
+	RefundStatusRejected RefundStatus = "rejected"
+)
+
+var (
+	ErrRefundNotAllowed      = errors.New("payment cannot be refunded")
+	ErrRefundExceedsCaptured = errors.New("refund exceeds the captured amount")
+)
+
+// RefundExceedsCapturedError is returned when a refund would give back more than what is left of
+// the captured amount.
+type RefundExceedsCapturedError struct {
+	Requested  money.Amount
+	Refundable money.Amount
+}
+
+func (e *RefundExceedsCapturedError) Error() string {
+	return fmt.Sprintf("%s: requested %s, refundable %s", ErrRefundExceedsCaptured, e.Requested, e.Refundable)
+}
+
+func (e *RefundExceedsCapturedError) Is(target error) bool {
+	return target == ErrRefundExceedsCaptured
+}
+
+// Refund gives back all or part of an approved payment. Rejected refunds are kept for auditing but
+// do not count towards the refunded amount.
+type Refund struct {
+	ID               uint      `gorm:"primaryKey"`
+	CreatedAt        time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt        time.Time
+	PaymentId        uint           `gorm:"index;not null"`
+	OrderId          uint           `gorm:"index;not null"`
+	Amount           money.Amount   `gorm:"type:numeric(12,2);not null"`
+	Currency         money.Currency `gorm:"size:3;not null;default:BRL"`
+	Status           RefundStatus   `gorm:"not null"`
+	Reason           string
+	ProviderRefundId string `gorm:"index"`
+	FailureReason    string
+}
+
+func (Refund) TableName() string {
+	return "refunds"
+}
+
+func NewRefund(payment *Payment, amount money.Amount, reason string) *Refund {
+	return &Refund{
+		PaymentId: payment.ID,
+		OrderId:   payment.OrderId,
+		Amount:    amount,
+		Currency:  payment.Currency,
+		Status:    RefundStatusPending,
+		Reason:    reason,
+	}
+}
+
+// IdempotencyKey is sent with every attempt to carry out the refund, so that the provider refunds it once.
+func (r *Refund) IdempotencyKey() string {
+	return fmt.Sprintf("refund-%d", r.ID)
+}
+
+// IsUnsettled reports whether the refund is pending without the provider having answered for it, e.g.
+// after a timeout: the provider may or may not have refunded it.
+func (r *Refund) IsUnsettled() bool {
+	return r.Status == RefundStatusPending && r.ProviderRefundId == ""
+}
+
+// UnsettledRefund returns the refund still waiting for an answer of the provider, if any.
+func UnsettledRefund(refunds []*Refund) *Refund {
+	for _, refund := range refunds {
+		if refund.IsUnsettled() {
+			return refund
+		}
+	}
+	return nil
+}
+
+// CountsTowardsRefunded reports whether the refund takes part of the captured amount.
+func (r *Refund) CountsTowardsRefunded() bool {
+	return r.Status != RefundStatusRejected
+}
+
+func (r *Refund) Reject(reason string) {
+	r.Status = RefundStatusRejected
+	r.FailureReason = reason
+}
+
+// RefundedAmount sums the refunds that count towards the refunded amount.
+func RefundedAmount(refunds []*Refund) money.Amount {
+	var total money.Amount
+	for _, refund := range refunds {
+		if refund.CountsTowardsRefunded() {
+			total += refund.Amount
+		}
+	}
+	return total
+}
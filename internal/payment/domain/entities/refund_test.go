@@ -0,0 +1,49 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefundedAmount_ShouldIgnoreRejectedRefunds(t *testing.T) {
+	// GIVEN approved, pending and rejected refunds
+	rejected := &entities.Refund{Amount: money.MustParse("30.00"), Status: entities.RefundStatusPending}
+	rejected.Reject("provider error")
+	refunds := []*entities.Refund{
+		{Amount: money.MustParse("10.00"), Status: entities.RefundStatusApproved},
+		{Amount: money.MustParse("5.50"), Status: entities.RefundStatusPending},
+		rejected,
+	}
+
+	// WHEN summing the refunded amount
+	total := entities.RefundedAmount(refunds)
+
+	// THEN only approved and pending refunds should count
+	assert.Equal(t, money.MustParse("15.50"), total)
+}
+
+func TestPayment_TransitionTo_PartiallyRefundedThenRefunded_ShouldSucceed(t *testing.T) {
+	// GIVEN an approved payment
+	payment := &entities.Payment{Status: entities.PaymentStatusApproved}
+
+	// WHEN refunding it in two steps
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusPartiallyRefunded))
+	assert.True(t, payment.Status.IsRefundable())
+	err := payment.TransitionTo(entities.PaymentStatusRefunded)
+
+	// THEN the payment should end fully refunded and no longer refundable
+	assert.NoError(t, err)
+	assert.False(t, payment.Status.IsRefundable())
+}
+
+func TestRefundExceedsCapturedError_ShouldMatchSentinel(t *testing.T) {
+	// GIVEN a refund above the refundable amount
+	err := &entities.RefundExceedsCapturedError{Requested: money.MustParse("60.00"), Refundable: money.MustParse("50.00")}
+
+	// THEN it should match the sentinel and describe the amounts
+	assert.ErrorIs(t, err, entities.ErrRefundExceedsCaptured)
+	assert.EqualError(t, err, "refund exceeds the captured amount: requested 60.00, refundable 50.00")
+}
@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+// SplitPayment is an order paid with several payments, its legs. Each leg is charged through its own
+// provider and moves through its own statuses; together they add up to the order total.
+type SplitPayment struct {
+	OrderId uint
+	SplitId string
+	// Legs holds the current attempt of each leg, in leg order.
+	Legs []*Payment
+}
+
+// NewSplitId returns a random id of 32 hex digits shared by the legs of a split payment.
+func NewSplitId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate split id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewSplitPayment builds the split payment from every attempt of its legs, oldest first. The current
+// attempt of a leg is its active one, otherwise its latest one.
+func NewSplitPayment(attempts []*Payment) *SplitPayment {
+	split := &SplitPayment{}
+	for _, attempt := range attempts {
+		if !attempt.IsSplitLeg() {
+			continue
+		}
+		split.OrderId = attempt.OrderId
+		split.SplitId = attempt.SplitId
+
+		for len(split.Legs) < attempt.Leg {
+			split.Legs = append(split.Legs, nil)
+		}
+		current := split.Legs[attempt.Leg-1]
+		if current == nil || attempt.Active || !current.Active {
+			split.Legs[attempt.Leg-1] = attempt
+		}
+	}
+	split.Legs = slices.DeleteFunc(split.Legs, func(leg *Payment) bool { return leg == nil })
+	return split
+}
+
+// Leg returns the current attempt of the leg with the given number.
+func (s *SplitPayment) Leg(leg int) (*Payment, error) {
+	for _, payment := range s.Legs {
+		if payment.Leg == leg {
+			return payment, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: order %d has no leg %d", ErrPaymentNotFound, s.OrderId, leg)
+}
+
+// Total is what the legs charge together, the order total.
+func (s *SplitPayment) Total() money.Amount {
+	total := money.Amount(0)
+	for _, leg := range s.Legs {
+		total += leg.Total
+	}
+	return total
+}
+
+// PaidAmount is what was collected by the legs that are paid.
+func (s *SplitPayment) PaidAmount() money.Amount {
+	paid := money.Amount(0)
+	for _, leg := range s.Legs {
+		if leg.Status.IsRefundable() {
+			paid += leg.CapturedAmount()
+		}
+	}
+	return paid
+}
+
+// IsFullyPaid reports whether every leg was approved and collected its whole amount.
+func (s *SplitPayment) IsFullyPaid() bool {
+	for _, leg := range s.Legs {
+		if leg.Status != PaymentStatusApproved {
+			return false
+		}
+	}
+	return len(s.Legs) > 0 && s.PaidAmount() == s.Total()
+}
+
+// Status sums the statuses of the legs up: the status they share, pending while a leg still waits to be
+// paid, partially refunded when some paid leg was refunded, and otherwise the status of the first leg
+// that kept the order from being paid.
+func (s *SplitPayment) Status() PaymentStatus {
+	if len(s.Legs) == 0 {
+		return ""
+	}
+
+	same, waiting, paid := true, false, true
+	for _, leg := range s.Legs {
+		same = same && leg.Status == s.Legs[0].Status
+		waiting = waiting || leg.Status == PaymentStatusPending || leg.Status == PaymentStatusAuthorized
+		paid = paid && (leg.Status.IsRefundable() || leg.Status == PaymentStatusRefunded)
+	}
+
+	switch {
+	case same:
+		return s.Legs[0].Status
+	case waiting:
+		return PaymentStatusPending
+	case paid:
+		return PaymentStatusPartiallyRefunded
+	}
+	for _, leg := range s.Legs {
+		if !leg.Status.IsRefundable() {
+			return leg.Status
+		}
+	}
+	return PaymentStatusPending
+}
+
+// MatchesAmounts reports whether the legs charge the given amounts, in order.
+func (s *SplitPayment) MatchesAmounts(amounts []money.Amount) bool {
+	if len(s.Legs) != len(amounts) {
+		return false
+	}
+	for i, leg := range s.Legs {
+		if leg.Total != amounts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Notifies reports whether an order status update caused by one of the legs applies to the whole
+// order: it is preparing once every leg is paid, and cancelled once no leg is waiting or paid.
+func (s *SplitPayment) Notifies(orderStatus int) bool {
+	switch orderStatus {
+	case OrderStatusPreparing:
+		return s.IsFullyPaid()
+	case OrderStatusCancelled:
+		for _, leg := range s.Legs {
+			if leg.Active {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLeg(id uint, leg int, total string, status entities.PaymentStatus) *entities.Payment {
+	return &entities.Payment{
+		ID:      id,
+		OrderId: 1,
+		SplitId: "split1",
+		Leg:     leg,
+		Total:   money.MustParse(total),
+		Status:  status,
+		Active:  !status.IsTerminal(),
+	}
+}
+
+func TestNewSplitPayment_ShouldPickCurrentAttemptOfEachLeg(t *testing.T) {
+	// GIVEN a declined first leg that was issued again, and a second leg
+	declined := newLeg(1, 1, "20.00", entities.PaymentStatusDeclined)
+	second := newLeg(2, 2, "30.00", entities.PaymentStatusPending)
+	reissued := newLeg(3, 1, "20.00", entities.PaymentStatusPending)
+
+	// WHEN building the split payment
+	split := entities.NewSplitPayment([]*entities.Payment{declined, second, reissued})
+
+	// THEN each leg should be its latest attempt, in leg order
+	assert.Equal(t, []*entities.Payment{reissued, second}, split.Legs)
+	assert.Equal(t, money.MustParse("50.00"), split.Total())
+	leg, err := split.Leg(2)
+	assert.NoError(t, err)
+	assert.Equal(t, second, leg)
+	_, err = split.Leg(3)
+	assert.ErrorIs(t, err, entities.ErrPaymentNotFound)
+}
+
+func TestSplitPayment_Status_ShouldSumLegsUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		legs     []entities.PaymentStatus
+		expected entities.PaymentStatus
+	}{
+		{"all approved", []entities.PaymentStatus{entities.PaymentStatusApproved, entities.PaymentStatusApproved}, entities.PaymentStatusApproved},
+		{"one still waiting", []entities.PaymentStatus{entities.PaymentStatusApproved, entities.PaymentStatusPending}, entities.PaymentStatusPending},
+		{"one refunded", []entities.PaymentStatus{entities.PaymentStatusApproved, entities.PaymentStatusRefunded}, entities.PaymentStatusPartiallyRefunded},
+		{"one declined", []entities.PaymentStatus{entities.PaymentStatusApproved, entities.PaymentStatusDeclined}, entities.PaymentStatusDeclined},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN a split payment with legs in the given statuses
+			split := &entities.SplitPayment{Legs: []*entities.Payment{
+				newLeg(1, 1, "20.00", tt.legs[0]),
+				newLeg(2, 2, "30.00", tt.legs[1]),
+			}}
+
+			// WHEN summing its status up, THEN it should reflect the whole order
+			assert.Equal(t, tt.expected, split.Status())
+		})
+	}
+}
+
+func TestSplitPayment_Notifies_ShouldWaitForTheWholeOrder(t *testing.T) {
+	// GIVEN a split payment with one leg approved and the other pending
+	first := newLeg(1, 1, "20.00", entities.PaymentStatusApproved)
+	second := newLeg(2, 2, "30.00", entities.PaymentStatusPending)
+	split := &entities.SplitPayment{Legs: []*entities.Payment{first, second}}
+
+	// THEN the order should neither be preparing nor cancelled
+	assert.False(t, split.Notifies(entities.OrderStatusPreparing))
+	assert.False(t, split.Notifies(entities.OrderStatusCancelled))
+	assert.Equal(t, money.MustParse("20.00"), split.PaidAmount())
+
+	// WHEN the second leg is approved, THEN the order should be preparing
+	assert.NoError(t, second.TransitionTo(entities.PaymentStatusApproved))
+	assert.True(t, split.Notifies(entities.OrderStatusPreparing))
+	assert.True(t, split.IsFullyPaid())
+}
+
+func TestSplitPayment_Notifies_WithPartialCapture_ShouldNotBeFullyPaid(t *testing.T) {
+	// GIVEN approved legs, one of them captured in part
+	first := newLeg(1, 1, "20.00", entities.PaymentStatusApproved)
+	second := newLeg(2, 2, "30.00", entities.PaymentStatusApproved)
+	second.CapturedTotal = money.MustParse("25.00")
+	split := &entities.SplitPayment{Legs: []*entities.Payment{first, second}}
+
+	// WHEN checking whether the order is paid, THEN it should not be
+	assert.False(t, split.IsFullyPaid())
+	assert.Equal(t, money.MustParse("45.00"), split.PaidAmount())
+}
+
+func TestSplitPayment_Notifies_WithEveryLegClosedUnpaid_ShouldCancelOrder(t *testing.T) {
+	// GIVEN a split payment whose legs all expired or were declined
+	split := &entities.SplitPayment{Legs: []*entities.Payment{
+		newLeg(1, 1, "20.00", entities.PaymentStatusExpired),
+		newLeg(2, 2, "30.00", entities.PaymentStatusDeclined),
+	}}
+
+	// WHEN checking a cancellation, THEN it should apply to the order
+	assert.True(t, split.Notifies(entities.OrderStatusCancelled))
+}
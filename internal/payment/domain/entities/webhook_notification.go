@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+type WebhookOutcome string
+
+const (
+	WebhookOutcomeReceived  WebhookOutcome = "received"
+	WebhookOutcomeProcessed WebhookOutcome = "processed"
+	// WebhookOutcomeOpen is a processed notification about a dispute that is still open. Mercado Pago
+	// notifies its resolution under the same id, topic and resource, so it is processed again.
+	WebhookOutcomeOpen    WebhookOutcome = "open"
+	WebhookOutcomeIgnored WebhookOutcome = "ignored"
+	WebhookOutcomeFailed  WebhookOutcome = "failed"
+)
+
+// WebhookNotification is the inbox record of a provider notification. ProviderId, Topic and Resource
+// identify a delivery, so retries of the same notification land on the same row.
+type WebhookNotification struct {
+	ID          uint      `gorm:"primaryKey"`
+	ProviderId  string    `gorm:"uniqueIndex:idx_webhook_notifications_delivery;not null"`
+	Topic       string    `gorm:"uniqueIndex:idx_webhook_notifications_delivery;not null"`
+	Resource    string    `gorm:"uniqueIndex:idx_webhook_notifications_delivery;not null"`
+	ReceivedAt  time.Time `gorm:"index;not null"`
+	ProcessedAt *time.Time
+	Deliveries  uint           `gorm:"not null;default:1"`
+	Outcome     WebhookOutcome `gorm:"index;not null"`
+	Error       string
+}
+
+func (WebhookNotification) TableName() string {
+	return "webhook_notifications"
+}
+
+// IsProcessed reports whether the notification already produced its side effects. Ignored and
+// failed notifications are processed again on redelivery, since the provider state may have moved on.
+func (n *WebhookNotification) IsProcessed() bool {
+	return n.Outcome == WebhookOutcomeProcessed
+}
+
+func (n *WebhookNotification) Complete(outcome WebhookOutcome, err error) {
+	now := time.Now()
+	n.ProcessedAt = &now
+	n.Outcome = outcome
+	n.Error = ""
+	if err != nil {
+		n.Outcome = WebhookOutcomeFailed
+		n.Error = err.Error()
+	}
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+
+type DisputeFilter struct {
+	OrderId   uint
+	PaymentId uint
+	Status    entities.DisputeStatus
+	Limit     int
+}
+
+type DisputeRepository interface {
+	// FindDispute returns nil when the provider dispute is not known yet.
+	FindDispute(provider string, disputeType entities.DisputeType, providerDisputeId string) (*entities.Dispute, error)
+	// SaveDispute stores the dispute and, when not nil, its payment with the status change in a
+	// single transaction. The change is checked against the stored status of the payment like in
+	// PaymentRepository.UpdatePaymentStatus.
+	SaveDispute(dispute *entities.Dispute, payment *entities.Payment, change *entities.PaymentStatusChange) error
+	// ListDisputes returns the matching disputes, most recently opened first.
+	ListDisputes(filter DisputeFilter) ([]*entities.Dispute, error)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+
+type IdempotencyKeyRepository interface {
+	AddIdempotencyKey(idempotencyKey *entities.IdempotencyKey) (*entities.IdempotencyKey, error)
+	// FindIdempotencyKey returns nil without error when the key was never used.
+	FindIdempotencyKey(key string) (*entities.IdempotencyKey, error)
+	UpdateIdempotencyKey(idempotencyKey *entities.IdempotencyKey) error
+	DeleteIdempotencyKey(idempotencyKey *entities.IdempotencyKey) error
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+)
+
+type OutboxMessageFilter struct {
+	OrderId uint
+	Status  entities.OutboxStatus
+	Limit   int
+}
+
+type OutboxRepository interface {
+	GetOutboxMessage(id uint) (*entities.OutboxMessage, error)
+	ListOutboxMessages(filter OutboxMessageFilter) ([]*entities.OutboxMessage, error)
+	// ClaimDueOutboxMessages claims up to limit pending messages whose next attempt is due, oldest first,
+	// and at most one per order: the oldest message of the order that is neither delivered nor failed.
+	// A claimed message is left out of every other claim for a while, so that replicas dispatching at
+	// the same time never deliver the same message, and the updates of an order are delivered in order.
+	ClaimDueOutboxMessages(now time.Time, limit int) ([]*entities.OutboxMessage, error)
+	UpdateOutboxMessage(message *entities.OutboxMessage) error
+}
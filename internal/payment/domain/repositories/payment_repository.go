@@ -1,9 +1,60 @@
 package repositories
 
-import "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+)
 
 type PaymentRepository interface {
 	AddPayment(payment *entities.Payment) (*entities.Payment, error)
+	GetPaymentById(id uint) (*entities.Payment, error)
+	// GetPaymentByOrderId returns the active payment of the order, or its latest one when none is active.
 	GetPaymentByOrderId(orderId uint) (*entities.Payment, error)
+	// GetPaymentByOrderLeg returns the effective payment of the given leg of an order paid in split legs,
+	// or like GetPaymentByOrderId the payment of an order paid at once when leg is 0. An order paid in
+	// split legs without a leg returns ErrPaymentLegRequired.
+	GetPaymentByOrderLeg(orderId uint, leg int) (*entities.Payment, error)
+	// FindActivePaymentByOrderId returns nil without error when the order has no active payment.
+	FindActivePaymentByOrderId(orderId uint) (*entities.Payment, error)
+	// FindPaymentByProviderPaymentId returns nil without error when no payment has the provider payment id.
+	FindPaymentByProviderPaymentId(provider string, providerPaymentId string) (*entities.Payment, error)
+	// FindPaymentByExternalReference returns the active payment with the external reference, or its
+	// latest one when none is active, and nil without error when there is none.
+	FindPaymentByExternalReference(externalReference string) (*entities.Payment, error)
+	// ClaimExpiredPayments claims up to limit active pending payments whose QR code expired at or before
+	// now. A claimed payment is left out of every other claim for a while, so that replicas sweeping at
+	// the same time never pick the same payment.
+	ClaimExpiredPayments(now time.Time, limit int) ([]*entities.Payment, error)
+	// ClaimStaleAuthorizedPayments claims, like ClaimExpiredPayments, up to limit active authorized
+	// payments that were authorized at or before authorizedBefore and never captured, oldest first.
+	ClaimStaleAuthorizedPayments(now, authorizedBefore time.Time, limit int) ([]*entities.Payment, error)
+	// ListPaymentsByOrderId returns every payment attempt of the order, oldest first.
+	ListPaymentsByOrderId(orderId uint) ([]*entities.Payment, error)
+	// ListSplitLegs returns every attempt of the legs of the latest split payment of the order, oldest
+	// first, and none when the order was never split.
+	ListSplitLegs(orderId uint) ([]*entities.Payment, error)
+	// AddSplitPayment saves the replaced payments with their status changes and adds the new legs in a
+	// single transaction.
+	AddSplitPayment(replaced []*entities.Payment, changes []*entities.PaymentStatusChange, legs []*entities.Payment) error
+	// UpdatePayment saves the payment, returning an InvalidStatusTransitionError when its stored status
+	// was moved meanwhile to one it cannot move from.
 	UpdatePayment(payment *entities.Payment) error
+	// ReplacePayment saves the replaced payment with its status change and adds the new payment in a
+	// single transaction.
+	ReplacePayment(replaced *entities.Payment, change *entities.PaymentStatusChange, payment *entities.Payment) (*entities.Payment, error)
+	// UpdatePaymentStatus saves the payment, its status change record and, when not nil, the outbox
+	// message in a single transaction. The message of a split payment leg is only added when it applies
+	// to the whole order (see SplitPayment.Notifies); the legs are locked meanwhile so that concurrent
+	// updates of the legs agree on it. The stored row is locked and the change re-checked against its
+	// status: a change a concurrent writer already made is skipped, and one no longer valid returns an
+	// InvalidStatusTransitionError. ReplacePayment and AddSplitPayment check their replaced payments alike.
+	UpdatePaymentStatus(payment *entities.Payment, change *entities.PaymentStatusChange, message *entities.OutboxMessage) error
+	// CancelPayment saves the cancelled payment with its status change after cancelCharge cancels it on the
+	// provider, holding the lock on the stored row meanwhile. Like UpdatePaymentStatus the change is checked
+	// against the stored status first, and cancelCharge is not called when it no longer applies; its error
+	// leaves the payment unchanged.
+	CancelPayment(payment *entities.Payment, change *entities.PaymentStatusChange, cancelCharge func() error) error
+	// ListPaymentStatusHistory returns the status changes of every payment of the order, oldest first.
+	ListPaymentStatusHistory(orderId uint) ([]*entities.PaymentStatusChange, error)
 }
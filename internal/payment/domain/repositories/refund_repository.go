@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+type RefundRepository interface {
+	// AddRefund stores the refund unless the refunds of its payment would then exceed limit, in which
+	// case it returns a RefundExceedsCapturedError.
+	AddRefund(refund *entities.Refund, limit money.Amount) (*entities.Refund, error)
+	// UpdateRefund saves the refund. Unless it was rejected, the payment is locked and moved in the same
+	// transaction to refunded or partially refunded, from the stored refunds the provider accepted, with
+	// its status change.
+	UpdateRefund(refund *entities.Refund) error
+	// ListRefundsByPaymentId returns the refunds of the payment, oldest first.
+	ListRefundsByPaymentId(paymentId uint) ([]*entities.Refund, error)
+}
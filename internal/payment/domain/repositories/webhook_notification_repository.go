@@ -0,0 +1,19 @@
+package repositories
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+
+type WebhookNotificationFilter struct {
+	ProviderId string
+	Topic      string
+	Resource   string
+	Outcome    entities.WebhookOutcome
+	Limit      int
+}
+
+type WebhookNotificationRepository interface {
+	AddWebhookNotification(notification *entities.WebhookNotification) (*entities.WebhookNotification, error)
+	// FindWebhookNotification returns nil without error when the delivery has not been seen yet.
+	FindWebhookNotification(providerId string, topic string, resource string) (*entities.WebhookNotification, error)
+	UpdateWebhookNotification(notification *entities.WebhookNotification) error
+	ListWebhookNotifications(filter WebhookNotificationFilter) ([]*entities.WebhookNotification, error)
+}
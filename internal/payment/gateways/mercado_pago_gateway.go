@@ -4,8 +4,19 @@ import (
 	"context"
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 )
 
 type MercadoPagoGateway interface {
 	GenerateQRCode(ctx context.Context, request dto.CreateQRCodeDTO) (dto.QRCodeResponseDto, error)
+	GetPayment(ctx context.Context, paymentId string) (dto.MercadoPagoPaymentResponseDto, error)
+	GetMerchantOrder(ctx context.Context, merchantOrderId string) (dto.MercadoPagoMerchantOrderResponseDto, error)
+	// GetInStoreOrder returns the QR order currently shown on the point of sale, or nil when it shows none.
+	GetInStoreOrder(ctx context.Context) (*dto.MercadoPagoInStoreOrderResponseDto, error)
+	// DeleteInStoreOrder removes the QR order currently shown on the point of sale.
+	DeleteInStoreOrder(ctx context.Context) error
+	GetChargeback(ctx context.Context, chargebackId string) (dto.MercadoPagoChargebackResponseDto, error)
+	GetClaim(ctx context.Context, claimId string) (dto.MercadoPagoClaimResponseDto, error)
+	// Refund gives back amount of the payment; the idempotency key makes retries of the same refund safe.
+	Refund(ctx context.Context, paymentId string, amount money.Amount, idempotencyKey string) (dto.MercadoPagoRefundResponseDto, error)
 }
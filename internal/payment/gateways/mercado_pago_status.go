@@ -0,0 +1,38 @@
+package gateways
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+)
+
+// MercadoPagoPaymentStatus maps the status of a Mercado Pago payment to ours. Statuses that are not
+// settled yet, like in_process or in_mediation, map to pending.
+func MercadoPagoPaymentStatus(payment dto.MercadoPagoPaymentResponseDto) entities.PaymentStatus {
+	switch payment.Status {
+	case "approved":
+		// Mercado Pago keeps partially refunded payments approved
+		if payment.StatusDetail == "partially_refunded" {
+			return entities.PaymentStatusPartiallyRefunded
+		}
+		return entities.PaymentStatusApproved
+	case "rejected":
+		return entities.PaymentStatusDeclined
+	case "cancelled":
+		return entities.PaymentStatusCancelled
+	case "refunded":
+		return entities.PaymentStatusRefunded
+	default:
+		return entities.PaymentStatusPending
+	}
+}
+
+func MercadoPagoRefundStatus(refund dto.MercadoPagoRefundResponseDto) entities.RefundStatus {
+	switch refund.Status {
+	case "approved":
+		return entities.RefundStatusApproved
+	case "rejected", "cancelled":
+		return entities.RefundStatusRejected
+	default:
+		return entities.RefundStatusPending
+	}
+}
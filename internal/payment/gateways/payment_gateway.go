@@ -0,0 +1,91 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+// ErrProviderRejected is matched by gateway errors for requests the provider answered and turned down
+// without acting on them. Any other error, such as a timeout, leaves unknown whether the provider acted.
+var ErrProviderRejected = errors.New("request rejected by the provider")
+
+// PaymentGateway is a payment provider as seen by the use cases. Each provider has an adapter
+// implementing it, registered in the PaymentGatewayRegistry under the payment types it charges.
+type PaymentGateway interface {
+	// Name is the provider name stored on the payments the gateway creates.
+	Name() string
+	CreateCharge(ctx context.Context, request ChargeRequest) (*Charge, error)
+	GetChargeStatus(ctx context.Context, providerPaymentId string) (*ChargeStatus, error)
+	// CancelCharge stops a pending payment from being paid.
+	CancelCharge(ctx context.Context, payment *entities.Payment) error
+	// RefundCharge gives back amount of the payment; the idempotency key makes retries of the same refund safe.
+	RefundCharge(ctx context.Context, payment *entities.Payment, amount money.Amount, idempotencyKey string) (*RefundResult, error)
+}
+
+// OptionalPaymentGateway is implemented by gateways that may be left unconfigured. Disabled gateways
+// are not registered, so their payment types are unsupported.
+type OptionalPaymentGateway interface {
+	Enabled() bool
+}
+
+// SingleSlotPaymentGateway is implemented by gateways that hold a single pending charge at a time, like a
+// point of sale showing one QR code: each new charge replaces the previous one. An order split in legs
+// has at most one leg charged through each of them.
+type SingleSlotPaymentGateway interface {
+	SingleSlot() bool
+}
+
+// ExpiringPaymentGateway is implemented by gateways whose provider stops a charge from being paid once
+// its ChargeRequest.ExpiresAt passes. Charges of other gateways are created without expiration, since
+// nothing would stop them from being paid after the payment expired.
+type ExpiringPaymentGateway interface {
+	ExpiresCharges() bool
+}
+
+// CapturablePaymentGateway is implemented by gateways that authorize payments first and collect them on
+// capture. Their payments become authorized instead of approved once paid.
+type CapturablePaymentGateway interface {
+	// CaptureCharge collects amount of an authorized payment and releases the rest of the hold.
+	CaptureCharge(ctx context.Context, payment *entities.Payment, amount money.Amount) error
+}
+
+type ChargeRequest struct {
+	ExternalReference string
+	Total             money.Money
+	Items             []dto.Item
+	// PaymentMethod is a card tokenized by the client, confirmed right away when set. Only used by
+	// card gateways.
+	PaymentMethod string
+	// ExpiresAt is when the charge stops being payable; nil when it does not expire. Only honored by
+	// gateways that implement ExpiringPaymentGateway.
+	ExpiresAt *time.Time
+}
+
+// Charge is the provider side of a new payment.
+type Charge struct {
+	ProviderOrderId   string
+	ProviderPaymentId string
+	QRData            string
+	// ClientSecret lets the client confirm a card payment that was not confirmed on creation.
+	ClientSecret string
+	// Txid is the id we gave a PIX charge.
+	Txid string
+}
+
+type ChargeStatus struct {
+	ProviderPaymentId string
+	ExternalReference string
+	Status            entities.PaymentStatus
+	// CapturedAmount is what the provider collected for an authorized payment; zero when not applicable.
+	CapturedAmount money.Amount
+}
+
+type RefundResult struct {
+	ProviderRefundId string
+	Status           entities.RefundStatus
+}
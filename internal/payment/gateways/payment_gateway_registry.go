@@ -0,0 +1,102 @@
+package gateways
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+)
+
+// PaymentGatewayRegistration registers a gateway under the payment type it charges.
+type PaymentGatewayRegistration struct {
+	PaymentType string
+	Gateway     PaymentGateway
+}
+
+type PaymentGatewayRegistryConfig struct {
+	// DefaultPaymentType is used for payment requests that do not name a type.
+	DefaultPaymentType string
+}
+
+func (c *PaymentGatewayRegistryConfig) Validate() error {
+	if c.DefaultPaymentType == "" {
+		return fmt.Errorf("invalid PaymentGatewayRegistryConfig: default payment type must be set")
+	}
+	return nil
+}
+
+func newPaymentGatewayRegistryConfig() *PaymentGatewayRegistryConfig {
+	defaultPaymentType := entities.PaymentTypeQRCode
+	if value := os.Getenv("PAYMENT_DEFAULT_TYPE"); value != "" {
+		defaultPaymentType = value
+	}
+	return &PaymentGatewayRegistryConfig{DefaultPaymentType: normalizePaymentType(defaultPaymentType)}
+}
+
+// PaymentGatewayRegistry selects the gateway of a payment, by payment type for new payments and by
+// provider name for existing ones.
+type PaymentGatewayRegistry struct {
+	config     *PaymentGatewayRegistryConfig
+	byType     map[string]PaymentGateway
+	byProvider map[string]PaymentGateway
+}
+
+func NewPaymentGatewayRegistry(registrations []PaymentGatewayRegistration) (*PaymentGatewayRegistry, error) {
+	return NewPaymentGatewayRegistryWithConfig(registrations, newPaymentGatewayRegistryConfig())
+}
+
+func NewPaymentGatewayRegistryWithConfig(registrations []PaymentGatewayRegistration, config *PaymentGatewayRegistryConfig) (*PaymentGatewayRegistry, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	registry := &PaymentGatewayRegistry{
+		config:     config,
+		byType:     make(map[string]PaymentGateway, len(registrations)),
+		byProvider: make(map[string]PaymentGateway, len(registrations)),
+	}
+	for _, registration := range registrations {
+		if optional, ok := registration.Gateway.(OptionalPaymentGateway); ok && !optional.Enabled() {
+			continue
+		}
+		paymentType := normalizePaymentType(registration.PaymentType)
+		if _, exists := registry.byType[paymentType]; exists {
+			return nil, fmt.Errorf("payment type %q is registered twice", paymentType)
+		}
+		registry.byType[paymentType] = registration.Gateway
+		registry.byProvider[registration.Gateway.Name()] = registration.Gateway
+	}
+
+	if _, exists := registry.byType[config.DefaultPaymentType]; !exists {
+		return nil, fmt.Errorf("default payment type %q has no gateway", config.DefaultPaymentType)
+	}
+	return registry, nil
+}
+
+// PaymentType normalizes the requested payment type, falling back to the default one.
+func (r *PaymentGatewayRegistry) PaymentType(paymentType string) string {
+	if normalized := normalizePaymentType(paymentType); normalized != "" {
+		return normalized
+	}
+	return r.config.DefaultPaymentType
+}
+
+func (r *PaymentGatewayRegistry) ForPaymentType(paymentType string) (PaymentGateway, error) {
+	gateway, exists := r.byType[r.PaymentType(paymentType)]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", entities.ErrUnsupportedPaymentType, paymentType)
+	}
+	return gateway, nil
+}
+
+// ForProvider returns the gateway of the provider a payment was created on. Payments stored before
+// providers were recorded have none.
+func (r *PaymentGatewayRegistry) ForProvider(provider string) (PaymentGateway, bool) {
+	gateway, exists := r.byProvider[provider]
+	return gateway, exists
+}
+
+func normalizePaymentType(paymentType string) string {
+	return strings.ToLower(strings.TrimSpace(paymentType))
+}
@@ -0,0 +1,144 @@
+package gateways_test
+
+import (
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGateway(t *testing.T, name string) *mockGateways.MockPaymentGateway {
+	gateway := mockGateways.NewMockPaymentGateway(t)
+	gateway.EXPECT().Name().Return(name).Maybe()
+	return gateway
+}
+
+func defaultConfig() *gateways.PaymentGatewayRegistryConfig {
+	return &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode}
+}
+
+func TestPaymentGatewayRegistry_ForPaymentType(t *testing.T) {
+	qrCode := newGateway(t, entities.PaymentProviderMercadoPago)
+	card := newGateway(t, "card-provider")
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: qrCode},
+		{PaymentType: "Card", Gateway: card},
+	}, defaultConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		paymentType string
+		expected    gateways.PaymentGateway
+	}{
+		{paymentType: "qrcode", expected: qrCode},
+		{paymentType: " QRCode ", expected: qrCode},
+		{paymentType: "", expected: qrCode},
+		{paymentType: "card", expected: card},
+	}
+	for _, test := range tests {
+		gateway, err := registry.ForPaymentType(test.paymentType)
+
+		assert.NoError(t, err, test.paymentType)
+		assert.Same(t, test.expected, gateway, test.paymentType)
+	}
+}
+
+func TestPaymentGatewayRegistry_WithUnknownPaymentType_ShouldReturnError(t *testing.T) {
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: newGateway(t, entities.PaymentProviderMercadoPago)},
+	}, defaultConfig())
+	require.NoError(t, err)
+
+	gateway, err := registry.ForPaymentType("boleto")
+
+	assert.ErrorIs(t, err, entities.ErrUnsupportedPaymentType)
+	assert.ErrorContains(t, err, "boleto")
+	assert.Nil(t, gateway)
+}
+
+func TestPaymentGatewayRegistry_PaymentType(t *testing.T) {
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: newGateway(t, entities.PaymentProviderMercadoPago)},
+	}, defaultConfig())
+	require.NoError(t, err)
+
+	assert.Equal(t, "qrcode", registry.PaymentType("QRCode"))
+	assert.Equal(t, "qrcode", registry.PaymentType("  "))
+	assert.Equal(t, "card", registry.PaymentType("CARD"))
+}
+
+func TestPaymentGatewayRegistry_ForProvider(t *testing.T) {
+	qrCode := newGateway(t, entities.PaymentProviderMercadoPago)
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: qrCode},
+	}, defaultConfig())
+	require.NoError(t, err)
+
+	gateway, ok := registry.ForProvider(entities.PaymentProviderMercadoPago)
+	assert.True(t, ok)
+	assert.Same(t, qrCode, gateway)
+
+	_, ok = registry.ForProvider("")
+	assert.False(t, ok)
+}
+
+type disabledGateway struct {
+	*mockGateways.MockPaymentGateway
+}
+
+func (disabledGateway) Enabled() bool {
+	return false
+}
+
+func TestNewPaymentGatewayRegistry_ShouldSkipDisabledGateways(t *testing.T) {
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: newGateway(t, entities.PaymentProviderMercadoPago)},
+		{PaymentType: entities.PaymentTypeCard, Gateway: disabledGateway{mockGateways.NewMockPaymentGateway(t)}},
+	}, defaultConfig())
+	require.NoError(t, err)
+
+	_, err = registry.ForPaymentType(entities.PaymentTypeCard)
+	assert.ErrorIs(t, err, entities.ErrUnsupportedPaymentType)
+	_, ok := registry.ForProvider(entities.PaymentProviderStripe)
+	assert.False(t, ok)
+}
+
+func TestNewPaymentGatewayRegistry_WithDuplicatePaymentType_ShouldFail(t *testing.T) {
+	_, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: "qrcode", Gateway: newGateway(t, entities.PaymentProviderMercadoPago)},
+		{PaymentType: "QRCODE", Gateway: newGateway(t, "other")},
+	}, defaultConfig())
+
+	assert.ErrorContains(t, err, "registered twice")
+}
+
+func TestNewPaymentGatewayRegistry_WithoutDefaultGateway_ShouldFail(t *testing.T) {
+	_, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: "card", Gateway: newGateway(t, "card-provider")},
+	}, defaultConfig())
+
+	assert.ErrorContains(t, err, "default payment type")
+}
+
+func TestNewPaymentGatewayRegistry_ShouldReadDefaultPaymentTypeFromEnv(t *testing.T) {
+	t.Setenv("PAYMENT_DEFAULT_TYPE", "Card")
+	card := newGateway(t, "card-provider")
+
+	registry, err := gateways.NewPaymentGatewayRegistry([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: newGateway(t, entities.PaymentProviderMercadoPago)},
+		{PaymentType: "card", Gateway: card},
+	})
+	require.NoError(t, err)
+
+	gateway, err := registry.ForPaymentType("")
+	assert.NoError(t, err)
+	assert.Same(t, card, gateway)
+}
+
+func TestPaymentGatewayRegistryConfig_Validate(t *testing.T) {
+	assert.NoError(t, defaultConfig().Validate())
+	assert.Error(t, (&gateways.PaymentGatewayRegistryConfig{}).Validate())
+}
@@ -0,0 +1,21 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+// PixGateway is the PIX API of the Banco Central do Brasil, as exposed by the PSP that receives our
+// PIX payments. Charges are identified by the txid we choose when creating them.
+type PixGateway interface {
+	// Configured reports whether the PSP was configured; PIX payments are disabled otherwise.
+	Configured() bool
+	CreateCob(ctx context.Context, txid string, request dto.PixCreateCobDto) (dto.PixCobDto, error)
+	GetCob(ctx context.Context, txid string) (dto.PixCobDto, error)
+	// CancelCob removes an unpaid charge so that its BR Code can no longer be paid.
+	CancelCob(ctx context.Context, txid string) (dto.PixCobDto, error)
+	// CreateRefund gives back amount of the payment endToEndId; reusing the refund id makes retries safe.
+	CreateRefund(ctx context.Context, endToEndId string, refundId string, amount money.Amount) (dto.PixDevolucaoDto, error)
+}
@@ -0,0 +1,58 @@
+package gateways
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+// PixCobStatus maps the status of a PIX charge to ours. The PSP removes the charges that were not paid
+// in time, which we record as expired. Refunds are read from the payments received for the charge.
+func PixCobStatus(cob dto.PixCobDto) entities.PaymentStatus {
+	switch cob.Status {
+	case "CONCLUIDA":
+		var received, refunded money.Amount
+		for _, pix := range cob.Pix {
+			received += parsePixAmount(pix.Valor)
+			for _, devolucao := range pix.Devolucoes {
+				if devolucao.Status == "DEVOLVIDO" {
+					refunded += parsePixAmount(devolucao.Valor)
+				}
+			}
+		}
+		if refunded > 0 {
+			if refunded >= received {
+				return entities.PaymentStatusRefunded
+			}
+			return entities.PaymentStatusPartiallyRefunded
+		}
+		return entities.PaymentStatusApproved
+	case "REMOVIDA_PELO_USUARIO_RECEBEDOR":
+		return entities.PaymentStatusCancelled
+	case "REMOVIDA_PELO_PSP":
+		return entities.PaymentStatusExpired
+	default:
+		// ATIVA: waiting to be paid
+		return entities.PaymentStatusPending
+	}
+}
+
+func PixRefundStatus(devolucao dto.PixDevolucaoDto) entities.RefundStatus {
+	switch devolucao.Status {
+	case "DEVOLVIDO":
+		return entities.RefundStatusApproved
+	case "NAO_REALIZADO":
+		return entities.RefundStatusRejected
+	default:
+		return entities.RefundStatusPending
+	}
+}
+
+func parsePixAmount(value string) money.Amount {
+	amount, err := money.Parse(value)
+	if err != nil {
+		println("ERROR: Invalid PIX amount", value+":", err.Error())
+		return 0
+	}
+	return amount
+}
@@ -0,0 +1,25 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+)
+
+// StripeGateway is the Stripe-compatible payment intents API used for card payments. Amounts are in
+// minor units, as Stripe expects them.
+type StripeGateway interface {
+	// Configured reports whether credentials were provided; card payments are disabled otherwise.
+	Configured() bool
+	CreatePaymentIntent(ctx context.Context, request dto.StripeCreatePaymentIntentDto) (dto.StripePaymentIntentDto, error)
+	// GetPaymentIntent returns the intent with its latest charge expanded.
+	GetPaymentIntent(ctx context.Context, paymentIntentId string) (dto.StripePaymentIntentDto, error)
+	// ConfirmPaymentIntent charges the intent with a payment method tokenized by the client. A declined
+	// card is not an error: the intent is returned with the decline in LastPaymentError.
+	ConfirmPaymentIntent(ctx context.Context, paymentIntentId string, paymentMethod string) (dto.StripePaymentIntentDto, error)
+	// CapturePaymentIntent captures amount of an authorized intent; zero captures all of it.
+	CapturePaymentIntent(ctx context.Context, paymentIntentId string, amount int64) (dto.StripePaymentIntentDto, error)
+	CancelPaymentIntent(ctx context.Context, paymentIntentId string) (dto.StripePaymentIntentDto, error)
+	// CreateRefund gives back amount of the intent; the idempotency key makes retries of the same refund safe.
+	CreateRefund(ctx context.Context, paymentIntentId string, amount int64, idempotencyKey string) (dto.StripeRefundDto, error)
+}
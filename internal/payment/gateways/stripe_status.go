@@ -0,0 +1,45 @@
+package gateways
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+)
+
+// StripePaymentIntentStatus maps the status of a payment intent to ours. A declined card leaves the
+// intent waiting for another payment method, which we record as a declined attempt. An intent waiting
+// to be captured is authorized. Refunds are read from the expanded latest charge.
+func StripePaymentIntentStatus(intent dto.StripePaymentIntentDto) entities.PaymentStatus {
+	switch intent.Status {
+	case "succeeded":
+		if charge := intent.LatestCharge; charge != nil && charge.AmountRefunded > 0 {
+			if charge.Refunded || charge.AmountRefunded >= charge.Amount {
+				return entities.PaymentStatusRefunded
+			}
+			return entities.PaymentStatusPartiallyRefunded
+		}
+		return entities.PaymentStatusApproved
+	case "requires_payment_method":
+		if intent.LastPaymentError != nil {
+			return entities.PaymentStatusDeclined
+		}
+		return entities.PaymentStatusPending
+	case "requires_capture":
+		return entities.PaymentStatusAuthorized
+	case "canceled":
+		return entities.PaymentStatusCancelled
+	default:
+		// requires_confirmation, requires_action and processing are not settled yet
+		return entities.PaymentStatusPending
+	}
+}
+
+func StripeRefundStatus(refund dto.StripeRefundDto) entities.RefundStatus {
+	switch refund.Status {
+	case "succeeded":
+		return entities.RefundStatusApproved
+	case "failed", "canceled":
+		return entities.RefundStatusRejected
+	default:
+		return entities.RefundStatusPending
+	}
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxDisputesLimit = 500
+
+type DisputeApiController struct {
+	disputeController controller.DisputeController
+}
+
+func NewDisputeApiController(disputeController controller.DisputeController) *DisputeApiController {
+	return &DisputeApiController{disputeController: disputeController}
+}
+
+func (c *DisputeApiController) RegisterRoutes(r chi.Router) {
+	r.Get("/v1/disputes", c.ListDisputes)
+}
+
+func (c *DisputeApiController) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &dto.ListDisputesRequestDto{
+		Status: query.Get("status"),
+	}
+
+	switch entities.DisputeStatus(request.Status) {
+	case "", entities.DisputeStatusOpen, entities.DisputeStatusClosed:
+	default:
+		http.Error(w, "status must be open or closed", http.StatusBadRequest)
+		return
+	}
+
+	if rawOrderId := query.Get("order_id"); rawOrderId != "" {
+		orderId, err := strconv.ParseUint(rawOrderId, 10, 64)
+		if err != nil {
+			http.Error(w, "order_id must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		request.OrderId = uint(orderId)
+	}
+
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 || limit > maxDisputesLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxDisputesLimit), http.StatusBadRequest)
+			return
+		}
+		request.Limit = limit
+	}
+
+	disputes, err := c.disputeController.ListDisputes(request)
+	if err != nil {
+		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(disputes)
+}
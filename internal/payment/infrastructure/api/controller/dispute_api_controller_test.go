@@ -0,0 +1,87 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	mockController "github.com/abattassini/tc-fiap-payment/mocks/payment/controller"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DisputeApiControllerTestSuite struct {
+	suite.Suite
+	mockDisputeController *mockController.MockDisputeController
+	router                *chi.Mux
+}
+
+func (suite *DisputeApiControllerTestSuite) SetupTest() {
+	suite.mockDisputeController = mockController.NewMockDisputeController(suite.T())
+	suite.router = chi.NewRouter()
+	controller.NewDisputeApiController(suite.mockDisputeController).RegisterRoutes(suite.router)
+}
+
+func TestDisputeApiControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(DisputeApiControllerTestSuite))
+}
+
+func (suite *DisputeApiControllerTestSuite) Test_ListDisputes_WithFilters_ShouldReturn200() {
+	// GIVEN open disputes of an order
+	expected := []*dto.DisputeResponseDto{{ID: 1, OrderId: 1, Type: "chargeback", Status: "open"}}
+
+	suite.mockDisputeController.EXPECT().
+		ListDisputes(&dto.ListDisputesRequestDto{OrderId: 1, Status: "open", Limit: 20}).
+		Return(expected, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/disputes?order_id=1&status=open&limit=20", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing disputes
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the disputes
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+
+	var response []*dto.DisputeResponseDto
+	assert.NoError(suite.T(), json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(suite.T(), expected, response)
+}
+
+func (suite *DisputeApiControllerTestSuite) Test_ListDisputes_WithInvalidQuery_ShouldReturn400() {
+	for _, query := range []string{"status=pending", "order_id=abc", "limit=0", "limit=100000"} {
+		// GIVEN an invalid query
+		req := httptest.NewRequest(http.MethodGet, "/v1/disputes?"+query, nil)
+		rec := httptest.NewRecorder()
+
+		// WHEN listing disputes
+		suite.router.ServeHTTP(rec, req)
+
+		// THEN should return 400
+		assert.Equal(suite.T(), http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func (suite *DisputeApiControllerTestSuite) Test_ListDisputes_WithError_ShouldReturn500() {
+	// GIVEN a failing controller
+	suite.mockDisputeController.EXPECT().
+		ListDisputes(mock.Anything).
+		Return(nil, errors.New("database error")).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/disputes", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing disputes
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 500
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+}
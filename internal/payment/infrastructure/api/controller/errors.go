@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"gorm.io/gorm"
+)
+
+// httpStatusFromError maps domain errors to the HTTP status returned to the caller.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, entities.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidStatusTransition),
+		errors.Is(err, entities.ErrOutboxMessageDelivered),
+		errors.Is(err, entities.ErrActivePaymentExists),
+		errors.Is(err, entities.ErrRefundNotAllowed),
+		errors.Is(err, entities.ErrCaptureNotAllowed):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrAmountMismatch),
+		errors.Is(err, entities.ErrRefundExceedsCaptured),
+		errors.Is(err, entities.ErrCaptureExceedsAuthorized),
+		errors.Is(err, entities.ErrUnsupportedPaymentType),
+		errors.Is(err, entities.ErrInvalidSplitPayment),
+		errors.Is(err, entities.ErrPaymentLegRequired),
+		errors.Is(err, entities.ErrInvalidTip):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
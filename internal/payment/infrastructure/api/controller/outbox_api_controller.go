@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxOutboxMessagesLimit = 500
+
+type OutboxApiController struct {
+	outboxController controller.OutboxController
+}
+
+func NewOutboxApiController(outboxController controller.OutboxController) *OutboxApiController {
+	return &OutboxApiController{outboxController: outboxController}
+}
+
+func (c *OutboxApiController) RegisterRoutes(r chi.Router) {
+	prefix := "/payment/outbox"
+	r.Get(prefix, c.ListMessages)
+	r.Post(prefix+"/{id}/retry", c.RetryMessage)
+}
+
+func (c *OutboxApiController) ListMessages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &dto.ListOutboxMessagesRequestDto{
+		Status: query.Get("status"),
+	}
+
+	if rawOrderId := query.Get("order_id"); rawOrderId != "" {
+		orderId, err := strconv.ParseUint(rawOrderId, 10, 64)
+		if err != nil {
+			http.Error(w, "order_id must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		request.OrderId = uint(orderId)
+	}
+
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 || limit > maxOutboxMessagesLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxOutboxMessagesLimit), http.StatusBadRequest)
+			return
+		}
+		request.Limit = limit
+	}
+
+	messages, err := c.outboxController.ListMessages(request)
+	if err != nil {
+		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(messages)
+}
+
+func (c *OutboxApiController) RetryMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	message, err := c.outboxController.RetryMessage(uint(id))
+	if err != nil {
+		http.Error(w, "Error processing request: "+err.Error(), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(message)
+}
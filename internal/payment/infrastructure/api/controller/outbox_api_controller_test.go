@@ -0,0 +1,143 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/controller"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	mockController "github.com/abattassini/tc-fiap-payment/mocks/payment/controller"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type OutboxApiControllerTestSuite struct {
+	suite.Suite
+	mockOutboxController *mockController.MockOutboxController
+	router               *chi.Mux
+}
+
+func (suite *OutboxApiControllerTestSuite) SetupTest() {
+	suite.mockOutboxController = mockController.NewMockOutboxController(suite.T())
+	suite.router = chi.NewRouter()
+	controller.NewOutboxApiController(suite.mockOutboxController).RegisterRoutes(suite.router)
+}
+
+func TestOutboxApiControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxApiControllerTestSuite))
+}
+
+func (suite *OutboxApiControllerTestSuite) Test_ListMessages_WithFilters_ShouldReturn200() {
+	// GIVEN failed messages for an order
+	expected := []*dto.OutboxMessageResponseDto{{ID: 1, OrderId: 1, Status: "failed", Attempts: 10}}
+
+	suite.mockOutboxController.EXPECT().
+		ListMessages(&dto.ListOutboxMessagesRequestDto{OrderId: 1, Status: "failed", Limit: 20}).
+		Return(expected, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/payment/outbox?order_id=1&status=failed&limit=20", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing messages
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the messages
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+
+	var response []*dto.OutboxMessageResponseDto
+	assert.NoError(suite.T(), json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(suite.T(), expected, response)
+}
+
+func (suite *OutboxApiControllerTestSuite) Test_ListMessages_WithInvalidQuery_ShouldReturn400() {
+	for _, query := range []string{"order_id=abc", "limit=0", "limit=100000"} {
+		// GIVEN an invalid query
+		req := httptest.NewRequest(http.MethodGet, "/payment/outbox?"+query, nil)
+		rec := httptest.NewRecorder()
+
+		// WHEN listing messages
+		suite.router.ServeHTTP(rec, req)
+
+		// THEN should return 400
+		assert.Equal(suite.T(), http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func (suite *OutboxApiControllerTestSuite) Test_ListMessages_WithError_ShouldReturn500() {
+	// GIVEN a failing controller
+	suite.mockOutboxController.EXPECT().
+		ListMessages(mock.Anything).
+		Return(nil, errors.New("database error")).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/payment/outbox", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing messages
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 500
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+}
+
+func (suite *OutboxApiControllerTestSuite) Test_RetryMessage_ShouldReturn200() {
+	// GIVEN a failed message
+	suite.mockOutboxController.EXPECT().
+		RetryMessage(uint(7)).
+		Return(&dto.OutboxMessageResponseDto{ID: 7, Status: "pending"}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/payment/outbox/7/retry", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN retrying it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+}
+
+func (suite *OutboxApiControllerTestSuite) Test_RetryMessage_WithErrors_ShouldMapStatus() {
+	cases := map[error]int{
+		entities.ErrOutboxMessageDelivered: http.StatusConflict,
+		gorm.ErrRecordNotFound:             http.StatusNotFound,
+		errors.New("database error"):       http.StatusInternalServerError,
+	}
+
+	for err, expectedStatus := range cases {
+		// GIVEN a retry that fails
+		suite.mockOutboxController.EXPECT().
+			RetryMessage(uint(7)).
+			Return(nil, err).
+			Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/payment/outbox/7/retry", nil)
+		rec := httptest.NewRecorder()
+
+		// WHEN retrying it
+		suite.router.ServeHTTP(rec, req)
+
+		// THEN the error should be mapped to its HTTP status
+		assert.Equal(suite.T(), expectedStatus, rec.Code, err.Error())
+	}
+}
+
+func (suite *OutboxApiControllerTestSuite) Test_RetryMessage_WithInvalidId_ShouldReturn400() {
+	// GIVEN an invalid id
+	req := httptest.NewRequest(http.MethodPost, "/payment/outbox/abc/retry", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN retrying it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 400
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
@@ -8,21 +8,33 @@ import (
 
 	paymentController "github.com/abattassini/tc-fiap-payment/internal/payment/controller"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
 	"github.com/go-chi/chi/v5"
 )
 
 type PaymentApiController struct {
-	paymentController paymentController.PaymentController
+	paymentController     paymentController.PaymentController
+	idempotencyKeyHandler *middleware.IdempotencyKeyHandler
 }
 
-func NewPaymentApiController(paymentService paymentController.PaymentController) *PaymentApiController {
-	return &PaymentApiController{paymentController: paymentService}
+func NewPaymentApiController(
+	paymentService paymentController.PaymentController,
+	idempotencyKeyHandler *middleware.IdempotencyKeyHandler) *PaymentApiController {
+	return &PaymentApiController{
+		paymentController:     paymentService,
+		idempotencyKeyHandler: idempotencyKeyHandler,
+	}
 }
 
 func (c *PaymentApiController) RegisterRoutes(r chi.Router) {
 	prefix := "/v1/payment"
-	r.Post(prefix, c.CreatePayment)
+	r.With(c.idempotencyKeyHandler.Middleware).Post(prefix, c.CreatePayment)
 	r.Get(prefix+"/{orderId}/status", c.GetPaymentStatusByOrderId)
+	r.Get(prefix+"/{orderId}/attempts", c.GetPaymentAttemptsByOrderId)
+	r.Get(prefix+"/{orderId}/history", c.GetPaymentHistoryByOrderId)
+	r.Post(prefix+"/{orderId}/cancel", c.CancelPayment)
+	r.With(c.idempotencyKeyHandler.Middleware).Post(prefix+"/{orderId}/refunds", c.RefundPayment)
+	r.Post(prefix+"/{orderId}/capture", c.CapturePayment)
 	r.Get(prefix+"/{orderId}", c.GetPaymentByOrderId)
 }
 
@@ -34,11 +46,16 @@ func (c *PaymentApiController) CreatePayment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if len(request.Legs) > 0 {
+		c.createSplitPayment(w, &request)
+		return
+	}
+
 	paymentCode, err := c.paymentController.CreatePayment(&request)
 	if err != nil {
 		// Log the actual error for debugging
 		println("Error creating payment:", err.Error())
-		http.Error(w, fmt.Sprintf("Error processing request: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Error processing request: %v", err), httpStatusFromError(err))
 		return
 	}
 
@@ -46,6 +63,19 @@ func (c *PaymentApiController) CreatePayment(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(paymentCode)
 }
 
+// createSplitPayment answers with the payment code of every leg, so the client can pay each of them.
+func (c *PaymentApiController) createSplitPayment(w http.ResponseWriter, request *dto.AddPaymentRequestDto) {
+	legs, err := c.paymentController.CreateSplitPayment(request)
+	if err != nil {
+		println("Error creating split payment:", err.Error())
+		http.Error(w, fmt.Sprintf("Error processing request: %v", err), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(legs)
+}
+
 func (c *PaymentApiController) GetPaymentStatusByOrderId(w http.ResponseWriter, r *http.Request) {
 	orderId, err := getOrderIDFromPath(r)
 	if err != nil {
@@ -80,6 +110,133 @@ func (c *PaymentApiController) GetPaymentByOrderId(w http.ResponseWriter, r *htt
 	json.NewEncoder(w).Encode(payment)
 }
 
+func (c *PaymentApiController) GetPaymentAttemptsByOrderId(w http.ResponseWriter, r *http.Request) {
+	orderId, err := getOrderIDFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attempts, err := c.paymentController.GetPaymentAttemptsByOrderId(orderId)
+	if err != nil {
+		http.Error(w, "Error processing request", httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attempts)
+}
+
+func (c *PaymentApiController) GetPaymentHistoryByOrderId(w http.ResponseWriter, r *http.Request) {
+	orderId, err := getOrderIDFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	history, err := c.paymentController.GetPaymentHistoryByOrderId(orderId)
+	if err != nil {
+		http.Error(w, "Error processing request", httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
+
+func (c *PaymentApiController) CancelPayment(w http.ResponseWriter, r *http.Request) {
+	orderId, err := getOrderIDFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leg, err := getLegFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, err := c.paymentController.CancelPayment(orderId, leg)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error processing request: %v", err), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (c *PaymentApiController) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	orderId, err := getOrderIDFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leg, err := getLegFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.RefundPaymentRequestDto
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+	if request.Amount < 0 {
+		http.Error(w, "amount must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := c.paymentController.RefundPayment(orderId, leg, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error processing request: %v", err), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+func (c *PaymentApiController) CapturePayment(w http.ResponseWriter, r *http.Request) {
+	orderId, err := getOrderIDFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leg, err := getLegFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.CapturePaymentRequestDto
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+	if request.Amount < 0 {
+		http.Error(w, "amount must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	payment, err := c.paymentController.CapturePayment(orderId, leg, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error processing request: %v", err), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(payment)
+}
+
 func getOrderIDFromPath(r *http.Request) (uint, error) {
 	vars := chi.URLParam(r, "orderId")
 	id, err := strconv.ParseUint(vars, 10, 64)
@@ -88,3 +245,17 @@ func getOrderIDFromPath(r *http.Request) (uint, error) {
 	}
 	return uint(id), nil
 }
+
+// getLegFromQuery reads the leg of an order paid in split legs from the leg query parameter; zero
+// when it is not given.
+func getLegFromQuery(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("leg")
+	if value == "" {
+		return 0, nil
+	}
+	leg, err := strconv.Atoi(value)
+	if err != nil || leg < 1 {
+		return 0, fmt.Errorf("invalid leg: %s", value)
+	}
+	return leg, nil
+}
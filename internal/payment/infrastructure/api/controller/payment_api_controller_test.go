@@ -4,13 +4,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/controller"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
 	mockController "github.com/abattassini/tc-fiap-payment/mocks/payment/controller"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,14 +25,20 @@ import (
 
 type PaymentApiControllerTestSuite struct {
 	suite.Suite
-	mockPaymentController *mockController.MockPaymentController
-	apiController         *controller.PaymentApiController
-	router                *chi.Mux
+	mockPaymentController        *mockController.MockPaymentController
+	mockIdempotencyKeyRepository *mockRepositories.MockIdempotencyKeyRepository
+	apiController                *controller.PaymentApiController
+	router                       *chi.Mux
 }
 
 func (suite *PaymentApiControllerTestSuite) SetupTest() {
 	suite.mockPaymentController = mockController.NewMockPaymentController(suite.T())
-	suite.apiController = controller.NewPaymentApiController(suite.mockPaymentController)
+	suite.mockIdempotencyKeyRepository = mockRepositories.NewMockIdempotencyKeyRepository(suite.T())
+	idempotencyKeyHandler, err := middleware.NewIdempotencyKeyHandlerWithConfig(
+		suite.mockIdempotencyKeyRepository,
+		&middleware.IdempotencyKeyConfig{TTL: time.Hour, ClaimTimeout: time.Minute})
+	suite.Require().NoError(err)
+	suite.apiController = controller.NewPaymentApiController(suite.mockPaymentController, idempotencyKeyHandler)
 	suite.router = chi.NewRouter()
 	suite.apiController.RegisterRoutes(suite.router)
 }
@@ -39,7 +51,7 @@ func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithValidRequest_
 	// GIVEN a valid payment request
 	request := dto.AddPaymentRequestDto{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
@@ -62,6 +74,41 @@ func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithValidRequest_
 	suite.mockPaymentController.AssertExpectations(suite.T())
 }
 
+func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithLegs_ShouldReturnPaymentCodeOfEachLeg() {
+	// GIVEN a request splitting the order into a QR code and a card leg
+	request := dto.AddPaymentRequestDto{
+		OrderId: 1,
+		Total:   money.MustParse("100.00"),
+		Legs: []dto.PaymentLegRequestDto{
+			{Type: entities.PaymentTypeQRCode, Amount: money.MustParse("60.00")},
+			{Type: entities.PaymentTypeCard, Amount: money.MustParse("40.00")},
+		},
+	}
+
+	suite.mockPaymentController.EXPECT().
+		CreateSplitPayment(mock.MatchedBy(func(request *dto.AddPaymentRequestDto) bool { return len(request.Legs) == 2 })).
+		Return([]*dto.PaymentLegCodeResponseDto{
+			{Leg: 1, Type: entities.PaymentTypeQRCode, Amount: money.MustParse("60.00"), Status: "pending", PaymentCode: "qr-data"},
+			{Leg: 2, Type: entities.PaymentTypeCard, Amount: money.MustParse("40.00"), Status: "pending", PaymentCode: "pi_secret"},
+		}, nil).
+		Once()
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN creating the payment
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 201 with the payment code of each leg
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	var response []dto.PaymentLegCodeResponseDto
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(suite.T(), response, 2)
+	assert.Equal(suite.T(), "pi_secret", response[1].PaymentCode)
+	suite.mockPaymentController.AssertNotCalled(suite.T(), "CreatePayment", mock.Anything)
+}
+
 func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithInvalidJSON_ShouldReturn400() {
 	// GIVEN invalid JSON
 	req := httptest.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBufferString("invalid json"))
@@ -78,7 +125,7 @@ func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithError_ShouldR
 	// GIVEN a payment request that will fail
 	request := dto.AddPaymentRequestDto{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
@@ -99,6 +146,84 @@ func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithError_ShouldR
 	suite.mockPaymentController.AssertExpectations(suite.T())
 }
 
+func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithActivePayment_ShouldReturn409() {
+	// GIVEN an order that already has an active payment
+	request := dto.AddPaymentRequestDto{
+		OrderId: 1,
+		Total:   money.MustParse("100.50"),
+		Type:    "QRCode",
+	}
+
+	suite.mockPaymentController.EXPECT().
+		CreatePayment(mock.Anything).
+		Return("", entities.ErrActivePaymentExists).
+		Once()
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN creating another payment
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 409
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	suite.mockPaymentController.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithAmountMismatch_ShouldReturn422() {
+	// GIVEN a request whose total differs from the order total
+	request := dto.AddPaymentRequestDto{
+		OrderId: 1,
+		Total:   money.MustParse("100.00"),
+		Type:    "QRCode",
+	}
+
+	suite.mockPaymentController.EXPECT().
+		CreatePayment(mock.Anything).
+		Return("", &entities.PaymentAmountMismatchError{
+			Requested:  money.MustParse("100.00"),
+			OrderTotal: money.MustParse("99.90"),
+		}).
+		Once()
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN creating the payment
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 422 explaining the mismatch
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "requested 100.00, order total is 99.90")
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithUnsupportedType_ShouldReturn422() {
+	// GIVEN a payment type no gateway charges
+	request := dto.AddPaymentRequestDto{
+		OrderId: 1,
+		Total:   money.MustParse("100.00"),
+		Type:    "boleto",
+	}
+
+	suite.mockPaymentController.EXPECT().
+		CreatePayment(mock.Anything).
+		Return("", fmt.Errorf("%w: %q", entities.ErrUnsupportedPaymentType, "boleto")).
+		Once()
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN creating the payment
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 422 naming the type
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), "unsupported payment type")
+}
+
 func (suite *PaymentApiControllerTestSuite) Test_GetPaymentStatusByOrderId_WithValidId_ShouldReturn200() {
 	// GIVEN a valid order ID
 	orderId := uint(1)
@@ -158,7 +283,7 @@ func (suite *PaymentApiControllerTestSuite) Test_GetPaymentByOrderId_WithValidId
 	expectedPayment := &dto.GetPaymentResponseDto{
 		ID:      1,
 		OrderId: orderId,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Status:  "Approved",
 		Type:    "QRCode",
 	}
@@ -210,3 +335,348 @@ func (suite *PaymentApiControllerTestSuite) Test_GetPaymentByOrderId_WithError_S
 	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
 	suite.mockPaymentController.AssertExpectations(suite.T())
 }
+
+func (suite *PaymentApiControllerTestSuite) Test_CreatePayment_WithReplayedIdempotencyKey_ShouldReturnStoredResponse() {
+	// GIVEN a key whose original request already completed
+	body := []byte(`{"orderId":1,"total":100.5,"type":"QRCode"}`)
+	completedAt := time.Now()
+
+	suite.mockIdempotencyKeyRepository.EXPECT().
+		FindIdempotencyKey("retry-1").
+		Return(&entities.IdempotencyKey{
+			ID:                 1,
+			Key:                "retry-1",
+			RequestFingerprint: middleware.RequestFingerprint(http.MethodPost, "/v1/payment", body),
+			CreatedAt:          completedAt,
+			CompletedAt:        &completedAt,
+			ResponseStatus:     http.StatusCreated,
+			ResponseBody:       []byte(`"qr-data"` + "\n"),
+		}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(body))
+	req.Header.Set(middleware.IdempotencyKeyHeader, "retry-1")
+	rec := httptest.NewRecorder()
+
+	// WHEN the client retries
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN the original response should be replayed without creating another payment
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	assert.Equal(suite.T(), "true", rec.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.JSONEq(suite.T(), `"qr-data"`, rec.Body.String())
+	suite.mockPaymentController.AssertNotCalled(suite.T(), "CreatePayment", mock.Anything)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_GetPaymentAttemptsByOrderId_ShouldReturn200() {
+	// GIVEN an order with attempts
+	attempts := []*dto.PaymentAttemptResponseDto{
+		{Attempt: 1, ID: 1, Status: "declined"},
+		{Attempt: 2, ID: 2, Status: "approved", Active: true},
+	}
+
+	suite.mockPaymentController.EXPECT().
+		GetPaymentAttemptsByOrderId(uint(1)).
+		Return(attempts, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/payment/1/attempts", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing attempts
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with every attempt
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var response []dto.PaymentAttemptResponseDto
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(suite.T(), response, 2)
+	assert.Equal(suite.T(), "approved", response[1].Status)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_GetPaymentAttemptsByOrderId_WithInvalidId_ShouldReturn400() {
+	// GIVEN an invalid order ID
+	req := httptest.NewRequest(http.MethodGet, "/v1/payment/invalid/attempts", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing attempts
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 400
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_GetPaymentHistoryByOrderId_ShouldReturn200() {
+	// GIVEN an order with status history
+	history := []*dto.PaymentStatusChangeResponseDto{
+		{ID: 1, PaymentId: 1, FromStatus: "pending", ToStatus: "approved", Source: "webhook", NotificationId: "987"},
+	}
+
+	suite.mockPaymentController.EXPECT().
+		GetPaymentHistoryByOrderId(uint(1)).
+		Return(history, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/payment/1/history", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN getting the history
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the status changes
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var response []dto.PaymentStatusChangeResponseDto
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(suite.T(), response, 1)
+	assert.Equal(suite.T(), "webhook", response[0].Source)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_GetPaymentHistoryByOrderId_WithError_ShouldReturn500() {
+	// GIVEN a failing controller
+	suite.mockPaymentController.EXPECT().
+		GetPaymentHistoryByOrderId(uint(1)).
+		Return(nil, errors.New("database error")).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/payment/1/history", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN getting the history
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 500
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CancelPayment_ShouldReturn200() {
+	// GIVEN a pending payment
+	suite.mockPaymentController.EXPECT().
+		CancelPayment(uint(1), 0).
+		Return(&dto.GetPaymentResponseDto{ID: 1, OrderId: 1, Status: "cancelled"}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN cancelling it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the cancelled payment
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var response dto.GetPaymentResponseDto
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "cancelled", response.Status)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CancelPayment_WithLeg_ShouldCancelThatLeg() {
+	// GIVEN an order paid in split legs
+	suite.mockPaymentController.EXPECT().
+		CancelPayment(uint(1), 2).
+		Return(&dto.GetPaymentResponseDto{ID: 2, OrderId: 1, Leg: 2, Status: "cancelled"}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/cancel?leg=2", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN cancelling its second leg
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the cancelled leg
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CancelPayment_WithInvalidLeg_ShouldReturn400() {
+	for _, leg := range []string{"abc", "0", "-1"} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/cancel?leg="+leg, nil)
+		rec := httptest.NewRecorder()
+
+		// WHEN cancelling a malformed leg
+		suite.router.ServeHTTP(rec, req)
+
+		// THEN should return 400
+		assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	}
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CancelPayment_WithoutLegOfSplitPayment_ShouldReturn422() {
+	// GIVEN an order paid in split legs
+	suite.mockPaymentController.EXPECT().
+		CancelPayment(uint(1), 0).
+		Return(nil, fmt.Errorf("%w: order 1", entities.ErrPaymentLegRequired)).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN cancelling without a leg
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 422
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CancelPayment_WithApprovedPayment_ShouldReturn409() {
+	// GIVEN an approved payment
+	suite.mockPaymentController.EXPECT().
+		CancelPayment(uint(1), 0).
+		Return(nil, &entities.InvalidStatusTransitionError{From: entities.PaymentStatusApproved, To: entities.PaymentStatusCancelled}).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN cancelling it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 409
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CancelPayment_WithInvalidOrderId_ShouldReturn400() {
+	// GIVEN a malformed order id
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/abc/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN cancelling
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 400
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_RefundPayment_ShouldReturn201() {
+	// GIVEN an approved payment
+	suite.mockPaymentController.EXPECT().
+		RefundPayment(uint(1), 0, &dto.RefundPaymentRequestDto{Amount: money.MustParse("10.00"), Reason: "missing item"}).
+		Return(&dto.RefundResponseDto{ID: 1, PaymentId: 1, Amount: money.MustParse("10.00"), Status: "approved"}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/refunds", bytes.NewBufferString(`{"amount":10,"reason":"missing item"}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN refunding part of it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 201 with the refund
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	var response dto.RefundResponseDto
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "approved", response.Status)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_RefundPayment_WithoutBody_ShouldRefundEverything() {
+	// GIVEN a refund request without a body
+	suite.mockPaymentController.EXPECT().
+		RefundPayment(uint(1), 0, &dto.RefundPaymentRequestDto{}).
+		Return(&dto.RefundResponseDto{ID: 1, PaymentId: 1, Amount: money.MustParse("50.00"), Status: "approved"}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/refunds", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN refunding
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 201
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_RefundPayment_WithNegativeAmount_ShouldReturn400() {
+	// GIVEN a negative refund amount
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/refunds", bytes.NewBufferString(`{"amount":-1}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN refunding
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 400
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_RefundPayment_ExceedingCapturedAmount_ShouldReturn422() {
+	// GIVEN a refund above the captured amount
+	suite.mockPaymentController.EXPECT().
+		RefundPayment(uint(1), 0, mock.Anything).
+		Return(nil, &entities.RefundExceedsCapturedError{Requested: money.MustParse("60.00"), Refundable: money.MustParse("50.00")}).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/refunds", bytes.NewBufferString(`{"amount":60}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN refunding
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 422
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_RefundPayment_WithPendingPayment_ShouldReturn409() {
+	// GIVEN a payment that was never approved
+	suite.mockPaymentController.EXPECT().
+		RefundPayment(uint(1), 0, mock.Anything).
+		Return(nil, entities.ErrRefundNotAllowed).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/refunds", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN refunding
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 409
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CapturePayment_ShouldReturn200() {
+	// GIVEN an authorized payment
+	suite.mockPaymentController.EXPECT().
+		CapturePayment(uint(1), 0, &dto.CapturePaymentRequestDto{Amount: money.MustParse("42.00")}).
+		Return(&dto.GetPaymentResponseDto{ID: 1, OrderId: 1, Status: "approved"}, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/capture", bytes.NewBufferString(`{"amount":42}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN capturing part of it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the approved payment
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	var response dto.GetPaymentResponseDto
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(suite.T(), "approved", response.Status)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CapturePayment_ExceedingAuthorization_ShouldReturn422() {
+	// GIVEN a capture above the authorized amount
+	suite.mockPaymentController.EXPECT().
+		CapturePayment(uint(1), 0, mock.Anything).
+		Return(nil, &entities.CaptureExceedsAuthorizedError{Requested: money.MustParse("60.00"), Authorized: money.MustParse("50.00")}).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/capture", bytes.NewBufferString(`{"amount":60}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN capturing
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 422
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+}
+
+func (suite *PaymentApiControllerTestSuite) Test_CapturePayment_WithoutAuthorization_ShouldReturn409() {
+	// GIVEN a payment that is not authorized
+	suite.mockPaymentController.EXPECT().
+		CapturePayment(uint(1), 0, &dto.CapturePaymentRequestDto{}).
+		Return(nil, entities.ErrCaptureNotAllowed).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment/1/capture", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN capturing
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 409
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+}
@@ -2,24 +2,43 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/controller"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
 	"github.com/go-chi/chi/v5"
 )
 
+const maxNotificationsLimit = 500
+
 type PaymentWebhookApiController struct {
 	paymentWebhookController controller.PaymentWebhookController
+	signatureVerifier        *middleware.MercadoPagoSignatureVerifier
+	stripeSignatureVerifier  *middleware.StripeSignatureVerifier
 }
 
-func NewPaymentWebhookApiController(paymentWebhookController controller.PaymentWebhookController) *PaymentWebhookApiController {
-	return &PaymentWebhookApiController{paymentWebhookController: paymentWebhookController}
+func NewPaymentWebhookApiController(
+	paymentWebhookController controller.PaymentWebhookController,
+	signatureVerifier *middleware.MercadoPagoSignatureVerifier,
+	stripeSignatureVerifier *middleware.StripeSignatureVerifier) *PaymentWebhookApiController {
+	return &PaymentWebhookApiController{
+		paymentWebhookController: paymentWebhookController,
+		signatureVerifier:        signatureVerifier,
+		stripeSignatureVerifier:  stripeSignatureVerifier,
+	}
 }
 
 func (c *PaymentWebhookApiController) RegisterRoutes(r chi.Router) {
 	prefix := "/payment/webhooks"
-	r.Post(prefix+"/notify", c.HandlePaymentNotification)
+	r.With(c.signatureVerifier.Middleware).Post(prefix+"/notify", c.HandlePaymentNotification)
+	r.With(c.stripeSignatureVerifier.Middleware).Post(prefix+"/stripe", c.HandleStripeEvent)
+	// The PSP posts to the registered webhook URL with /pix appended. It authenticates with mutual TLS,
+	// terminated in front of the service; a notification only makes us look the charge up anyway
+	r.Post(prefix+"/pix", c.HandlePixNotification)
+	r.Get(prefix+"/notifications", c.ListNotifications)
 }
 
 func (c *PaymentWebhookApiController) HandlePaymentNotification(w http.ResponseWriter, r *http.Request) {
@@ -32,9 +51,72 @@ func (c *PaymentWebhookApiController) HandlePaymentNotification(w http.ResponseW
 
 	err := c.paymentWebhookController.HandleWebhook(&request)
 	if err != nil {
-		http.Error(w, "Error processing webhook: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error processing webhook: "+err.Error(), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *PaymentWebhookApiController) HandleStripeEvent(w http.ResponseWriter, r *http.Request) {
+	var event dto.StripeEventDto
+
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil || event.Id == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := c.paymentWebhookController.HandleStripeEvent(&event)
+	if err != nil {
+		http.Error(w, "Error processing webhook: "+err.Error(), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *PaymentWebhookApiController) HandlePixNotification(w http.ResponseWriter, r *http.Request) {
+	var notification dto.PixWebhookDto
+
+	if err := json.NewDecoder(r.Body).Decode(&notification); err != nil || len(notification.Pix) == 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := c.paymentWebhookController.HandlePixNotification(&notification)
+	if err != nil {
+		http.Error(w, "Error processing webhook: "+err.Error(), httpStatusFromError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *PaymentWebhookApiController) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed <= 0 || parsed > maxNotificationsLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxNotificationsLimit), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	notifications, err := c.paymentWebhookController.ListNotifications(&dto.ListWebhookNotificationsRequestDto{
+		ProviderId: query.Get("provider_id"),
+		Topic:      query.Get("topic"),
+		Resource:   query.Get("resource"),
+		Outcome:    query.Get("outcome"),
+		Limit:      limit,
+	})
+	if err != nil {
+		http.Error(w, "Error processing request", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(notifications)
 }
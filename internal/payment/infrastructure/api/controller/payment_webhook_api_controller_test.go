@@ -6,10 +6,14 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/controller"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
 	mockController "github.com/abattassini/tc-fiap-payment/mocks/payment/controller"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +21,11 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+const (
+	webhookSecret       = "test_secret"
+	stripeWebhookSecret = "whsec_test"
+)
+
 type PaymentWebhookApiControllerTestSuite struct {
 	suite.Suite
 	mockWebhookController *mockController.MockPaymentWebhookController
@@ -25,12 +34,42 @@ type PaymentWebhookApiControllerTestSuite struct {
 }
 
 func (suite *PaymentWebhookApiControllerTestSuite) SetupTest() {
+	suite.setupWithMode(middleware.SignatureModeEnforce)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) setupWithMode(mode string) {
+	verifier, err := middleware.NewMercadoPagoSignatureVerifierWithConfig(&middleware.MercadoPagoSignatureConfig{
+		Secret: webhookSecret,
+		Mode:   mode,
+	})
+	suite.Require().NoError(err)
+	stripeVerifier, err := middleware.NewStripeSignatureVerifierWithConfig(&middleware.StripeSignatureConfig{
+		Secret: stripeWebhookSecret,
+		Mode:   mode,
+	})
+	suite.Require().NoError(err)
+
 	suite.mockWebhookController = mockController.NewMockPaymentWebhookController(suite.T())
-	suite.apiController = controller.NewPaymentWebhookApiController(suite.mockWebhookController)
+	suite.apiController = controller.NewPaymentWebhookApiController(suite.mockWebhookController, verifier, stripeVerifier)
 	suite.router = chi.NewRouter()
 	suite.apiController.RegisterRoutes(suite.router)
 }
 
+func signRequest(req *http.Request) *http.Request {
+	ts := "1704908010"
+	requestId := "bb56a2f1-6aae-46ac-982e-9dcd3581d08e"
+	signature := middleware.Sign(webhookSecret, req.URL.Query().Get("data.id"), requestId, ts)
+	req.Header.Set("x-request-id", requestId)
+	req.Header.Set("x-signature", "ts="+ts+",v1="+signature)
+	return req
+}
+
+func signStripeRequest(req *http.Request, body []byte) *http.Request {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Stripe-Signature", "t="+ts+",v1="+middleware.SignStripePayload(stripeWebhookSecret, ts, body))
+	return req
+}
+
 func TestPaymentWebhookApiControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(PaymentWebhookApiControllerTestSuite))
 }
@@ -50,7 +89,7 @@ func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotificatio
 		Once()
 
 	body, _ := json.Marshal(request)
-	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", bytes.NewBuffer(body))
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=1", bytes.NewBuffer(body)))
 	rec := httptest.NewRecorder()
 
 	// WHEN handling webhook
@@ -63,7 +102,7 @@ func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotificatio
 
 func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotification_WithInvalidJSON_ShouldReturn400() {
 	// GIVEN invalid JSON
-	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", bytes.NewBufferString("invalid json"))
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", bytes.NewBufferString("invalid json")))
 	rec := httptest.NewRecorder()
 
 	// WHEN handling webhook with invalid JSON
@@ -88,7 +127,7 @@ func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotificatio
 		Once()
 
 	body, _ := json.Marshal(request)
-	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", bytes.NewBuffer(body))
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=1", bytes.NewBuffer(body)))
 	rec := httptest.NewRecorder()
 
 	// WHEN webhook processing fails
@@ -98,3 +137,234 @@ func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotificatio
 	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
 	suite.mockWebhookController.AssertExpectations(suite.T())
 }
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotification_WithInvalidTransition_ShouldReturn409() {
+	// GIVEN a webhook request that tries to move a payment backwards
+	request := dto.MercadoPagoWebhookNotificationRequestDTO{
+		Id:    "1",
+		Topic: "payment.failed",
+	}
+
+	suite.mockWebhookController.EXPECT().
+		HandleWebhook(mock.Anything).
+		Return(&entities.InvalidStatusTransitionError{
+			From: entities.PaymentStatusApproved,
+			To:   entities.PaymentStatusDeclined,
+		}).
+		Once()
+
+	body, _ := json.Marshal(request)
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=1", bytes.NewBuffer(body)))
+	rec := httptest.NewRecorder()
+
+	// WHEN webhook processing hits an invalid transition
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 409
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	suite.mockWebhookController.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotification_WithoutSignature_ShouldReturn401() {
+	// GIVEN an unsigned webhook request
+	body, _ := json.Marshal(dto.MercadoPagoWebhookNotificationRequestDTO{Id: "1", Topic: "payment"})
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN handling webhook
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 401 without processing the notification
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	suite.mockWebhookController.AssertNotCalled(suite.T(), "HandleWebhook", mock.Anything)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotification_WithTamperedSignature_ShouldReturn401() {
+	// GIVEN a request signed for a different data.id
+	body, _ := json.Marshal(dto.MercadoPagoWebhookNotificationRequestDTO{Id: "1", Topic: "payment"})
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=1", bytes.NewBuffer(body)))
+	req.URL.RawQuery = "data.id=2"
+	rec := httptest.NewRecorder()
+
+	// WHEN handling webhook
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 401 without processing the notification
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	suite.mockWebhookController.AssertNotCalled(suite.T(), "HandleWebhook", mock.Anything)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotification_WithBodyForOtherResource_ShouldReturn401() {
+	// GIVEN a request signed for data.id 1 whose body is about payment 2
+	body, _ := json.Marshal(dto.MercadoPagoWebhookNotificationRequestDTO{Id: "10", Topic: "payment", Resource: "2"})
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=1", bytes.NewBuffer(body)))
+	rec := httptest.NewRecorder()
+
+	// WHEN handling webhook
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 401 without processing the notification
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	suite.mockWebhookController.AssertNotCalled(suite.T(), "HandleWebhook", mock.Anything)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePaymentNotification_WithoutSignatureInLogOnlyMode_ShouldReturn200() {
+	// GIVEN the verifier running in log-only mode
+	suite.setupWithMode(middleware.SignatureModeLogOnly)
+
+	suite.mockWebhookController.EXPECT().
+		HandleWebhook(mock.Anything).
+		Return(nil).
+		Once()
+
+	body, _ := json.Marshal(dto.MercadoPagoWebhookNotificationRequestDTO{Id: "1", Topic: "payment"})
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN handling an unsigned webhook
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN the notification should still be processed
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	suite.mockWebhookController.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandleStripeEvent_WithSignedEvent_ShouldReturn200() {
+	// GIVEN a signed payment intent event
+	body := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","object":"payment_intent"}}}`)
+
+	suite.mockWebhookController.EXPECT().
+		HandleStripeEvent(mock.MatchedBy(func(event *dto.StripeEventDto) bool {
+			return event.Id == "evt_1" && event.PaymentIntentId() == "pi_1"
+		})).
+		Return(nil).
+		Once()
+
+	req := signStripeRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", bytes.NewBuffer(body)), body)
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the event
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN it should be forwarded
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandleStripeEvent_WithMercadoPagoSignature_ShouldReturn401() {
+	// GIVEN an event signed the Mercado Pago way
+	body := []byte(`{"id":"evt_1","type":"payment_intent.succeeded"}`)
+	req := signRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", bytes.NewBuffer(body)))
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the event
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	suite.mockWebhookController.AssertNotCalled(suite.T(), "HandleStripeEvent", mock.Anything)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandleStripeEvent_WithoutEventId_ShouldReturn400() {
+	// GIVEN a signed body that is not an event
+	body := []byte(`{}`)
+	req := signStripeRequest(httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", bytes.NewBuffer(body)), body)
+	rec := httptest.NewRecorder()
+
+	// WHEN handling it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePixNotification_ShouldReturn200() {
+	// GIVEN a PIX notification
+	body := []byte(`{"pix":[{"endToEndId":"E1","txid":"txid1","valor":"50.00","horario":"2024-01-10T12:00:00Z"}]}`)
+
+	suite.mockWebhookController.EXPECT().
+		HandlePixNotification(mock.MatchedBy(func(notification *dto.PixWebhookDto) bool {
+			return len(notification.Pix) == 1 && notification.Pix[0].Txid == "txid1"
+		})).
+		Return(nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/pix", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the notification
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN it should be forwarded
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_HandlePixNotification_WithoutPix_ShouldReturn400() {
+	// GIVEN a body without any PIX
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/pix", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+
+	// WHEN handling it
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_ListNotifications_WithFilters_ShouldReturn200() {
+	// GIVEN stored notifications matching the filter
+	expected := []*dto.WebhookNotificationResponseDto{
+		{ID: 1, ProviderId: "987", Topic: "payment", Resource: "987", Outcome: "processed"},
+	}
+
+	suite.mockWebhookController.EXPECT().
+		ListNotifications(&dto.ListWebhookNotificationsRequestDto{
+			ProviderId: "987",
+			Outcome:    "processed",
+			Limit:      20,
+		}).
+		Return(expected, nil).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/payment/webhooks/notifications?provider_id=987&outcome=processed&limit=20", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing notifications
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 200 with the notifications
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+
+	var response []*dto.WebhookNotificationResponseDto
+	assert.NoError(suite.T(), json.NewDecoder(rec.Body).Decode(&response))
+	assert.Len(suite.T(), response, 1)
+	assert.Equal(suite.T(), "987", response[0].ProviderId)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_ListNotifications_WithInvalidLimit_ShouldReturn400() {
+	// GIVEN an out of range limit
+	req := httptest.NewRequest(http.MethodGet, "/payment/webhooks/notifications?limit=100000", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing notifications
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 400
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (suite *PaymentWebhookApiControllerTestSuite) Test_ListNotifications_WithError_ShouldReturn500() {
+	// GIVEN a failing controller
+	suite.mockWebhookController.EXPECT().
+		ListNotifications(mock.Anything).
+		Return(nil, errors.New("database error")).
+		Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/payment/webhooks/notifications", nil)
+	rec := httptest.NewRecorder()
+
+	// WHEN listing notifications
+	suite.router.ServeHTTP(rec, req)
+
+	// THEN should return 500
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+}
@@ -1,7 +1,29 @@
 package dto
 
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
 type AddPaymentRequestDto struct {
-	OrderId uint    `json:"orderId"`
-	Total   float32 `json:"total"`
-	Type    string  `json:"type"`
+	OrderId    uint         `json:"orderId"`
+	Total      money.Amount `json:"total"`
+	Type       string       `json:"type"`
+	Regenerate bool         `json:"regenerate"`
+	// PaymentMethod is a card tokenized by the client, used to confirm card payments right away.
+	PaymentMethod string `json:"paymentMethod,omitempty"`
+	// Legs splits the order into several payments adding up to its total; Type and PaymentMethod are
+	// then given per leg.
+	Legs []PaymentLegRequestDto `json:"legs,omitempty"`
+	// Tip is charged on top of the order and its service fees.
+	Tip *TipRequestDto `json:"tip,omitempty"`
+}
+
+// TipRequestDto gives a tip either as an amount or as a percentage of the order total.
+type TipRequestDto struct {
+	Amount     money.Amount `json:"amount,omitempty"`
+	Percentage money.Amount `json:"percentage,omitempty"`
+}
+
+type PaymentLegRequestDto struct {
+	Type          string       `json:"type"`
+	Amount        money.Amount `json:"amount"`
+	PaymentMethod string       `json:"paymentMethod,omitempty"`
 }
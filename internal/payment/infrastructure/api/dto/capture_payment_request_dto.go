@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type CapturePaymentRequestDto struct {
+	// Amount to collect; omitted or zero captures the whole authorization.
+	Amount money.Amount `json:"amount"`
+}
@@ -1,21 +1,27 @@
 package dto
 
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+// MercadoPagoTimeLayout is the date format Mercado Pago expects, e.g. 2023-08-22T16:34:56.559-04:00.
+const MercadoPagoTimeLayout = "2006-01-02T15:04:05.000-07:00"
+
 type CreateQRCodeDTO struct {
-	ExternalReference string  `json:"external_reference"`
-	Title             string  `json:"title"`
-	Description       string  `json:"description"`
-	NotificationURL   string  `json:"notification_url"`
-	TotalAmount       float32 `json:"total_amount"`
-	Items             []Item  `json:"items"`
+	ExternalReference string       `json:"external_reference"`
+	Title             string       `json:"title"`
+	Description       string       `json:"description"`
+	NotificationURL   string       `json:"notification_url"`
+	TotalAmount       money.Amount `json:"total_amount"`
+	Items             []Item       `json:"items"`
+	ExpirationDate    string       `json:"expiration_date,omitempty"`
 }
 
 type Item struct {
-	SKUNumber   string  `json:"sku_number"`
-	Category    string  `json:"category"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	UnitPrice   float32 `json:"unit_price"`
-	Quantity    int     `json:"quantity"`
-	UnitMeasure string  `json:"unit_measure"`
-	TotalAmount float32 `json:"total_amount"`
+	SKUNumber   string       `json:"sku_number"`
+	Category    string       `json:"category"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	UnitPrice   money.Amount `json:"unit_price"`
+	Quantity    int          `json:"quantity"`
+	UnitMeasure string       `json:"unit_measure"`
+	TotalAmount money.Amount `json:"total_amount"`
 }
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+type DisputeResponseDto struct {
+	ID                uint           `json:"id"`
+	PaymentId         uint           `json:"payment_id"`
+	OrderId           uint           `json:"order_id"`
+	Provider          string         `json:"provider"`
+	Type              string         `json:"type"`
+	ProviderDisputeId string         `json:"provider_dispute_id"`
+	Amount            money.Amount   `json:"amount"`
+	Currency          money.Currency `json:"currency"`
+	Status            string         `json:"status"`
+	Outcome           string         `json:"outcome,omitempty"`
+	Reason            string         `json:"reason,omitempty"`
+	OpenedAt          time.Time      `json:"opened_at"`
+	ClosedAt          *time.Time     `json:"closed_at"`
+}
@@ -1,12 +1,39 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
 
 type GetPaymentResponseDto struct {
-	ID        uint      `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	OrderId   uint      `json:"order_id"`
-	Total     float32   `json:"total"`
-	Type      string    `json:"type"`
-	Status    string    `json:"status"`
+	ID        uint           `json:"id,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	OrderId   uint           `json:"order_id"`
+	Total     money.Amount   `json:"total"`
+	Currency  money.Currency `json:"currency"`
+	Type      string         `json:"type"`
+	Status    string         `json:"status"`
+
+	Provider          string `json:"provider,omitempty"`
+	InStoreOrderId    string `json:"in_store_order_id,omitempty"`
+	ExternalReference string `json:"external_reference,omitempty"`
+	QRData            string `json:"qr_data,omitempty"`
+	ProviderPaymentId string `json:"provider_payment_id,omitempty"`
+	Txid              string `json:"txid,omitempty"`
+	// CapturedTotal is what was collected of a card authorization, which a partial capture leaves below Total.
+	CapturedTotal money.Amount `json:"captured_total,omitempty"`
+	// RefundedTotal is what was given back of the payment through accepted refunds.
+	RefundedTotal money.Amount `json:"refunded_total,omitempty"`
+	// TipAmount and ServiceFeeAmount are the parts of Total charged as a tip and as service fees.
+	TipAmount        money.Amount `json:"tip_amount,omitempty"`
+	ServiceFeeAmount money.Amount `json:"service_fee_amount,omitempty"`
+	// Revenue is what a collected payment brought in for the order and its service fees, leaving the tip out.
+	Revenue money.Amount `json:"revenue,omitempty"`
+
+	// Leg is the number of the leg within a split payment.
+	Leg int `json:"leg,omitempty"`
+	// PaidAmount and Legs are only set for an order paid in split legs, which has no id of its own.
+	PaidAmount money.Amount             `json:"paid_amount,omitempty"`
+	Legs       []*GetPaymentResponseDto `json:"legs,omitempty"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+type ListDisputesRequestDto struct {
+	OrderId uint
+	Status  string
+	Limit   int
+}
@@ -0,0 +1,7 @@
+package dto
+
+type ListOutboxMessagesRequestDto struct {
+	OrderId uint
+	Status  string
+	Limit   int
+}
@@ -0,0 +1,9 @@
+package dto
+
+type ListWebhookNotificationsRequestDto struct {
+	ProviderId string
+	Topic      string
+	Resource   string
+	Outcome    string
+	Limit      int
+}
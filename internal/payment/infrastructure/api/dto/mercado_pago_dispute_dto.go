@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type MercadoPagoChargebackResponseDto struct {
+	Id       string       `json:"id"`
+	Payments []int64      `json:"payments"`
+	Amount   money.Amount `json:"amount"`
+	Currency string       `json:"currency"`
+	// CoverageApplied is null while the chargeback is being analysed, true when Mercado Pago covers
+	// it in favour of the seller and false when the money goes back to the buyer.
+	CoverageApplied     *bool  `json:"coverage_applied"`
+	DocumentationStatus string `json:"documentation_status"`
+	ReasonCode          string `json:"reason_code"`
+}
+
+type MercadoPagoClaimResponseDto struct {
+	Id int64 `json:"id"`
+	// Resource is the kind of resource the claim is about and ResourceId its id, a payment id
+	// when Resource is "payment".
+	Resource   string                         `json:"resource"`
+	ResourceId int64                          `json:"resource_id"`
+	Status     string                         `json:"status"`
+	ReasonId   string                         `json:"reason_id"`
+	Resolution *MercadoPagoClaimResolutionDto `json:"resolution"`
+}
+
+type MercadoPagoClaimResolutionDto struct {
+	Reason    string   `json:"reason"`
+	Benefited []string `json:"benefited"`
+}
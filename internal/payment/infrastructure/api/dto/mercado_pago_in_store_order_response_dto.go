@@ -0,0 +1,6 @@
+package dto
+
+type MercadoPagoInStoreOrderResponseDto struct {
+	InStoreOrderId    string `json:"in_store_order_id"`
+	ExternalReference string `json:"external_reference"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type MercadoPagoMerchantOrderResponseDto struct {
+	Id                int64                            `json:"id"`
+	Status            string                           `json:"status"`
+	OrderStatus       string                           `json:"order_status"`
+	ExternalReference string                           `json:"external_reference"`
+	Payments          []*MercadoPagoPaymentResponseDto `json:"payments"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type MercadoPagoPaymentResponseDto struct {
+	Id                int64        `json:"id"`
+	Status            string       `json:"status"`
+	StatusDetail      string       `json:"status_detail"`
+	ExternalReference string       `json:"external_reference"`
+	TransactionAmount money.Amount `json:"transaction_amount"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type MercadoPagoRefundRequestDto struct {
+	Amount money.Amount `json:"amount"`
+}
+
+type MercadoPagoRefundResponseDto struct {
+	Id        int64        `json:"id"`
+	PaymentId int64        `json:"payment_id"`
+	Amount    money.Amount `json:"amount"`
+	Status    string       `json:"status"`
+}
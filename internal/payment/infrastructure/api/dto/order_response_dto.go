@@ -1,17 +1,19 @@
 package dto
 
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
 type OrderResponseDto struct {
-	ID          uint                `json:"id"`
-	TotalAmount float32             `json:"total_amount"`
-	Products    []*OrderProductDto  `json:"products"`
+	ID          uint               `json:"id"`
+	TotalAmount money.Amount       `json:"total_amount"`
+	Products    []*OrderProductDto `json:"products"`
 }
 
 type OrderProductDto struct {
-	ProductId   uint    `json:"product_id"`
-	Price       float32 `json:"price"`
-	Quantity    uint    `json:"quantity"`
-	Name        string  `json:"name"`
-	ImageLink   string  `json:"image_link"`
-	Description string  `json:"description"`
-	Category    int     `json:"category"`
+	ProductId   uint         `json:"product_id"`
+	Price       money.Amount `json:"price"`
+	Quantity    uint         `json:"quantity"`
+	Name        string       `json:"name"`
+	ImageLink   string       `json:"image_link"`
+	Description string       `json:"description"`
+	Category    int          `json:"category"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type OutboxMessageResponseDto struct {
+	ID            uint       `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	OrderId       uint       `json:"order_id"`
+	OrderStatus   int        `json:"order_status"`
+	Status        string     `json:"status"`
+	Attempts      uint       `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	LastError     string     `json:"last_error,omitempty"`
+}
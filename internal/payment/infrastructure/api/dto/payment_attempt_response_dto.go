@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+type PaymentAttemptResponseDto struct {
+	Attempt   int            `json:"attempt"`
+	ID        uint           `json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Total     money.Amount   `json:"total"`
+	Currency  money.Currency `json:"currency"`
+	Type      string         `json:"type"`
+	Status    string         `json:"status"`
+	Active    bool           `json:"active"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+// PaymentLegCodeResponseDto is what the client needs to pay one leg of a split payment.
+type PaymentLegCodeResponseDto struct {
+	Leg         int          `json:"leg"`
+	Type        string       `json:"type"`
+	Amount      money.Amount `json:"amount"`
+	Status      string       `json:"status"`
+	PaymentCode string       `json:"payment_code,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type PaymentStatusChangeResponseDto struct {
+	ID             uint      `json:"id"`
+	PaymentId      uint      `json:"payment_id"`
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `json:"to_status"`
+	Source         string    `json:"source"`
+	NotificationId string    `json:"notification_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,87 @@
+package dto
+
+// The PIX DTOs follow the PIX API of the Banco Central do Brasil, which every PSP implements. Amounts
+// are decimal strings such as "50.00".
+
+const PixExternalReferenceInfo = "external_reference"
+
+// PixCreateCobDto is the body of PUT /v2/cob/{txid}, creating an immediate charge (cobrança).
+type PixCreateCobDto struct {
+	Calendario         PixCalendarioDto      `json:"calendario"`
+	Valor              PixValorDto           `json:"valor"`
+	Chave              string                `json:"chave"`
+	SolicitacaoPagador string                `json:"solicitacaoPagador,omitempty"`
+	InfoAdicionais     []PixInfoAdicionalDto `json:"infoAdicionais,omitempty"`
+}
+
+type PixCalendarioDto struct {
+	Criacao string `json:"criacao,omitempty"`
+	// Expiracao is how many seconds after its creation the charge can be paid.
+	Expiracao int64 `json:"expiracao,omitempty"`
+}
+
+type PixValorDto struct {
+	Original string `json:"original"`
+}
+
+type PixInfoAdicionalDto struct {
+	Nome  string `json:"nome"`
+	Valor string `json:"valor"`
+}
+
+// PixCobDto is a charge as returned by the PSP. Status is ATIVA, CONCLUIDA, REMOVIDA_PELO_USUARIO_RECEBEDOR
+// or REMOVIDA_PELO_PSP; Pix lists the payments received for it.
+type PixCobDto struct {
+	Txid           string                `json:"txid"`
+	Revisao        int                   `json:"revisao"`
+	Calendario     PixCalendarioDto      `json:"calendario"`
+	Status         string                `json:"status"`
+	Valor          PixValorDto           `json:"valor"`
+	Chave          string                `json:"chave"`
+	Location       string                `json:"location"`
+	PixCopiaECola  string                `json:"pixCopiaECola,omitempty"`
+	InfoAdicionais []PixInfoAdicionalDto `json:"infoAdicionais,omitempty"`
+	Pix            []PixDto              `json:"pix,omitempty"`
+}
+
+// ExternalReference is the reference we attached to the charge as additional information.
+func (d PixCobDto) ExternalReference() string {
+	for _, info := range d.InfoAdicionais {
+		if info.Nome == PixExternalReferenceInfo {
+			return info.Valor
+		}
+	}
+	return ""
+}
+
+// PixDto is a received payment, identified by its end to end id.
+type PixDto struct {
+	EndToEndId  string            `json:"endToEndId"`
+	Txid        string            `json:"txid,omitempty"`
+	Valor       string            `json:"valor"`
+	Horario     string            `json:"horario"`
+	InfoPagador string            `json:"infoPagador,omitempty"`
+	Devolucoes  []PixDevolucaoDto `json:"devolucoes,omitempty"`
+}
+
+// PixDevolucaoDto is a refund of a received payment. Status is EM_PROCESSAMENTO, DEVOLVIDO or NAO_REALIZADO.
+type PixDevolucaoDto struct {
+	Id     string `json:"id"`
+	RtrId  string `json:"rtrId"`
+	Valor  string `json:"valor"`
+	Status string `json:"status"`
+	Motivo string `json:"motivo,omitempty"`
+}
+
+type PixCreateDevolucaoDto struct {
+	Valor string `json:"valor"`
+}
+
+type PixPatchCobDto struct {
+	Status string `json:"status"`
+}
+
+// PixWebhookDto is the body the PSP posts to <webhook URL>/pix when payments are received or refunded.
+type PixWebhookDto struct {
+	Pix []PixDto `json:"pix"`
+}
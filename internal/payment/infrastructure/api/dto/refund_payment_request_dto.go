@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type RefundPaymentRequestDto struct {
+	// Amount to give back; omitted or zero refunds everything that was not refunded yet.
+	Amount money.Amount `json:"amount"`
+	Reason string       `json:"reason"`
+}
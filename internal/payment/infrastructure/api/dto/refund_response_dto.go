@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+type RefundResponseDto struct {
+	ID               uint           `json:"id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	PaymentId        uint           `json:"payment_id"`
+	Amount           money.Amount   `json:"amount"`
+	Currency         money.Currency `json:"currency"`
+	Status           string         `json:"status"`
+	Reason           string         `json:"reason,omitempty"`
+	ProviderRefundId string         `json:"provider_refund_id,omitempty"`
+	FailureReason    string         `json:"failure_reason,omitempty"`
+}
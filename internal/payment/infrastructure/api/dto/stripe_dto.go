@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// StripeCreatePaymentIntentDto is sent form-encoded, like every Stripe request. Amounts are in minor units.
+type StripeCreatePaymentIntentDto struct {
+	Amount   int64
+	Currency string
+	// CaptureMethod is "automatic" or "manual"; empty uses the Stripe default.
+	CaptureMethod string
+	Description   string
+	Metadata      map[string]string
+}
+
+func (d StripeCreatePaymentIntentDto) Form() url.Values {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(d.Amount, 10))
+	form.Set("currency", d.Currency)
+	form.Set("payment_method_types[]", "card")
+	if d.CaptureMethod != "" {
+		form.Set("capture_method", d.CaptureMethod)
+	}
+	if d.Description != "" {
+		form.Set("description", d.Description)
+	}
+	for key, value := range d.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+	return form
+}
+
+type StripePaymentIntentDto struct {
+	Id                 string            `json:"id"`
+	Object             string            `json:"object"`
+	Amount             int64             `json:"amount"`
+	AmountCapturable   int64             `json:"amount_capturable"`
+	AmountReceived     int64             `json:"amount_received"`
+	Currency           string            `json:"currency"`
+	Status             string            `json:"status"`
+	CaptureMethod      string            `json:"capture_method"`
+	ClientSecret       string            `json:"client_secret"`
+	CancellationReason string            `json:"cancellation_reason"`
+	Metadata           map[string]string `json:"metadata"`
+	LastPaymentError   *StripeErrorDto   `json:"last_payment_error"`
+	// LatestCharge only carries the charge details when requested with expand[]=latest_charge.
+	LatestCharge *StripeChargeDto `json:"latest_charge"`
+}
+
+type StripeChargeDto struct {
+	Id             string `json:"id"`
+	Amount         int64  `json:"amount"`
+	AmountRefunded int64  `json:"amount_refunded"`
+	Refunded       bool   `json:"refunded"`
+	Status         string `json:"status"`
+}
+
+// UnmarshalJSON accepts both an expanded charge and the bare charge id Stripe sends otherwise.
+func (c *StripeChargeDto) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*c = StripeChargeDto{}
+		return json.Unmarshal(data, &c.Id)
+	}
+
+	type charge StripeChargeDto
+	return json.Unmarshal(data, (*charge)(c))
+}
+
+type StripeRefundDto struct {
+	Id            string `json:"id"`
+	Amount        int64  `json:"amount"`
+	PaymentIntent string `json:"payment_intent"`
+	Status        string `json:"status"`
+}
+
+type StripeErrorDto struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// PaymentIntent is set on card errors, with the intent left in requires_payment_method.
+	PaymentIntent *StripePaymentIntentDto `json:"payment_intent,omitempty"`
+}
+
+type StripeErrorResponseDto struct {
+	Error StripeErrorDto `json:"error"`
+}
+
+// StripeEventDto is a Stripe webhook event; Data.Object is the resource the event is about.
+type StripeEventDto struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object StripeEventObjectDto `json:"object"`
+	} `json:"data"`
+}
+
+type StripeEventObjectDto struct {
+	Id     string `json:"id"`
+	Object string `json:"object"`
+	// PaymentIntent links charges and refunds to their payment intent.
+	PaymentIntent string `json:"payment_intent"`
+}
+
+// PaymentIntentId returns the payment intent an event is about, or empty for other resources.
+func (e *StripeEventDto) PaymentIntentId() string {
+	if e.Data.Object.Object == "payment_intent" {
+		return e.Data.Object.Id
+	}
+	return e.Data.Object.PaymentIntent
+}
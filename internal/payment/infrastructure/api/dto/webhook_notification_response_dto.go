@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type WebhookNotificationResponseDto struct {
+	ID          uint       `json:"id"`
+	ProviderId  string     `json:"provider_id"`
+	Topic       string     `json:"topic"`
+	Resource    string     `json:"resource"`
+	ReceivedAt  time.Time  `json:"received_at"`
+	ProcessedAt *time.Time `json:"processed_at"`
+	Deliveries  uint       `json:"deliveries"`
+	Outcome     string     `json:"outcome"`
+	Error       string     `json:"error,omitempty"`
+}
@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+)
+
+const (
+	IdempotencyKeyHeader         = "Idempotency-Key"
+	IdempotentReplayedHeader     = "Idempotent-Replayed"
+	maxIdempotencyKeyLength      = 255
+	defaultIdempotencyKeyTTL     = 24 * time.Hour
+	idempotencyKeyTTLEnvVariable = "IDEMPOTENCY_KEY_TTL"
+
+	defaultIdempotencyKeyClaimTimeout     = 2 * time.Minute
+	idempotencyKeyClaimTimeoutEnvVariable = "IDEMPOTENCY_KEY_CLAIM_TIMEOUT"
+)
+
+type IdempotencyKeyConfig struct {
+	TTL time.Duration
+	// ClaimTimeout is how long a request may run before a retry with its key is let through, for when the
+	// original request died without releasing the key.
+	ClaimTimeout time.Duration
+}
+
+func (c *IdempotencyKeyConfig) Validate() error {
+	if c.TTL <= 0 {
+		return fmt.Errorf("invalid IdempotencyKeyConfig: TTL must be positive")
+	}
+	if c.ClaimTimeout <= 0 {
+		return fmt.Errorf("invalid IdempotencyKeyConfig: claim timeout must be positive")
+	}
+	return nil
+}
+
+func newIdempotencyKeyConfig() (*IdempotencyKeyConfig, error) {
+	ttl := defaultIdempotencyKeyTTL
+	if raw := os.Getenv(idempotencyKeyTTLEnvVariable); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", idempotencyKeyTTLEnvVariable, err)
+		}
+		ttl = parsed
+	}
+
+	claimTimeout := defaultIdempotencyKeyClaimTimeout
+	if raw := os.Getenv(idempotencyKeyClaimTimeoutEnvVariable); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", idempotencyKeyClaimTimeoutEnvVariable, err)
+		}
+		claimTimeout = parsed
+	}
+	return &IdempotencyKeyConfig{TTL: ttl, ClaimTimeout: claimTimeout}, nil
+}
+
+// IdempotencyKeyHandler replays the stored response of requests retried with the same
+// Idempotency-Key header, so that client retries do not repeat side effects.
+type IdempotencyKeyHandler struct {
+	config     *IdempotencyKeyConfig
+	repository repositories.IdempotencyKeyRepository
+}
+
+func NewIdempotencyKeyHandler(repository repositories.IdempotencyKeyRepository) (*IdempotencyKeyHandler, error) {
+	config, err := newIdempotencyKeyConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewIdempotencyKeyHandlerWithConfig(repository, config)
+}
+
+func NewIdempotencyKeyHandlerWithConfig(
+	repository repositories.IdempotencyKeyRepository,
+	config *IdempotencyKeyConfig) (*IdempotencyKeyHandler, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &IdempotencyKeyHandler{config: config, repository: repository}, nil
+}
+
+// Middleware stores the response of keyed requests and replays it for retries. A key reused with a
+// different request is rejected with 422, and a retry arriving while the original request is still
+// running gets 409 until the claim times out. Server errors and panics are not stored, so the client may
+// retry them with the same key.
+func (h *IdempotencyKeyHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := RequestFingerprint(r.Method, r.URL.Path, body)
+
+		existing, err := h.find(key)
+		if err == nil && existing == nil {
+			var created *entities.IdempotencyKey
+			created, err = h.repository.AddIdempotencyKey(&entities.IdempotencyKey{
+				Key:                key,
+				RequestFingerprint: fingerprint,
+				CreatedAt:          time.Now(),
+			})
+			if err == nil {
+				h.serve(next, created, w, r)
+				return
+			}
+
+			// Another request may have claimed the key concurrently
+			if concurrent, findErr := h.repository.FindIdempotencyKey(key); findErr == nil && concurrent != nil {
+				existing, err = concurrent, nil
+			}
+		}
+		if err != nil {
+			log.Printf("Failed to claim idempotency key %q: %v", key, err)
+			http.Error(w, "Error processing request", http.StatusInternalServerError)
+			return
+		}
+
+		switch {
+		case existing.RequestFingerprint != fingerprint:
+			http.Error(w, IdempotencyKeyHeader+" was already used with a different request", http.StatusUnprocessableEntity)
+		case !existing.IsCompleted():
+			http.Error(w, "A request with this "+IdempotencyKeyHeader+" is still being processed", http.StatusConflict)
+		default:
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			if existing.ResponseContentType != "" {
+				w.Header().Set("Content-Type", existing.ResponseContentType)
+			}
+			w.WriteHeader(existing.ResponseStatus)
+			w.Write(existing.ResponseBody)
+		}
+	})
+}
+
+// serve runs the request under the claimed key and stores its response, releasing the key when the
+// request panics.
+func (h *IdempotencyKeyHandler) serve(next http.Handler, claimed *entities.IdempotencyKey, w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			h.release(claimed)
+			panic(recovered)
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	next.ServeHTTP(recorder, r)
+	h.store(claimed, recorder)
+}
+
+// find returns the stored key, discarding it once it is older than the configured TTL, or once its
+// request has run longer than the claim timeout without completing.
+func (h *IdempotencyKeyHandler) find(key string) (*entities.IdempotencyKey, error) {
+	existing, err := h.repository.FindIdempotencyKey(key)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if existing.IsExpired(h.config.TTL, now) || existing.IsAbandoned(h.config.ClaimTimeout, now) {
+		if err := h.repository.DeleteIdempotencyKey(existing); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return existing, nil
+}
+
+func (h *IdempotencyKeyHandler) store(idempotencyKey *entities.IdempotencyKey, recorder *responseRecorder) {
+	if recorder.status >= http.StatusInternalServerError {
+		h.release(idempotencyKey)
+		return
+	}
+
+	idempotencyKey.Complete(recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+	if err := h.repository.UpdateIdempotencyKey(idempotencyKey); err != nil {
+		log.Printf("Failed to store response for idempotency key %q: %v", idempotencyKey.Key, err)
+	}
+}
+
+// release deletes the claim of a request that failed, so that the client may retry it with the same key.
+func (h *IdempotencyKeyHandler) release(idempotencyKey *entities.IdempotencyKey) {
+	if err := h.repository.DeleteIdempotencyKey(idempotencyKey); err != nil {
+		log.Printf("Failed to release idempotency key %q: %v", idempotencyKey.Key, err)
+	}
+}
+
+// RequestFingerprint identifies the request a key was first used with.
+func RequestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder captures the response while writing it through to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(body []byte) (int, error) {
+	r.body.Write(body)
+	return r.ResponseWriter.Write(body)
+}
@@ -0,0 +1,391 @@
+package middleware_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const idempotentRequestBody = `{"orderId":1,"total":100.5,"type":"QRCode"}`
+
+type IdempotencyKeyHandlerTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockIdempotencyKeyRepository
+	handler        http.Handler
+	calls          int
+	status         int
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockIdempotencyKeyRepository(suite.T())
+	idempotencyKeyHandler, err := middleware.NewIdempotencyKeyHandlerWithConfig(
+		suite.mockRepository,
+		&middleware.IdempotencyKeyConfig{TTL: time.Hour, ClaimTimeout: time.Minute})
+	suite.Require().NoError(err)
+
+	suite.calls = 0
+	suite.status = http.StatusCreated
+	suite.handler = idempotencyKeyHandler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(suite.status)
+		w.Write([]byte(`"qr-data"`))
+	}))
+}
+
+func TestIdempotencyKeyHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyKeyHandlerTestSuite))
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) newRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/payment", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) completedKey(key, body string, createdAt time.Time) *entities.IdempotencyKey {
+	completedAt := createdAt
+	return &entities.IdempotencyKey{
+		ID:                  1,
+		Key:                 key,
+		RequestFingerprint:  middleware.RequestFingerprint(http.MethodPost, "/v1/payment", []byte(body)),
+		CreatedAt:           createdAt,
+		CompletedAt:         &completedAt,
+		ResponseStatus:      http.StatusCreated,
+		ResponseContentType: "application/json",
+		ResponseBody:        []byte(`"stored-qr-data"`),
+	}
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithoutKey_ShouldPassThrough() {
+	// GIVEN a request without Idempotency-Key
+	rec := httptest.NewRecorder()
+
+	// WHEN handling it
+	suite.handler.ServeHTTP(rec, suite.newRequest("", idempotentRequestBody))
+
+	// THEN the handler should run without touching the store
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	assert.Equal(suite.T(), 1, suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithNewKey_ShouldStoreResponse() {
+	// GIVEN a key that was never used
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(nil, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddIdempotencyKey(mock.MatchedBy(func(k *entities.IdempotencyKey) bool {
+			return k.Key == "key-1" && !k.IsCompleted() &&
+				k.RequestFingerprint == middleware.RequestFingerprint(http.MethodPost, "/v1/payment", []byte(idempotentRequestBody))
+		})).
+		RunAndReturn(func(k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+			k.ID = 1
+			return k, nil
+		}).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdateIdempotencyKey(mock.MatchedBy(func(k *entities.IdempotencyKey) bool {
+			return k.IsCompleted() &&
+				k.ResponseStatus == http.StatusCreated &&
+				k.ResponseContentType == "application/json" &&
+				bytes.Equal(k.ResponseBody, []byte(`"qr-data"`))
+		})).
+		Return(nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the request
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN the handler should run and its response be stored
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	assert.Equal(suite.T(), `"qr-data"`, rec.Body.String())
+	assert.Equal(suite.T(), 1, suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithCompletedKey_ShouldReplayResponse() {
+	// GIVEN a key whose request already completed
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(suite.completedKey("key-1", idempotentRequestBody, time.Now()), nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN the same request is retried
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN the stored response should be replayed without running the handler
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	assert.Equal(suite.T(), `"stored-qr-data"`, rec.Body.String())
+	assert.Equal(suite.T(), "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), "true", rec.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Zero(suite.T(), suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithDifferentBody_ShouldReturn422() {
+	// GIVEN a key used with another request body
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(suite.completedKey("key-1", idempotentRequestBody, time.Now()), nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN the key is reused with a different body
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", `{"orderId":2,"total":10,"type":"QRCode"}`))
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+	assert.Zero(suite.T(), suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithRequestInProgress_ShouldReturn409() {
+	// GIVEN a key whose original request has not finished
+	inProgress := suite.completedKey("key-1", idempotentRequestBody, time.Now())
+	inProgress.CompletedAt = nil
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(inProgress, nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN the client retries concurrently
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN it should be told to wait
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	assert.Zero(suite.T(), suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithAbandonedClaim_ShouldRunAgain() {
+	// GIVEN a key whose original request never finished, claimed longer ago than the claim timeout
+	abandoned := suite.completedKey("key-1", idempotentRequestBody, time.Now().Add(-2*time.Minute))
+	abandoned.CompletedAt = nil
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(abandoned, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		DeleteIdempotencyKey(abandoned).
+		Return(nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddIdempotencyKey(mock.Anything).
+		RunAndReturn(func(k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+			return k, nil
+		}).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdateIdempotencyKey(mock.Anything).
+		Return(nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN the client retries
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN the request should be handled as new
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	assert.Equal(suite.T(), 1, suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithConcurrentClaim_ShouldReturn409() {
+	// GIVEN a key claimed by another request between lookup and insert
+	inProgress := suite.completedKey("key-1", idempotentRequestBody, time.Now())
+	inProgress.CompletedAt = nil
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(nil, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddIdempotencyKey(mock.Anything).
+		Return(nil, errors.New("duplicate key value violates unique constraint")).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(inProgress, nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the request
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN it should not run twice
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+	assert.Zero(suite.T(), suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithExpiredKey_ShouldRunAgain() {
+	// GIVEN a key older than the TTL
+	expired := suite.completedKey("key-1", idempotentRequestBody, time.Now().Add(-2*time.Hour))
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(expired, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		DeleteIdempotencyKey(expired).
+		Return(nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddIdempotencyKey(mock.Anything).
+		RunAndReturn(func(k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+			return k, nil
+		}).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdateIdempotencyKey(mock.Anything).
+		Return(nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN the key is used again
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN the request should be handled as new
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code)
+	assert.Equal(suite.T(), 1, suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithServerError_ShouldReleaseKey() {
+	// GIVEN a handler that fails
+	suite.status = http.StatusInternalServerError
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(nil, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddIdempotencyKey(mock.Anything).
+		RunAndReturn(func(k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+			return k, nil
+		}).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		DeleteIdempotencyKey(mock.MatchedBy(func(k *entities.IdempotencyKey) bool {
+			return k.Key == "key-1"
+		})).
+		Return(nil).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the request
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN the error should be returned and the key released for a retry
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithPanic_ShouldReleaseKey() {
+	// GIVEN a handler that panics
+	handler, err := middleware.NewIdempotencyKeyHandlerWithConfig(
+		suite.mockRepository,
+		&middleware.IdempotencyKeyConfig{TTL: time.Hour, ClaimTimeout: time.Minute})
+	suite.Require().NoError(err)
+	panicking := handler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("unexpected")
+	}))
+
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(nil, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddIdempotencyKey(mock.Anything).
+		RunAndReturn(func(k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+			return k, nil
+		}).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		DeleteIdempotencyKey(mock.MatchedBy(func(k *entities.IdempotencyKey) bool {
+			return k.Key == "key-1"
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN handling the request
+	serve := func() { panicking.ServeHTTP(httptest.NewRecorder(), suite.newRequest("key-1", idempotentRequestBody)) }
+
+	// THEN the panic should go on to the recoverer and the key be released for a retry
+	assert.PanicsWithValue(suite.T(), "unexpected", serve)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithStoreError_ShouldReturn500() {
+	// GIVEN a store that cannot be read
+	suite.mockRepository.EXPECT().
+		FindIdempotencyKey("key-1").
+		Return(nil, errors.New("database error")).
+		Once()
+
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the request
+	suite.handler.ServeHTTP(rec, suite.newRequest("key-1", idempotentRequestBody))
+
+	// THEN it should fail without running the handler
+	assert.Equal(suite.T(), http.StatusInternalServerError, rec.Code)
+	assert.Zero(suite.T(), suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_Middleware_WithOversizedKey_ShouldReturn400() {
+	// GIVEN a key longer than allowed
+	rec := httptest.NewRecorder()
+
+	// WHEN handling the request
+	suite.handler.ServeHTTP(rec, suite.newRequest(strings.Repeat("k", 256), idempotentRequestBody))
+
+	// THEN it should be rejected
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+	assert.Zero(suite.T(), suite.calls)
+}
+
+func (suite *IdempotencyKeyHandlerTestSuite) Test_NewIdempotencyKeyHandler_WithInvalidTTL_ShouldFail() {
+	// GIVEN an unparsable TTL
+	suite.T().Setenv("IDEMPOTENCY_KEY_TTL", "tomorrow")
+
+	// WHEN creating the handler
+	handler, err := middleware.NewIdempotencyKeyHandler(suite.mockRepository)
+
+	// THEN it should fail
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), handler)
+}
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+)
+
+const (
+	signatureHeader = "x-signature"
+	requestIdHeader = "x-request-id"
+
+	SignatureModeEnforce = "enforce"
+	SignatureModeLogOnly = "log-only"
+)
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// webhookSignatureResults counts verification outcomes, exposed through /debug/vars.
+	webhookSignatureResults = expvar.NewMap("mercado_pago_webhook_signature_results")
+)
+
+type MercadoPagoSignatureConfig struct {
+	Secret string
+	Mode   string
+	// Tolerance is how old a signed notification may be, to stop replays; zero accepts any age.
+	Tolerance time.Duration
+}
+
+func (c *MercadoPagoSignatureConfig) Validate() error {
+	if c.Mode != SignatureModeEnforce && c.Mode != SignatureModeLogOnly {
+		return fmt.Errorf("invalid MercadoPagoSignatureConfig: unknown mode %q", c.Mode)
+	}
+	if c.Mode == SignatureModeEnforce && c.Secret == "" {
+		return fmt.Errorf("invalid MercadoPagoSignatureConfig: secret must be set in %s mode", SignatureModeEnforce)
+	}
+	if c.Tolerance < 0 {
+		return fmt.Errorf("invalid MercadoPagoSignatureConfig: tolerance must not be negative")
+	}
+	return nil
+}
+
+func newMercadoPagoSignatureConfig() (*MercadoPagoSignatureConfig, error) {
+	mode := os.Getenv("MERCADO_PAGO_WEBHOOK_SIGNATURE_MODE")
+	if mode == "" {
+		mode = SignatureModeEnforce
+	}
+
+	tolerance := 5 * time.Minute
+	if value := os.Getenv("MERCADO_PAGO_WEBHOOK_TOLERANCE"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MERCADO_PAGO_WEBHOOK_TOLERANCE: %w", err)
+		}
+		tolerance = parsed
+	}
+	return &MercadoPagoSignatureConfig{
+		Secret:    os.Getenv("MERCADO_PAGO_WEBHOOK_SECRET"),
+		Mode:      mode,
+		Tolerance: tolerance,
+	}, nil
+}
+
+// MercadoPagoSignatureVerifier checks the x-signature header Mercado Pago attaches to webhook notifications.
+type MercadoPagoSignatureVerifier struct {
+	config *MercadoPagoSignatureConfig
+	now    func() time.Time
+}
+
+func NewMercadoPagoSignatureVerifier() (*MercadoPagoSignatureVerifier, error) {
+	config, err := newMercadoPagoSignatureConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewMercadoPagoSignatureVerifierWithConfig(config)
+}
+
+func NewMercadoPagoSignatureVerifierWithConfig(config *MercadoPagoSignatureConfig) (*MercadoPagoSignatureVerifier, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &MercadoPagoSignatureVerifier{config: config, now: time.Now}, nil
+}
+
+// Middleware rejects requests that fail verification with 401, unless running in log-only mode.
+func (v *MercadoPagoSignatureVerifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.Verify(r)
+		switch {
+		case err == nil:
+			webhookSignatureResults.Add("valid", 1)
+		case errors.Is(err, ErrMissingSignature):
+			webhookSignatureResults.Add("missing", 1)
+		case errors.Is(err, ErrExpiredSignature):
+			webhookSignatureResults.Add("expired", 1)
+		default:
+			webhookSignatureResults.Add("invalid", 1)
+		}
+
+		if err != nil {
+			if v.config.Mode == SignatureModeLogOnly {
+				log.Printf("WARN: accepting webhook with failed signature check (log-only mode): %v", err)
+			} else {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Verify validates the request against the manifest "id:<data.id>;request-id:<x-request-id>;ts:<ts>;"
+// signed with HMAC-SHA256, as documented by Mercado Pago. Only data.id is signed, so the resource in
+// the body, which is what gets processed, must be that same id. The body is restored for the handler.
+func (v *MercadoPagoSignatureVerifier) Verify(r *http.Request) error {
+	signature := r.Header.Get(signatureHeader)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	ts, hash := parseSignatureHeader(signature)
+	if ts == "" || hash == "" {
+		return fmt.Errorf("%w: malformed %s header", ErrInvalidSignature, signatureHeader)
+	}
+
+	dataId := r.URL.Query().Get("data.id")
+	expected := Sign(v.config.Secret, dataId, r.Header.Get(requestIdHeader), ts)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(hash))) {
+		return ErrInvalidSignature
+	}
+
+	if v.config.Tolerance > 0 {
+		signedAt, err := parseSignatureTimestamp(ts)
+		if err != nil {
+			return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+		}
+		if age := v.now().Sub(signedAt); age > v.config.Tolerance || -age > v.config.Tolerance {
+			return ErrExpiredSignature
+		}
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read body: %v", ErrInvalidSignature, err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(payload))
+
+	var notification dto.MercadoPagoWebhookNotificationRequestDTO
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		// Nothing can be processed from it, the handler rejects it
+		return nil
+	}
+	if resourceId := notificationResourceId(notification); !strings.EqualFold(resourceId, dataId) {
+		return fmt.Errorf("%w: notification is about %q, but data.id %q was signed", ErrInvalidSignature, resourceId, dataId)
+	}
+
+	return nil
+}
+
+// notificationResourceId is the id of the resource a notification is processed for: the last segment
+// of its resource, which is either a bare id or a URL, or its id when it has no resource.
+func notificationResourceId(notification dto.MercadoPagoWebhookNotificationRequestDTO) string {
+	resource := strings.TrimRight(notification.Resource, "/")
+	if resource == "" {
+		return notification.Id
+	}
+	return resource[strings.LastIndex(resource, "/")+1:]
+}
+
+// parseSignatureTimestamp reads the ts of the signature, which Mercado Pago sends in milliseconds;
+// values in seconds are accepted too.
+func parseSignatureTimestamp(ts string) (time.Time, error) {
+	value, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if value < 1e12 {
+		return time.Unix(value, 0), nil
+	}
+	return time.UnixMilli(value), nil
+}
+
+// Sign computes the hex encoded HMAC-SHA256 of the signature manifest. Parts with empty values are
+// left out of the manifest, mirroring what Mercado Pago does.
+func Sign(secret, dataId, requestId, ts string) string {
+	var manifest strings.Builder
+	if dataId != "" {
+		manifest.WriteString("id:" + strings.ToLower(dataId) + ";")
+	}
+	if requestId != "" {
+		manifest.WriteString("request-id:" + requestId + ";")
+	}
+	manifest.WriteString("ts:" + ts + ";")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseSignatureHeader(header string) (ts string, hash string) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "ts":
+			ts = value
+		case "v1":
+			hash = value
+		}
+	}
+	return ts, hash
+}
@@ -0,0 +1,205 @@
+package middleware_test
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MercadoPagoSignatureVerifierTestSuite struct {
+	suite.Suite
+	verifier *middleware.MercadoPagoSignatureVerifier
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) SetupTest() {
+	verifier, err := middleware.NewMercadoPagoSignatureVerifierWithConfig(&middleware.MercadoPagoSignatureConfig{
+		Secret: "secret",
+		Mode:   middleware.SignatureModeEnforce,
+	})
+	suite.Require().NoError(err)
+	suite.verifier = verifier
+}
+
+func TestMercadoPagoSignatureVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(MercadoPagoSignatureVerifierTestSuite))
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithValidSignature_ShouldSucceed() {
+	// GIVEN a request signed with the configured secret
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=ABC123&type=payment", nil)
+	req.Header.Set("x-request-id", "req-1")
+	req.Header.Set("x-signature", "ts=1704908010,v1="+middleware.Sign("secret", "abc123", "req-1", "1704908010"))
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be accepted
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithWrongSecret_ShouldFail() {
+	// GIVEN a request signed with another secret
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=123", nil)
+	req.Header.Set("x-request-id", "req-1")
+	req.Header.Set("x-signature", "ts=1704908010,v1="+middleware.Sign("other", "123", "req-1", "1704908010"))
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, middleware.ErrInvalidSignature)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithMissingHeader_ShouldFail() {
+	// GIVEN an unsigned request
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", nil)
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, middleware.ErrMissingSignature)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithMalformedHeader_ShouldFail() {
+	// GIVEN a signature header without a hash
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", nil)
+	req.Header.Set("x-signature", "ts=1704908010")
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, middleware.ErrInvalidSignature)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithBodyForSignedResource_ShouldSucceed() {
+	// GIVEN a merchant order notification whose resource URL ends with the signed data.id
+	body := `{"id":"55","topic":"merchant_order","resource":"https://api.mercadolibre.com/merchant_orders/123"}`
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=123", strings.NewReader(body))
+	req.Header.Set("x-request-id", "req-1")
+	req.Header.Set("x-signature", "ts=1704908010,v1="+middleware.Sign("secret", "123", "req-1", "1704908010"))
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be accepted
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithBodyForOtherResource_ShouldFail() {
+	// GIVEN a validly signed request whose body is about another payment
+	body := `{"id":"55","topic":"payment","resource":"456"}`
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=123", strings.NewReader(body))
+	req.Header.Set("x-request-id", "req-1")
+	req.Header.Set("x-signature", "ts=1704908010,v1="+middleware.Sign("secret", "123", "req-1", "1704908010"))
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, middleware.ErrInvalidSignature)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithinTolerance_ShouldSucceed() {
+	// GIVEN a verifier with a tolerance and a request signed a minute ago, in milliseconds
+	verifier := suite.verifierWithTolerance(5 * time.Minute)
+	ts := strconv.FormatInt(time.Now().Add(-time.Minute).UnixMilli(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=123", nil)
+	req.Header.Set("x-request-id", "req-1")
+	req.Header.Set("x-signature", "ts="+ts+",v1="+middleware.Sign("secret", "123", "req-1", ts))
+
+	// WHEN verifying it
+	err := verifier.Verify(req)
+
+	// THEN it should be accepted
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Verify_WithExpiredTimestamp_ShouldFail() {
+	// GIVEN a verifier with a tolerance and a request signed an hour ago
+	verifier := suite.verifierWithTolerance(5 * time.Minute)
+	ts := strconv.FormatInt(time.Now().Add(-time.Hour).UnixMilli(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify?data.id=123", nil)
+	req.Header.Set("x-request-id", "req-1")
+	req.Header.Set("x-signature", "ts="+ts+",v1="+middleware.Sign("secret", "123", "req-1", ts))
+
+	// WHEN verifying it
+	err := verifier.Verify(req)
+
+	// THEN it should be rejected as a replay
+	assert.ErrorIs(suite.T(), err, middleware.ErrExpiredSignature)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_Middleware_WithInvalidSignature_ShouldCountAndReturn401() {
+	// GIVEN an unsigned request
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/notify", nil)
+	rec := httptest.NewRecorder()
+	counters := expvar.Get("mercado_pago_webhook_signature_results").(*expvar.Map)
+	before := counterValue(counters, "missing")
+
+	handler := suite.verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Fail("next handler should not be called")
+	}))
+
+	// WHEN passing through the middleware
+	handler.ServeHTTP(rec, req)
+
+	// THEN it should be rejected and counted
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	assert.Equal(suite.T(), before+1, counterValue(counters, "missing"))
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_NewMercadoPagoSignatureVerifier_WithoutSecret_ShouldReturnError() {
+	// GIVEN enforce mode without a secret
+	os.Unsetenv("MERCADO_PAGO_WEBHOOK_SECRET")
+	os.Unsetenv("MERCADO_PAGO_WEBHOOK_SIGNATURE_MODE")
+
+	// WHEN creating the verifier
+	verifier, err := middleware.NewMercadoPagoSignatureVerifier()
+
+	// THEN error should be returned
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), verifier)
+	assert.Contains(suite.T(), err.Error(), "invalid MercadoPagoSignatureConfig")
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) Test_NewMercadoPagoSignatureVerifier_WithLogOnlyMode_ShouldNotRequireSecret() {
+	// GIVEN log-only mode without a secret
+	os.Unsetenv("MERCADO_PAGO_WEBHOOK_SECRET")
+	os.Setenv("MERCADO_PAGO_WEBHOOK_SIGNATURE_MODE", middleware.SignatureModeLogOnly)
+	defer os.Unsetenv("MERCADO_PAGO_WEBHOOK_SIGNATURE_MODE")
+
+	// WHEN creating the verifier
+	verifier, err := middleware.NewMercadoPagoSignatureVerifier()
+
+	// THEN it should be created
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), verifier)
+}
+
+func (suite *MercadoPagoSignatureVerifierTestSuite) verifierWithTolerance(tolerance time.Duration) *middleware.MercadoPagoSignatureVerifier {
+	verifier, err := middleware.NewMercadoPagoSignatureVerifierWithConfig(&middleware.MercadoPagoSignatureConfig{
+		Secret:    "secret",
+		Mode:      middleware.SignatureModeEnforce,
+		Tolerance: tolerance,
+	})
+	suite.Require().NoError(err)
+	return verifier
+}
+
+func counterValue(counters *expvar.Map, key string) int64 {
+	if v, ok := counters.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const stripeSignatureHeader = "Stripe-Signature"
+
+var (
+	ErrExpiredSignature = errors.New("expired webhook signature")
+
+	// stripeWebhookSignatureResults counts verification outcomes, exposed through /debug/vars.
+	stripeWebhookSignatureResults = expvar.NewMap("stripe_webhook_signature_results")
+)
+
+type StripeSignatureConfig struct {
+	// Secret is the signing secret of the webhook endpoint. Without one every event fails
+	// verification, which is harmless while card payments are disabled.
+	Secret string
+	Mode   string
+	// Tolerance is how old a signed event may be, to stop replays; zero accepts any age.
+	Tolerance time.Duration
+}
+
+func (c *StripeSignatureConfig) Validate() error {
+	if c.Mode != SignatureModeEnforce && c.Mode != SignatureModeLogOnly {
+		return fmt.Errorf("invalid StripeSignatureConfig: unknown mode %q", c.Mode)
+	}
+	if c.Tolerance < 0 {
+		return fmt.Errorf("invalid StripeSignatureConfig: tolerance must not be negative")
+	}
+	return nil
+}
+
+func newStripeSignatureConfig() (*StripeSignatureConfig, error) {
+	mode := os.Getenv("STRIPE_WEBHOOK_SIGNATURE_MODE")
+	if mode == "" {
+		mode = SignatureModeEnforce
+	}
+
+	tolerance := 5 * time.Minute
+	if value := os.Getenv("STRIPE_WEBHOOK_TOLERANCE"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid STRIPE_WEBHOOK_TOLERANCE: %w", err)
+		}
+		tolerance = parsed
+	}
+	return &StripeSignatureConfig{
+		Secret:    os.Getenv("STRIPE_WEBHOOK_SECRET"),
+		Mode:      mode,
+		Tolerance: tolerance,
+	}, nil
+}
+
+// StripeSignatureVerifier checks the Stripe-Signature header Stripe attaches to webhook events.
+type StripeSignatureVerifier struct {
+	config *StripeSignatureConfig
+	now    func() time.Time
+}
+
+func NewStripeSignatureVerifier() (*StripeSignatureVerifier, error) {
+	config, err := newStripeSignatureConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewStripeSignatureVerifierWithConfig(config)
+}
+
+func NewStripeSignatureVerifierWithConfig(config *StripeSignatureConfig) (*StripeSignatureVerifier, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &StripeSignatureVerifier{config: config, now: time.Now}, nil
+}
+
+// Middleware rejects requests that fail verification with 401, unless running in log-only mode.
+func (v *StripeSignatureVerifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.Verify(r)
+		switch {
+		case err == nil:
+			stripeWebhookSignatureResults.Add("valid", 1)
+		case errors.Is(err, ErrMissingSignature):
+			stripeWebhookSignatureResults.Add("missing", 1)
+		case errors.Is(err, ErrExpiredSignature):
+			stripeWebhookSignatureResults.Add("expired", 1)
+		default:
+			stripeWebhookSignatureResults.Add("invalid", 1)
+		}
+
+		if err != nil {
+			if v.config.Mode == SignatureModeLogOnly {
+				log.Printf("WARN: accepting Stripe event with failed signature check (log-only mode): %v", err)
+			} else {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Verify validates the request against "<t>.<body>" signed with HMAC-SHA256, as documented by
+// Stripe. The body is restored for the handler.
+func (v *StripeSignatureVerifier) Verify(r *http.Request) error {
+	header := r.Header.Get(stripeSignatureHeader)
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	ts, signatures := parseStripeSignatureHeader(header)
+	if ts == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed %s header", ErrInvalidSignature, stripeSignatureHeader)
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read body: %v", ErrInvalidSignature, err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(payload))
+
+	if v.config.Secret == "" {
+		return fmt.Errorf("%w: no webhook secret configured", ErrInvalidSignature)
+	}
+
+	expected := SignStripePayload(v.config.Secret, ts, payload)
+	valid := false
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if v.config.Tolerance > 0 {
+		seconds, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+		}
+		if age := v.now().Sub(time.Unix(seconds, 0)); age > v.config.Tolerance || -age > v.config.Tolerance {
+			return ErrExpiredSignature
+		}
+	}
+
+	return nil
+}
+
+// SignStripePayload computes the hex encoded HMAC-SHA256 Stripe puts in the v1 part of the header,
+// ts being the unix time in seconds.
+func SignStripePayload(secret, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseStripeSignatureHeader returns the timestamp and every v1 signature, as Stripe sends one per
+// active secret while a secret is being rolled.
+func parseStripeSignatureHeader(header string) (ts string, signatures []string) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	return ts, signatures
+}
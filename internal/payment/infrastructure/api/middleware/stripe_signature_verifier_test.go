@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const stripeEvent = `{"id":"evt_1","type":"payment_intent.succeeded"}`
+
+type StripeSignatureVerifierTestSuite struct {
+	suite.Suite
+	verifier *middleware.StripeSignatureVerifier
+}
+
+func (suite *StripeSignatureVerifierTestSuite) SetupTest() {
+	verifier, err := middleware.NewStripeSignatureVerifierWithConfig(&middleware.StripeSignatureConfig{
+		Secret:    "whsec_test",
+		Mode:      middleware.SignatureModeEnforce,
+		Tolerance: 5 * time.Minute,
+	})
+	suite.Require().NoError(err)
+	suite.verifier = verifier
+}
+
+func TestStripeSignatureVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(StripeSignatureVerifierTestSuite))
+}
+
+func newStripeEventRequest(secret string, signedAt time.Time) *http.Request {
+	ts := strconv.FormatInt(signedAt.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", strings.NewReader(stripeEvent))
+	req.Header.Set("Stripe-Signature", "t="+ts+",v1="+middleware.SignStripePayload(secret, ts, []byte(stripeEvent)))
+	return req
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_Verify_WithValidSignature_ShouldSucceedAndKeepBody() {
+	// GIVEN an event signed with the configured secret
+	req := newStripeEventRequest("whsec_test", time.Now())
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be accepted and the body still readable
+	assert.NoError(suite.T(), err)
+	body, _ := io.ReadAll(req.Body)
+	assert.Equal(suite.T(), stripeEvent, string(body))
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_Verify_WithRolledSecret_ShouldAcceptAnySignature() {
+	// GIVEN an event signed with an old and the current secret
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", strings.NewReader(stripeEvent))
+	req.Header.Set("Stripe-Signature", "t="+ts+
+		",v1="+middleware.SignStripePayload("whsec_old", ts, []byte(stripeEvent))+
+		",v1="+middleware.SignStripePayload("whsec_test", ts, []byte(stripeEvent)))
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be accepted
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_Verify_WithWrongSecret_ShouldFail() {
+	// GIVEN an event signed with another secret
+	req := newStripeEventRequest("whsec_other", time.Now())
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, middleware.ErrInvalidSignature)
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_Verify_WithOldTimestamp_ShouldFail() {
+	// GIVEN an event signed outside the tolerance
+	req := newStripeEventRequest("whsec_test", time.Now().Add(-time.Hour))
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected as a replay
+	assert.ErrorIs(suite.T(), err, middleware.ErrExpiredSignature)
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_Verify_WithMalformedHeader_ShouldFail() {
+	// GIVEN a signature header without a signature
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", strings.NewReader(stripeEvent))
+	req.Header.Set("Stripe-Signature", "t=1704908010")
+
+	// WHEN verifying it
+	err := suite.verifier.Verify(req)
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, middleware.ErrInvalidSignature)
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_Middleware_WithMissingSignature_ShouldCountAndReturn401() {
+	// GIVEN an unsigned event
+	req := httptest.NewRequest(http.MethodPost, "/payment/webhooks/stripe", strings.NewReader(stripeEvent))
+	rec := httptest.NewRecorder()
+	counters := expvar.Get("stripe_webhook_signature_results").(*expvar.Map)
+	before := counterValue(counters, "missing")
+
+	handler := suite.verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Fail("next handler should not be called")
+	}))
+
+	// WHEN passing through the middleware
+	handler.ServeHTTP(rec, req)
+
+	// THEN it should be rejected and counted
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	assert.Equal(suite.T(), before+1, counterValue(counters, "missing"))
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_NewStripeSignatureVerifier_WithoutSecret_ShouldRejectEvents() {
+	// GIVEN no webhook secret
+	suite.T().Setenv("STRIPE_WEBHOOK_SECRET", "")
+	suite.T().Setenv("STRIPE_WEBHOOK_SIGNATURE_MODE", "")
+	suite.T().Setenv("STRIPE_WEBHOOK_TOLERANCE", "")
+
+	// WHEN creating the verifier
+	verifier, err := middleware.NewStripeSignatureVerifier()
+
+	// THEN it should be created but reject every event
+	assert.NoError(suite.T(), err)
+	assert.ErrorIs(suite.T(), verifier.Verify(newStripeEventRequest("", time.Now())), middleware.ErrInvalidSignature)
+}
+
+func (suite *StripeSignatureVerifierTestSuite) Test_NewStripeSignatureVerifier_WithInvalidTolerance_ShouldReturnError() {
+	// GIVEN an unparseable tolerance
+	suite.T().Setenv("STRIPE_WEBHOOK_TOLERANCE", "soon")
+
+	// WHEN creating the verifier
+	verifier, err := middleware.NewStripeSignatureVerifier()
+
+	// THEN error should be returned
+	assert.ErrorContains(suite.T(), err, "STRIPE_WEBHOOK_TOLERANCE")
+	assert.Nil(suite.T(), verifier)
+}
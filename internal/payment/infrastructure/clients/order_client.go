@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	"github.com/abattassini/tc-fiap-payment/pkg/rest"
 )
@@ -20,17 +22,39 @@ type OrderClient interface {
 type OrderClientImpl struct {
 	httpClient rest.HTTPClient
 	baseURL    string
+	// statusCodes maps the order statuses we send to the codes of the Order Service, which are configured
+	// since they belong to its contract. A status without a code is not sent.
+	statusCodes map[int]int
 }
 
-func NewOrderClient(httpClient rest.HTTPClient) *OrderClientImpl {
+func NewOrderClient(httpClient rest.HTTPClient) (*OrderClientImpl, error) {
 	baseURL := os.Getenv("ORDER_SERVICE_URL")
 	if baseURL == "" {
 		baseURL = "http://localhost:8081"
 	}
-	return &OrderClientImpl{
-		httpClient: httpClient,
-		baseURL:    baseURL,
+
+	// Preparing has always been sent as 2; there is no default for cancelled
+	statusCodes := map[int]int{entities.OrderStatusPreparing: 2}
+	for status, name := range map[int]string{
+		entities.OrderStatusPreparing: "ORDER_STATUS_PREPARING",
+		entities.OrderStatusCancelled: "ORDER_STATUS_CANCELLED",
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		code, err := strconv.Atoi(value)
+		if err != nil || code < 0 {
+			return nil, fmt.Errorf("invalid %s: %q", name, value)
+		}
+		statusCodes[status] = code
 	}
+
+	return &OrderClientImpl{
+		httpClient:  httpClient,
+		baseURL:     baseURL,
+		statusCodes: statusCodes,
+	}, nil
 }
 
 func (c *OrderClientImpl) GetOrder(orderId uint) (*dto.OrderResponseDto, error) {
@@ -61,10 +85,14 @@ func (c *OrderClientImpl) GetOrder(orderId uint) (*dto.OrderResponseDto, error)
 }
 
 func (c *OrderClientImpl) UpdateOrderStatus(orderId uint, status int) error {
+	code, ok := c.statusCodes[status]
+	if !ok {
+		return fmt.Errorf("failed to update order status: no Order Service code is configured for order status %d", status)
+	}
 	url := fmt.Sprintf("%s/v1/order/%d/status", c.baseURL, orderId)
 
 	// Create request body with status
-	requestBody := map[string]uint{"status": uint(status)}
+	requestBody := map[string]uint{"status": uint(code)}
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
 		return err
@@ -9,8 +9,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/clients"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -38,11 +40,15 @@ type OrderClientTestSuite struct {
 func (suite *OrderClientTestSuite) SetupTest() {
 	suite.mockHTTPClient = new(MockHTTPClient)
 	os.Setenv("ORDER_SERVICE_URL", "http://localhost:8081")
-	suite.client = clients.NewOrderClient(suite.mockHTTPClient)
+	os.Setenv("ORDER_STATUS_CANCELLED", "7")
+	client, err := clients.NewOrderClient(suite.mockHTTPClient)
+	suite.Require().NoError(err)
+	suite.client = client
 }
 
 func (suite *OrderClientTestSuite) TearDownTest() {
 	os.Unsetenv("ORDER_SERVICE_URL")
+	os.Unsetenv("ORDER_STATUS_CANCELLED")
 }
 
 func TestOrderClientTestSuite(t *testing.T) {
@@ -54,12 +60,12 @@ func (suite *OrderClientTestSuite) Test_GetOrder_WithValidOrderId_ShouldReturnOr
 	orderId := uint(1)
 	expectedOrder := &dto.OrderResponseDto{
 		ID:          orderId,
-		TotalAmount: 100.50,
+		TotalAmount: money.MustParse("100.50"),
 		Products: []*dto.OrderProductDto{
 			{
 				ProductId: 1,
 				Name:      "Product Test",
-				Price:     50.25,
+				Price:     money.MustParse("50.25"),
 				Quantity:  2,
 			},
 		},
@@ -80,7 +86,7 @@ func (suite *OrderClientTestSuite) Test_GetOrder_WithValidOrderId_ShouldReturnOr
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), order)
 	assert.Equal(suite.T(), orderId, order.ID)
-	assert.Equal(suite.T(), float32(100.50), order.TotalAmount)
+	assert.Equal(suite.T(), money.MustParse("100.50"), order.TotalAmount)
 	suite.mockHTTPClient.AssertExpectations(suite.T())
 }
 
@@ -162,6 +168,31 @@ func (suite *OrderClientTestSuite) Test_UpdateOrderStatus_WithValidData_ShouldSu
 	suite.mockHTTPClient.AssertExpectations(suite.T())
 }
 
+func (suite *OrderClientTestSuite) Test_UpdateOrderStatus_ShouldSendOrderServiceStatusCodes() {
+	for status, body := range map[int]string{
+		entities.OrderStatusPreparing: `{"status":2}`,
+		entities.OrderStatusCancelled: `{"status":7}`,
+	} {
+		// GIVEN the Order Service accepting the update
+		var sent []byte
+		suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == http.MethodPut && req.URL.String() == "http://localhost:8081/v1/order/1/status"
+		})).Run(func(args mock.Arguments) {
+			sent, _ = io.ReadAll(args.Get(0).(*http.Request).Body)
+		}).Return(&http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       io.NopCloser(bytes.NewReader(nil)),
+		}, nil).Once()
+
+		// WHEN updating the order status
+		err := suite.client.UpdateOrderStatus(1, status)
+
+		// THEN the configured status code of the Order Service should be sent
+		assert.NoError(suite.T(), err)
+		assert.JSONEq(suite.T(), body, string(sent))
+	}
+}
+
 func (suite *OrderClientTestSuite) Test_UpdateOrderStatus_WithNoContentStatus_ShouldSucceed() {
 	// GIVEN valid order ID and status
 	orderId := uint(1)
@@ -225,8 +256,34 @@ func (suite *OrderClientTestSuite) Test_NewOrderClient_WithoutEnvVar_ShouldUseDe
 	os.Unsetenv("ORDER_SERVICE_URL")
 
 	// WHEN creating client
-	client := clients.NewOrderClient(suite.mockHTTPClient)
+	client, err := clients.NewOrderClient(suite.mockHTTPClient)
 
 	// THEN client should be created with default URL
+	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), client)
 }
+
+func (suite *OrderClientTestSuite) Test_UpdateOrderStatus_WithoutConfiguredCode_ShouldNotSend() {
+	// GIVEN no Order Service code configured for cancelled orders
+	os.Unsetenv("ORDER_STATUS_CANCELLED")
+	client, err := clients.NewOrderClient(suite.mockHTTPClient)
+	suite.Require().NoError(err)
+
+	// WHEN cancelling an order
+	err = client.UpdateOrderStatus(1, entities.OrderStatusCancelled)
+
+	// THEN an error should be returned without calling the Order Service
+	assert.Error(suite.T(), err)
+	suite.mockHTTPClient.AssertNotCalled(suite.T(), "Do", mock.Anything)
+}
+
+func (suite *OrderClientTestSuite) Test_NewOrderClient_WithInvalidStatusCode_ShouldFail() {
+	// GIVEN a status code that is not a number
+	os.Setenv("ORDER_STATUS_CANCELLED", "cancelled")
+
+	// WHEN creating client
+	_, err := clients.NewOrderClient(suite.mockHTTPClient)
+
+	// THEN an error should be returned
+	assert.ErrorContains(suite.T(), err, "ORDER_STATUS_CANCELLED")
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 
 	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/abattassini/tc-fiap-payment/pkg/rest"
 )
 
@@ -108,3 +110,160 @@ func (s *MercadoPagoGatewayImpl) GenerateQRCode(ctx context.Context, request dto
 
 	return s.handleResponse(resp)
 }
+
+func (s *MercadoPagoGatewayImpl) GetPayment(ctx context.Context, paymentId string) (dto.MercadoPagoPaymentResponseDto, error) {
+	endpoint := fmt.Sprintf("%s/v1/payments/%s", s.config.BaseURL, url.PathEscape(paymentId))
+
+	var response dto.MercadoPagoPaymentResponseDto
+	if err := s.get(ctx, endpoint, &response); err != nil {
+		return dto.MercadoPagoPaymentResponseDto{}, fmt.Errorf("failed to get payment %s: %w", paymentId, err)
+	}
+
+	return response, nil
+}
+
+func (s *MercadoPagoGatewayImpl) GetMerchantOrder(ctx context.Context, merchantOrderId string) (dto.MercadoPagoMerchantOrderResponseDto, error) {
+	endpoint := fmt.Sprintf("%s/merchant_orders/%s", s.config.BaseURL, url.PathEscape(merchantOrderId))
+
+	var response dto.MercadoPagoMerchantOrderResponseDto
+	if err := s.get(ctx, endpoint, &response); err != nil {
+		return dto.MercadoPagoMerchantOrderResponseDto{}, fmt.Errorf("failed to get merchant order %s: %w", merchantOrderId, err)
+	}
+
+	return response, nil
+}
+
+func (s *MercadoPagoGatewayImpl) GetInStoreOrder(ctx context.Context) (*dto.MercadoPagoInStoreOrderResponseDto, error) {
+	endpoint := fmt.Sprintf("%s/instore/qr/seller/collectors/%s/pos/%s/orders", s.config.BaseURL, s.config.ClientId, s.config.Pos)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.config.Token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get in-store order, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var response dto.MercadoPagoInStoreOrderResponseDto
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return &response, nil
+}
+
+func (s *MercadoPagoGatewayImpl) DeleteInStoreOrder(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/instore/qr/seller/collectors/%s/pos/%s/orders", s.config.BaseURL, s.config.ClientId, s.config.Pos)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.config.Token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete in-store order, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func (s *MercadoPagoGatewayImpl) GetChargeback(ctx context.Context, chargebackId string) (dto.MercadoPagoChargebackResponseDto, error) {
+	endpoint := fmt.Sprintf("%s/v1/chargebacks/%s", s.config.BaseURL, url.PathEscape(chargebackId))
+
+	var response dto.MercadoPagoChargebackResponseDto
+	if err := s.get(ctx, endpoint, &response); err != nil {
+		return dto.MercadoPagoChargebackResponseDto{}, fmt.Errorf("failed to get chargeback %s: %w", chargebackId, err)
+	}
+
+	return response, nil
+}
+
+func (s *MercadoPagoGatewayImpl) GetClaim(ctx context.Context, claimId string) (dto.MercadoPagoClaimResponseDto, error) {
+	endpoint := fmt.Sprintf("%s/post-purchase/v1/claims/%s", s.config.BaseURL, url.PathEscape(claimId))
+
+	var response dto.MercadoPagoClaimResponseDto
+	if err := s.get(ctx, endpoint, &response); err != nil {
+		return dto.MercadoPagoClaimResponseDto{}, fmt.Errorf("failed to get claim %s: %w", claimId, err)
+	}
+
+	return response, nil
+}
+
+func (s *MercadoPagoGatewayImpl) Refund(ctx context.Context, paymentId string, amount money.Amount, idempotencyKey string) (dto.MercadoPagoRefundResponseDto, error) {
+	endpoint := fmt.Sprintf("%s/v1/payments/%s/refunds", s.config.BaseURL, url.PathEscape(paymentId))
+	payload, err := json.Marshal(dto.MercadoPagoRefundRequestDto{Amount: amount})
+	if err != nil {
+		return dto.MercadoPagoRefundResponseDto{}, fmt.Errorf("failed to marshal request payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(payload))
+	if err != nil {
+		return dto.MercadoPagoRefundResponseDto{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	req.Header.Set("X-Idempotency-Key", idempotencyKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return dto.MercadoPagoRefundResponseDto{}, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return dto.MercadoPagoRefundResponseDto{}, fmt.Errorf("failed to refund payment %s, %w", paymentId, &providerStatusError{statusCode: resp.StatusCode, body: string(body)})
+	}
+
+	var response dto.MercadoPagoRefundResponseDto
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return dto.MercadoPagoRefundResponseDto{}, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return response, nil
+}
+
+func (s *MercadoPagoGatewayImpl) get(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.config.Token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
@@ -10,8 +10,10 @@ import (
 	"os"
 	"testing"
 
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -61,16 +63,16 @@ func (suite *MercadoPagoGatewayTestSuite) Test_GenerateQRCode_WithValidRequest_S
 		Title:             "Test Order",
 		Description:       "Test Description",
 		NotificationURL:   "http://localhost:8082/webhook",
-		TotalAmount:       100.50,
+		TotalAmount:       money.MustParse("100.50"),
 		Items: []dto.Item{
 			{
 				SKUNumber:   "1",
 				Category:    "food",
 				Title:       "Product",
 				Description: "Description",
-				UnitPrice:   50.25,
+				UnitPrice:   money.MustParse("50.25"),
 				Quantity:    2,
-				TotalAmount: 100.50,
+				TotalAmount: money.MustParse("100.50"),
 			},
 		},
 	}
@@ -102,7 +104,7 @@ func (suite *MercadoPagoGatewayTestSuite) Test_GenerateQRCode_WithHTTPError_Shou
 	// GIVEN a QR code request
 	request := dto.CreateQRCodeDTO{
 		ExternalReference: "order-1",
-		TotalAmount:       100.50,
+		TotalAmount:       money.MustParse("100.50"),
 	}
 
 	expectedError := errors.New("connection failed")
@@ -124,7 +126,7 @@ func (suite *MercadoPagoGatewayTestSuite) Test_GenerateQRCode_WithNonCreatedStat
 	// GIVEN a QR code request
 	request := dto.CreateQRCodeDTO{
 		ExternalReference: "order-1",
-		TotalAmount:       100.50,
+		TotalAmount:       money.MustParse("100.50"),
 	}
 
 	response := &http.Response{
@@ -150,7 +152,7 @@ func (suite *MercadoPagoGatewayTestSuite) Test_GenerateQRCode_WithInvalidJSON_Sh
 	// GIVEN a QR code request
 	request := dto.CreateQRCodeDTO{
 		ExternalReference: "order-1",
-		TotalAmount:       100.50,
+		TotalAmount:       money.MustParse("100.50"),
 	}
 
 	response := &http.Response{
@@ -195,3 +197,375 @@ func (suite *MercadoPagoGatewayTestSuite) Test_NewMercadoPagoGatewayImpl_WithVal
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), gateway)
 }
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetPayment_WithValidId_ShouldReturnPayment() {
+	// GIVEN a payment known by Mercado Pago
+	expectedResponse := dto.MercadoPagoPaymentResponseDto{
+		Id:                987,
+		Status:            "approved",
+		ExternalReference: "order-1",
+		TransactionAmount: money.MustParse("100.50"),
+	}
+
+	responseBody, _ := json.Marshal(expectedResponse)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.String() == "https://api.mercadopago.com/v1/payments/987" &&
+			req.Header.Get("Authorization") == "Bearer test_token"
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the payment
+	result, err := gateway.GetPayment(context.Background(), "987")
+
+	// THEN the provider payment should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expectedResponse, result)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetPayment_WithNotFoundStatus_ShouldReturnError() {
+	// GIVEN a payment unknown to Mercado Pago
+	response := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewReader([]byte("not found"))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the payment
+	result, err := gateway.GetPayment(context.Background(), "987")
+
+	// THEN error should be returned
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "failed to get payment 987")
+	assert.Contains(suite.T(), err.Error(), "unexpected status: 404")
+	assert.Empty(suite.T(), result.Status)
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetPayment_WithHTTPError_ShouldReturnError() {
+	// GIVEN a failing HTTP client
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(nil, errors.New("connection failed")).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the payment
+	_, err = gateway.GetPayment(context.Background(), "987")
+
+	// THEN error should be returned
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "HTTP request failed")
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetMerchantOrder_WithValidId_ShouldReturnMerchantOrder() {
+	// GIVEN a merchant order known by Mercado Pago
+	expectedResponse := dto.MercadoPagoMerchantOrderResponseDto{
+		Id:                555,
+		Status:            "closed",
+		OrderStatus:       "paid",
+		ExternalReference: "order-1",
+		Payments: []*dto.MercadoPagoPaymentResponseDto{
+			{Id: 987, Status: "approved", TransactionAmount: money.MustParse("100.50")},
+		},
+	}
+
+	responseBody, _ := json.Marshal(expectedResponse)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.String() == "https://api.mercadopago.com/merchant_orders/555"
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the merchant order
+	result, err := gateway.GetMerchantOrder(context.Background(), "555")
+
+	// THEN the merchant order should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expectedResponse, result)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetMerchantOrder_WithInvalidJSON_ShouldReturnError() {
+	// GIVEN an invalid response body
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte("invalid json"))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the merchant order
+	_, err = gateway.GetMerchantOrder(context.Background(), "555")
+
+	// THEN error should be returned
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "failed to decode response body")
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetInStoreOrder_ShouldReturnPOSOrder() {
+	// GIVEN a point of sale showing a QR order
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"in_store_order_id":"in-store-1","external_reference":"order-1"}`))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.String() == "https://api.mercadopago.com/instore/qr/seller/collectors/client123/pos/pos123/orders" &&
+			req.Header.Get("Authorization") == "Bearer test_token"
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the in-store order
+	order, err := gateway.GetInStoreOrder(context.Background())
+
+	// THEN it should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "in-store-1", order.InStoreOrderId)
+	assert.Equal(suite.T(), "order-1", order.ExternalReference)
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetInStoreOrder_WithEmptyPOS_ShouldReturnNil() {
+	// GIVEN a point of sale showing no QR order
+	response := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":"not_found"}`))),
+	}
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the in-store order
+	order, err := gateway.GetInStoreOrder(context.Background())
+
+	// THEN nothing should be returned
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), order)
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_DeleteInStoreOrder_ShouldDeletePOSOrder() {
+	// GIVEN a point of sale showing a QR order
+	response := &http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodDelete &&
+			req.URL.String() == "https://api.mercadopago.com/instore/qr/seller/collectors/client123/pos/pos123/orders" &&
+			req.Header.Get("Authorization") == "Bearer test_token"
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN deleting the in-store order
+	err = gateway.DeleteInStoreOrder(context.Background())
+
+	// THEN it should succeed
+	assert.NoError(suite.T(), err)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_DeleteInStoreOrder_WithErrorStatus_ShouldReturnError() {
+	// GIVEN Mercado Pago rejecting the request
+	response := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       io.NopCloser(bytes.NewReader([]byte("invalid pos"))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN deleting the in-store order
+	err = gateway.DeleteInStoreOrder(context.Background())
+
+	// THEN error should be returned
+	assert.ErrorContains(suite.T(), err, "failed to delete in-store order, status: 400")
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_Refund_ShouldRefundAmountWithIdempotencyKey() {
+	// GIVEN Mercado Pago accepting a partial refund
+	responseBody, _ := json.Marshal(dto.MercadoPagoRefundResponseDto{
+		Id:        555,
+		PaymentId: 123456,
+		Amount:    money.MustParse("10.00"),
+		Status:    "approved",
+	})
+	response := &http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		body, _ := io.ReadAll(req.Body)
+		return req.Method == http.MethodPost &&
+			req.URL.String() == "https://api.mercadopago.com/v1/payments/123456/refunds" &&
+			req.Header.Get("X-Idempotency-Key") == "refund-1" &&
+			string(body) == `{"amount":10.00}`
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN refunding
+	result, err := gateway.Refund(context.Background(), "123456", money.MustParse("10.00"), "refund-1")
+
+	// THEN the provider refund should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(555), result.Id)
+	assert.Equal(suite.T(), "approved", result.Status)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_Refund_WithErrorStatus_ShouldReturnError() {
+	// GIVEN Mercado Pago rejecting the refund
+	response := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       io.NopCloser(bytes.NewReader([]byte("amount exceeds payment"))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN refunding
+	_, err = gateway.Refund(context.Background(), "123456", money.MustParse("10.00"), "refund-1")
+
+	// THEN error should be returned, telling that nothing was refunded
+	assert.ErrorContains(suite.T(), err, "failed to refund payment 123456, status: 400")
+	assert.ErrorIs(suite.T(), err, paymentGateways.ErrProviderRejected)
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_Refund_WithServerError_ShouldNotTellRejected() {
+	// GIVEN Mercado Pago failing while refunding
+	response := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       io.NopCloser(bytes.NewReader([]byte("upstream timeout"))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN refunding
+	_, err = gateway.Refund(context.Background(), "123456", money.MustParse("10.00"), "refund-1")
+
+	// THEN the error should leave open whether the refund went through
+	assert.Error(suite.T(), err)
+	assert.NotErrorIs(suite.T(), err, paymentGateways.ErrProviderRejected)
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetChargeback_WithValidId_ShouldReturnChargeback() {
+	// GIVEN a chargeback covered by Mercado Pago
+	coverageApplied := true
+	expectedResponse := dto.MercadoPagoChargebackResponseDto{
+		Id:              "cb-1",
+		Payments:        []int64{987},
+		Amount:          money.MustParse("100.50"),
+		Currency:        "BRL",
+		CoverageApplied: &coverageApplied,
+	}
+
+	responseBody, _ := json.Marshal(expectedResponse)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.String() == "https://api.mercadopago.com/v1/chargebacks/cb-1" &&
+			req.Header.Get("Authorization") == "Bearer test_token"
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the chargeback
+	result, err := gateway.GetChargeback(context.Background(), "cb-1")
+
+	// THEN the provider chargeback should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expectedResponse, result)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetClaim_WithValidId_ShouldReturnClaim() {
+	// GIVEN an open claim about a payment
+	expectedResponse := dto.MercadoPagoClaimResponseDto{
+		Id:         42,
+		Resource:   "payment",
+		ResourceId: 987,
+		Status:     "opened",
+		ReasonId:   "PDD9939",
+	}
+
+	responseBody, _ := json.Marshal(expectedResponse)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.String() == "https://api.mercadopago.com/post-purchase/v1/claims/42"
+	})).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the claim
+	result, err := gateway.GetClaim(context.Background(), "42")
+
+	// THEN the provider claim should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expectedResponse, result)
+}
+
+func (suite *MercadoPagoGatewayTestSuite) Test_GetClaim_WithNotFoundStatus_ShouldReturnError() {
+	// GIVEN a claim unknown to Mercado Pago
+	response := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewReader([]byte("not found"))),
+	}
+
+	suite.mockHTTPClient.On("Do", mock.Anything).Return(response, nil).Once()
+
+	gateway, err := gateways.NewMercadoPagoGatewayImplWithClient(suite.mockHTTPClient)
+	assert.NoError(suite.T(), err)
+
+	// WHEN getting the claim
+	_, err = gateway.GetClaim(context.Background(), "42")
+
+	// THEN error should be returned
+	assert.ErrorContains(suite.T(), err, "failed to get claim 42")
+}
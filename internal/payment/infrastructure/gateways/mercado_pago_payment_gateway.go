@@ -0,0 +1,115 @@
+package gateways
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+var (
+	_ paymentGateways.PaymentGateway           = (*MercadoPagoPaymentGateway)(nil)
+	_ paymentGateways.SingleSlotPaymentGateway = (*MercadoPagoPaymentGateway)(nil)
+	_ paymentGateways.ExpiringPaymentGateway   = (*MercadoPagoPaymentGateway)(nil)
+)
+
+// MercadoPagoPaymentGateway adapts the Mercado Pago in-store QR code API to the PaymentGateway port.
+type MercadoPagoPaymentGateway struct {
+	mercadoPagoGateway paymentGateways.MercadoPagoGateway
+	notificationURL    string
+}
+
+func NewMercadoPagoPaymentGateway(mercadoPagoGateway paymentGateways.MercadoPagoGateway) *MercadoPagoPaymentGateway {
+	return &MercadoPagoPaymentGateway{
+		mercadoPagoGateway: mercadoPagoGateway,
+		notificationURL:    os.Getenv("MERCADO_PAGO_WEBHOOK_CALLBACK_URL"),
+	}
+}
+
+func (g *MercadoPagoPaymentGateway) Name() string {
+	return entities.PaymentProviderMercadoPago
+}
+
+// SingleSlot is true since the point of sale only ever shows the QR code of the latest charge.
+func (g *MercadoPagoPaymentGateway) SingleSlot() bool {
+	return true
+}
+
+// ExpiresCharges is true since the QR code is created with the expiration_date of the charge.
+func (g *MercadoPagoPaymentGateway) ExpiresCharges() bool {
+	return true
+}
+
+func (g *MercadoPagoPaymentGateway) CreateCharge(ctx context.Context, request paymentGateways.ChargeRequest) (*paymentGateways.Charge, error) {
+	createQRCode := dto.CreateQRCodeDTO{
+		ExternalReference: request.ExternalReference,
+		Title:             "Fiap",
+		Description:       "Fiap",
+		NotificationURL:   g.notificationURL,
+		TotalAmount:       request.Total.Amount,
+		Items:             request.Items,
+	}
+	if request.ExpiresAt != nil {
+		createQRCode.ExpirationDate = request.ExpiresAt.Format(dto.MercadoPagoTimeLayout)
+	}
+
+	qrCode, err := g.mercadoPagoGateway.GenerateQRCode(ctx, createQRCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentGateways.Charge{
+		ProviderOrderId: qrCode.InStoreOrderId,
+		QRData:          qrCode.QRData,
+	}, nil
+}
+
+func (g *MercadoPagoPaymentGateway) GetChargeStatus(ctx context.Context, providerPaymentId string) (*paymentGateways.ChargeStatus, error) {
+	payment, err := g.mercadoPagoGateway.GetPayment(ctx, providerPaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentGateways.ChargeStatus{
+		ProviderPaymentId: strconv.FormatInt(payment.Id, 10),
+		ExternalReference: payment.ExternalReference,
+		Status:            paymentGateways.MercadoPagoPaymentStatus(payment),
+	}, nil
+}
+
+// CancelCharge clears the point of sale when it still shows the QR code of the payment. Since it
+// only ever shows the latest one, a newer charge on it is left alone.
+func (g *MercadoPagoPaymentGateway) CancelCharge(ctx context.Context, payment *entities.Payment) error {
+	order, err := g.mercadoPagoGateway.GetInStoreOrder(ctx)
+	if err != nil {
+		return err
+	}
+	if order == nil || !showsPayment(order, payment) {
+		return nil
+	}
+
+	return g.mercadoPagoGateway.DeleteInStoreOrder(ctx)
+}
+
+func (g *MercadoPagoPaymentGateway) RefundCharge(ctx context.Context, payment *entities.Payment, amount money.Amount, idempotencyKey string) (*paymentGateways.RefundResult, error) {
+	refund, err := g.mercadoPagoGateway.Refund(ctx, payment.ProviderPaymentId, amount, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentGateways.RefundResult{
+		ProviderRefundId: strconv.FormatInt(refund.Id, 10),
+		Status:           paymentGateways.MercadoPagoRefundStatus(refund),
+	}, nil
+}
+
+func showsPayment(order *dto.MercadoPagoInStoreOrderResponseDto, payment *entities.Payment) bool {
+	if order.InStoreOrderId != "" && payment.ProviderOrderId != "" {
+		return order.InStoreOrderId == payment.ProviderOrderId
+	}
+	return order.ExternalReference == payment.ExternalReference
+}
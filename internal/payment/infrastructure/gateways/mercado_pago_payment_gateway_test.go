@@ -0,0 +1,189 @@
+package gateways_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MercadoPagoPaymentGatewayTestSuite struct {
+	suite.Suite
+	mockMercadoPago *mockGateways.MockMercadoPagoGateway
+	gateway         *gateways.MercadoPagoPaymentGateway
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) SetupTest() {
+	suite.T().Setenv("MERCADO_PAGO_WEBHOOK_CALLBACK_URL", "https://payment.example.com/payment/webhooks/notify")
+	suite.mockMercadoPago = mockGateways.NewMockMercadoPagoGateway(suite.T())
+	suite.gateway = gateways.NewMercadoPagoPaymentGateway(suite.mockMercadoPago)
+}
+
+func TestMercadoPagoPaymentGatewayTestSuite(t *testing.T) {
+	suite.Run(t, new(MercadoPagoPaymentGatewayTestSuite))
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_CreateCharge_ShouldGenerateQRCode() {
+	// GIVEN a charge that expires
+	expiresAt := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	request := paymentGateways.ChargeRequest{
+		ExternalReference: "order-1",
+		Total:             money.New(money.MustParse("10.00"), money.BRL),
+		Items:             []dto.Item{{Title: "Produto", TotalAmount: money.MustParse("10.00")}},
+		ExpiresAt:         &expiresAt,
+	}
+
+	suite.mockMercadoPago.EXPECT().
+		GenerateQRCode(mock.Anything, mock.MatchedBy(func(qr dto.CreateQRCodeDTO) bool {
+			return qr.ExternalReference == "order-1" &&
+				qr.TotalAmount == money.MustParse("10.00") &&
+				len(qr.Items) == 1 &&
+				qr.NotificationURL == "https://payment.example.com/payment/webhooks/notify" &&
+				qr.ExpirationDate == expiresAt.Format(dto.MercadoPagoTimeLayout)
+		})).
+		Return(dto.QRCodeResponseDto{QRData: "qr-data", InStoreOrderId: "in-store-order-1"}, nil).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), request)
+
+	// THEN the QR code and the in-store order should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "qr-data", charge.QRData)
+	assert.Equal(suite.T(), "in-store-order-1", charge.ProviderOrderId)
+	assert.Empty(suite.T(), charge.ProviderPaymentId)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_CreateCharge_WithoutExpiration_ShouldNotSendExpirationDate() {
+	// GIVEN a charge that does not expire
+	suite.mockMercadoPago.EXPECT().
+		GenerateQRCode(mock.Anything, mock.MatchedBy(func(qr dto.CreateQRCodeDTO) bool {
+			return qr.ExpirationDate == ""
+		})).
+		Return(dto.QRCodeResponseDto{QRData: "qr-data"}, nil).
+		Once()
+
+	// WHEN creating the charge
+	_, err := suite.gateway.CreateCharge(context.Background(), paymentGateways.ChargeRequest{ExternalReference: "order-1"})
+
+	// THEN no expiration should be sent
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_CreateCharge_WithError_ShouldReturnError() {
+	// GIVEN Mercado Pago failing
+	expectedError := errors.New("mercado pago unavailable")
+	suite.mockMercadoPago.EXPECT().
+		GenerateQRCode(mock.Anything, mock.Anything).
+		Return(dto.QRCodeResponseDto{}, expectedError).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), paymentGateways.ChargeRequest{})
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), charge)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_GetChargeStatus_ShouldMapPaymentStatus() {
+	// GIVEN a partially refunded Mercado Pago payment
+	suite.mockMercadoPago.EXPECT().
+		GetPayment(mock.Anything, "123").
+		Return(dto.MercadoPagoPaymentResponseDto{
+			Id:                123,
+			Status:            "approved",
+			StatusDetail:      "partially_refunded",
+			ExternalReference: "order-1",
+		}, nil).
+		Once()
+
+	// WHEN getting its status
+	status, err := suite.gateway.GetChargeStatus(context.Background(), "123")
+
+	// THEN it should be mapped to ours
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "123", status.ProviderPaymentId)
+	assert.Equal(suite.T(), "order-1", status.ExternalReference)
+	assert.Equal(suite.T(), entities.PaymentStatusPartiallyRefunded, status.Status)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_CancelCharge_ShouldDeleteInStoreOrder() {
+	// GIVEN a pending payment shown on the point of sale
+	suite.mockMercadoPago.EXPECT().
+		GetInStoreOrder(mock.Anything).
+		Return(&dto.MercadoPagoInStoreOrderResponseDto{InStoreOrderId: "in-store-1", ExternalReference: "order-1"}, nil).
+		Once()
+	suite.mockMercadoPago.EXPECT().
+		DeleteInStoreOrder(mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN cancelling it
+	err := suite.gateway.CancelCharge(context.Background(), &entities.Payment{ID: 1, ProviderOrderId: "in-store-1", ExternalReference: "order-1"})
+
+	// THEN the point of sale should be cleared
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_CancelCharge_WithNewerChargeOnPointOfSale_ShouldKeepIt() {
+	// GIVEN a point of sale already showing the QR code of another payment
+	suite.mockMercadoPago.EXPECT().
+		GetInStoreOrder(mock.Anything).
+		Return(&dto.MercadoPagoInStoreOrderResponseDto{InStoreOrderId: "in-store-2", ExternalReference: "order-2"}, nil).
+		Once()
+
+	// WHEN cancelling the older payment
+	err := suite.gateway.CancelCharge(context.Background(), &entities.Payment{ID: 1, ProviderOrderId: "in-store-1", ExternalReference: "order-1"})
+
+	// THEN the newer QR code should be left on the point of sale
+	assert.NoError(suite.T(), err)
+	suite.mockMercadoPago.AssertNotCalled(suite.T(), "DeleteInStoreOrder", mock.Anything)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_CancelCharge_WithEmptyPointOfSale_ShouldSucceed() {
+	// GIVEN a point of sale showing no QR code
+	suite.mockMercadoPago.EXPECT().
+		GetInStoreOrder(mock.Anything).
+		Return(nil, nil).
+		Once()
+
+	// WHEN cancelling the payment
+	err := suite.gateway.CancelCharge(context.Background(), &entities.Payment{ID: 1, ProviderOrderId: "in-store-1"})
+
+	// THEN there should be nothing to clear
+	assert.NoError(suite.T(), err)
+	suite.mockMercadoPago.AssertNotCalled(suite.T(), "DeleteInStoreOrder", mock.Anything)
+}
+
+func (suite *MercadoPagoPaymentGatewayTestSuite) Test_RefundCharge_ShouldRefundProviderPayment() {
+	// GIVEN an approved payment
+	payment := &entities.Payment{ID: 1, ProviderPaymentId: "123"}
+
+	suite.mockMercadoPago.EXPECT().
+		Refund(mock.Anything, "123", money.MustParse("5.00"), "refund-7").
+		Return(dto.MercadoPagoRefundResponseDto{Id: 555, Status: "rejected"}, nil).
+		Once()
+
+	// WHEN refunding part of it
+	result, err := suite.gateway.RefundCharge(context.Background(), payment, money.MustParse("5.00"), "refund-7")
+
+	// THEN the refund id and status should be mapped
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "555", result.ProviderRefundId)
+	assert.Equal(suite.T(), entities.RefundStatusRejected, result.Status)
+}
+
+func TestMercadoPagoPaymentGateway_Name(t *testing.T) {
+	assert.Equal(t, entities.PaymentProviderMercadoPago, gateways.NewMercadoPagoPaymentGateway(nil).Name())
+}
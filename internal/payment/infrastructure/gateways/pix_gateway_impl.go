@@ -0,0 +1,135 @@
+package gateways
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/abattassini/tc-fiap-payment/pkg/rest"
+)
+
+var (
+	_ paymentGateways.PixGateway = (*PixGatewayImpl)(nil)
+)
+
+type PixGatewayImpl struct {
+	config *PixConfig
+	client rest.HTTPClient
+}
+
+type PixConfig struct {
+	// BaseURL is the PIX API of the PSP; PIX payments are disabled without one.
+	BaseURL     string
+	AccessToken string
+}
+
+func (c *PixConfig) Validate() error {
+	if c.BaseURL != "" && c.AccessToken == "" {
+		return fmt.Errorf("invalid PixConfig: access token must be set along with the base URL")
+	}
+	return nil
+}
+
+func newPixConfig() *PixConfig {
+	return &PixConfig{
+		BaseURL:     strings.TrimSuffix(os.Getenv("PIX_BASEURL"), "/"),
+		AccessToken: os.Getenv("PIX_ACCESS_TOKEN"),
+	}
+}
+
+func NewPixGatewayImpl() (*PixGatewayImpl, error) {
+	return NewPixGatewayImplWithClient(&http.Client{})
+}
+
+func NewPixGatewayImplWithClient(client rest.HTTPClient) (*PixGatewayImpl, error) {
+	config := newPixConfig()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &PixGatewayImpl{config: config, client: client}, nil
+}
+
+func (p *PixGatewayImpl) Configured() bool {
+	return p.config.BaseURL != ""
+}
+
+func (p *PixGatewayImpl) CreateCob(ctx context.Context, txid string, request dto.PixCreateCobDto) (dto.PixCobDto, error) {
+	var response dto.PixCobDto
+	if err := p.send(ctx, http.MethodPut, "/v2/cob/"+url.PathEscape(txid), request, &response); err != nil {
+		return dto.PixCobDto{}, fmt.Errorf("failed to create PIX charge %s: %w", txid, err)
+	}
+	return response, nil
+}
+
+func (p *PixGatewayImpl) GetCob(ctx context.Context, txid string) (dto.PixCobDto, error) {
+	var response dto.PixCobDto
+	if err := p.send(ctx, http.MethodGet, "/v2/cob/"+url.PathEscape(txid), nil, &response); err != nil {
+		return dto.PixCobDto{}, fmt.Errorf("failed to get PIX charge %s: %w", txid, err)
+	}
+	return response, nil
+}
+
+func (p *PixGatewayImpl) CancelCob(ctx context.Context, txid string) (dto.PixCobDto, error) {
+	var response dto.PixCobDto
+	request := dto.PixPatchCobDto{Status: "REMOVIDA_PELO_USUARIO_RECEBEDOR"}
+	if err := p.send(ctx, http.MethodPatch, "/v2/cob/"+url.PathEscape(txid), request, &response); err != nil {
+		return dto.PixCobDto{}, fmt.Errorf("failed to cancel PIX charge %s: %w", txid, err)
+	}
+	return response, nil
+}
+
+func (p *PixGatewayImpl) CreateRefund(ctx context.Context, endToEndId string, refundId string, amount money.Amount) (dto.PixDevolucaoDto, error) {
+	path := fmt.Sprintf("/v2/pix/%s/devolucao/%s", url.PathEscape(endToEndId), url.PathEscape(refundId))
+
+	var response dto.PixDevolucaoDto
+	if err := p.send(ctx, http.MethodPut, path, dto.PixCreateDevolucaoDto{Valor: amount.String()}, &response); err != nil {
+		return dto.PixDevolucaoDto{}, fmt.Errorf("failed to refund PIX payment %s: %w", endToEndId, err)
+	}
+	return response, nil
+}
+
+func (p *PixGatewayImpl) send(ctx context.Context, method string, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.config.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.AccessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Creating a charge or a refund answers 201, everything else 200
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected %w", &providerStatusError{statusCode: resp.StatusCode, body: string(body)})
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package gateways_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PixGatewayTestSuite struct {
+	suite.Suite
+	mockHTTPClient *MockHTTPClient
+	gateway        *gateways.PixGatewayImpl
+}
+
+func (suite *PixGatewayTestSuite) SetupTest() {
+	suite.T().Setenv("PIX_BASEURL", "https://pix.psp.test/api/")
+	suite.T().Setenv("PIX_ACCESS_TOKEN", "token_123")
+	suite.mockHTTPClient = new(MockHTTPClient)
+
+	gateway, err := gateways.NewPixGatewayImplWithClient(suite.mockHTTPClient)
+	require.NoError(suite.T(), err)
+	suite.gateway = gateway
+}
+
+func TestPixGatewayTestSuite(t *testing.T) {
+	suite.Run(t, new(PixGatewayTestSuite))
+}
+
+func (suite *PixGatewayTestSuite) Test_CreateCob_ShouldPutChargeUnderTxid() {
+	// GIVEN a charge request
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		var body dto.PixCreateCobDto
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return false
+		}
+		return req.Method == http.MethodPut &&
+			req.URL.String() == "https://pix.psp.test/api/v2/cob/txid123" &&
+			req.Header.Get("Authorization") == "Bearer token_123" &&
+			req.Header.Get("Content-Type") == "application/json" &&
+			body.Valor.Original == "50.00" &&
+			body.Calendario.Expiracao == 1800
+	})).Return(jsonResponse(http.StatusCreated, `{"txid":"txid123","status":"ATIVA","location":"pix.psp.test/qr/v2/abc"}`), nil).Once()
+
+	// WHEN creating it
+	cob, err := suite.gateway.CreateCob(context.Background(), "txid123", dto.PixCreateCobDto{
+		Calendario: dto.PixCalendarioDto{Expiracao: 1800},
+		Valor:      dto.PixValorDto{Original: "50.00"},
+		Chave:      "pix@example.com",
+	})
+
+	// THEN the created charge should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pix.psp.test/qr/v2/abc", cob.Location)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *PixGatewayTestSuite) Test_GetCob_ShouldReturnReceivedPix() {
+	// GIVEN a paid charge
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.Path == "/api/v2/cob/txid123"
+	})).Return(jsonResponse(http.StatusOK, `{"txid":"txid123","status":"CONCLUIDA","infoAdicionais":[{"nome":"external_reference","valor":"order-1"}],"pix":[{"endToEndId":"E1","valor":"50.00"}]}`), nil).Once()
+
+	// WHEN getting it
+	cob, err := suite.gateway.GetCob(context.Background(), "txid123")
+
+	// THEN the payment and our reference should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "CONCLUIDA", cob.Status)
+	assert.Equal(suite.T(), "order-1", cob.ExternalReference())
+	assert.Equal(suite.T(), "E1", cob.Pix[0].EndToEndId)
+}
+
+func (suite *PixGatewayTestSuite) Test_CancelCob_ShouldPatchStatus() {
+	// GIVEN an unpaid charge
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		var body dto.PixPatchCobDto
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return false
+		}
+		return req.Method == http.MethodPatch &&
+			req.URL.Path == "/api/v2/cob/txid123" &&
+			body.Status == "REMOVIDA_PELO_USUARIO_RECEBEDOR"
+	})).Return(jsonResponse(http.StatusOK, `{"txid":"txid123","status":"REMOVIDA_PELO_USUARIO_RECEBEDOR"}`), nil).Once()
+
+	// WHEN cancelling it
+	cob, err := suite.gateway.CancelCob(context.Background(), "txid123")
+
+	// THEN it should be removed
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "REMOVIDA_PELO_USUARIO_RECEBEDOR", cob.Status)
+}
+
+func (suite *PixGatewayTestSuite) Test_CreateRefund_ShouldPutRefundUnderId() {
+	// GIVEN a refund of part of a payment
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		var body dto.PixCreateDevolucaoDto
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return false
+		}
+		return req.Method == http.MethodPut &&
+			req.URL.Path == "/api/v2/pix/E1/devolucao/refund1" &&
+			body.Valor == "20.00"
+	})).Return(jsonResponse(http.StatusCreated, `{"id":"refund1","rtrId":"D1","valor":"20.00","status":"EM_PROCESSAMENTO"}`), nil).Once()
+
+	// WHEN refunding it
+	devolucao, err := suite.gateway.CreateRefund(context.Background(), "E1", "refund1", money.MustParse("20.00"))
+
+	// THEN the refund should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "D1", devolucao.RtrId)
+	assert.Equal(suite.T(), "EM_PROCESSAMENTO", devolucao.Status)
+}
+
+func (suite *PixGatewayTestSuite) Test_GetCob_WithUnexpectedStatus_ShouldReturnError() {
+	// GIVEN an unknown charge
+	suite.mockHTTPClient.On("Do", mock.Anything).
+		Return(jsonResponse(http.StatusNotFound, `{"title":"Cobrança não encontrada"}`), nil).Once()
+
+	// WHEN getting it
+	_, err := suite.gateway.GetCob(context.Background(), "missing")
+
+	// THEN error should be returned
+	assert.ErrorContains(suite.T(), err, "unexpected status: 404")
+}
+
+func (suite *PixGatewayTestSuite) Test_Configured_WithoutBaseURL_ShouldBeFalse() {
+	// GIVEN no PSP
+	suite.T().Setenv("PIX_BASEURL", "")
+	suite.T().Setenv("PIX_ACCESS_TOKEN", "")
+
+	// WHEN creating the gateway
+	gateway, err := gateways.NewPixGatewayImplWithClient(suite.mockHTTPClient)
+
+	// THEN it should be created but not configured
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), gateway.Configured())
+}
+
+func (suite *PixGatewayTestSuite) Test_NewPixGatewayImpl_WithoutAccessToken_ShouldReturnError() {
+	// GIVEN a PSP without credentials
+	suite.T().Setenv("PIX_ACCESS_TOKEN", "")
+
+	// WHEN creating the gateway
+	gateway, err := gateways.NewPixGatewayImplWithClient(suite.mockHTTPClient)
+
+	// THEN error should be returned
+	assert.ErrorContains(suite.T(), err, "access token")
+	assert.Nil(suite.T(), gateway)
+}
@@ -0,0 +1,166 @@
+package gateways
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/abattassini/tc-fiap-payment/pkg/pix"
+)
+
+var (
+	_ paymentGateways.PaymentGateway         = (*PixPaymentGateway)(nil)
+	_ paymentGateways.OptionalPaymentGateway = (*PixPaymentGateway)(nil)
+	_ paymentGateways.ExpiringPaymentGateway = (*PixPaymentGateway)(nil)
+)
+
+// defaultPixExpiration is how long a PIX charge stays payable when the payment does not expire.
+const defaultPixExpiration = time.Hour
+
+// maxPixRefundIdLength is the longest refund id the PIX API accepts.
+const maxPixRefundIdLength = 35
+
+// PixPaymentGateway adapts the PIX API of our PSP to the PaymentGateway port. Each payment is a charge
+// identified by a txid of our own; the customer pays its dynamic BR Code, which we build from the
+// location the PSP gives the charge.
+type PixPaymentGateway struct {
+	pixGateway   paymentGateways.PixGateway
+	key          string
+	merchantName string
+	merchantCity string
+}
+
+func NewPixPaymentGateway(pixGateway paymentGateways.PixGateway) *PixPaymentGateway {
+	return &PixPaymentGateway{
+		pixGateway:   pixGateway,
+		key:          os.Getenv("PIX_KEY"),
+		merchantName: os.Getenv("PIX_MERCHANT_NAME"),
+		merchantCity: os.Getenv("PIX_MERCHANT_CITY"),
+	}
+}
+
+func (g *PixPaymentGateway) Name() string {
+	return entities.PaymentProviderPix
+}
+
+// Enabled leaves PIX payments unsupported until the PSP, the PIX key and the merchant are configured.
+func (g *PixPaymentGateway) Enabled() bool {
+	return g.pixGateway.Configured() && g.key != "" && g.merchantName != "" && g.merchantCity != ""
+}
+
+// ExpiresCharges is true since the PSP stops accepting an immediate charge once its expiracao passes.
+func (g *PixPaymentGateway) ExpiresCharges() bool {
+	return true
+}
+
+func (g *PixPaymentGateway) CreateCharge(ctx context.Context, request paymentGateways.ChargeRequest) (*paymentGateways.Charge, error) {
+	expiration := defaultPixExpiration
+	if request.ExpiresAt != nil {
+		expiration = max(time.Until(*request.ExpiresAt), time.Second)
+	}
+
+	txid := pix.NewTxid()
+	cob, err := g.pixGateway.CreateCob(ctx, txid, dto.PixCreateCobDto{
+		Calendario:         dto.PixCalendarioDto{Expiracao: int64(expiration / time.Second)},
+		Valor:              dto.PixValorDto{Original: request.Total.Amount.String()},
+		Chave:              g.key,
+		SolicitacaoPagador: request.ExternalReference,
+		InfoAdicionais: []dto.PixInfoAdicionalDto{
+			{Nome: dto.PixExternalReferenceInfo, Valor: request.ExternalReference},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cob.Location == "" {
+		return nil, fmt.Errorf("PIX charge %s was created without a location", txid)
+	}
+
+	brCode, err := pix.Payload{
+		URL:          cob.Location,
+		MerchantName: g.merchantName,
+		MerchantCity: g.merchantCity,
+		Amount:       request.Total.Amount,
+	}.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the BR Code of PIX charge %s: %w", txid, err)
+	}
+
+	return &paymentGateways.Charge{
+		QRData: brCode,
+		Txid:   txid,
+	}, nil
+}
+
+// GetChargeStatus looks the charge up by its txid. The provider payment id is the end to end id of
+// the PIX that paid it, empty while it is unpaid.
+func (g *PixPaymentGateway) GetChargeStatus(ctx context.Context, txid string) (*paymentGateways.ChargeStatus, error) {
+	cob, err := g.pixGateway.GetCob(ctx, txid)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &paymentGateways.ChargeStatus{
+		ExternalReference: cob.ExternalReference(),
+		Status:            paymentGateways.PixCobStatus(cob),
+	}
+	if len(cob.Pix) > 0 {
+		status.ProviderPaymentId = cob.Pix[0].EndToEndId
+	}
+	return status, nil
+}
+
+func (g *PixPaymentGateway) CancelCharge(ctx context.Context, payment *entities.Payment) error {
+	_, err := g.pixGateway.CancelCob(ctx, payment.Txid)
+	return err
+}
+
+// RefundCharge refunds the PIX that paid the charge, looking it up when the payment was approved
+// without its end to end id.
+func (g *PixPaymentGateway) RefundCharge(ctx context.Context, payment *entities.Payment, amount money.Amount, idempotencyKey string) (*paymentGateways.RefundResult, error) {
+	endToEndId := payment.ProviderPaymentId
+	if endToEndId == "" {
+		status, err := g.GetChargeStatus(ctx, payment.Txid)
+		if err != nil {
+			return nil, err
+		}
+		if status.ProviderPaymentId == "" {
+			return nil, fmt.Errorf("%w: PIX charge %s has not been paid", paymentGateways.ErrProviderRejected, payment.Txid)
+		}
+		endToEndId = status.ProviderPaymentId
+	}
+
+	devolucao, err := g.pixGateway.CreateRefund(ctx, endToEndId, pixRefundId(idempotencyKey), amount)
+	if err != nil {
+		return nil, err
+	}
+
+	refundId := devolucao.RtrId
+	if refundId == "" {
+		refundId = devolucao.Id
+	}
+	return &paymentGateways.RefundResult{
+		ProviderRefundId: refundId,
+		Status:           paymentGateways.PixRefundStatus(devolucao),
+	}, nil
+}
+
+// pixRefundId turns the idempotency key into a refund id, which may only hold letters and digits.
+func pixRefundId(idempotencyKey string) string {
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, idempotencyKey)
+	if len(id) > maxPixRefundIdLength {
+		id = id[:maxPixRefundIdLength]
+	}
+	return id
+}
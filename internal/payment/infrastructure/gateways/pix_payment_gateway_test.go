@@ -0,0 +1,196 @@
+package gateways_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/abattassini/tc-fiap-payment/pkg/pix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PixPaymentGatewayTestSuite struct {
+	suite.Suite
+	mockPix *mockGateways.MockPixGateway
+	gateway *gateways.PixPaymentGateway
+}
+
+func (suite *PixPaymentGatewayTestSuite) SetupTest() {
+	suite.T().Setenv("PIX_KEY", "pix@example.com")
+	suite.T().Setenv("PIX_MERCHANT_NAME", "Fiap Lanches")
+	suite.T().Setenv("PIX_MERCHANT_CITY", "São Paulo")
+	suite.mockPix = mockGateways.NewMockPixGateway(suite.T())
+	suite.gateway = gateways.NewPixPaymentGateway(suite.mockPix)
+}
+
+func TestPixPaymentGatewayTestSuite(t *testing.T) {
+	suite.Run(t, new(PixPaymentGatewayTestSuite))
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_CreateCharge_ShouldReturnDynamicBRCodeAndTxid() {
+	// GIVEN a PIX charge expiring in 30 minutes
+	expiresAt := time.Now().Add(30 * time.Minute)
+	request := paymentGateways.ChargeRequest{
+		ExternalReference: "order-1",
+		Total:             money.New(money.MustParse("50.00"), money.BRL),
+		ExpiresAt:         &expiresAt,
+	}
+
+	var createdTxid string
+	suite.mockPix.EXPECT().
+		CreateCob(mock.Anything, mock.Anything, mock.MatchedBy(func(cob dto.PixCreateCobDto) bool {
+			return cob.Chave == "pix@example.com" &&
+				cob.Valor.Original == "50.00" &&
+				cob.Calendario.Expiracao > 1790 && cob.Calendario.Expiracao <= 1800 &&
+				cob.InfoAdicionais[0].Valor == "order-1"
+		})).
+		RunAndReturn(func(ctx context.Context, txid string, request dto.PixCreateCobDto) (dto.PixCobDto, error) {
+			createdTxid = txid
+			return dto.PixCobDto{Txid: txid, Status: "ATIVA", Location: "pix.psp.test/qr/v2/abc"}, nil
+		}).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), request)
+
+	// THEN the txid should be returned with a BR Code pointing to the charge
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), createdTxid, charge.Txid)
+	assert.Len(suite.T(), charge.Txid, 32)
+
+	payload, err := pix.Decode(charge.QRData)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "pix.psp.test/qr/v2/abc", payload.URL)
+	assert.Equal(suite.T(), money.MustParse("50.00"), payload.Amount)
+	assert.Equal(suite.T(), "Sao Paulo", payload.MerchantCity)
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_CreateCharge_WithoutLocation_ShouldReturnError() {
+	// GIVEN a PSP that does not give the charge a location
+	suite.mockPix.EXPECT().
+		CreateCob(mock.Anything, mock.Anything, mock.Anything).
+		Return(dto.PixCobDto{Status: "ATIVA"}, nil).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), paymentGateways.ChargeRequest{
+		ExternalReference: "order-1",
+		Total:             money.New(money.MustParse("50.00"), money.BRL),
+	})
+
+	// THEN error should be returned
+	assert.ErrorContains(suite.T(), err, "without a location")
+	assert.Nil(suite.T(), charge)
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_GetChargeStatus_WithPaidCharge_ShouldReturnEndToEndId() {
+	// GIVEN a charge paid and partially refunded
+	suite.mockPix.EXPECT().
+		GetCob(mock.Anything, "txid1").
+		Return(dto.PixCobDto{
+			Txid:           "txid1",
+			Status:         "CONCLUIDA",
+			InfoAdicionais: []dto.PixInfoAdicionalDto{{Nome: dto.PixExternalReferenceInfo, Valor: "order-1"}},
+			Pix: []dto.PixDto{{
+				EndToEndId: "E1",
+				Valor:      "50.00",
+				Devolucoes: []dto.PixDevolucaoDto{
+					{Id: "refund1", Valor: "20.00", Status: "DEVOLVIDO"},
+					{Id: "refund2", Valor: "30.00", Status: "NAO_REALIZADO"},
+				},
+			}},
+		}, nil).
+		Once()
+
+	// WHEN getting its status
+	status, err := suite.gateway.GetChargeStatus(context.Background(), "txid1")
+
+	// THEN only the refund that went through should count
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "E1", status.ProviderPaymentId)
+	assert.Equal(suite.T(), "order-1", status.ExternalReference)
+	assert.Equal(suite.T(), entities.PaymentStatusPartiallyRefunded, status.Status)
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_GetChargeStatus_WithRemovedCharge_ShouldMapStatus() {
+	tests := []struct {
+		cobStatus string
+		expected  entities.PaymentStatus
+	}{
+		{"ATIVA", entities.PaymentStatusPending},
+		{"REMOVIDA_PELO_USUARIO_RECEBEDOR", entities.PaymentStatusCancelled},
+		{"REMOVIDA_PELO_PSP", entities.PaymentStatusExpired},
+	}
+
+	for _, tt := range tests {
+		// GIVEN an unpaid charge
+		suite.mockPix.EXPECT().
+			GetCob(mock.Anything, "txid1").
+			Return(dto.PixCobDto{Txid: "txid1", Status: tt.cobStatus}, nil).
+			Once()
+
+		// WHEN getting its status
+		status, err := suite.gateway.GetChargeStatus(context.Background(), "txid1")
+
+		// THEN it should be mapped without a provider payment id
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), tt.expected, status.Status, tt.cobStatus)
+		assert.Empty(suite.T(), status.ProviderPaymentId)
+	}
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_CancelCharge_ShouldCancelCobByTxid() {
+	// GIVEN a pending PIX payment
+	payment := &entities.Payment{Txid: "txid1"}
+	suite.mockPix.EXPECT().
+		CancelCob(mock.Anything, "txid1").
+		Return(dto.PixCobDto{Status: "REMOVIDA_PELO_USUARIO_RECEBEDOR"}, nil).
+		Once()
+
+	// WHEN cancelling it
+	err := suite.gateway.CancelCharge(context.Background(), payment)
+
+	// THEN the charge should be removed
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_RefundCharge_WithoutEndToEndId_ShouldLookItUp() {
+	// GIVEN an approved payment whose end to end id was never recorded
+	payment := &entities.Payment{Txid: "txid1"}
+	suite.mockPix.EXPECT().
+		GetCob(mock.Anything, "txid1").
+		Return(dto.PixCobDto{Status: "CONCLUIDA", Pix: []dto.PixDto{{EndToEndId: "E1", Valor: "50.00"}}}, nil).
+		Once()
+	suite.mockPix.EXPECT().
+		CreateRefund(mock.Anything, "E1", "refund7", money.MustParse("20.00")).
+		Return(dto.PixDevolucaoDto{Id: "refund7", RtrId: "D1", Status: "DEVOLVIDO"}, nil).
+		Once()
+
+	// WHEN refunding part of it
+	refund, err := suite.gateway.RefundCharge(context.Background(), payment, money.MustParse("20.00"), "refund-7")
+
+	// THEN the PIX should be refunded under an id made of the idempotency key
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "D1", refund.ProviderRefundId)
+	assert.Equal(suite.T(), entities.RefundStatusApproved, refund.Status)
+}
+
+func (suite *PixPaymentGatewayTestSuite) Test_Enabled_WithoutKey_ShouldBeFalse() {
+	// GIVEN a configured PSP but no PIX key
+	suite.T().Setenv("PIX_KEY", "")
+	suite.mockPix.EXPECT().Configured().Return(true).Maybe()
+
+	// WHEN checking the gateway
+	enabled := gateways.NewPixPaymentGateway(suite.mockPix).Enabled()
+
+	// THEN it should be disabled
+	assert.False(suite.T(), enabled)
+}
@@ -0,0 +1,32 @@
+package gateways
+
+import (
+	"fmt"
+	"net/http"
+
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+)
+
+// providerStatusError is an unexpected status answered by a provider.
+type providerStatusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *providerStatusError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.statusCode, e.body)
+}
+
+// Is matches ErrProviderRejected for client errors, which the provider answers without acting on the
+// request. Timeouts, conflicts with a request still in flight, rate limits and server errors do not
+// say whether it acted.
+func (e *providerStatusError) Is(target error) bool {
+	if target != paymentGateways.ErrProviderRejected {
+		return false
+	}
+	switch e.statusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return e.statusCode >= 400 && e.statusCode < 500
+}
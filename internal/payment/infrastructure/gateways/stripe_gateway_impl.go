@@ -0,0 +1,192 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/rest"
+)
+
+var (
+	_ paymentGateways.StripeGateway = (*StripeGatewayImpl)(nil)
+)
+
+const defaultStripeBaseURL = "https://api.stripe.com"
+
+type StripeGatewayImpl struct {
+	config *StripeConfig
+	client rest.HTTPClient
+}
+
+type StripeConfig struct {
+	BaseURL string
+	// SecretKey authenticates the requests; card payments are disabled without one.
+	SecretKey string
+}
+
+func (c *StripeConfig) Validate() error {
+	if c.BaseURL == "" {
+		return fmt.Errorf("invalid StripeConfig: base URL must be set")
+	}
+	return nil
+}
+
+func newStripeConfig() *StripeConfig {
+	baseURL := defaultStripeBaseURL
+	if value := os.Getenv("STRIPE_BASEURL"); value != "" {
+		baseURL = value
+	}
+	return &StripeConfig{
+		BaseURL:   strings.TrimSuffix(baseURL, "/"),
+		SecretKey: os.Getenv("STRIPE_SECRET_KEY"),
+	}
+}
+
+func NewStripeGatewayImpl() (*StripeGatewayImpl, error) {
+	return NewStripeGatewayImplWithClient(&http.Client{})
+}
+
+func NewStripeGatewayImplWithClient(client rest.HTTPClient) (*StripeGatewayImpl, error) {
+	config := newStripeConfig()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &StripeGatewayImpl{config: config, client: client}, nil
+}
+
+func (s *StripeGatewayImpl) Configured() bool {
+	return s.config.SecretKey != ""
+}
+
+func (s *StripeGatewayImpl) CreatePaymentIntent(ctx context.Context, request dto.StripeCreatePaymentIntentDto) (dto.StripePaymentIntentDto, error) {
+	var response dto.StripePaymentIntentDto
+	if err := s.post(ctx, "/v1/payment_intents", request.Form(), "", &response); err != nil {
+		return dto.StripePaymentIntentDto{}, fmt.Errorf("failed to create payment intent: %w", err)
+	}
+	return response, nil
+}
+
+func (s *StripeGatewayImpl) GetPaymentIntent(ctx context.Context, paymentIntentId string) (dto.StripePaymentIntentDto, error) {
+	endpoint := fmt.Sprintf("%s/v1/payment_intents/%s?%s", s.config.BaseURL, url.PathEscape(paymentIntentId), url.Values{"expand[]": {"latest_charge"}}.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return dto.StripePaymentIntentDto{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	var response dto.StripePaymentIntentDto
+	if err := s.do(req, &response); err != nil {
+		return dto.StripePaymentIntentDto{}, fmt.Errorf("failed to get payment intent %s: %w", paymentIntentId, err)
+	}
+	return response, nil
+}
+
+func (s *StripeGatewayImpl) ConfirmPaymentIntent(ctx context.Context, paymentIntentId string, paymentMethod string) (dto.StripePaymentIntentDto, error) {
+	path := fmt.Sprintf("/v1/payment_intents/%s/confirm", url.PathEscape(paymentIntentId))
+
+	var response dto.StripePaymentIntentDto
+	err := s.post(ctx, path, url.Values{"payment_method": {paymentMethod}}, "", &response)
+	if cardErr, ok := err.(*stripeCardError); ok && cardErr.response.Error.PaymentIntent != nil {
+		// A declined card is an outcome of the payment, not a failure to reach the provider
+		return *cardErr.response.Error.PaymentIntent, nil
+	}
+	if err != nil {
+		return dto.StripePaymentIntentDto{}, fmt.Errorf("failed to confirm payment intent %s: %w", paymentIntentId, err)
+	}
+	return response, nil
+}
+
+func (s *StripeGatewayImpl) CapturePaymentIntent(ctx context.Context, paymentIntentId string, amount int64) (dto.StripePaymentIntentDto, error) {
+	path := fmt.Sprintf("/v1/payment_intents/%s/capture", url.PathEscape(paymentIntentId))
+	form := url.Values{}
+	if amount > 0 {
+		form.Set("amount_to_capture", strconv.FormatInt(amount, 10))
+	}
+
+	var response dto.StripePaymentIntentDto
+	if err := s.post(ctx, path, form, "", &response); err != nil {
+		return dto.StripePaymentIntentDto{}, fmt.Errorf("failed to capture payment intent %s: %w", paymentIntentId, err)
+	}
+	return response, nil
+}
+
+func (s *StripeGatewayImpl) CancelPaymentIntent(ctx context.Context, paymentIntentId string) (dto.StripePaymentIntentDto, error) {
+	path := fmt.Sprintf("/v1/payment_intents/%s/cancel", url.PathEscape(paymentIntentId))
+
+	var response dto.StripePaymentIntentDto
+	if err := s.post(ctx, path, url.Values{}, "", &response); err != nil {
+		return dto.StripePaymentIntentDto{}, fmt.Errorf("failed to cancel payment intent %s: %w", paymentIntentId, err)
+	}
+	return response, nil
+}
+
+func (s *StripeGatewayImpl) CreateRefund(ctx context.Context, paymentIntentId string, amount int64, idempotencyKey string) (dto.StripeRefundDto, error) {
+	form := url.Values{"payment_intent": {paymentIntentId}}
+	if amount > 0 {
+		form.Set("amount", strconv.FormatInt(amount, 10))
+	}
+
+	var response dto.StripeRefundDto
+	if err := s.post(ctx, "/v1/refunds", form, idempotencyKey, &response); err != nil {
+		return dto.StripeRefundDto{}, fmt.Errorf("failed to refund payment intent %s: %w", paymentIntentId, err)
+	}
+	return response, nil
+}
+
+func (s *StripeGatewayImpl) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	return s.do(req, out)
+}
+
+func (s *StripeGatewayImpl) do(req *http.Request, out any) error {
+	req.Header.Set("Authorization", "Bearer "+s.config.SecretKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPaymentRequired {
+		var response dto.StripeErrorResponseDto
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return fmt.Errorf("failed to decode error body: %w", err)
+		}
+		return &stripeCardError{response: response}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected %w", &providerStatusError{statusCode: resp.StatusCode, body: string(body)})
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
+
+// stripeCardError is the 402 Stripe answers with when a card is declined.
+type stripeCardError struct {
+	response dto.StripeErrorResponseDto
+}
+
+func (e *stripeCardError) Error() string {
+	return fmt.Sprintf("card error: %s (%s)", e.response.Error.Message, e.response.Error.Code)
+}
@@ -0,0 +1,157 @@
+package gateways_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StripeGatewayTestSuite struct {
+	suite.Suite
+	mockHTTPClient *MockHTTPClient
+	gateway        *gateways.StripeGatewayImpl
+}
+
+func (suite *StripeGatewayTestSuite) SetupTest() {
+	suite.T().Setenv("STRIPE_BASEURL", "https://api.stripe.test/")
+	suite.T().Setenv("STRIPE_SECRET_KEY", "sk_test_123")
+	suite.mockHTTPClient = new(MockHTTPClient)
+
+	gateway, err := gateways.NewStripeGatewayImplWithClient(suite.mockHTTPClient)
+	require.NoError(suite.T(), err)
+	suite.gateway = gateway
+}
+
+func TestStripeGatewayTestSuite(t *testing.T) {
+	suite.Run(t, new(StripeGatewayTestSuite))
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString(body))}
+}
+
+func (suite *StripeGatewayTestSuite) Test_CreatePaymentIntent_ShouldPostForm() {
+	// GIVEN a payment intent request
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		if err := req.ParseForm(); err != nil {
+			return false
+		}
+		return req.Method == http.MethodPost &&
+			req.URL.String() == "https://api.stripe.test/v1/payment_intents" &&
+			req.Header.Get("Authorization") == "Bearer sk_test_123" &&
+			req.Header.Get("Content-Type") == "application/x-www-form-urlencoded" &&
+			req.PostForm.Get("amount") == "1050" &&
+			req.PostForm.Get("currency") == "brl" &&
+			req.PostForm.Get("metadata[external_reference]") == "order-1"
+	})).Return(jsonResponse(http.StatusOK, `{"id":"pi_1","status":"requires_payment_method","client_secret":"pi_1_secret"}`), nil).Once()
+
+	// WHEN creating it
+	intent, err := suite.gateway.CreatePaymentIntent(context.Background(), dto.StripeCreatePaymentIntentDto{
+		Amount:   1050,
+		Currency: "brl",
+		Metadata: map[string]string{"external_reference": "order-1"},
+	})
+
+	// THEN the intent should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pi_1", intent.Id)
+	assert.Equal(suite.T(), "pi_1_secret", intent.ClientSecret)
+	suite.mockHTTPClient.AssertExpectations(suite.T())
+}
+
+func (suite *StripeGatewayTestSuite) Test_GetPaymentIntent_ShouldExpandLatestCharge() {
+	// GIVEN a refunded payment intent
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.Path == "/v1/payment_intents/pi_1" &&
+			req.URL.Query().Get("expand[]") == "latest_charge"
+	})).Return(jsonResponse(http.StatusOK, `{"id":"pi_1","status":"succeeded","latest_charge":{"id":"ch_1","amount":1000,"amount_refunded":400}}`), nil).Once()
+
+	// WHEN getting it
+	intent, err := suite.gateway.GetPaymentIntent(context.Background(), "pi_1")
+
+	// THEN the charge details should be decoded
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "ch_1", intent.LatestCharge.Id)
+	assert.Equal(suite.T(), int64(400), intent.LatestCharge.AmountRefunded)
+}
+
+func (suite *StripeGatewayTestSuite) Test_GetPaymentIntent_WithUnexpandedCharge_ShouldDecodeChargeId() {
+	// GIVEN a payment intent with the charge id only
+	suite.mockHTTPClient.On("Do", mock.Anything).
+		Return(jsonResponse(http.StatusOK, `{"id":"pi_1","status":"succeeded","latest_charge":"ch_1"}`), nil).Once()
+
+	// WHEN getting it
+	intent, err := suite.gateway.GetPaymentIntent(context.Background(), "pi_1")
+
+	// THEN the charge id should be kept
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "ch_1", intent.LatestCharge.Id)
+}
+
+func (suite *StripeGatewayTestSuite) Test_ConfirmPaymentIntent_WithDeclinedCard_ShouldReturnIntent() {
+	// GIVEN Stripe declining the card
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/payment_intents/pi_1/confirm"
+	})).Return(jsonResponse(http.StatusPaymentRequired, `{"error":{"type":"card_error","code":"card_declined","message":"Your card was declined.","payment_intent":{"id":"pi_1","status":"requires_payment_method","last_payment_error":{"code":"card_declined"}}}}`), nil).Once()
+
+	// WHEN confirming the intent
+	intent, err := suite.gateway.ConfirmPaymentIntent(context.Background(), "pi_1", "pm_card_chargeDeclined")
+
+	// THEN the declined intent should be returned without an error
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "requires_payment_method", intent.Status)
+	assert.Equal(suite.T(), "card_declined", intent.LastPaymentError.Code)
+}
+
+func (suite *StripeGatewayTestSuite) Test_CancelPaymentIntent_WithErrorStatus_ShouldReturnError() {
+	// GIVEN an intent that can no longer be cancelled
+	suite.mockHTTPClient.On("Do", mock.Anything).
+		Return(jsonResponse(http.StatusBadRequest, `{"error":{"type":"invalid_request_error"}}`), nil).Once()
+
+	// WHEN cancelling it
+	_, err := suite.gateway.CancelPaymentIntent(context.Background(), "pi_1")
+
+	// THEN the error should be returned
+	assert.ErrorContains(suite.T(), err, "failed to cancel payment intent pi_1")
+	assert.ErrorContains(suite.T(), err, "400")
+}
+
+func (suite *StripeGatewayTestSuite) Test_CreateRefund_ShouldSendIdempotencyKey() {
+	// GIVEN a partial refund
+	suite.mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		if err := req.ParseForm(); err != nil {
+			return false
+		}
+		return req.URL.Path == "/v1/refunds" &&
+			req.Header.Get("Idempotency-Key") == "refund-7" &&
+			req.PostForm.Get("payment_intent") == "pi_1" &&
+			req.PostForm.Get("amount") == "500"
+	})).Return(jsonResponse(http.StatusOK, `{"id":"re_1","status":"succeeded","amount":500}`), nil).Once()
+
+	// WHEN refunding
+	refund, err := suite.gateway.CreateRefund(context.Background(), "pi_1", 500, "refund-7")
+
+	// THEN the refund should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "re_1", refund.Id)
+}
+
+func TestNewStripeGatewayImpl_WithoutSecretKey_ShouldNotBeConfigured(t *testing.T) {
+	t.Setenv("STRIPE_BASEURL", "")
+	t.Setenv("STRIPE_SECRET_KEY", "")
+
+	gateway, err := gateways.NewStripeGatewayImpl()
+
+	assert.NoError(t, err)
+	assert.False(t, gateway.Configured())
+}
@@ -0,0 +1,102 @@
+package gateways
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+var (
+	_ paymentGateways.PaymentGateway           = (*StripePaymentGateway)(nil)
+	_ paymentGateways.OptionalPaymentGateway   = (*StripePaymentGateway)(nil)
+	_ paymentGateways.CapturablePaymentGateway = (*StripePaymentGateway)(nil)
+)
+
+// StripePaymentGateway adapts the Stripe payment intents API to the PaymentGateway port. Each payment
+// is a payment intent; the client confirms it with the returned client secret, unless a tokenized
+// payment method came with the request. Intents are captured manually, so a confirmed card is only
+// authorized until CaptureCharge.
+type StripePaymentGateway struct {
+	stripeGateway paymentGateways.StripeGateway
+}
+
+func NewStripePaymentGateway(stripeGateway paymentGateways.StripeGateway) *StripePaymentGateway {
+	return &StripePaymentGateway{stripeGateway: stripeGateway}
+}
+
+func (g *StripePaymentGateway) Name() string {
+	return entities.PaymentProviderStripe
+}
+
+// Enabled leaves card payments unsupported when no Stripe secret key is configured.
+func (g *StripePaymentGateway) Enabled() bool {
+	return g.stripeGateway.Configured()
+}
+
+func (g *StripePaymentGateway) CreateCharge(ctx context.Context, request paymentGateways.ChargeRequest) (*paymentGateways.Charge, error) {
+	intent, err := g.stripeGateway.CreatePaymentIntent(ctx, dto.StripeCreatePaymentIntentDto{
+		Amount:        request.Total.Amount.MinorUnits(),
+		Currency:      strings.ToLower(string(request.Total.Currency)),
+		CaptureMethod: "manual",
+		Description:   request.ExternalReference,
+		Metadata:      map[string]string{"external_reference": request.ExternalReference},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if request.PaymentMethod != "" {
+		// The outcome of the confirmation arrives through the webhook, like any other settlement
+		if _, err := g.stripeGateway.ConfirmPaymentIntent(ctx, intent.Id, request.PaymentMethod); err != nil {
+			if _, cancelErr := g.stripeGateway.CancelPaymentIntent(ctx, intent.Id); cancelErr != nil {
+				println("ERROR: Failed to cancel unconfirmed payment intent", intent.Id+":", cancelErr.Error())
+			}
+			return nil, err
+		}
+	}
+
+	return &paymentGateways.Charge{
+		ProviderPaymentId: intent.Id,
+		ClientSecret:      intent.ClientSecret,
+	}, nil
+}
+
+func (g *StripePaymentGateway) GetChargeStatus(ctx context.Context, providerPaymentId string) (*paymentGateways.ChargeStatus, error) {
+	intent, err := g.stripeGateway.GetPaymentIntent(ctx, providerPaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentGateways.ChargeStatus{
+		ProviderPaymentId: intent.Id,
+		ExternalReference: intent.Metadata["external_reference"],
+		Status:            paymentGateways.StripePaymentIntentStatus(intent),
+		CapturedAmount:    money.FromMinorUnits(intent.AmountReceived),
+	}, nil
+}
+
+func (g *StripePaymentGateway) CancelCharge(ctx context.Context, payment *entities.Payment) error {
+	_, err := g.stripeGateway.CancelPaymentIntent(ctx, payment.ProviderPaymentId)
+	return err
+}
+
+func (g *StripePaymentGateway) CaptureCharge(ctx context.Context, payment *entities.Payment, amount money.Amount) error {
+	_, err := g.stripeGateway.CapturePaymentIntent(ctx, payment.ProviderPaymentId, amount.MinorUnits())
+	return err
+}
+
+func (g *StripePaymentGateway) RefundCharge(ctx context.Context, payment *entities.Payment, amount money.Amount, idempotencyKey string) (*paymentGateways.RefundResult, error) {
+	refund, err := g.stripeGateway.CreateRefund(ctx, payment.ProviderPaymentId, amount.MinorUnits(), idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentGateways.RefundResult{
+		ProviderRefundId: refund.Id,
+		Status:           paymentGateways.StripeRefundStatus(refund),
+	}, nil
+}
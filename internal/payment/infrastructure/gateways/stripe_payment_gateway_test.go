@@ -0,0 +1,192 @@
+package gateways_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	paymentGateways "github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/gateways"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StripePaymentGatewayTestSuite struct {
+	suite.Suite
+	mockStripe *mockGateways.MockStripeGateway
+	gateway    *gateways.StripePaymentGateway
+}
+
+func (suite *StripePaymentGatewayTestSuite) SetupTest() {
+	suite.mockStripe = mockGateways.NewMockStripeGateway(suite.T())
+	suite.gateway = gateways.NewStripePaymentGateway(suite.mockStripe)
+}
+
+func TestStripePaymentGatewayTestSuite(t *testing.T) {
+	suite.Run(t, new(StripePaymentGatewayTestSuite))
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_CreateCharge_ShouldCreatePaymentIntent() {
+	// GIVEN a card charge without a payment method
+	request := paymentGateways.ChargeRequest{
+		ExternalReference: "order-1",
+		Total:             money.New(money.MustParse("10.50"), money.BRL),
+	}
+
+	suite.mockStripe.EXPECT().
+		CreatePaymentIntent(mock.Anything, mock.MatchedBy(func(intent dto.StripeCreatePaymentIntentDto) bool {
+			return intent.Amount == 1050 &&
+				intent.Currency == "brl" &&
+				intent.CaptureMethod == "manual" &&
+				intent.Metadata["external_reference"] == "order-1"
+		})).
+		Return(dto.StripePaymentIntentDto{Id: "pi_1", ClientSecret: "pi_1_secret", Status: "requires_payment_method"}, nil).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), request)
+
+	// THEN the intent should be returned for the client to confirm
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pi_1", charge.ProviderPaymentId)
+	assert.Equal(suite.T(), "pi_1_secret", charge.ClientSecret)
+	assert.Empty(suite.T(), charge.QRData)
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_CreateCharge_WithPaymentMethod_ShouldConfirmPaymentIntent() {
+	// GIVEN a card charge with a tokenized card
+	suite.mockStripe.EXPECT().
+		CreatePaymentIntent(mock.Anything, mock.Anything).
+		Return(dto.StripePaymentIntentDto{Id: "pi_1", ClientSecret: "pi_1_secret"}, nil).
+		Once()
+	suite.mockStripe.EXPECT().
+		ConfirmPaymentIntent(mock.Anything, "pi_1", "pm_card_visa").
+		Return(dto.StripePaymentIntentDto{Id: "pi_1", Status: "succeeded"}, nil).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), paymentGateways.ChargeRequest{PaymentMethod: "pm_card_visa"})
+
+	// THEN the intent should be confirmed
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pi_1", charge.ProviderPaymentId)
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_CreateCharge_WithConfirmError_ShouldCancelPaymentIntent() {
+	// GIVEN Stripe failing to confirm the intent
+	expectedError := errors.New("stripe unavailable")
+	suite.mockStripe.EXPECT().
+		CreatePaymentIntent(mock.Anything, mock.Anything).
+		Return(dto.StripePaymentIntentDto{Id: "pi_1"}, nil).
+		Once()
+	suite.mockStripe.EXPECT().
+		ConfirmPaymentIntent(mock.Anything, "pi_1", "pm_card_visa").
+		Return(dto.StripePaymentIntentDto{}, expectedError).
+		Once()
+	suite.mockStripe.EXPECT().
+		CancelPaymentIntent(mock.Anything, "pi_1").
+		Return(dto.StripePaymentIntentDto{Id: "pi_1", Status: "canceled"}, nil).
+		Once()
+
+	// WHEN creating the charge
+	charge, err := suite.gateway.CreateCharge(context.Background(), paymentGateways.ChargeRequest{PaymentMethod: "pm_card_visa"})
+
+	// THEN the intent should be cancelled and the error returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), charge)
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_GetChargeStatus_ShouldMapPaymentIntentStatus() {
+	tests := []struct {
+		intent   dto.StripePaymentIntentDto
+		expected entities.PaymentStatus
+	}{
+		{intent: dto.StripePaymentIntentDto{Status: "succeeded"}, expected: entities.PaymentStatusApproved},
+		{intent: dto.StripePaymentIntentDto{Status: "succeeded", LatestCharge: &dto.StripeChargeDto{Amount: 1000, AmountRefunded: 400}}, expected: entities.PaymentStatusPartiallyRefunded},
+		{intent: dto.StripePaymentIntentDto{Status: "succeeded", LatestCharge: &dto.StripeChargeDto{Amount: 1000, AmountRefunded: 1000, Refunded: true}}, expected: entities.PaymentStatusRefunded},
+		{intent: dto.StripePaymentIntentDto{Status: "requires_payment_method"}, expected: entities.PaymentStatusPending},
+		{intent: dto.StripePaymentIntentDto{Status: "requires_payment_method", LastPaymentError: &dto.StripeErrorDto{Code: "card_declined"}}, expected: entities.PaymentStatusDeclined},
+		{intent: dto.StripePaymentIntentDto{Status: "processing"}, expected: entities.PaymentStatusPending},
+		{intent: dto.StripePaymentIntentDto{Status: "requires_capture"}, expected: entities.PaymentStatusAuthorized},
+		{intent: dto.StripePaymentIntentDto{Status: "canceled"}, expected: entities.PaymentStatusCancelled},
+	}
+	for _, test := range tests {
+		// GIVEN a payment intent
+		test.intent.Id = "pi_1"
+		test.intent.Metadata = map[string]string{"external_reference": "order-1"}
+		suite.mockStripe.EXPECT().
+			GetPaymentIntent(mock.Anything, "pi_1").
+			Return(test.intent, nil).
+			Once()
+
+		// WHEN getting its status
+		status, err := suite.gateway.GetChargeStatus(context.Background(), "pi_1")
+
+		// THEN it should be mapped to ours
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), "pi_1", status.ProviderPaymentId)
+		assert.Equal(suite.T(), "order-1", status.ExternalReference)
+		assert.Equal(suite.T(), test.expected, status.Status, test.intent.Status)
+	}
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_CancelCharge_ShouldCancelPaymentIntent() {
+	// GIVEN a pending card payment
+	suite.mockStripe.EXPECT().
+		CancelPaymentIntent(mock.Anything, "pi_1").
+		Return(dto.StripePaymentIntentDto{Id: "pi_1", Status: "canceled"}, nil).
+		Once()
+
+	// WHEN cancelling it
+	err := suite.gateway.CancelCharge(context.Background(), &entities.Payment{ID: 1, ProviderPaymentId: "pi_1"})
+
+	// THEN the payment intent should be cancelled
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_CaptureCharge_ShouldCaptureAmountInMinorUnits() {
+	// GIVEN an authorized card payment
+	suite.mockStripe.EXPECT().
+		CapturePaymentIntent(mock.Anything, "pi_1", int64(4200)).
+		Return(dto.StripePaymentIntentDto{Id: "pi_1", Status: "succeeded", AmountReceived: 4200}, nil).
+		Once()
+
+	// WHEN capturing part of it
+	err := suite.gateway.CaptureCharge(context.Background(), &entities.Payment{ID: 1, ProviderPaymentId: "pi_1"}, money.MustParse("42.00"))
+
+	// THEN the payment intent should be captured
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *StripePaymentGatewayTestSuite) Test_RefundCharge_ShouldRefundPaymentIntent() {
+	// GIVEN an approved card payment
+	payment := &entities.Payment{ID: 1, ProviderPaymentId: "pi_1"}
+
+	suite.mockStripe.EXPECT().
+		CreateRefund(mock.Anything, "pi_1", int64(500), "refund-7").
+		Return(dto.StripeRefundDto{Id: "re_1", Status: "succeeded"}, nil).
+		Once()
+
+	// WHEN refunding part of it
+	result, err := suite.gateway.RefundCharge(context.Background(), payment, money.MustParse("5.00"), "refund-7")
+
+	// THEN the refund id and status should be mapped
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "re_1", result.ProviderRefundId)
+	assert.Equal(suite.T(), entities.RefundStatusApproved, result.Status)
+}
+
+func TestStripePaymentGateway_Enabled(t *testing.T) {
+	stripe := mockGateways.NewMockStripeGateway(t)
+	stripe.EXPECT().Configured().Return(false).Once()
+
+	gateway := gateways.NewStripePaymentGateway(stripe)
+
+	assert.Equal(t, entities.PaymentProviderStripe, gateway.Name())
+	assert.False(t, gateway.Enabled())
+}
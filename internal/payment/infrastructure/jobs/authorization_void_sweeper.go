@@ -0,0 +1,118 @@
+package jobs
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	voidAuthorizationsUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/voidAuthorizations"
+)
+
+// voidedAuthorizations counts authorizations voided by the sweeper, exposed through /debug/vars.
+var voidedAuthorizations = expvar.NewInt("authorizations_voided")
+
+type AuthorizationVoidSweeperConfig struct {
+	// VoidAfter is how long an authorization may wait for its capture before it is voided.
+	VoidAfter time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+func (c *AuthorizationVoidSweeperConfig) Validate() error {
+	if c.VoidAfter <= 0 || c.Interval <= 0 || c.BatchSize <= 0 {
+		return fmt.Errorf("invalid AuthorizationVoidSweeperConfig: void after, interval and batch size must be positive")
+	}
+	return nil
+}
+
+func newAuthorizationVoidSweeperConfig() (*AuthorizationVoidSweeperConfig, error) {
+	voidAfter, err := durationFromEnv("PAYMENT_AUTHORIZATION_VOID_AFTER", 48*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := durationFromEnv("PAYMENT_AUTHORIZATION_VOID_INTERVAL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := intFromEnv("PAYMENT_AUTHORIZATION_VOID_BATCH_SIZE", 50)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizationVoidSweeperConfig{
+		VoidAfter: voidAfter,
+		Interval:  interval,
+		BatchSize: batchSize,
+	}, nil
+}
+
+// AuthorizationVoidSweeper periodically voids card authorizations that were not captured in time, so
+// the customer's funds are not held for an order that will not be prepared.
+type AuthorizationVoidSweeper struct {
+	config                    *AuthorizationVoidSweeperConfig
+	voidAuthorizationsUseCase voidAuthorizationsUseCase.VoidAuthorizationsUseCase
+	stop                      chan struct{}
+	done                      sync.WaitGroup
+}
+
+func NewAuthorizationVoidSweeper(voidAuthorizationsUseCase voidAuthorizationsUseCase.VoidAuthorizationsUseCase) (*AuthorizationVoidSweeper, error) {
+	config, err := newAuthorizationVoidSweeperConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewAuthorizationVoidSweeperWithConfig(voidAuthorizationsUseCase, config)
+}
+
+func NewAuthorizationVoidSweeperWithConfig(
+	voidAuthorizationsUseCase voidAuthorizationsUseCase.VoidAuthorizationsUseCase,
+	config *AuthorizationVoidSweeperConfig) (*AuthorizationVoidSweeper, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &AuthorizationVoidSweeper{
+		config:                    config,
+		voidAuthorizationsUseCase: voidAuthorizationsUseCase,
+	}, nil
+}
+
+func (s *AuthorizationVoidSweeper) Start() {
+	s.stop = make(chan struct{})
+	s.done.Add(1)
+
+	go func() {
+		defer s.done.Done()
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the sweep loop and waits for an in-flight sweep to finish.
+func (s *AuthorizationVoidSweeper) Stop() {
+	close(s.stop)
+	s.done.Wait()
+}
+
+func (s *AuthorizationVoidSweeper) RunOnce() {
+	command := commands.NewVoidAuthorizationsCommand(time.Now().Add(-s.config.VoidAfter), s.config.BatchSize)
+	voided, err := s.voidAuthorizationsUseCase.Execute(command)
+	voidedAuthorizations.Add(int64(voided))
+
+	if err != nil {
+		log.Printf("Authorization void sweep failed: %v", err)
+	}
+	if voided > 0 {
+		log.Printf("Voided %d uncaptured authorization(s)", voided)
+	}
+}
@@ -0,0 +1,92 @@
+package jobs_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/jobs"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockVoidAuthorizations "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/voidAuthorizations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func validAuthorizationVoidSweeperConfig() *jobs.AuthorizationVoidSweeperConfig {
+	return &jobs.AuthorizationVoidSweeperConfig{
+		VoidAfter: time.Hour,
+		Interval:  10 * time.Millisecond,
+		BatchSize: 50,
+	}
+}
+
+func TestAuthorizationVoidSweeperConfig_Validate(t *testing.T) {
+	// GIVEN a valid configuration
+	config := validAuthorizationVoidSweeperConfig()
+	assert.NoError(t, config.Validate())
+
+	// WHEN authorizations would be voided right away
+	config.VoidAfter = 0
+
+	// THEN it should be rejected
+	assert.Error(t, config.Validate())
+}
+
+func TestNewAuthorizationVoidSweeper_WithInvalidEnv_ShouldFail(t *testing.T) {
+	// GIVEN an unparsable void window
+	t.Setenv("PAYMENT_AUTHORIZATION_VOID_AFTER", "two days")
+
+	// WHEN creating the sweeper
+	sweeper, err := jobs.NewAuthorizationVoidSweeper(mockVoidAuthorizations.NewMockVoidAuthorizationsUseCase(t))
+
+	// THEN it should fail
+	assert.Error(t, err)
+	assert.Nil(t, sweeper)
+}
+
+func TestAuthorizationVoidSweeper_RunOnce_ShouldVoidAuthorizationsOlderThanWindow(t *testing.T) {
+	// GIVEN a sweeper voiding authorizations after an hour
+	useCase := mockVoidAuthorizations.NewMockVoidAuthorizationsUseCase(t)
+	sweeper, err := jobs.NewAuthorizationVoidSweeperWithConfig(useCase, validAuthorizationVoidSweeperConfig())
+	assert.NoError(t, err)
+
+	useCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.VoidAuthorizationsCommand) bool {
+			age := time.Since(command.AuthorizedBefore)
+			return command.BatchSize == 50 && age >= time.Hour && age < time.Hour+time.Minute
+		})).
+		Return(1, errors.New("database error")).
+		Once()
+
+	// WHEN running a sweep, THEN errors should be logged rather than propagated
+	sweeper.RunOnce()
+}
+
+func TestAuthorizationVoidSweeper_StartStop_ShouldSweepPeriodically(t *testing.T) {
+	// GIVEN a sweeper with a short interval
+	useCase := mockVoidAuthorizations.NewMockVoidAuthorizationsUseCase(t)
+	sweeper, err := jobs.NewAuthorizationVoidSweeperWithConfig(useCase, validAuthorizationVoidSweeperConfig())
+	assert.NoError(t, err)
+
+	swept := make(chan struct{}, 1)
+	useCase.EXPECT().
+		Execute(mock.Anything).
+		Run(func(*commands.VoidAuthorizationsCommand) {
+			select {
+			case swept <- struct{}{}:
+			default:
+			}
+		}).
+		Return(0, nil)
+
+	// WHEN starting it
+	sweeper.Start()
+
+	// THEN it should sweep until stopped
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Fatal("authorizations were not swept")
+	}
+	sweeper.Stop()
+}
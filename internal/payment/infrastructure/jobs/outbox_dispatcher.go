@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	dispatchOutboxUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/dispatchOutbox"
+)
+
+// outboxDeliveries counts dispatch outcomes, exposed through /debug/vars.
+var outboxDeliveries = expvar.NewMap("order_status_outbox_deliveries")
+
+type OutboxDispatcherConfig struct {
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts uint
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (c *OutboxDispatcherConfig) Validate() error {
+	if c.Interval <= 0 || c.BaseBackoff <= 0 || c.MaxBackoff < c.BaseBackoff {
+		return fmt.Errorf("invalid OutboxDispatcherConfig: interval and backoffs must be positive and max backoff not below base backoff")
+	}
+	if c.BatchSize <= 0 || c.MaxAttempts == 0 {
+		return fmt.Errorf("invalid OutboxDispatcherConfig: batch size and max attempts must be positive")
+	}
+	return nil
+}
+
+func newOutboxDispatcherConfig() (*OutboxDispatcherConfig, error) {
+	interval, err := durationFromEnv("ORDER_OUTBOX_DISPATCH_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := intFromEnv("ORDER_OUTBOX_BATCH_SIZE", 20)
+	if err != nil {
+		return nil, err
+	}
+	maxAttempts, err := intFromEnv("ORDER_OUTBOX_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return nil, err
+	}
+	baseBackoff, err := durationFromEnv("ORDER_OUTBOX_BASE_BACKOFF", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	maxBackoff, err := durationFromEnv("ORDER_OUTBOX_MAX_BACKOFF", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxDispatcherConfig{
+		Interval:    interval,
+		BatchSize:   batchSize,
+		MaxAttempts: uint(max(maxAttempts, 0)),
+		BaseBackoff: baseBackoff,
+		MaxBackoff:  maxBackoff,
+	}, nil
+}
+
+// OutboxDispatcher periodically delivers pending order status updates to the Order Service.
+type OutboxDispatcher struct {
+	config                *OutboxDispatcherConfig
+	dispatchOutboxUseCase dispatchOutboxUseCase.DispatchOutboxUseCase
+	stop                  chan struct{}
+	done                  sync.WaitGroup
+}
+
+func NewOutboxDispatcher(dispatchOutboxUseCase dispatchOutboxUseCase.DispatchOutboxUseCase) (*OutboxDispatcher, error) {
+	config, err := newOutboxDispatcherConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewOutboxDispatcherWithConfig(dispatchOutboxUseCase, config)
+}
+
+func NewOutboxDispatcherWithConfig(
+	dispatchOutboxUseCase dispatchOutboxUseCase.DispatchOutboxUseCase,
+	config *OutboxDispatcherConfig) (*OutboxDispatcher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &OutboxDispatcher{
+		config:                config,
+		dispatchOutboxUseCase: dispatchOutboxUseCase,
+	}, nil
+}
+
+func (d *OutboxDispatcher) Start() {
+	d.stop = make(chan struct{})
+	d.done.Add(1)
+
+	go func() {
+		defer d.done.Done()
+
+		ticker := time.NewTicker(d.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.RunOnce()
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the dispatch loop and waits for an in-flight round to finish.
+func (d *OutboxDispatcher) Stop() {
+	close(d.stop)
+	d.done.Wait()
+}
+
+func (d *OutboxDispatcher) RunOnce() {
+	result, err := d.dispatchOutboxUseCase.Execute(commands.NewDispatchOutboxCommand(
+		d.config.BatchSize,
+		d.config.MaxAttempts,
+		d.config.BaseBackoff,
+		d.config.MaxBackoff))
+
+	outboxDeliveries.Add("delivered", int64(result.Delivered))
+	outboxDeliveries.Add("rescheduled", int64(result.Rescheduled))
+	outboxDeliveries.Add("failed", int64(result.Failed))
+
+	if err != nil {
+		log.Printf("Order status outbox dispatch failed: %v", err)
+	}
+	if result.Failed > 0 {
+		log.Printf("%d order status update(s) exhausted their delivery attempts", result.Failed)
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func intFromEnv(key string, fallback int) (int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,99 @@
+package jobs_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/jobs"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	dispatchoutbox "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/dispatchOutbox"
+	mockDispatchOutbox "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/dispatchOutbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func validOutboxDispatcherConfig() *jobs.OutboxDispatcherConfig {
+	return &jobs.OutboxDispatcherConfig{
+		Interval:    10 * time.Millisecond,
+		BatchSize:   20,
+		MaxAttempts: 5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  time.Minute,
+	}
+}
+
+func TestOutboxDispatcherConfig_Validate(t *testing.T) {
+	// GIVEN a valid configuration
+	config := validOutboxDispatcherConfig()
+	assert.NoError(t, config.Validate())
+
+	// WHEN max backoff is below base backoff
+	config.MaxBackoff = time.Millisecond
+
+	// THEN it should be rejected
+	assert.Error(t, config.Validate())
+
+	// WHEN no attempts are allowed
+	config = validOutboxDispatcherConfig()
+	config.MaxAttempts = 0
+
+	// THEN it should be rejected
+	assert.Error(t, config.Validate())
+}
+
+func TestNewOutboxDispatcher_WithInvalidEnv_ShouldFail(t *testing.T) {
+	// GIVEN an unparsable interval
+	t.Setenv("ORDER_OUTBOX_DISPATCH_INTERVAL", "soon")
+
+	// WHEN creating the dispatcher
+	dispatcher, err := jobs.NewOutboxDispatcher(mockDispatchOutbox.NewMockDispatchOutboxUseCase(t))
+
+	// THEN it should fail
+	assert.Error(t, err)
+	assert.Nil(t, dispatcher)
+}
+
+func TestOutboxDispatcher_RunOnce_ShouldDispatchWithConfiguredPolicy(t *testing.T) {
+	// GIVEN a dispatcher
+	useCase := mockDispatchOutbox.NewMockDispatchOutboxUseCase(t)
+	dispatcher, err := jobs.NewOutboxDispatcherWithConfig(useCase, validOutboxDispatcherConfig())
+	assert.NoError(t, err)
+
+	useCase.EXPECT().
+		Execute(commands.NewDispatchOutboxCommand(20, 5, time.Second, time.Minute)).
+		Return(dispatchoutbox.DispatchResult{Delivered: 1}, errors.New("database error")).
+		Once()
+
+	// WHEN running a round, THEN errors should be logged rather than propagated
+	dispatcher.RunOnce()
+}
+
+func TestOutboxDispatcher_StartStop_ShouldDispatchPeriodically(t *testing.T) {
+	// GIVEN a dispatcher with a short interval
+	useCase := mockDispatchOutbox.NewMockDispatchOutboxUseCase(t)
+	dispatcher, err := jobs.NewOutboxDispatcherWithConfig(useCase, validOutboxDispatcherConfig())
+	assert.NoError(t, err)
+
+	dispatched := make(chan struct{}, 1)
+	useCase.EXPECT().
+		Execute(mock.Anything).
+		Run(func(*commands.DispatchOutboxCommand) {
+			select {
+			case dispatched <- struct{}{}:
+			default:
+			}
+		}).
+		Return(dispatchoutbox.DispatchResult{}, nil)
+
+	// WHEN starting it
+	dispatcher.Start()
+
+	// THEN it should dispatch until stopped
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("outbox was not dispatched")
+	}
+	dispatcher.Stop()
+}
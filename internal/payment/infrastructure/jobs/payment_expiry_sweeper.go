@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	expirePaymentsUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/expirePayments"
+)
+
+// expiredPayments counts payments expired by the sweeper, exposed through /debug/vars.
+var expiredPayments = expvar.NewInt("payments_expired")
+
+type PaymentExpirySweeperConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func (c *PaymentExpirySweeperConfig) Validate() error {
+	if c.Interval <= 0 || c.BatchSize <= 0 {
+		return fmt.Errorf("invalid PaymentExpirySweeperConfig: interval and batch size must be positive")
+	}
+	return nil
+}
+
+func newPaymentExpirySweeperConfig() (*PaymentExpirySweeperConfig, error) {
+	interval, err := durationFromEnv("PAYMENT_EXPIRY_SWEEP_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := intFromEnv("PAYMENT_EXPIRY_BATCH_SIZE", 50)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentExpirySweeperConfig{
+		Interval:  interval,
+		BatchSize: batchSize,
+	}, nil
+}
+
+// PaymentExpirySweeper periodically expires pending payments whose QR code is past its expiration date.
+type PaymentExpirySweeper struct {
+	config                *PaymentExpirySweeperConfig
+	expirePaymentsUseCase expirePaymentsUseCase.ExpirePaymentsUseCase
+	stop                  chan struct{}
+	done                  sync.WaitGroup
+}
+
+func NewPaymentExpirySweeper(expirePaymentsUseCase expirePaymentsUseCase.ExpirePaymentsUseCase) (*PaymentExpirySweeper, error) {
+	config, err := newPaymentExpirySweeperConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewPaymentExpirySweeperWithConfig(expirePaymentsUseCase, config)
+}
+
+func NewPaymentExpirySweeperWithConfig(
+	expirePaymentsUseCase expirePaymentsUseCase.ExpirePaymentsUseCase,
+	config *PaymentExpirySweeperConfig) (*PaymentExpirySweeper, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &PaymentExpirySweeper{
+		config:                config,
+		expirePaymentsUseCase: expirePaymentsUseCase,
+	}, nil
+}
+
+func (s *PaymentExpirySweeper) Start() {
+	s.stop = make(chan struct{})
+	s.done.Add(1)
+
+	go func() {
+		defer s.done.Done()
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the sweep loop and waits for an in-flight sweep to finish.
+func (s *PaymentExpirySweeper) Stop() {
+	close(s.stop)
+	s.done.Wait()
+}
+
+func (s *PaymentExpirySweeper) RunOnce() {
+	expired, err := s.expirePaymentsUseCase.Execute(commands.NewExpirePaymentsCommand(s.config.BatchSize))
+	expiredPayments.Add(int64(expired))
+
+	if err != nil {
+		log.Printf("Payment expiry sweep failed: %v", err)
+	}
+	if expired > 0 {
+		log.Printf("Expired %d pending payment(s)", expired)
+	}
+}
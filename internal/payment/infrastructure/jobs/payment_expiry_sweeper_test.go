@@ -0,0 +1,88 @@
+package jobs_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/jobs"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockExpirePayments "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/expirePayments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func validPaymentExpirySweeperConfig() *jobs.PaymentExpirySweeperConfig {
+	return &jobs.PaymentExpirySweeperConfig{
+		Interval:  10 * time.Millisecond,
+		BatchSize: 50,
+	}
+}
+
+func TestPaymentExpirySweeperConfig_Validate(t *testing.T) {
+	// GIVEN a valid configuration
+	config := validPaymentExpirySweeperConfig()
+	assert.NoError(t, config.Validate())
+
+	// WHEN the batch size is not positive
+	config.BatchSize = 0
+
+	// THEN it should be rejected
+	assert.Error(t, config.Validate())
+}
+
+func TestNewPaymentExpirySweeper_WithInvalidEnv_ShouldFail(t *testing.T) {
+	// GIVEN an unparsable interval
+	t.Setenv("PAYMENT_EXPIRY_SWEEP_INTERVAL", "soon")
+
+	// WHEN creating the sweeper
+	sweeper, err := jobs.NewPaymentExpirySweeper(mockExpirePayments.NewMockExpirePaymentsUseCase(t))
+
+	// THEN it should fail
+	assert.Error(t, err)
+	assert.Nil(t, sweeper)
+}
+
+func TestPaymentExpirySweeper_RunOnce_ShouldSweepConfiguredBatch(t *testing.T) {
+	// GIVEN a sweeper
+	useCase := mockExpirePayments.NewMockExpirePaymentsUseCase(t)
+	sweeper, err := jobs.NewPaymentExpirySweeperWithConfig(useCase, validPaymentExpirySweeperConfig())
+	assert.NoError(t, err)
+
+	useCase.EXPECT().
+		Execute(commands.NewExpirePaymentsCommand(50)).
+		Return(1, errors.New("database error")).
+		Once()
+
+	// WHEN running a sweep, THEN errors should be logged rather than propagated
+	sweeper.RunOnce()
+}
+
+func TestPaymentExpirySweeper_StartStop_ShouldSweepPeriodically(t *testing.T) {
+	// GIVEN a sweeper with a short interval
+	useCase := mockExpirePayments.NewMockExpirePaymentsUseCase(t)
+	sweeper, err := jobs.NewPaymentExpirySweeperWithConfig(useCase, validPaymentExpirySweeperConfig())
+	assert.NoError(t, err)
+
+	swept := make(chan struct{}, 1)
+	useCase.EXPECT().
+		Execute(mock.Anything).
+		Run(func(*commands.ExpirePaymentsCommand) {
+			select {
+			case swept <- struct{}{}:
+			default:
+			}
+		}).
+		Return(0, nil)
+
+	// WHEN starting it
+	sweeper.Start()
+
+	// THEN it should sweep until stopped
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Fatal("payments were not swept")
+	}
+	sweeper.Stop()
+}
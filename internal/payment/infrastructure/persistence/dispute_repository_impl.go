@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	_ repositories.DisputeRepository = (*DisputeRepositoryImpl)(nil)
+)
+
+const defaultDisputesLimit = 50
+
+type DisputeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewDisputeRepositoryImpl(db *gorm.DB) *DisputeRepositoryImpl {
+	return &DisputeRepositoryImpl{db: db}
+}
+
+func (r *DisputeRepositoryImpl) FindDispute(provider string, disputeType entities.DisputeType, providerDisputeId string) (*entities.Dispute, error) {
+	var dispute entities.Dispute
+	err := r.db.
+		Where("provider = ? AND type = ? AND provider_dispute_id = ?", provider, disputeType, providerDisputeId).
+		First(&dispute).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *DisputeRepositoryImpl) SaveDispute(dispute *entities.Dispute, payment *entities.Payment, change *entities.PaymentStatusChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(dispute).Error; err != nil {
+			return err
+		}
+		if payment == nil {
+			return nil
+		}
+		if err := lockPaymentStatus(tx, payment, change); err != nil {
+			if errors.Is(err, errStatusAlreadyApplied) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		if change == nil {
+			return nil
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (r *DisputeRepositoryImpl) ListDisputes(filter repositories.DisputeFilter) ([]*entities.Dispute, error) {
+	query := r.db.Model(&entities.Dispute{})
+	if filter.OrderId != 0 {
+		query = query.Where("order_id = ?", filter.OrderId)
+	}
+	if filter.PaymentId != 0 {
+		query = query.Where("payment_id = ?", filter.PaymentId)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDisputesLimit
+	}
+
+	var disputes []*entities.Dispute
+	if err := query.Order("opened_at DESC, id DESC").Limit(limit).Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
@@ -0,0 +1,79 @@
+package persistence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisputeRepository_FindDispute(t *testing.T) {
+	// GIVEN a stored chargeback
+	db := setupTestDB(t)
+	payment := newApprovedPayment(t, persistence.NewPaymentRepositoryImpl(db))
+	payment.Provider = entities.PaymentProviderMercadoPago
+	repo := persistence.NewDisputeRepositoryImpl(db)
+	dispute := entities.NewDispute(payment, entities.DisputeTypeChargeback, "cb-1", payment.Total, "fraud", time.Now())
+	assert.NoError(t, repo.SaveDispute(dispute, nil, nil))
+
+	// WHEN finding it and a claim with the same provider id
+	found, err := repo.FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeChargeback, "cb-1")
+	missing, missingErr := repo.FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeClaim, "cb-1")
+
+	// THEN only the chargeback should be found
+	assert.NoError(t, err)
+	assert.Equal(t, dispute.ID, found.ID)
+	assert.Equal(t, entities.PaymentStatusApproved, found.PaymentStatus)
+	assert.NoError(t, missingErr)
+	assert.Nil(t, missing)
+}
+
+func TestDisputeRepository_SaveDispute_ShouldSavePaymentAndStatusChange(t *testing.T) {
+	// GIVEN a new dispute on an approved payment
+	db := setupTestDB(t)
+	paymentRepo := persistence.NewPaymentRepositoryImpl(db)
+	payment := newApprovedPayment(t, paymentRepo)
+	repo := persistence.NewDisputeRepositoryImpl(db)
+	dispute := entities.NewDispute(payment, entities.DisputeTypeClaim, "42", payment.Total, "", time.Now())
+
+	// WHEN saving it with the payment moved to disputed
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusDisputed))
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusApproved, entities.PaymentStatusChangeSourceWebhook, "57")
+	err := repo.SaveDispute(dispute, payment, change)
+
+	// THEN dispute, payment and status change should be stored
+	assert.NoError(t, err)
+	stored, _ := paymentRepo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusDisputed, stored.Status)
+	changes, _ := paymentRepo.ListPaymentStatusHistory(payment.OrderId)
+	assert.Len(t, changes, 1)
+}
+
+func TestDisputeRepository_ListDisputes(t *testing.T) {
+	// GIVEN an open and a closed dispute
+	db := setupTestDB(t)
+	payment := newApprovedPayment(t, persistence.NewPaymentRepositoryImpl(db))
+	repo := persistence.NewDisputeRepositoryImpl(db)
+	now := time.Now()
+
+	open := entities.NewDispute(payment, entities.DisputeTypeChargeback, "cb-1", payment.Total, "", now)
+	closed := entities.NewDispute(payment, entities.DisputeTypeClaim, "42", payment.Total, "", now.Add(-time.Hour))
+	closed.Close(entities.DisputeOutcomeWon, now)
+	assert.NoError(t, repo.SaveDispute(open, nil, nil))
+	assert.NoError(t, repo.SaveDispute(closed, nil, nil))
+
+	// WHEN listing all and only the open disputes
+	all, err := repo.ListDisputes(repositories.DisputeFilter{OrderId: payment.OrderId})
+	onlyOpen, openErr := repo.ListDisputes(repositories.DisputeFilter{PaymentId: payment.ID, Status: entities.DisputeStatusOpen})
+
+	// THEN disputes should be returned most recently opened first, filtered by payment and status
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, "cb-1", all[0].ProviderDisputeId)
+	assert.NoError(t, openErr)
+	assert.Len(t, onlyOpen, 1)
+	assert.Equal(t, open.ID, onlyOpen[0].ID)
+}
@@ -0,0 +1,48 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	_ repositories.IdempotencyKeyRepository = (*IdempotencyKeyRepositoryImpl)(nil)
+)
+
+type IdempotencyKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepositoryImpl(db *gorm.DB) *IdempotencyKeyRepositoryImpl {
+	return &IdempotencyKeyRepositoryImpl{db: db}
+}
+
+func (r *IdempotencyKeyRepositoryImpl) AddIdempotencyKey(idempotencyKey *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+	if err := r.db.Create(idempotencyKey).Error; err != nil {
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+func (r *IdempotencyKeyRepositoryImpl) FindIdempotencyKey(key string) (*entities.IdempotencyKey, error) {
+	idempotencyKey := &entities.IdempotencyKey{}
+	err := r.db.Where(&entities.IdempotencyKey{Key: key}).First(idempotencyKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+func (r *IdempotencyKeyRepositoryImpl) UpdateIdempotencyKey(idempotencyKey *entities.IdempotencyKey) error {
+	return r.db.Save(idempotencyKey).Error
+}
+
+func (r *IdempotencyKeyRepositoryImpl) DeleteIdempotencyKey(idempotencyKey *entities.IdempotencyKey) error {
+	return r.db.Delete(idempotencyKey).Error
+}
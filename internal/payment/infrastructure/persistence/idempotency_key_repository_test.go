@@ -0,0 +1,65 @@
+package persistence_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeyRepository_AddAndComplete(t *testing.T) {
+	// GIVEN a claimed key
+	db := setupTestDB(t)
+	repo := persistence.NewIdempotencyKeyRepositoryImpl(db)
+	key, err := repo.AddIdempotencyKey(&entities.IdempotencyKey{
+		Key:                "key-1",
+		RequestFingerprint: "abc",
+		CreatedAt:          time.Now(),
+	})
+	assert.NoError(t, err)
+
+	// WHEN storing its response
+	key.Complete(http.StatusCreated, "application/json", []byte(`"qr-data"`))
+	err = repo.UpdateIdempotencyKey(key)
+
+	// THEN the response should be found by key
+	assert.NoError(t, err)
+	stored, err := repo.FindIdempotencyKey("key-1")
+	assert.NoError(t, err)
+	assert.True(t, stored.IsCompleted())
+	assert.Equal(t, http.StatusCreated, stored.ResponseStatus)
+	assert.Equal(t, []byte(`"qr-data"`), stored.ResponseBody)
+}
+
+func TestIdempotencyKeyRepository_AddIdempotencyKey_Duplicate(t *testing.T) {
+	// GIVEN a claimed key
+	db := setupTestDB(t)
+	repo := persistence.NewIdempotencyKeyRepositoryImpl(db)
+	_, err := repo.AddIdempotencyKey(&entities.IdempotencyKey{Key: "key-1", RequestFingerprint: "abc", CreatedAt: time.Now()})
+	assert.NoError(t, err)
+
+	// WHEN claiming it again
+	_, err = repo.AddIdempotencyKey(&entities.IdempotencyKey{Key: "key-1", RequestFingerprint: "abc", CreatedAt: time.Now()})
+
+	// THEN the unique key should reject it
+	assert.Error(t, err)
+}
+
+func TestIdempotencyKeyRepository_DeleteIdempotencyKey(t *testing.T) {
+	// GIVEN a claimed key
+	db := setupTestDB(t)
+	repo := persistence.NewIdempotencyKeyRepositoryImpl(db)
+	key, _ := repo.AddIdempotencyKey(&entities.IdempotencyKey{Key: "key-1", RequestFingerprint: "abc", CreatedAt: time.Now()})
+
+	// WHEN releasing it
+	err := repo.DeleteIdempotencyKey(key)
+
+	// THEN it should no longer be found
+	assert.NoError(t, err)
+	stored, err := repo.FindIdempotencyKey("key-1")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
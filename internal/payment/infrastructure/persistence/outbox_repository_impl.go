@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ repositories.OutboxRepository = (*OutboxRepositoryImpl)(nil)
+)
+
+const defaultOutboxMessagesLimit = 50
+
+// outboxClaimLease is how long a message claimed by a dispatcher stays out of the other dispatchers; it
+// outlasts a batch of deliveries, and lets another replica deliver the message once a dispatcher died
+// halfway.
+const outboxClaimLease = 5 * time.Minute
+
+type OutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepositoryImpl(db *gorm.DB) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{db: db}
+}
+
+func (r *OutboxRepositoryImpl) GetOutboxMessage(id uint) (*entities.OutboxMessage, error) {
+	message := &entities.OutboxMessage{}
+	if err := r.db.First(message, id).Error; err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (r *OutboxRepositoryImpl) ListOutboxMessages(filter repositories.OutboxMessageFilter) ([]*entities.OutboxMessage, error) {
+	query := r.db.Model(&entities.OutboxMessage{})
+	if filter.OrderId != 0 {
+		query = query.Where("order_id = ?", filter.OrderId)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultOutboxMessagesLimit
+	}
+
+	var messages []*entities.OutboxMessage
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *OutboxRepositoryImpl) ClaimDueOutboxMessages(now time.Time, limit int) ([]*entities.OutboxMessage, error) {
+	var messages []*entities.OutboxMessage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Only the oldest undelivered message of an order is claimed: while it is locked by a concurrent
+		// claim, claimed or waiting for its next attempt, the later updates of the order wait behind it
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entities.OutboxStatusPending, now).
+			Where("claimed_until IS NULL OR claimed_until <= ?", now).
+			Where(`NOT EXISTS (SELECT 1 FROM order_status_outbox earlier
+				WHERE earlier.order_id = order_status_outbox.order_id AND earlier.id < order_status_outbox.id
+				AND earlier.status IN ?)`, []entities.OutboxStatus{entities.OutboxStatusPending, entities.OutboxStatusFailed}).
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		claimedUntil := now.Add(outboxClaimLease)
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+			message.ClaimedUntil = &claimedUntil
+		}
+		return tx.Model(&entities.OutboxMessage{}).Where("id IN ?", ids).Update("claimed_until", claimedUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *OutboxRepositoryImpl) UpdateOutboxMessage(message *entities.OutboxMessage) error {
+	return r.db.Save(message).Error
+}
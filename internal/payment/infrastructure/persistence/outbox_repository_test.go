@@ -0,0 +1,149 @@
+package persistence_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOutboxRepository_ClaimDueOutboxMessages(t *testing.T) {
+	// GIVEN due, scheduled, delivered and failed messages
+	db := setupTestDB(t)
+	repo := persistence.NewOutboxRepositoryImpl(db)
+	now := time.Now()
+
+	due := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	due.NextAttemptAt = now.Add(-time.Minute)
+	scheduled := entities.NewOrderStatusOutboxMessage(2, entities.OrderStatusPreparing)
+	scheduled.NextAttemptAt = now.Add(time.Minute)
+	delivered := entities.NewOrderStatusOutboxMessage(3, entities.OrderStatusPreparing)
+	delivered.MarkDelivered()
+	failed := entities.NewOrderStatusOutboxMessage(4, entities.OrderStatusPreparing)
+	failed.MarkAttemptFailed(errors.New("order service unavailable"), now, 1)
+	db.Create([]*entities.OutboxMessage{due, scheduled, delivered, failed})
+
+	// WHEN claiming due messages
+	result, err := repo.ClaimDueOutboxMessages(now, 10)
+
+	// THEN only the due pending message should be returned
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, uint(1), result[0].OrderId)
+}
+
+func TestOutboxRepository_ClaimDueOutboxMessages_WithClaimHeld_ShouldSkipMessage(t *testing.T) {
+	// GIVEN a due message already claimed by another dispatcher
+	db := setupTestDB(t)
+	repo := persistence.NewOutboxRepositoryImpl(db)
+	now := time.Now()
+
+	due := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	due.NextAttemptAt = now.Add(-time.Minute)
+	db.Create(due)
+	claimed, _ := repo.ClaimDueOutboxMessages(now, 10)
+
+	// WHEN claiming due messages again, while the claim holds and once it lapsed
+	held, heldErr := repo.ClaimDueOutboxMessages(now.Add(time.Minute), 10)
+	lapsed, lapsedErr := repo.ClaimDueOutboxMessages(now.Add(time.Hour), 10)
+
+	// THEN the message should only be claimed again once the claim lapsed
+	assert.Len(t, claimed, 1)
+	assert.NoError(t, heldErr)
+	assert.Empty(t, held)
+	assert.NoError(t, lapsedErr)
+	assert.Len(t, lapsed, 1)
+	assert.Equal(t, due.ID, lapsed[0].ID)
+}
+
+func TestOutboxRepository_ClaimDueOutboxMessages_ShouldClaimOrdersInSequence(t *testing.T) {
+	// GIVEN an order with a rescheduled update followed by a due one, and an order with two due updates
+	// behind a failed one
+	db := setupTestDB(t)
+	repo := persistence.NewOutboxRepositoryImpl(db)
+	now := time.Now()
+
+	rescheduled := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	rescheduled.NextAttemptAt = now.Add(time.Minute)
+	behindRescheduled := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusCancelled)
+	behindRescheduled.NextAttemptAt = now.Add(-time.Minute)
+	failed := entities.NewOrderStatusOutboxMessage(2, entities.OrderStatusPreparing)
+	failed.MarkAttemptFailed(errors.New("order service unavailable"), now, 1)
+	behindFailed := entities.NewOrderStatusOutboxMessage(2, entities.OrderStatusCancelled)
+	behindFailed.NextAttemptAt = now.Add(-time.Minute)
+	first := entities.NewOrderStatusOutboxMessage(3, entities.OrderStatusPreparing)
+	first.NextAttemptAt = now.Add(-time.Minute)
+	second := entities.NewOrderStatusOutboxMessage(3, entities.OrderStatusCancelled)
+	second.NextAttemptAt = now.Add(-time.Minute)
+	db.Create([]*entities.OutboxMessage{rescheduled, behindRescheduled, failed, behindFailed, first, second})
+
+	// WHEN claiming due messages
+	result, err := repo.ClaimDueOutboxMessages(now, 10)
+
+	// THEN only the oldest update of the order without earlier undelivered updates should be claimed
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, first.ID, result[0].ID)
+}
+
+func TestOutboxRepository_GetOutboxMessage_NotFound(t *testing.T) {
+	// GIVEN an empty outbox
+	db := setupTestDB(t)
+	repo := persistence.NewOutboxRepositoryImpl(db)
+
+	// WHEN getting an unknown message
+	result, err := repo.GetOutboxMessage(999)
+
+	// THEN not found should be returned
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, result)
+}
+
+func TestOutboxRepository_UpdateOutboxMessage(t *testing.T) {
+	// GIVEN a stored message
+	db := setupTestDB(t)
+	repo := persistence.NewOutboxRepositoryImpl(db)
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	db.Create(message)
+
+	// WHEN marking it delivered
+	message.MarkDelivered()
+	err := repo.UpdateOutboxMessage(message)
+
+	// THEN the delivery should be persisted
+	assert.NoError(t, err)
+	stored, _ := repo.GetOutboxMessage(message.ID)
+	assert.Equal(t, entities.OutboxStatusDelivered, stored.Status)
+	assert.Equal(t, uint(1), stored.Attempts)
+	assert.NotNil(t, stored.DeliveredAt)
+}
+
+func TestOutboxRepository_ListOutboxMessages(t *testing.T) {
+	// GIVEN messages for different orders and statuses
+	db := setupTestDB(t)
+	repo := persistence.NewOutboxRepositoryImpl(db)
+
+	failed := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	failed.MarkAttemptFailed(errors.New("order service unavailable"), time.Now(), 1)
+	db.Create([]*entities.OutboxMessage{
+		failed,
+		entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing),
+		entities.NewOrderStatusOutboxMessage(2, entities.OrderStatusPreparing),
+	})
+
+	// WHEN filtering by order and status
+	byOrder, err := repo.ListOutboxMessages(repositories.OutboxMessageFilter{OrderId: 1})
+	assert.NoError(t, err)
+	byStatus, err := repo.ListOutboxMessages(repositories.OutboxMessageFilter{Status: entities.OutboxStatusFailed})
+	assert.NoError(t, err)
+
+	// THEN only matching messages should be returned
+	assert.Len(t, byOrder, 2)
+	assert.Len(t, byStatus, 1)
+	assert.Equal(t, "order service unavailable", byStatus[0].LastError)
+}
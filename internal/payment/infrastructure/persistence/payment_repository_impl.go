@@ -1,15 +1,28 @@
 package persistence
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	_ repositories.PaymentRepository = (*PaymentRepositoryImpl)(nil)
 )
 
+// errStatusAlreadyApplied ends, without writing anything, a status change that a concurrent writer
+// already made.
+var errStatusAlreadyApplied = errors.New("payment status already applied")
+
+// sweepClaimLease is how long a payment claimed by a sweep stays out of the other sweeps; it outlasts a
+// batch, and lets another replica retry the payment once a sweep fails on it or dies halfway.
+const sweepClaimLease = 5 * time.Minute
+
 type PaymentRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -25,16 +38,302 @@ func (r *PaymentRepositoryImpl) AddPayment(payment *entities.Payment) (*entities
 	return payment, nil
 }
 
+func (r *PaymentRepositoryImpl) GetPaymentById(id uint) (*entities.Payment, error) {
+	payment := &entities.Payment{}
+	if err := r.db.First(payment, id).Error; err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
 func (r *PaymentRepositoryImpl) GetPaymentByOrderId(orderId uint) (*entities.Payment, error) {
 	payment := &entities.Payment{}
 	if err := r.db.
 		Where("order_id = ?", orderId).
+		Order("active DESC, id DESC").
 		First(payment).Error; err != nil {
 		return nil, err
 	}
 	return payment, nil
 }
 
+func (r *PaymentRepositoryImpl) GetPaymentByOrderLeg(orderId uint, leg int) (*entities.Payment, error) {
+	if leg == 0 {
+		payment, err := r.GetPaymentByOrderId(orderId)
+		if err == nil && payment.IsSplitLeg() {
+			return nil, fmt.Errorf("%w: order %d", entities.ErrPaymentLegRequired, orderId)
+		}
+		return payment, err
+	}
+
+	attempts, err := r.ListSplitLegs(orderId)
+	if err != nil {
+		return nil, err
+	}
+	return entities.NewSplitPayment(attempts).Leg(leg)
+}
+
+func (r *PaymentRepositoryImpl) FindActivePaymentByOrderId(orderId uint) (*entities.Payment, error) {
+	payment := &entities.Payment{}
+	err := r.db.
+		Where("order_id = ? AND active", orderId).
+		First(payment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PaymentRepositoryImpl) FindPaymentByProviderPaymentId(provider string, providerPaymentId string) (*entities.Payment, error) {
+	payment := &entities.Payment{}
+	err := r.db.
+		Where("provider = ? AND provider_payment_id = ?", provider, providerPaymentId).
+		Order("id DESC").
+		First(payment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PaymentRepositoryImpl) FindPaymentByExternalReference(externalReference string) (*entities.Payment, error) {
+	payment := &entities.Payment{}
+	err := r.db.
+		Where("external_reference = ?", externalReference).
+		Order("active DESC, id DESC").
+		First(payment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PaymentRepositoryImpl) ClaimExpiredPayments(now time.Time, limit int) ([]*entities.Payment, error) {
+	return r.claimPayments(now, limit, "expires_at ASC",
+		"active AND status = ? AND expires_at <= ?", entities.PaymentStatusPending, now)
+}
+
+func (r *PaymentRepositoryImpl) ClaimStaleAuthorizedPayments(now, authorizedBefore time.Time, limit int) ([]*entities.Payment, error) {
+	return r.claimPayments(now, limit, "authorized_at ASC",
+		"active AND status = ? AND authorized_at <= ?", entities.PaymentStatusAuthorized, authorizedBefore)
+}
+
+// claimPayments picks up to limit payments matching query that no sweep holds, skipping the rows a
+// concurrent claim has locked, and holds them for sweepClaimLease so that replicas sweep disjoint batches.
+func (r *PaymentRepositoryImpl) claimPayments(now time.Time, limit int, order string, query string, args ...any) ([]*entities.Payment, error) {
+	var payments []*entities.Payment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(query, args...).
+			Where("claimed_until IS NULL OR claimed_until <= ?", now).
+			Order(order).
+			Limit(limit).
+			Find(&payments).Error; err != nil {
+			return err
+		}
+		if len(payments) == 0 {
+			return nil
+		}
+		claimedUntil := now.Add(sweepClaimLease)
+		ids := make([]uint, len(payments))
+		for i, payment := range payments {
+			ids[i] = payment.ID
+			payment.ClaimedUntil = &claimedUntil
+		}
+		return tx.Model(&entities.Payment{}).Where("id IN ?", ids).Update("claimed_until", claimedUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepositoryImpl) ListPaymentsByOrderId(orderId uint) ([]*entities.Payment, error) {
+	var payments []*entities.Payment
+	if err := r.db.
+		Where("order_id = ?", orderId).
+		Order("id ASC").
+		Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepositoryImpl) ListSplitLegs(orderId uint) ([]*entities.Payment, error) {
+	latestSplit := r.db.Model(&entities.Payment{}).
+		Select("split_id").
+		Where("order_id = ? AND leg > 0", orderId).
+		Order("id DESC").
+		Limit(1)
+
+	var legs []*entities.Payment
+	if err := r.db.
+		Where("order_id = ? AND split_id = (?)", orderId, latestSplit).
+		Order("id ASC").
+		Find(&legs).Error; err != nil {
+		return nil, err
+	}
+	return legs, nil
+}
+
+func (r *PaymentRepositoryImpl) AddSplitPayment(replaced []*entities.Payment, changes []*entities.PaymentStatusChange, legs []*entities.Payment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Replaced payments go first so that they no longer count as the active payment of their leg
+		for i, payment := range replaced {
+			if err := lockPaymentStatus(tx, payment, changes[i]); err != nil {
+				return err
+			}
+			if err := tx.Save(payment).Error; err != nil {
+				return err
+			}
+		}
+		for _, change := range changes {
+			if err := tx.Create(change).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(legs).Error
+	})
+}
+
 func (r *PaymentRepositoryImpl) UpdatePayment(payment *entities.Payment) error {
-	return r.db.Save(payment).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPaymentStatus(tx, payment, nil); err != nil {
+			return err
+		}
+		return tx.Save(payment).Error
+	})
+}
+
+func (r *PaymentRepositoryImpl) ReplacePayment(replaced *entities.Payment, change *entities.PaymentStatusChange, payment *entities.Payment) (*entities.Payment, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPaymentStatus(tx, replaced, change); err != nil {
+			return err
+		}
+		if err := tx.Save(replaced).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		return tx.Create(payment).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PaymentRepositoryImpl) UpdatePaymentStatus(payment *entities.Payment, change *entities.PaymentStatusChange, message *entities.OutboxMessage) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		splitLeg := message != nil && payment.IsSplitLeg()
+		if splitLeg {
+			// Lock the legs so that the last one to be paid sees the others paid
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("split_id = ?", payment.SplitId).
+				Find(&[]*entities.Payment{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := lockPaymentStatus(tx, payment, change); err != nil {
+			return err
+		}
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+
+		if splitLeg {
+			var legs []*entities.Payment
+			if err := tx.Where("split_id = ?", payment.SplitId).Order("id ASC").Find(&legs).Error; err != nil {
+				return err
+			}
+			if !entities.NewSplitPayment(legs).Notifies(message.OrderStatus) {
+				return nil
+			}
+		}
+		if message == nil {
+			return nil
+		}
+		return tx.Create(message).Error
+	})
+	if errors.Is(err, errStatusAlreadyApplied) {
+		return nil
+	}
+	return err
+}
+
+func (r *PaymentRepositoryImpl) CancelPayment(payment *entities.Payment, change *entities.PaymentStatusChange, cancelCharge func() error) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPaymentStatus(tx, payment, change); err != nil {
+			return err
+		}
+		// The provider is asked while the row is locked, so that a webhook approving the payment meanwhile
+		// waits and then finds it cancelled
+		if err := cancelCharge(); err != nil {
+			return err
+		}
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+	if errors.Is(err, errStatusAlreadyApplied) {
+		return nil
+	}
+	return err
+}
+
+// lockPaymentStatus locks the row of the payment and checks the status change against the stored
+// status. The payment was read without a lock, so another writer (a webhook, the expiry sweeper, a
+// cancel) may have moved it since; the change is only saved when it is still valid from the stored
+// status, and is then recorded from it. A nil change stands for an update that keeps the status. The
+// refunded total is only kept by the refund repository, so the stored one is carried over.
+func lockPaymentStatus(tx *gorm.DB, payment *entities.Payment, change *entities.PaymentStatusChange) error {
+	var stored entities.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status", "refunded_total").First(&stored, payment.ID).Error; err != nil {
+		return err
+	}
+	payment.RefundedTotal = stored.RefundedTotal
+
+	from := payment.Status
+	if change != nil {
+		from = change.FromStatus
+	}
+	switch {
+	case stored.Status == from:
+		return nil
+	case change != nil && stored.Status == payment.Status:
+		return errStatusAlreadyApplied
+	case stored.Status != payment.Status && !stored.Status.CanTransitionTo(payment.Status):
+		return &entities.InvalidStatusTransitionError{From: stored.Status, To: payment.Status}
+	}
+	if change != nil {
+		change.FromStatus = stored.Status
+	}
+	return nil
+}
+
+func (r *PaymentRepositoryImpl) ListPaymentStatusHistory(orderId uint) ([]*entities.PaymentStatusChange, error) {
+	var changes []*entities.PaymentStatusChange
+	if err := r.db.
+		Where("order_id = ?", orderId).
+		Order("id ASC").
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
 }
@@ -2,9 +2,11 @@ package persistence_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -14,7 +16,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&entities.Payment{})
+	err = db.AutoMigrate(&entities.Payment{}, &entities.PaymentStatusChange{}, &entities.Refund{}, &entities.Dispute{}, &entities.WebhookNotification{}, &entities.OutboxMessage{}, &entities.IdempotencyKey{})
 	assert.NoError(t, err)
 
 	return db
@@ -27,9 +29,9 @@ func TestPaymentRepository_AddPayment(t *testing.T) {
 
 	payment := &entities.Payment{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
-		Status:  "pending",
+		Status:  entities.PaymentStatusPending,
 	}
 
 	// WHEN adding payment
@@ -50,9 +52,9 @@ func TestPaymentRepository_GetPaymentByOrderId(t *testing.T) {
 
 	payment := &entities.Payment{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
-		Status:  "pending",
+		Status:  entities.PaymentStatusPending,
 	}
 	repo.AddPayment(payment)
 
@@ -87,14 +89,14 @@ func TestPaymentRepository_UpdatePayment(t *testing.T) {
 
 	payment := &entities.Payment{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
-		Status:  "pending",
+		Status:  entities.PaymentStatusPending,
 	}
 	repo.AddPayment(payment)
 
 	// WHEN updating payment
-	payment.Status = "Approved"
+	payment.Status = entities.PaymentStatusApproved
 	err := repo.UpdatePayment(payment)
 
 	// THEN payment should be updated
@@ -102,5 +104,712 @@ func TestPaymentRepository_UpdatePayment(t *testing.T) {
 
 	// Verify update
 	updated, _ := repo.GetPaymentByOrderId(1)
-	assert.Equal(t, "Approved", updated.Status)
+	assert.Equal(t, entities.PaymentStatusApproved, updated.Status)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_WithOutbox(t *testing.T) {
+	// GIVEN a pending payment
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+
+	payment := &entities.Payment{
+		OrderId: 1,
+		Total:   money.MustParse("100.50"),
+		Type:    "QRCode",
+		Status:  entities.PaymentStatusPending,
+	}
+	repo.AddPayment(payment)
+
+	// WHEN approving it together with an order status update
+	payment.Status = entities.PaymentStatusApproved
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "987")
+	err := repo.UpdatePaymentStatus(payment, change, entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing))
+
+	// THEN the payment, its status change and the outbox message should be stored
+	assert.NoError(t, err)
+	result, _ := repo.GetPaymentByOrderId(1)
+	assert.Equal(t, entities.PaymentStatusApproved, result.Status)
+
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Len(t, history, 1)
+	assert.Equal(t, payment.ID, history[0].PaymentId)
+	assert.Equal(t, entities.PaymentStatusPending, history[0].FromStatus)
+	assert.Equal(t, entities.PaymentStatusApproved, history[0].ToStatus)
+	assert.Equal(t, entities.PaymentStatusChangeSourceWebhook, history[0].Source)
+	assert.Equal(t, "987", history[0].NotificationId)
+
+	var messages []*entities.OutboxMessage
+	db.Find(&messages)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, uint(1), messages[0].OrderId)
+	assert.Equal(t, entities.OrderStatusPreparing, messages[0].OrderStatus)
+	assert.Equal(t, entities.OutboxStatusPending, messages[0].Status)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_WithoutOutbox(t *testing.T) {
+	// GIVEN a pending payment
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	payment, _ := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+
+	// WHEN declining it
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusDeclined))
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceAPI, "")
+	err := repo.UpdatePaymentStatus(payment, change, nil)
+
+	// THEN the change should be recorded without an outbox message
+	assert.NoError(t, err)
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Len(t, history, 1)
+	assert.Equal(t, entities.PaymentStatusDeclined, history[0].ToStatus)
+
+	var count int64
+	db.Model(&entities.OutboxMessage{}).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestPaymentRepository_FindPaymentByProviderPaymentId(t *testing.T) {
+	// GIVEN a payment reported by Mercado Pago
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	payment := entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode")
+	payment.Provider = entities.PaymentProviderMercadoPago
+	payment.ProviderPaymentId = "987"
+	repo.AddPayment(payment)
+
+	// WHEN looking it up by provider payment id
+	found, err := repo.FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, "987")
+	missing, missingErr := repo.FindPaymentByProviderPaymentId("other", "987")
+
+	// THEN it should only be found for its provider
+	assert.NoError(t, err)
+	assert.Equal(t, payment.ID, found.ID)
+	assert.NoError(t, missingErr)
+	assert.Nil(t, missing)
+}
+
+func TestPaymentRepository_FindPaymentByExternalReference_ShouldPreferActivePayment(t *testing.T) {
+	// GIVEN a cancelled and a pending attempt with the same external reference
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+
+	pending := entities.NewPayment(1, total, "QRCode")
+	pending.ExternalReference = "order-1"
+	repo.AddPayment(pending)
+
+	cancelled := entities.NewPayment(1, total, "QRCode")
+	cancelled.ExternalReference = "order-1"
+	cancelled.Status = entities.PaymentStatusCancelled
+	cancelled.Active = false
+	repo.AddPayment(cancelled)
+
+	// WHEN looking them up by external reference
+	found, err := repo.FindPaymentByExternalReference("order-1")
+	missing, missingErr := repo.FindPaymentByExternalReference("order-2")
+
+	// THEN the active attempt should be returned
+	assert.NoError(t, err)
+	assert.Equal(t, pending.ID, found.ID)
+	assert.NoError(t, missingErr)
+	assert.Nil(t, missing)
+}
+
+func TestPaymentRepository_ClaimExpiredPayments(t *testing.T) {
+	// GIVEN an overdue pending payment, a pending payment still valid and one without expiration
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	now := time.Now()
+
+	overdue := entities.NewPayment(1, total, "QRCode")
+	overdueAt := now.Add(-time.Minute)
+	overdue.ExpiresAt = &overdueAt
+	repo.AddPayment(overdue)
+
+	valid := entities.NewPayment(2, total, "QRCode")
+	validUntil := now.Add(time.Minute)
+	valid.ExpiresAt = &validUntil
+	repo.AddPayment(valid)
+
+	repo.AddPayment(entities.NewPayment(3, total, "QRCode"))
+
+	// WHEN claiming expired payments
+	payments, err := repo.ClaimExpiredPayments(now, 10)
+
+	// THEN only the overdue one should be returned
+	assert.NoError(t, err)
+	assert.Len(t, payments, 1)
+	assert.Equal(t, overdue.ID, payments[0].ID)
+}
+
+func TestPaymentRepository_ClaimExpiredPayments_WithClaimHeld_ShouldSkipPayment(t *testing.T) {
+	// GIVEN an overdue pending payment already claimed by another sweep
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	now := time.Now()
+
+	overdue := entities.NewPayment(1, total, "QRCode")
+	overdueAt := now.Add(-time.Minute)
+	overdue.ExpiresAt = &overdueAt
+	repo.AddPayment(overdue)
+	claimed, _ := repo.ClaimExpiredPayments(now, 10)
+
+	// WHEN claiming expired payments again, while the claim holds and once it lapsed
+	held, heldErr := repo.ClaimExpiredPayments(now.Add(time.Minute), 10)
+	lapsed, lapsedErr := repo.ClaimExpiredPayments(now.Add(time.Hour), 10)
+
+	// THEN the payment should only be claimed again once the claim lapsed
+	assert.Len(t, claimed, 1)
+	assert.NoError(t, heldErr)
+	assert.Empty(t, held)
+	assert.NoError(t, lapsedErr)
+	assert.Len(t, lapsed, 1)
+	assert.Equal(t, overdue.ID, lapsed[0].ID)
+}
+
+func TestPaymentRepository_ClaimStaleAuthorizedPayments(t *testing.T) {
+	// GIVEN an old authorization, a recent one and a pending card payment
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	now := time.Now()
+
+	stale := entities.NewPayment(1, total, entities.PaymentTypeCard)
+	stale.Status = entities.PaymentStatusAuthorized
+	staleAt := now.Add(-48 * time.Hour)
+	stale.AuthorizedAt = &staleAt
+	repo.AddPayment(stale)
+
+	recent := entities.NewPayment(2, total, entities.PaymentTypeCard)
+	recent.Status = entities.PaymentStatusAuthorized
+	recentAt := now.Add(-time.Hour)
+	recent.AuthorizedAt = &recentAt
+	repo.AddPayment(recent)
+
+	repo.AddPayment(entities.NewPayment(3, total, entities.PaymentTypeCard))
+
+	// WHEN claiming authorizations older than a day
+	payments, err := repo.ClaimStaleAuthorizedPayments(now, now.Add(-24*time.Hour), 10)
+
+	// THEN only the old one should be returned
+	assert.NoError(t, err)
+	assert.Len(t, payments, 1)
+	assert.Equal(t, stale.ID, payments[0].ID)
+}
+
+func TestPaymentRepository_ReplacePayment(t *testing.T) {
+	// GIVEN a pending payment
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	old, _ := repo.AddPayment(entities.NewPayment(1, total, "QRCode"))
+
+	// WHEN replacing it with a new attempt
+	assert.NoError(t, old.TransitionTo(entities.PaymentStatusCancelled))
+	change := entities.NewPaymentStatusChange(old, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceAPI, "")
+	replacement := entities.NewPayment(1, total, "QRCode")
+	replacement.QRData = "new-qr-data"
+	saved, err := repo.ReplacePayment(old, change, replacement)
+
+	// THEN the old payment should be cancelled and the new one active
+	assert.NoError(t, err)
+	assert.NotEqual(t, old.ID, saved.ID)
+
+	active, _ := repo.FindActivePaymentByOrderId(1)
+	assert.Equal(t, saved.ID, active.ID)
+	assert.Equal(t, "new-qr-data", active.QRData)
+
+	attempts, _ := repo.ListPaymentsByOrderId(1)
+	assert.Len(t, attempts, 2)
+	assert.Equal(t, entities.PaymentStatusCancelled, attempts[0].Status)
+
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Len(t, history, 1)
+	assert.Equal(t, entities.PaymentStatusCancelled, history[0].ToStatus)
+}
+
+func TestPaymentRepository_ReplacePayment_WithActiveConflict_ShouldRollBack(t *testing.T) {
+	// GIVEN a pending payment
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	old, _ := repo.AddPayment(entities.NewPayment(1, total, "QRCode"))
+
+	// WHEN the replacement is inserted without cancelling the old payment
+	change := entities.NewPaymentStatusChange(old, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceAPI, "")
+	_, err := repo.ReplacePayment(old, change, entities.NewPayment(1, total, "QRCode"))
+
+	// THEN nothing should be written
+	assert.Error(t, err)
+	attempts, _ := repo.ListPaymentsByOrderId(1)
+	assert.Len(t, attempts, 1)
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Empty(t, history)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_RollsBackOnFailure(t *testing.T) {
+	// GIVEN a pending payment and an outbox message that cannot be inserted
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+
+	payment := &entities.Payment{
+		OrderId: 1,
+		Total:   money.MustParse("100.50"),
+		Type:    "QRCode",
+		Status:  entities.PaymentStatusPending,
+	}
+	repo.AddPayment(payment)
+
+	existing := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	db.Create(existing)
+
+	// WHEN the outbox insert fails
+	payment.Status = entities.PaymentStatusApproved
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "987")
+	duplicate := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	duplicate.ID = existing.ID
+	err := repo.UpdatePaymentStatus(payment, change, duplicate)
+
+	// THEN the payment update and the status change should be rolled back
+	assert.Error(t, err)
+	result, _ := repo.GetPaymentByOrderId(1)
+	assert.Equal(t, entities.PaymentStatusPending, result.Status)
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Empty(t, history)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_WithConcurrentlyExpiredPayment_ShouldReject(t *testing.T) {
+	// GIVEN a pending payment read by a webhook, then expired by the sweeper
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	payment, _ := repo.AddPayment(entities.NewPayment(1, total, "QRCode"))
+	stale, _ := repo.GetPaymentById(payment.ID)
+
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusExpired))
+	expired := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceReconciliation, "")
+	assert.NoError(t, repo.UpdatePaymentStatus(payment, expired, entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusCancelled)))
+
+	// WHEN the webhook saves its approval of the stale copy
+	assert.NoError(t, stale.TransitionTo(entities.PaymentStatusApproved))
+	approved := entities.NewPaymentStatusChange(stale, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "987")
+	err := repo.UpdatePaymentStatus(stale, approved, entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing))
+
+	// THEN the approval should be rejected and the expiry kept
+	var transitionErr *entities.InvalidStatusTransitionError
+	assert.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, entities.PaymentStatusExpired, transitionErr.From)
+	result, _ := repo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusExpired, result.Status)
+	var messages []*entities.OutboxMessage
+	db.Find(&messages)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, entities.OrderStatusCancelled, messages[0].OrderStatus)
+}
+
+func TestPaymentRepository_CancelPayment_WithConcurrentlyApprovedPayment_ShouldNotCancelCharge(t *testing.T) {
+	// GIVEN a pending payment read by a cancellation, then approved by a webhook
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	payment, _ := repo.AddPayment(entities.NewPayment(1, total, "QRCode"))
+	stale, _ := repo.GetPaymentById(payment.ID)
+
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusApproved))
+	approved := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "987")
+	assert.NoError(t, repo.UpdatePaymentStatus(payment, approved, nil))
+
+	// WHEN the cancellation saves the stale copy
+	assert.NoError(t, stale.TransitionTo(entities.PaymentStatusCancelled))
+	cancelled := entities.NewPaymentStatusChange(stale, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceAPI, "")
+	cancelCalled := false
+	err := repo.CancelPayment(stale, cancelled, func() error {
+		cancelCalled = true
+		return nil
+	})
+
+	// THEN the charge should not be cancelled on the provider and the approval kept
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusTransition)
+	assert.False(t, cancelCalled)
+	result, _ := repo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusApproved, result.Status)
+}
+
+func TestPaymentRepository_CancelPayment_WithCancelChargeError_ShouldKeepPaymentPending(t *testing.T) {
+	// GIVEN a pending payment whose charge cannot be cancelled on the provider
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	payment, _ := repo.AddPayment(entities.NewPayment(1, total, "QRCode"))
+
+	// WHEN cancelling it
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusCancelled))
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceAPI, "")
+	err := repo.CancelPayment(payment, change, func() error { return assert.AnError })
+
+	// THEN the payment should stay pending without a status change
+	assert.ErrorIs(t, err, assert.AnError)
+	result, _ := repo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusPending, result.Status)
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Empty(t, history)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_WithChangeAlreadyApplied_ShouldSkipIt(t *testing.T) {
+	// GIVEN a pending payment approved by one webhook while another one read it
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	payment, _ := repo.AddPayment(entities.NewPayment(1, total, "QRCode"))
+	stale, _ := repo.GetPaymentById(payment.ID)
+
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusApproved))
+	change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "1")
+	assert.NoError(t, repo.UpdatePaymentStatus(payment, change, entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)))
+
+	// WHEN the other webhook saves the same approval
+	assert.NoError(t, stale.TransitionTo(entities.PaymentStatusApproved))
+	duplicate := entities.NewPaymentStatusChange(stale, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "2")
+	err := repo.UpdatePaymentStatus(stale, duplicate, entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing))
+
+	// THEN it should succeed without recording the change or notifying the order twice
+	assert.NoError(t, err)
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Len(t, history, 1)
+	var messages []*entities.OutboxMessage
+	db.Find(&messages)
+	assert.Len(t, messages, 1)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_FromMovedStatus_ShouldRecordStoredStatus(t *testing.T) {
+	// GIVEN a pending card payment authorized while a capture read it as pending
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("100.50"), money.BRL)
+	payment, _ := repo.AddPayment(entities.NewPayment(1, total, "card"))
+	stale, _ := repo.GetPaymentById(payment.ID)
+
+	assert.NoError(t, payment.TransitionTo(entities.PaymentStatusAuthorized))
+	authorized := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "1")
+	assert.NoError(t, repo.UpdatePaymentStatus(payment, authorized, nil))
+
+	// WHEN the stale copy is approved, which is still valid from authorized
+	assert.NoError(t, stale.TransitionTo(entities.PaymentStatusApproved))
+	approved := entities.NewPaymentStatusChange(stale, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "2")
+	err := repo.UpdatePaymentStatus(stale, approved, nil)
+
+	// THEN the change should be recorded from the stored status
+	assert.NoError(t, err)
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Len(t, history, 2)
+	assert.Equal(t, entities.PaymentStatusAuthorized, history[1].FromStatus)
+	assert.Equal(t, entities.PaymentStatusApproved, history[1].ToStatus)
+}
+
+func TestPaymentRepository_UpdatePayment_WithConcurrentlyRefundedPayment_ShouldReject(t *testing.T) {
+	// GIVEN an approved payment refunded while a webhook read it as approved
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	payment := &entities.Payment{OrderId: 1, Total: money.MustParse("100.50"), Type: "QRCode", Status: entities.PaymentStatusApproved}
+	repo.AddPayment(payment)
+	stale, _ := repo.GetPaymentById(payment.ID)
+	db.Model(payment).Update("status", entities.PaymentStatusRefunded)
+
+	// WHEN the webhook saves the stale copy
+	stale.ProviderPaymentId = "987"
+	err := repo.UpdatePayment(stale)
+
+	// THEN the refund should not be overwritten
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusTransition)
+	result, _ := repo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusRefunded, result.Status)
+}
+
+func TestPaymentRepository_UpdatePayment_ShouldKeepStoredRefundedTotal(t *testing.T) {
+	// GIVEN a partially refunded payment, read by a writer that does not track its refunds
+	db := setupTestDB(t)
+	paymentRepo := persistence.NewPaymentRepositoryImpl(db)
+	payment := newApprovedPayment(t, paymentRepo)
+	repo := persistence.NewRefundRepositoryImpl(db)
+	refund, _ := repo.AddRefund(entities.NewRefund(payment, money.MustParse("20.00"), ""), payment.Total)
+	refund.Status, refund.ProviderRefundId = entities.RefundStatusApproved, "555"
+	assert.NoError(t, repo.UpdateRefund(refund))
+	stale, _ := paymentRepo.GetPaymentById(payment.ID)
+	stale.RefundedTotal = 0
+
+	// WHEN it saves the payment
+	stale.ProviderPaymentId = "987"
+	err := paymentRepo.UpdatePayment(stale)
+
+	// THEN the refunded total should be kept from the stored refunds
+	assert.NoError(t, err)
+	stored, _ := paymentRepo.GetPaymentById(payment.ID)
+	assert.Equal(t, money.MustParse("20.00"), stored.RefundedTotal)
+	assert.Equal(t, "987", stored.ProviderPaymentId)
+}
+
+func TestPaymentRepository_ListPaymentStatusHistory_ShouldSpanAttempts(t *testing.T) {
+	// GIVEN an order whose first attempt was declined and the second approved
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	for _, status := range []entities.PaymentStatus{entities.PaymentStatusDeclined, entities.PaymentStatusApproved} {
+		payment, _ := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+		assert.NoError(t, payment.TransitionTo(status))
+		change := entities.NewPaymentStatusChange(payment, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "")
+		assert.NoError(t, repo.UpdatePaymentStatus(payment, change, nil))
+	}
+
+	// WHEN listing the history of the order
+	history, err := repo.ListPaymentStatusHistory(1)
+
+	// THEN both changes should be returned in order
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, entities.PaymentStatusDeclined, history[0].ToStatus)
+	assert.Equal(t, entities.PaymentStatusApproved, history[1].ToStatus)
+	assert.NotEqual(t, history[0].PaymentId, history[1].PaymentId)
+}
+
+func TestPaymentRepository_ActivePaymentPerOrder_ShouldBeUnique(t *testing.T) {
+	// GIVEN an order with an active payment
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	_, err := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+	assert.NoError(t, err)
+
+	// WHEN adding a second active payment for the same order
+	_, err = repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+
+	// THEN the partial unique index should reject it
+	assert.Error(t, err)
+}
+
+func TestPaymentRepository_AddSplitPayment_ShouldKeepOneActivePaymentPerLeg(t *testing.T) {
+	// GIVEN an order paid in two legs
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	legs := []*entities.Payment{
+		entities.NewPaymentLeg(1, "split1", 1, money.New(money.MustParse("20.00"), money.BRL), entities.PaymentTypeCard),
+		entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypeQRCode),
+	}
+
+	// WHEN adding both legs
+	err := repo.AddSplitPayment(nil, nil, legs)
+
+	// THEN both should be active at once
+	assert.NoError(t, err)
+	listed, _ := repo.ListSplitLegs(1)
+	assert.Len(t, listed, 2)
+
+	// AND a second active payment for a leg should be rejected
+	err = repo.AddSplitPayment(nil, nil, []*entities.Payment{
+		entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypePix),
+	})
+	assert.Error(t, err)
+}
+
+func TestPaymentRepository_AddSplitPayment_WithActiveSinglePayment_ShouldBeRejected(t *testing.T) {
+	// GIVEN an order with an active payment of its whole total
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	_, err := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("50.00"), money.BRL), entities.PaymentTypeQRCode))
+	assert.NoError(t, err)
+
+	// WHEN a concurrent request splits the order
+	err = repo.AddSplitPayment(nil, nil, []*entities.Payment{
+		entities.NewPaymentLeg(1, "split1", 1, money.New(money.MustParse("20.00"), money.BRL), entities.PaymentTypeCard),
+		entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypePix),
+	})
+
+	// THEN the partial unique index should reject the legs
+	assert.Error(t, err)
+	listed, _ := repo.ListSplitLegs(1)
+	assert.Empty(t, listed)
+
+	// AND a payment of the whole order should be rejected while a split is active
+	other := setupTestDB(t)
+	otherRepo := persistence.NewPaymentRepositoryImpl(other)
+	assert.NoError(t, otherRepo.AddSplitPayment(nil, nil, []*entities.Payment{
+		entities.NewPaymentLeg(1, "split1", 1, money.New(money.MustParse("20.00"), money.BRL), entities.PaymentTypeCard),
+		entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypePix),
+	}))
+	_, err = otherRepo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("50.00"), money.BRL), entities.PaymentTypeQRCode))
+	assert.Error(t, err)
+}
+
+func TestPaymentRepository_AddSplitPayment_ShouldReplaceLeg(t *testing.T) {
+	// GIVEN a split payment whose second leg is pending
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	first := entities.NewPaymentLeg(1, "split1", 1, money.New(money.MustParse("20.00"), money.BRL), entities.PaymentTypeCard)
+	second := entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypeQRCode)
+	assert.NoError(t, repo.AddSplitPayment(nil, nil, []*entities.Payment{first, second}))
+
+	// WHEN issuing the second leg again
+	assert.NoError(t, second.TransitionTo(entities.PaymentStatusCancelled))
+	change := entities.NewPaymentStatusChange(second, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceAPI, "")
+	reissued := entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypePix)
+	err := repo.AddSplitPayment([]*entities.Payment{second}, []*entities.PaymentStatusChange{change}, []*entities.Payment{reissued})
+
+	// THEN the new attempt should be the current one of the leg
+	assert.NoError(t, err)
+	attempts, _ := repo.ListSplitLegs(1)
+	assert.Len(t, attempts, 3)
+	split := entities.NewSplitPayment(attempts)
+	leg, _ := split.Leg(2)
+	assert.Equal(t, reissued.ID, leg.ID)
+}
+
+func TestPaymentRepository_ListSplitLegs_ShouldOnlyReturnLatestSplit(t *testing.T) {
+	// GIVEN an order whose first split expired and was split again, and another order
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	total := money.New(money.MustParse("25.00"), money.BRL)
+	expired := entities.NewPaymentLeg(1, "split1", 1, total, entities.PaymentTypeQRCode)
+	expired.Status, expired.Active = entities.PaymentStatusExpired, false
+	repo.AddPayment(expired)
+	latest := entities.NewPaymentLeg(1, "split2", 1, total, entities.PaymentTypeQRCode)
+	repo.AddPayment(latest)
+	repo.AddPayment(entities.NewPaymentLeg(2, "split3", 1, total, entities.PaymentTypeQRCode))
+
+	// WHEN listing the legs of the order
+	legs, err := repo.ListSplitLegs(1)
+
+	// THEN only the latest split should be returned
+	assert.NoError(t, err)
+	assert.Len(t, legs, 1)
+	assert.Equal(t, latest.ID, legs[0].ID)
+}
+
+func TestPaymentRepository_GetPaymentByOrderLeg(t *testing.T) {
+	// GIVEN an order paid at once and an order paid in two legs
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	whole := entities.NewPayment(1, money.New(money.MustParse("50.00"), money.BRL), entities.PaymentTypeQRCode)
+	repo.AddPayment(whole)
+	first := entities.NewPaymentLeg(2, "split1", 1, money.New(money.MustParse("20.00"), money.BRL), entities.PaymentTypeCard)
+	second := entities.NewPaymentLeg(2, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypeQRCode)
+	assert.NoError(t, repo.AddSplitPayment(nil, nil, []*entities.Payment{first, second}))
+
+	// WHEN getting the payments by order and leg
+	byOrder, byOrderErr := repo.GetPaymentByOrderLeg(1, 0)
+	byLeg, byLegErr := repo.GetPaymentByOrderLeg(2, 2)
+	_, withoutLegErr := repo.GetPaymentByOrderLeg(2, 0)
+	_, unknownLegErr := repo.GetPaymentByOrderLeg(2, 3)
+
+	// THEN the payment of the order or of its leg should be returned, and a split order should need a
+	// known leg
+	assert.NoError(t, byOrderErr)
+	assert.Equal(t, whole.ID, byOrder.ID)
+	assert.NoError(t, byLegErr)
+	assert.Equal(t, second.ID, byLeg.ID)
+	assert.ErrorIs(t, withoutLegErr, entities.ErrPaymentLegRequired)
+	assert.ErrorIs(t, unknownLegErr, entities.ErrPaymentNotFound)
+}
+
+func TestPaymentRepository_UpdatePaymentStatus_WithSplitLeg_ShouldNotifyOnceFullyPaid(t *testing.T) {
+	// GIVEN an order paid in two pending legs
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	first := entities.NewPaymentLeg(1, "split1", 1, money.New(money.MustParse("20.00"), money.BRL), entities.PaymentTypeCard)
+	second := entities.NewPaymentLeg(1, "split1", 2, money.New(money.MustParse("30.00"), money.BRL), entities.PaymentTypeQRCode)
+	assert.NoError(t, repo.AddSplitPayment(nil, nil, []*entities.Payment{first, second}))
+
+	approve := func(leg *entities.Payment) error {
+		assert.NoError(t, leg.TransitionTo(entities.PaymentStatusApproved))
+		change := entities.NewPaymentStatusChange(leg, entities.PaymentStatusPending, entities.PaymentStatusChangeSourceWebhook, "")
+		return repo.UpdatePaymentStatus(leg, change, entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing))
+	}
+
+	// WHEN the first leg is approved
+	assert.NoError(t, approve(first))
+
+	// THEN the order should not move yet
+	var messages []*entities.OutboxMessage
+	db.Find(&messages)
+	assert.Empty(t, messages)
+
+	// WHEN the second leg is approved
+	assert.NoError(t, approve(second))
+
+	// THEN the order should move to preparing once
+	db.Find(&messages)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, entities.OrderStatusPreparing, messages[0].OrderStatus)
+
+	history, _ := repo.ListPaymentStatusHistory(1)
+	assert.Len(t, history, 2)
+}
+
+func TestPaymentRepository_InactivePayments_ShouldNotBlockNewPayment(t *testing.T) {
+	// GIVEN an order whose payment was declined
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	declined, _ := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+	assert.NoError(t, declined.TransitionTo(entities.PaymentStatusDeclined))
+	assert.NoError(t, repo.UpdatePayment(declined))
+
+	// WHEN adding a new attempt
+	retry, err := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+
+	// THEN it should be accepted and become the order's payment
+	assert.NoError(t, err)
+	active, err := repo.FindActivePaymentByOrderId(1)
+	assert.NoError(t, err)
+	assert.Equal(t, retry.ID, active.ID)
+	current, err := repo.GetPaymentByOrderId(1)
+	assert.NoError(t, err)
+	assert.Equal(t, retry.ID, current.ID)
+}
+
+func TestPaymentRepository_FindActivePaymentByOrderId_NotFound(t *testing.T) {
+	// GIVEN an order without active payments
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+
+	// WHEN finding its active payment
+	result, err := repo.FindActivePaymentByOrderId(1)
+
+	// THEN nil should be returned without error
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestPaymentRepository_ListPaymentsByOrderId_ShouldReturnAttemptsOldestFirst(t *testing.T) {
+	// GIVEN an order whose first attempt expired and was retried
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	expired, _ := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+	assert.NoError(t, expired.TransitionTo(entities.PaymentStatusExpired))
+	assert.NoError(t, repo.UpdatePayment(expired))
+	retry, _ := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("100.50"), money.BRL), "QRCode"))
+	_, _ = repo.AddPayment(entities.NewPayment(2, money.New(money.MustParse("50"), money.BRL), "QRCode"))
+
+	// WHEN listing the order's attempts
+	attempts, err := repo.ListPaymentsByOrderId(1)
+
+	// THEN both attempts should be returned, oldest first
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 2)
+	assert.Equal(t, expired.ID, attempts[0].ID)
+	assert.Equal(t, entities.PaymentStatusExpired, attempts[0].Status)
+	assert.Equal(t, retry.ID, attempts[1].ID)
+	assert.False(t, attempts[1].UpdatedAt.IsZero())
+}
+
+func TestPaymentRepository_Total_ShouldRoundTripExactly(t *testing.T) {
+	// GIVEN a payment whose total is not representable as a float
+	db := setupTestDB(t)
+	repo := persistence.NewPaymentRepositoryImpl(db)
+	_, err := repo.AddPayment(entities.NewPayment(1, money.New(money.MustParse("0.30"), money.BRL), "QRCode"))
+	assert.NoError(t, err)
+
+	// WHEN reading it back
+	result, err := repo.GetPaymentByOrderId(1)
+
+	// THEN the amount and currency should be preserved
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(money.MustParse("0.30"), money.BRL), result.Money())
 }
@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ repositories.RefundRepository = (*RefundRepositoryImpl)(nil)
+)
+
+type RefundRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefundRepositoryImpl(db *gorm.DB) *RefundRepositoryImpl {
+	return &RefundRepositoryImpl{db: db}
+}
+
+func (r *RefundRepositoryImpl) AddRefund(refund *entities.Refund, limit money.Amount) (*entities.Refund, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the payment so that concurrent refunds of it are checked against the limit one at a time
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&entities.Payment{}, refund.PaymentId).Error; err != nil {
+			return err
+		}
+
+		refunded, err := refundedAmount(tx, refund.PaymentId)
+		if err != nil {
+			return err
+		}
+
+		if refunded+refund.Amount > limit {
+			return &entities.RefundExceedsCapturedError{Requested: refund.Amount, Refundable: limit - refunded}
+		}
+		return tx.Create(refund).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+func (r *RefundRepositoryImpl) UpdateRefund(refund *entities.Refund) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if !refund.CountsTowardsRefunded() {
+			return tx.Save(refund).Error
+		}
+
+		// Lock the payment so that refunds settled at the same time each see the amount of the others,
+		// and the payment is moved from its stored status rather than from the one read before the refund
+		payment := &entities.Payment{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(payment, refund.PaymentId).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(refund).Error; err != nil {
+			return err
+		}
+
+		refunded, err := acceptedRefundAmount(tx, refund.PaymentId)
+		if err != nil {
+			return err
+		}
+		payment.RefundedTotal = refunded
+		previousStatus := payment.Status
+		status := payment.RefundedStatus(refunded)
+		if previousStatus == status || !previousStatus.CanTransitionTo(status) {
+			// The refund is recorded either way, e.g. on a payment a lost dispute already refunded
+			return tx.Save(payment).Error
+		}
+		if err := payment.TransitionTo(status); err != nil {
+			return err
+		}
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		return tx.Create(entities.NewPaymentStatusChange(payment, previousStatus, entities.PaymentStatusChangeSourceAPI, "")).Error
+	})
+}
+
+func (r *RefundRepositoryImpl) ListRefundsByPaymentId(paymentId uint) ([]*entities.Refund, error) {
+	var refunds []*entities.Refund
+	if err := r.db.
+		Where("payment_id = ?", paymentId).
+		Order("id ASC").
+		Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// refundedAmount sums the refunds of the payment that count towards the refunded amount.
+func refundedAmount(tx *gorm.DB, paymentId uint) (money.Amount, error) {
+	var refunded money.Amount
+	err := tx.Model(&entities.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("payment_id = ? AND status <> ?", paymentId, entities.RefundStatusRejected).
+		Row().
+		Scan(&refunded)
+	return refunded, err
+}
+
+// acceptedRefundAmount sums the refunds of the payment that the provider accepted, leaving out the ones
+// still waiting for its answer, which may yet be rejected.
+func acceptedRefundAmount(tx *gorm.DB, paymentId uint) (money.Amount, error) {
+	var refunded money.Amount
+	err := tx.Model(&entities.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("payment_id = ? AND status <> ? AND provider_refund_id <> ''", paymentId, entities.RefundStatusRejected).
+		Row().
+		Scan(&refunded)
+	return refunded, err
+}
@@ -0,0 +1,132 @@
+package persistence_test
+
+import (
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func newApprovedPayment(t *testing.T, repo *persistence.PaymentRepositoryImpl) *entities.Payment {
+	payment := entities.NewPayment(1, money.New(money.MustParse("50.00"), money.BRL), "qr")
+	payment.Status = entities.PaymentStatusApproved
+	payment.Active = false
+	payment, err := repo.AddPayment(payment)
+	assert.NoError(t, err)
+	return payment
+}
+
+func TestRefundRepository_AddRefund_ShouldIgnoreRejectedRefunds(t *testing.T) {
+	// GIVEN a payment with an approved and a rejected refund
+	db := setupTestDB(t)
+	payment := newApprovedPayment(t, persistence.NewPaymentRepositoryImpl(db))
+	repo := persistence.NewRefundRepositoryImpl(db)
+
+	_, err := repo.AddRefund(entities.NewRefund(payment, money.MustParse("20.00"), ""), payment.Total)
+	assert.NoError(t, err)
+	rejected := entities.NewRefund(payment, money.MustParse("30.00"), "")
+	rejected.Reject("provider error")
+	_, err = repo.AddRefund(rejected, payment.Total)
+	assert.NoError(t, err)
+
+	// WHEN adding a refund for the remaining amount
+	refund, err := repo.AddRefund(entities.NewRefund(payment, money.MustParse("30.00"), ""), payment.Total)
+
+	// THEN it should be stored
+	assert.NoError(t, err)
+	assert.NotZero(t, refund.ID)
+}
+
+func TestRefundRepository_AddRefund_ExceedingLimit_ShouldReturnError(t *testing.T) {
+	// GIVEN a payment with 20.00 refunded
+	db := setupTestDB(t)
+	payment := newApprovedPayment(t, persistence.NewPaymentRepositoryImpl(db))
+	repo := persistence.NewRefundRepositoryImpl(db)
+
+	_, err := repo.AddRefund(entities.NewRefund(payment, money.MustParse("20.00"), ""), payment.Total)
+	assert.NoError(t, err)
+
+	// WHEN adding a refund above what is left
+	refund, err := repo.AddRefund(entities.NewRefund(payment, money.MustParse("30.01"), ""), payment.Total)
+
+	// THEN it should be rejected and not stored
+	assert.ErrorIs(t, err, entities.ErrRefundExceedsCaptured)
+	assert.Nil(t, refund)
+	refunds, err := repo.ListRefundsByPaymentId(payment.ID)
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 1)
+}
+
+func TestRefundRepository_UpdateRefund_ShouldSavePaymentAndStatusChange(t *testing.T) {
+	// GIVEN a pending refund of the whole payment
+	db := setupTestDB(t)
+	paymentRepo := persistence.NewPaymentRepositoryImpl(db)
+	payment := newApprovedPayment(t, paymentRepo)
+	repo := persistence.NewRefundRepositoryImpl(db)
+	refund, err := repo.AddRefund(entities.NewRefund(payment, payment.Total, ""), payment.Total)
+	assert.NoError(t, err)
+
+	// WHEN the refund is approved
+	refund.Status, refund.ProviderRefundId = entities.RefundStatusApproved, "555"
+	err = repo.UpdateRefund(refund)
+
+	// THEN refund, refunded payment and status change should be stored
+	assert.NoError(t, err)
+	refunds, _ := repo.ListRefundsByPaymentId(payment.ID)
+	assert.Equal(t, entities.RefundStatusApproved, refunds[0].Status)
+	stored, _ := paymentRepo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusRefunded, stored.Status)
+	assert.Equal(t, payment.Total, stored.RefundedTotal)
+	changes, _ := paymentRepo.ListPaymentStatusHistory(payment.OrderId)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, entities.PaymentStatusApproved, changes[0].FromStatus)
+	assert.Equal(t, entities.PaymentStatusChangeSourceAPI, changes[0].Source)
+}
+
+func TestRefundRepository_UpdateRefund_WithConcurrentRefunds_ShouldRefundFromStoredRefunds(t *testing.T) {
+	// GIVEN two refunds of half the payment, both added before either was settled
+	db := setupTestDB(t)
+	paymentRepo := persistence.NewPaymentRepositoryImpl(db)
+	payment := newApprovedPayment(t, paymentRepo)
+	repo := persistence.NewRefundRepositoryImpl(db)
+	half := payment.Total / 2
+	first, _ := repo.AddRefund(entities.NewRefund(payment, half, ""), payment.Total)
+	second, _ := repo.AddRefund(entities.NewRefund(payment, payment.Total-half, ""), payment.Total)
+
+	// WHEN both refunds are approved
+	first.Status, first.ProviderRefundId = entities.RefundStatusApproved, "555"
+	firstErr := repo.UpdateRefund(first)
+	second.Status, second.ProviderRefundId = entities.RefundStatusApproved, "556"
+	secondErr := repo.UpdateRefund(second)
+
+	// THEN the payment should end refunded, having been partially refunded in between
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	stored, _ := paymentRepo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusRefunded, stored.Status)
+	assert.Equal(t, payment.Total, stored.RefundedTotal)
+	changes, _ := paymentRepo.ListPaymentStatusHistory(payment.OrderId)
+	assert.Len(t, changes, 2)
+}
+
+func TestRefundRepository_UpdateRefund_WithRejectedRefund_ShouldKeepPayment(t *testing.T) {
+	// GIVEN a pending refund of the whole payment
+	db := setupTestDB(t)
+	paymentRepo := persistence.NewPaymentRepositoryImpl(db)
+	payment := newApprovedPayment(t, paymentRepo)
+	repo := persistence.NewRefundRepositoryImpl(db)
+	refund, _ := repo.AddRefund(entities.NewRefund(payment, payment.Total, ""), payment.Total)
+
+	// WHEN the provider rejects it
+	refund.Reject("insufficient balance")
+	err := repo.UpdateRefund(refund)
+
+	// THEN the refund should be rejected and the payment left approved
+	assert.NoError(t, err)
+	refunds, _ := repo.ListRefundsByPaymentId(payment.ID)
+	assert.Equal(t, entities.RefundStatusRejected, refunds[0].Status)
+	stored, _ := paymentRepo.GetPaymentById(payment.ID)
+	assert.Equal(t, entities.PaymentStatusApproved, stored.Status)
+}
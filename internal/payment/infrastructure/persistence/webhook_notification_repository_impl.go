@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	_ repositories.WebhookNotificationRepository = (*WebhookNotificationRepositoryImpl)(nil)
+)
+
+const defaultWebhookNotificationsLimit = 50
+
+type WebhookNotificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookNotificationRepositoryImpl(db *gorm.DB) *WebhookNotificationRepositoryImpl {
+	return &WebhookNotificationRepositoryImpl{db: db}
+}
+
+func (r *WebhookNotificationRepositoryImpl) AddWebhookNotification(notification *entities.WebhookNotification) (*entities.WebhookNotification, error) {
+	if err := r.db.Create(notification).Error; err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+func (r *WebhookNotificationRepositoryImpl) FindWebhookNotification(providerId string, topic string, resource string) (*entities.WebhookNotification, error) {
+	notification := &entities.WebhookNotification{}
+	err := r.db.
+		Where("provider_id = ? AND topic = ? AND resource = ?", providerId, topic, resource).
+		First(notification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+func (r *WebhookNotificationRepositoryImpl) UpdateWebhookNotification(notification *entities.WebhookNotification) error {
+	return r.db.Save(notification).Error
+}
+
+func (r *WebhookNotificationRepositoryImpl) ListWebhookNotifications(filter repositories.WebhookNotificationFilter) ([]*entities.WebhookNotification, error) {
+	query := r.db.Model(&entities.WebhookNotification{})
+	if filter.ProviderId != "" {
+		query = query.Where("provider_id = ?", filter.ProviderId)
+	}
+	if filter.Topic != "" {
+		query = query.Where("topic = ?", filter.Topic)
+	}
+	if filter.Resource != "" {
+		query = query.Where("resource = ?", filter.Resource)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultWebhookNotificationsLimit
+	}
+
+	var notifications []*entities.WebhookNotification
+	if err := query.Order("received_at DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
@@ -0,0 +1,108 @@
+package persistence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWebhookNotification(providerId string, outcome entities.WebhookOutcome, receivedAt time.Time) *entities.WebhookNotification {
+	return &entities.WebhookNotification{
+		ProviderId: providerId,
+		Topic:      "payment",
+		Resource:   providerId,
+		ReceivedAt: receivedAt,
+		Deliveries: 1,
+		Outcome:    outcome,
+	}
+}
+
+func TestWebhookNotificationRepository_FindWebhookNotification(t *testing.T) {
+	// GIVEN a recorded notification
+	db := setupTestDB(t)
+	repo := persistence.NewWebhookNotificationRepositoryImpl(db)
+	_, err := repo.AddWebhookNotification(newWebhookNotification("987", entities.WebhookOutcomeReceived, time.Now()))
+	assert.NoError(t, err)
+
+	// WHEN finding it by its delivery key
+	result, err := repo.FindWebhookNotification("987", "payment", "987")
+
+	// THEN it should be returned
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, entities.WebhookOutcomeReceived, result.Outcome)
+}
+
+func TestWebhookNotificationRepository_FindWebhookNotification_NotFound(t *testing.T) {
+	// GIVEN an empty inbox
+	db := setupTestDB(t)
+	repo := persistence.NewWebhookNotificationRepositoryImpl(db)
+
+	// WHEN finding an unknown delivery
+	result, err := repo.FindWebhookNotification("987", "payment", "987")
+
+	// THEN nil should be returned without error
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestWebhookNotificationRepository_AddWebhookNotification_Duplicate(t *testing.T) {
+	// GIVEN a recorded notification
+	db := setupTestDB(t)
+	repo := persistence.NewWebhookNotificationRepositoryImpl(db)
+	_, err := repo.AddWebhookNotification(newWebhookNotification("987", entities.WebhookOutcomeReceived, time.Now()))
+	assert.NoError(t, err)
+
+	// WHEN recording the same delivery again
+	_, err = repo.AddWebhookNotification(newWebhookNotification("987", entities.WebhookOutcomeReceived, time.Now()))
+
+	// THEN the unique delivery key should reject it
+	assert.Error(t, err)
+}
+
+func TestWebhookNotificationRepository_UpdateWebhookNotification(t *testing.T) {
+	// GIVEN a recorded notification
+	db := setupTestDB(t)
+	repo := persistence.NewWebhookNotificationRepositoryImpl(db)
+	notification, _ := repo.AddWebhookNotification(newWebhookNotification("987", entities.WebhookOutcomeReceived, time.Now()))
+
+	// WHEN completing it
+	notification.Complete(entities.WebhookOutcomeProcessed, nil)
+	err := repo.UpdateWebhookNotification(notification)
+
+	// THEN the outcome should be persisted
+	assert.NoError(t, err)
+	updated, _ := repo.FindWebhookNotification("987", "payment", "987")
+	assert.Equal(t, entities.WebhookOutcomeProcessed, updated.Outcome)
+	assert.NotNil(t, updated.ProcessedAt)
+}
+
+func TestWebhookNotificationRepository_ListWebhookNotifications(t *testing.T) {
+	// GIVEN notifications with different outcomes
+	db := setupTestDB(t)
+	repo := persistence.NewWebhookNotificationRepositoryImpl(db)
+	now := time.Now()
+	repo.AddWebhookNotification(newWebhookNotification("1", entities.WebhookOutcomeFailed, now.Add(-2*time.Minute)))
+	repo.AddWebhookNotification(newWebhookNotification("2", entities.WebhookOutcomeProcessed, now.Add(-time.Minute)))
+	repo.AddWebhookNotification(newWebhookNotification("3", entities.WebhookOutcomeFailed, now))
+
+	// WHEN listing failed notifications
+	result, err := repo.ListWebhookNotifications(repositories.WebhookNotificationFilter{
+		Outcome: entities.WebhookOutcomeFailed,
+	})
+
+	// THEN only failed ones should be returned, newest first
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "3", result[0].ProviderId)
+	assert.Equal(t, "1", result[1].ProviderId)
+
+	// AND the limit should be honored
+	limited, err := repo.ListWebhookNotifications(repositories.WebhookNotificationFilter{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
@@ -7,4 +7,13 @@ import (
 
 type PaymentPresenter interface {
 	Present(payment *entities.Payment) *dto.GetPaymentResponseDto
+	PresentSplitPayment(split *entities.SplitPayment) *dto.GetPaymentResponseDto
+	PresentSplitPaymentCodes(split *entities.SplitPayment) []*dto.PaymentLegCodeResponseDto
+	PresentPaymentAttempts(payments []*entities.Payment) []*dto.PaymentAttemptResponseDto
+	PresentPaymentStatusHistory(changes []*entities.PaymentStatusChange) []*dto.PaymentStatusChangeResponseDto
+	PresentWebhookNotifications(notifications []*entities.WebhookNotification) []*dto.WebhookNotificationResponseDto
+	PresentOutboxMessage(message *entities.OutboxMessage) *dto.OutboxMessageResponseDto
+	PresentOutboxMessages(messages []*entities.OutboxMessage) []*dto.OutboxMessageResponseDto
+	PresentRefund(refund *entities.Refund) *dto.RefundResponseDto
+	PresentDisputes(disputes []*entities.Dispute) []*dto.DisputeResponseDto
 }
@@ -16,12 +16,180 @@ func NewPaymentPresenterImpl() *PaymentPresenterImpl {
 }
 
 func (p *PaymentPresenterImpl) Present(payment *entities.Payment) *dto.GetPaymentResponseDto {
-	return &dto.GetPaymentResponseDto{
+	response := &dto.GetPaymentResponseDto{
 		ID:        payment.ID,
 		CreatedAt: payment.CreatedAt,
 		OrderId:   payment.OrderId,
 		Total:     payment.Total,
+		Currency:  payment.Currency,
 		Type:      payment.Type,
-		Status:    payment.Status,
+		Status:    string(payment.Status),
+
+		Provider:          payment.Provider,
+		InStoreOrderId:    payment.ProviderOrderId,
+		ExternalReference: payment.ExternalReference,
+		QRData:            payment.QRData,
+		ProviderPaymentId: payment.ProviderPaymentId,
+		Txid:              payment.Txid,
+		CapturedTotal:     payment.CapturedTotal,
+		RefundedTotal:     payment.RefundedTotal,
+		TipAmount:         payment.TipAmount,
+		ServiceFeeAmount:  payment.ServiceFeeAmount,
+		Leg:               payment.Leg,
+	}
+	// Only a payment that collected money brought in revenue
+	if payment.Status.IsRefundable() {
+		response.Revenue = payment.Revenue()
+	}
+	return response
+}
+
+// PresentSplitPayment presents an order paid in split legs as a single payment of the order total,
+// with each leg presented as a payment of its own.
+func (p *PaymentPresenterImpl) PresentSplitPayment(split *entities.SplitPayment) *dto.GetPaymentResponseDto {
+	response := &dto.GetPaymentResponseDto{
+		OrderId:    split.OrderId,
+		Total:      split.Total(),
+		Type:       entities.PaymentTypeSplit,
+		Status:     string(split.Status()),
+		PaidAmount: split.PaidAmount(),
+		Legs:       make([]*dto.GetPaymentResponseDto, 0, len(split.Legs)),
+	}
+	for _, leg := range split.Legs {
+		presented := p.Present(leg)
+		response.Legs = append(response.Legs, presented)
+		response.Revenue += presented.Revenue
+		response.TipAmount += leg.TipAmount
+		response.ServiceFeeAmount += leg.ServiceFeeAmount
+	}
+	if len(split.Legs) > 0 {
+		response.CreatedAt = split.Legs[0].CreatedAt
+		response.Currency = split.Legs[0].Currency
+	}
+	return response
+}
+
+func (p *PaymentPresenterImpl) PresentSplitPaymentCodes(split *entities.SplitPayment) []*dto.PaymentLegCodeResponseDto {
+	response := make([]*dto.PaymentLegCodeResponseDto, 0, len(split.Legs))
+	for _, leg := range split.Legs {
+		response = append(response, &dto.PaymentLegCodeResponseDto{
+			Leg:         leg.Leg,
+			Type:        leg.Type,
+			Amount:      leg.Total,
+			Status:      string(leg.Status),
+			PaymentCode: leg.PaymentCode(),
+		})
+	}
+	return response
+}
+
+// PresentPaymentAttempts expects the attempts oldest first and numbers them from 1.
+func (p *PaymentPresenterImpl) PresentPaymentAttempts(payments []*entities.Payment) []*dto.PaymentAttemptResponseDto {
+	response := make([]*dto.PaymentAttemptResponseDto, 0, len(payments))
+	for i, payment := range payments {
+		response = append(response, &dto.PaymentAttemptResponseDto{
+			Attempt:   i + 1,
+			ID:        payment.ID,
+			CreatedAt: payment.CreatedAt,
+			UpdatedAt: payment.UpdatedAt,
+			Total:     payment.Total,
+			Currency:  payment.Currency,
+			Type:      payment.Type,
+			Status:    string(payment.Status),
+			Active:    payment.Active,
+		})
+	}
+	return response
+}
+
+func (p *PaymentPresenterImpl) PresentPaymentStatusHistory(changes []*entities.PaymentStatusChange) []*dto.PaymentStatusChangeResponseDto {
+	response := make([]*dto.PaymentStatusChangeResponseDto, 0, len(changes))
+	for _, change := range changes {
+		response = append(response, &dto.PaymentStatusChangeResponseDto{
+			ID:             change.ID,
+			PaymentId:      change.PaymentId,
+			FromStatus:     string(change.FromStatus),
+			ToStatus:       string(change.ToStatus),
+			Source:         string(change.Source),
+			NotificationId: change.NotificationId,
+			CreatedAt:      change.CreatedAt,
+		})
+	}
+	return response
+}
+
+func (p *PaymentPresenterImpl) PresentWebhookNotifications(notifications []*entities.WebhookNotification) []*dto.WebhookNotificationResponseDto {
+	response := make([]*dto.WebhookNotificationResponseDto, 0, len(notifications))
+	for _, notification := range notifications {
+		response = append(response, &dto.WebhookNotificationResponseDto{
+			ID:          notification.ID,
+			ProviderId:  notification.ProviderId,
+			Topic:       notification.Topic,
+			Resource:    notification.Resource,
+			ReceivedAt:  notification.ReceivedAt,
+			ProcessedAt: notification.ProcessedAt,
+			Deliveries:  notification.Deliveries,
+			Outcome:     string(notification.Outcome),
+			Error:       notification.Error,
+		})
+	}
+	return response
+}
+
+func (p *PaymentPresenterImpl) PresentOutboxMessage(message *entities.OutboxMessage) *dto.OutboxMessageResponseDto {
+	return &dto.OutboxMessageResponseDto{
+		ID:            message.ID,
+		CreatedAt:     message.CreatedAt,
+		OrderId:       message.OrderId,
+		OrderStatus:   message.OrderStatus,
+		Status:        string(message.Status),
+		Attempts:      message.Attempts,
+		NextAttemptAt: message.NextAttemptAt,
+		DeliveredAt:   message.DeliveredAt,
+		LastError:     message.LastError,
+	}
+}
+
+func (p *PaymentPresenterImpl) PresentOutboxMessages(messages []*entities.OutboxMessage) []*dto.OutboxMessageResponseDto {
+	response := make([]*dto.OutboxMessageResponseDto, 0, len(messages))
+	for _, message := range messages {
+		response = append(response, p.PresentOutboxMessage(message))
+	}
+	return response
+}
+
+func (p *PaymentPresenterImpl) PresentRefund(refund *entities.Refund) *dto.RefundResponseDto {
+	return &dto.RefundResponseDto{
+		ID:               refund.ID,
+		CreatedAt:        refund.CreatedAt,
+		PaymentId:        refund.PaymentId,
+		Amount:           refund.Amount,
+		Currency:         refund.Currency,
+		Status:           string(refund.Status),
+		Reason:           refund.Reason,
+		ProviderRefundId: refund.ProviderRefundId,
+		FailureReason:    refund.FailureReason,
+	}
+}
+
+func (p *PaymentPresenterImpl) PresentDisputes(disputes []*entities.Dispute) []*dto.DisputeResponseDto {
+	response := make([]*dto.DisputeResponseDto, 0, len(disputes))
+	for _, dispute := range disputes {
+		response = append(response, &dto.DisputeResponseDto{
+			ID:                dispute.ID,
+			PaymentId:         dispute.PaymentId,
+			OrderId:           dispute.OrderId,
+			Provider:          dispute.Provider,
+			Type:              string(dispute.Type),
+			ProviderDisputeId: dispute.ProviderDisputeId,
+			Amount:            dispute.Amount,
+			Currency:          dispute.Currency,
+			Status:            string(dispute.Status),
+			Outcome:           string(dispute.Outcome),
+			Reason:            dispute.Reason,
+			OpenedAt:          dispute.OpenedAt,
+			ClosedAt:          dispute.ClosedAt,
+		})
 	}
+	return response
 }
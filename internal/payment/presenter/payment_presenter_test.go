@@ -6,6 +6,7 @@ import (
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/presenter"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -29,7 +30,7 @@ func (suite *PaymentPresenterTestSuite) Test_Present_WithValidPayment_ShouldRetu
 		ID:        1,
 		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 		OrderId:   123,
-		Total:     250.75,
+		Total:     money.MustParse("250.75"),
 		Type:      "credit_card",
 		Status:    "Approved",
 	}
@@ -41,8 +42,212 @@ func (suite *PaymentPresenterTestSuite) Test_Present_WithValidPayment_ShouldRetu
 	assert.NotNil(suite.T(), dto)
 	assert.Equal(suite.T(), uint(1), dto.ID)
 	assert.Equal(suite.T(), uint(123), dto.OrderId)
-	assert.Equal(suite.T(), float32(250.75), dto.Total)
+	assert.Equal(suite.T(), money.MustParse("250.75"), dto.Total)
 	assert.Equal(suite.T(), "credit_card", dto.Type)
 	assert.Equal(suite.T(), "Approved", dto.Status)
 	assert.Equal(suite.T(), payment.CreatedAt, dto.CreatedAt)
 }
+
+func (suite *PaymentPresenterTestSuite) Test_Present_WithProviderReferences_ShouldExposeThem() {
+	// GIVEN a payment settled on Mercado Pago
+	payment := &entities.Payment{
+		ID:                1,
+		OrderId:           123,
+		Status:            entities.PaymentStatusApproved,
+		Provider:          entities.PaymentProviderMercadoPago,
+		ProviderOrderId:   "in-store-order-1",
+		ExternalReference: "order-123",
+		QRData:            "qr-data",
+		ProviderPaymentId: "987",
+	}
+
+	// WHEN presenting the payment
+	dto := suite.presenter.Present(payment)
+
+	// THEN the provider references should be exposed
+	assert.Equal(suite.T(), "mercadopago", dto.Provider)
+	assert.Equal(suite.T(), "in-store-order-1", dto.InStoreOrderId)
+	assert.Equal(suite.T(), "order-123", dto.ExternalReference)
+	assert.Equal(suite.T(), "qr-data", dto.QRData)
+	assert.Equal(suite.T(), "987", dto.ProviderPaymentId)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_Present_WithPartialRefund_ShouldLeaveRefundOutOfRevenue() {
+	// GIVEN a payment of 60.00 with a 6.00 tip, 20.00 of which was refunded
+	payment := &entities.Payment{
+		ID:            1,
+		OrderId:       123,
+		Total:         money.MustParse("60.00"),
+		TipAmount:     money.MustParse("6.00"),
+		RefundedTotal: money.MustParse("20.00"),
+		Status:        entities.PaymentStatusPartiallyRefunded,
+	}
+
+	// WHEN presenting the payment
+	dto := suite.presenter.Present(payment)
+
+	// THEN revenue should leave both the tip and the refund out
+	assert.Equal(suite.T(), money.MustParse("34.00"), dto.Revenue)
+	assert.Equal(suite.T(), money.MustParse("20.00"), dto.RefundedTotal)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_PresentSplitPayment_ShouldPresentOrderTotalWithLegs() {
+	// GIVEN an order split into a paid QR code leg and a pending card leg
+	split := &entities.SplitPayment{
+		OrderId: 123,
+		SplitId: "split",
+		Legs: []*entities.Payment{
+			{ID: 1, OrderId: 123, SplitId: "split", Leg: 1, Total: money.MustParse("60.00"), TipAmount: money.MustParse("6.00"), Type: entities.PaymentTypeQRCode, Status: entities.PaymentStatusApproved, QRData: "qr-data"},
+			{ID: 2, OrderId: 123, SplitId: "split", Leg: 2, Total: money.MustParse("40.00"), TipAmount: money.MustParse("4.00"), Type: entities.PaymentTypeCard, Status: entities.PaymentStatusPending, ClientSecret: "pi_secret"},
+		},
+	}
+
+	// WHEN presenting the split payment and its payment codes
+	dto := suite.presenter.PresentSplitPayment(split)
+	codes := suite.presenter.PresentSplitPaymentCodes(split)
+
+	// THEN the order total should be presented with every leg
+	assert.Zero(suite.T(), dto.ID)
+	assert.Equal(suite.T(), entities.PaymentTypeSplit, dto.Type)
+	assert.Equal(suite.T(), money.MustParse("100.00"), dto.Total)
+	assert.Equal(suite.T(), money.MustParse("60.00"), dto.PaidAmount)
+	assert.Equal(suite.T(), money.MustParse("54.00"), dto.Revenue, "only the paid leg, less its tip, is revenue")
+	assert.Zero(suite.T(), dto.Legs[1].Revenue)
+	assert.Equal(suite.T(), string(entities.PaymentStatusPending), dto.Status)
+	assert.Len(suite.T(), dto.Legs, 2)
+	assert.Equal(suite.T(), 2, dto.Legs[1].Leg)
+	assert.Equal(suite.T(), "qr-data", codes[0].PaymentCode)
+	assert.Equal(suite.T(), "pi_secret", codes[1].PaymentCode)
+	assert.Equal(suite.T(), money.MustParse("40.00"), codes[1].Amount)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_PresentWebhookNotifications_WithNotifications_ShouldReturnDTOs() {
+	// GIVEN inbox notifications
+	processedAt := time.Date(2025, 1, 1, 0, 0, 5, 0, time.UTC)
+	notifications := []*entities.WebhookNotification{
+		{
+			ID:          1,
+			ProviderId:  "987",
+			Topic:       "payment",
+			Resource:    "987",
+			ReceivedAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			ProcessedAt: &processedAt,
+			Deliveries:  2,
+			Outcome:     entities.WebhookOutcomeFailed,
+			Error:       "order service unavailable",
+		},
+	}
+
+	// WHEN presenting them
+	dtos := suite.presenter.PresentWebhookNotifications(notifications)
+
+	// THEN every field should be mapped
+	assert.Len(suite.T(), dtos, 1)
+	assert.Equal(suite.T(), uint(1), dtos[0].ID)
+	assert.Equal(suite.T(), "987", dtos[0].ProviderId)
+	assert.Equal(suite.T(), "payment", dtos[0].Topic)
+	assert.Equal(suite.T(), "987", dtos[0].Resource)
+	assert.Equal(suite.T(), &processedAt, dtos[0].ProcessedAt)
+	assert.Equal(suite.T(), uint(2), dtos[0].Deliveries)
+	assert.Equal(suite.T(), "failed", dtos[0].Outcome)
+	assert.Equal(suite.T(), "order service unavailable", dtos[0].Error)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_PresentWebhookNotifications_WithNoNotifications_ShouldReturnEmptySlice() {
+	// WHEN presenting an empty result
+	dtos := suite.presenter.PresentWebhookNotifications(nil)
+
+	// THEN an empty, non-nil slice should be returned so it encodes as []
+	assert.NotNil(suite.T(), dtos)
+	assert.Empty(suite.T(), dtos)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_PresentOutboxMessages_ShouldMapEveryMessage() {
+	// GIVEN outbox messages
+	deliveredAt := time.Date(2025, 1, 1, 0, 0, 5, 0, time.UTC)
+	messages := []*entities.OutboxMessage{
+		{
+			ID:            1,
+			OrderId:       10,
+			OrderStatus:   entities.OrderStatusPreparing,
+			Status:        entities.OutboxStatusDelivered,
+			Attempts:      2,
+			NextAttemptAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			DeliveredAt:   &deliveredAt,
+		},
+		{
+			ID:          2,
+			OrderId:     11,
+			OrderStatus: entities.OrderStatusPreparing,
+			Status:      entities.OutboxStatusFailed,
+			Attempts:    10,
+			LastError:   "order service unavailable",
+		},
+	}
+
+	// WHEN presenting them
+	dtos := suite.presenter.PresentOutboxMessages(messages)
+
+	// THEN every field should be mapped
+	assert.Len(suite.T(), dtos, 2)
+	assert.Equal(suite.T(), uint(10), dtos[0].OrderId)
+	assert.Equal(suite.T(), entities.OrderStatusPreparing, dtos[0].OrderStatus)
+	assert.Equal(suite.T(), "delivered", dtos[0].Status)
+	assert.Equal(suite.T(), uint(2), dtos[0].Attempts)
+	assert.Equal(suite.T(), &deliveredAt, dtos[0].DeliveredAt)
+	assert.Equal(suite.T(), "failed", dtos[1].Status)
+	assert.Equal(suite.T(), "order service unavailable", dtos[1].LastError)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_PresentPaymentAttempts_ShouldNumberAttemptsInOrder() {
+	// GIVEN a declined attempt followed by an approved one
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	payments := []*entities.Payment{
+		{ID: 7, CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Minute), OrderId: 1, Total: 10, Type: "QRCode", Status: entities.PaymentStatusDeclined},
+		{ID: 9, CreatedAt: createdAt.Add(2 * time.Minute), OrderId: 1, Total: 10, Type: "QRCode", Status: entities.PaymentStatusApproved, Active: true},
+	}
+
+	// WHEN presenting them
+	dtos := suite.presenter.PresentPaymentAttempts(payments)
+
+	// THEN attempts should be numbered from 1 and keep their timestamps
+	assert.Len(suite.T(), dtos, 2)
+	assert.Equal(suite.T(), 1, dtos[0].Attempt)
+	assert.Equal(suite.T(), uint(7), dtos[0].ID)
+	assert.Equal(suite.T(), "declined", dtos[0].Status)
+	assert.Equal(suite.T(), createdAt.Add(time.Minute), dtos[0].UpdatedAt)
+	assert.False(suite.T(), dtos[0].Active)
+	assert.Equal(suite.T(), 2, dtos[1].Attempt)
+	assert.Equal(suite.T(), "approved", dtos[1].Status)
+	assert.True(suite.T(), dtos[1].Active)
+}
+
+func (suite *PaymentPresenterTestSuite) Test_PresentPaymentStatusHistory_ShouldMapEveryChange() {
+	// GIVEN a status change triggered by a webhook
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	changes := []*entities.PaymentStatusChange{
+		{
+			ID:             3,
+			PaymentId:      2,
+			OrderId:        1,
+			FromStatus:     entities.PaymentStatusPending,
+			ToStatus:       entities.PaymentStatusApproved,
+			Source:         entities.PaymentStatusChangeSourceWebhook,
+			NotificationId: "987",
+			CreatedAt:      createdAt,
+		},
+	}
+
+	// WHEN presenting the history
+	dtos := suite.presenter.PresentPaymentStatusHistory(changes)
+
+	// THEN every field should be mapped
+	assert.Len(suite.T(), dtos, 1)
+	assert.Equal(suite.T(), uint(3), dtos[0].ID)
+	assert.Equal(suite.T(), uint(2), dtos[0].PaymentId)
+	assert.Equal(suite.T(), "pending", dtos[0].FromStatus)
+	assert.Equal(suite.T(), "approved", dtos[0].ToStatus)
+	assert.Equal(suite.T(), "webhook", dtos[0].Source)
+	assert.Equal(suite.T(), "987", dtos[0].NotificationId)
+	assert.Equal(suite.T(), createdAt, dtos[0].CreatedAt)
+}
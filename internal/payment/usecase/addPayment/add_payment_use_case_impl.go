@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
@@ -11,52 +13,353 @@ import (
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/clients"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 )
 
 var (
 	_ AddPaymentUseCase = (*AddPaymentUseCaseImpl)(nil)
 )
 
+type AddPaymentConfig struct {
+	// AmountTolerance is the largest accepted difference between the requested amount, the order
+	// total and the sum of the order items.
+	AmountTolerance money.Amount
+	// QRCodeTTL is how long a QR code stays payable; zero disables expiration.
+	QRCodeTTL time.Duration
+	// ServiceFees are charged on top of the order items of every payment.
+	ServiceFees []ServiceFee
+}
+
+// ServiceFee is a line added to the charge of every order: a fixed Amount, or a Percentage of the order
+// total.
+type ServiceFee struct {
+	Title      string
+	Amount     money.Amount
+	Percentage money.Amount
+}
+
+func (c *AddPaymentConfig) Validate() error {
+	if c.AmountTolerance < 0 {
+		return fmt.Errorf("invalid AddPaymentConfig: amount tolerance must not be negative")
+	}
+	if c.QRCodeTTL < 0 {
+		return fmt.Errorf("invalid AddPaymentConfig: QR code TTL must not be negative")
+	}
+	for _, fee := range c.ServiceFees {
+		if fee.Title == "" {
+			return fmt.Errorf("invalid AddPaymentConfig: service fees must have a title")
+		}
+		if fee.Amount < 0 || fee.Percentage < 0 || (fee.Amount > 0 && fee.Percentage > 0) {
+			return fmt.Errorf("invalid AddPaymentConfig: service fee %q must have either a non-negative amount or a percentage", fee.Title)
+		}
+	}
+	return nil
+}
+
+// chargeExpiry is when a charge created now through gateway stops being payable, or nil when it does not
+// expire: either expiration is disabled or the provider would not enforce it.
+func (c *AddPaymentConfig) chargeExpiry(gateway gateways.PaymentGateway, now time.Time) *time.Time {
+	if c.QRCodeTTL <= 0 {
+		return nil
+	}
+	if expiring, ok := gateway.(gateways.ExpiringPaymentGateway); !ok || !expiring.ExpiresCharges() {
+		return nil
+	}
+	expiresAt := now.Add(c.QRCodeTTL)
+	return &expiresAt
+}
+
+func newAddPaymentConfig() (*AddPaymentConfig, error) {
+	tolerance := money.Amount(0)
+	if value := os.Getenv("PAYMENT_AMOUNT_TOLERANCE"); value != "" {
+		parsed, err := money.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PAYMENT_AMOUNT_TOLERANCE: %w", err)
+		}
+		tolerance = parsed
+	}
+
+	ttl := 30 * time.Minute
+	if value := os.Getenv("PAYMENT_QR_CODE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PAYMENT_QR_CODE_TTL: %w", err)
+		}
+		ttl = parsed
+	}
+
+	serviceFees, err := parseServiceFees(os.Getenv("PAYMENT_SERVICE_FEES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYMENT_SERVICE_FEES: %w", err)
+	}
+	return &AddPaymentConfig{AmountTolerance: tolerance, QRCodeTTL: ttl, ServiceFees: serviceFees}, nil
+}
+
+// parseServiceFees reads fees written as title:amount or title:percentage%, separated by commas, e.g.
+// "Taxa de serviço:10%,Embalagem:2.00".
+func parseServiceFees(value string) ([]ServiceFee, error) {
+	var fees []ServiceFee
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		separator := strings.LastIndex(entry, ":")
+		if separator < 0 {
+			return nil, fmt.Errorf("service fee %q has no amount", entry)
+		}
+
+		fee := ServiceFee{Title: strings.TrimSpace(entry[:separator])}
+		amount, target := strings.TrimSpace(entry[separator+1:]), &fee.Amount
+		if percentage, ok := strings.CutSuffix(amount, "%"); ok {
+			amount, target = percentage, &fee.Percentage
+		}
+		parsed, err := money.Parse(amount)
+		if err != nil {
+			return nil, err
+		}
+		*target = parsed
+		fees = append(fees, fee)
+	}
+	return fees, nil
+}
+
 type AddPaymentUseCaseImpl struct {
-	mercadoPagoGateway gateways.MercadoPagoGateway
-	orderClient        clients.OrderClient
-	paymentRepository  repositories.PaymentRepository
+	config            *AddPaymentConfig
+	gatewayRegistry   *gateways.PaymentGatewayRegistry
+	orderClient       clients.OrderClient
+	paymentRepository repositories.PaymentRepository
 }
 
 func NewAddPaymentUseCaseImpl(
-	mercadoPagoGateway gateways.MercadoPagoGateway,
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
 	orderClient clients.OrderClient,
-	paymentRepository repositories.PaymentRepository) *AddPaymentUseCaseImpl {
-	return &AddPaymentUseCaseImpl{
-		mercadoPagoGateway: mercadoPagoGateway,
-		orderClient:        orderClient,
-		paymentRepository:  paymentRepository,
+	paymentRepository repositories.PaymentRepository) (*AddPaymentUseCaseImpl, error) {
+	config, err := newAddPaymentConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewAddPaymentUseCaseImplWithConfig(gatewayRegistry, orderClient, paymentRepository, config)
+}
+
+func NewAddPaymentUseCaseImplWithConfig(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	orderClient clients.OrderClient,
+	paymentRepository repositories.PaymentRepository,
+	config *AddPaymentConfig) (*AddPaymentUseCaseImpl, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
+	return &AddPaymentUseCaseImpl{
+		config:            config,
+		gatewayRegistry:   gatewayRegistry,
+		orderClient:       orderClient,
+		paymentRepository: paymentRepository,
+	}, nil
 }
 
 func (u *AddPaymentUseCaseImpl) Execute(command *commands.AddPaymentCommand) (string, error) {
-	paymentEntity := entities.Payment{
-		OrderId: command.OrderId,
-		Total:   command.Total,
-		Type:    command.Type,
-		Status:  "pending",
+	// The payment type selects the provider that charges the order
+	paymentType := u.gatewayRegistry.PaymentType(command.Type)
+	gateway, err := u.gatewayRegistry.ForPaymentType(paymentType)
+	if err != nil {
+		return "", err
 	}
 
-	paymentResult, err := u.paymentRepository.AddPayment(&paymentEntity)
+	activePayment, err := u.paymentRepository.FindActivePaymentByOrderId(command.OrderId)
 	if err != nil {
 		return "", err
 	}
 
+	if activePayment != nil {
+		if activePayment.IsSplitLeg() {
+			return "", fmt.Errorf("%w: order %d is paid in split legs", entities.ErrActivePaymentExists, command.OrderId)
+		}
+		if activePayment.Status != entities.PaymentStatusPending {
+			return "", fmt.Errorf("%w: payment %d is %s", entities.ErrActivePaymentExists, activePayment.ID, activePayment.Status)
+		}
+
+		if !command.Regenerate && !activePayment.IsExpired(time.Now()) {
+			if activePayment.PaymentCode() == "" {
+				return "", fmt.Errorf("%w: payment %d has no QR code yet, retry with regenerate to replace it", entities.ErrActivePaymentExists, activePayment.ID)
+			}
+			// Hand out the QR code of the pending payment instead of orphaning it
+			return activePayment.PaymentCode(), nil
+		}
+	}
+
 	// Get order details from Order Service
-	order, err := u.orderClient.GetOrder(paymentResult.OrderId)
+	order, err := u.orderClient.GetOrder(command.OrderId)
 	if err != nil {
 		// Log the error for debugging
 		println("ERROR: Failed to get order from Order Service:", err.Error())
 		return "", fmt.Errorf("failed to get order from Order Service: %w", err)
 	}
 
-	var items []dto.Item
+	if err := u.config.validateAmounts(command.Total, order); err != nil {
+		return "", err
+	}
+	ordered, err := u.config.chargeOrder(order, command.Tip)
+	if err != nil {
+		return "", err
+	}
+
+	total := money.New(ordered.Total, money.BRL)
+	chargeRequest := gateways.ChargeRequest{
+		ExternalReference: entities.OrderExternalReference(order.ID),
+		Total:             total,
+		Items:             ordered.Items,
+		PaymentMethod:     command.PaymentMethod,
+		ExpiresAt:         u.config.chargeExpiry(gateway, time.Now()),
+	}
+
+	charge, err := gateway.CreateCharge(context.Background(), chargeRequest)
+	if err != nil {
+		u.recordFailedPayment(command, paymentType, gateway, chargeRequest, err)
+		return "", err
+	}
 
+	// The payment is only stored once the provider created the charge, with the Order Service total
+	// and the fees and tip that were actually charged
+	payment := entities.NewPayment(command.OrderId, total, paymentType)
+	payment.TipAmount = ordered.Tip
+	payment.ServiceFeeAmount = ordered.ServiceFees
+	payment.Provider = gateway.Name()
+	payment.ProviderOrderId = charge.ProviderOrderId
+	payment.ProviderPaymentId = charge.ProviderPaymentId
+	payment.ExternalReference = chargeRequest.ExternalReference
+	payment.QRData = charge.QRData
+	payment.ClientSecret = charge.ClientSecret
+	payment.Txid = charge.Txid
+	payment.ExpiresAt = chargeRequest.ExpiresAt
+
+	if _, err := u.persistPayment(payment, activePayment); err != nil {
+		// A concurrent request may have created the active payment first
+		concurrent, findErr := u.paymentRepository.FindActivePaymentByOrderId(command.OrderId)
+		if findErr == nil && concurrent != nil && (activePayment == nil || concurrent.ID != activePayment.ID) {
+			return "", fmt.Errorf("%w: payment %d", entities.ErrActivePaymentExists, concurrent.ID)
+		}
+		println("ERROR: Failed to store payment after creating its QR code:", err.Error())
+		return "", err
+	}
+
+	return payment.PaymentCode(), nil
+}
+
+// persistPayment adds the new payment, closing the pending payment it replaces in the same transaction.
+func (u *AddPaymentUseCaseImpl) persistPayment(payment *entities.Payment, replaced *entities.Payment) (*entities.Payment, error) {
+	if replaced == nil {
+		return u.paymentRepository.AddPayment(payment)
+	}
+
+	change, err := closeReplacedPayment(replaced)
+	if err != nil {
+		return nil, err
+	}
+	return u.paymentRepository.ReplacePayment(replaced, change, payment)
+}
+
+// closeReplacedPayment moves a pending payment that is replaced by a new one out of the way. A replaced
+// payment whose QR code already expired is recorded as expired rather than cancelled.
+func closeReplacedPayment(replaced *entities.Payment) (*entities.PaymentStatusChange, error) {
+	status, source := entities.PaymentStatusCancelled, entities.PaymentStatusChangeSourceAPI
+	if replaced.IsExpired(time.Now()) {
+		status, source = entities.PaymentStatusExpired, entities.PaymentStatusChangeSourceReconciliation
+	}
+
+	previousStatus := replaced.Status
+	if err := replaced.TransitionTo(status); err != nil {
+		return nil, err
+	}
+	return entities.NewPaymentStatusChange(replaced, previousStatus, source, ""), nil
+}
+
+// recordFailedPayment keeps a failed attempt for auditing. It never fails the request on its own,
+// since the provider error is what the caller needs to see.
+func (u *AddPaymentUseCaseImpl) recordFailedPayment(command *commands.AddPaymentCommand, paymentType string, gateway gateways.PaymentGateway, chargeRequest gateways.ChargeRequest, cause error) {
+	failed := entities.NewFailedPayment(command.OrderId, chargeRequest.Total, paymentType, cause.Error())
+	failed.Provider = gateway.Name()
+	failed.ExternalReference = chargeRequest.ExternalReference
+	if _, err := u.paymentRepository.AddPayment(failed); err != nil {
+		println("ERROR: Failed to record failed payment:", err.Error())
+	}
+}
+
+// validateAmounts rejects requests whose amount disagrees with the Order Service, and orders whose
+// items do not add up to their total.
+func (c *AddPaymentConfig) validateAmounts(requested money.Amount, order *dto.OrderResponseDto) error {
+	if !c.withinTolerance(requested, order.TotalAmount) {
+		return &entities.PaymentAmountMismatchError{Requested: requested, OrderTotal: order.TotalAmount}
+	}
+
+	if itemsTotal := money.Sum(lineTotals(order)...); !c.withinTolerance(itemsTotal, order.TotalAmount) {
+		return &entities.OrderTotalMismatchError{OrderTotal: order.TotalAmount, ItemsTotal: itemsTotal}
+	}
+	return nil
+}
+
+func (c *AddPaymentConfig) withinTolerance(amount, expected money.Amount) bool {
+	difference := amount - expected
+	return difference <= c.AmountTolerance && -difference <= c.AmountTolerance
+}
+
+// orderCharge is what a payment of the order charges: the order items, followed by the service fees
+// and the tip.
+type orderCharge struct {
+	Items       []dto.Item
+	Total       money.Amount
+	ServiceFees money.Amount
+	Tip         money.Amount
+}
+
+// chargeOrder adds the configured service fees and the tip to the order items. Percentages are taken
+// of the order total, and the tip may be at most the order total.
+func (c *AddPaymentConfig) chargeOrder(order *dto.OrderResponseDto, tip commands.Tip) (*orderCharge, error) {
+	if tip.Amount < 0 || tip.Percentage < 0 || (tip.Amount > 0 && tip.Percentage > 0) {
+		return nil, fmt.Errorf("%w: give either a non-negative amount or a percentage", entities.ErrInvalidTip)
+	}
+
+	charge := &orderCharge{Items: itemsFromOrder(order), Total: order.TotalAmount}
+	for _, fee := range c.ServiceFees {
+		amount := fee.Amount
+		if fee.Percentage > 0 {
+			amount = order.TotalAmount.Percent(fee.Percentage)
+		}
+		if amount > 0 {
+			charge.Items = append(charge.Items, chargeItem("service-fee", fee.Title, amount))
+			charge.ServiceFees += amount
+		}
+	}
+
+	charge.Tip = tip.Amount
+	if tip.Percentage > 0 {
+		charge.Tip = order.TotalAmount.Percent(tip.Percentage)
+	}
+	if charge.Tip > order.TotalAmount {
+		return nil, fmt.Errorf("%w: a tip of %s exceeds the order total of %s", entities.ErrInvalidTip, charge.Tip, order.TotalAmount)
+	}
+	if charge.Tip > 0 {
+		charge.Items = append(charge.Items, chargeItem("tip", "Gorjeta", charge.Tip))
+	}
+
+	charge.Total += charge.ServiceFees + charge.Tip
+	return charge, nil
+}
+
+func chargeItem(sku, title string, amount money.Amount) dto.Item {
+	return dto.Item{SKUNumber: sku, Title: title, UnitPrice: amount, Quantity: 1, TotalAmount: amount}
+}
+
+// legItem is the single line charged by a split payment leg. Spreading the leg amount across the
+// order lines would break their unit price times quantity, which the providers validate.
+func legItem(orderId uint, leg int, amount money.Amount) dto.Item {
+	return chargeItem("split-leg", fmt.Sprintf("Pedido %d - parte %d", orderId, leg), amount)
+}
+
+// itemsFromOrder builds the QR order lines. Mercado Pago requires the line totals to add up to the
+// order total and each line total to be its unit price times its quantity, so a difference within
+// the configured tolerance goes on an adjustment line of its own instead of into the product lines.
+func itemsFromOrder(order *dto.OrderResponseDto) []dto.Item {
+	var items []dto.Item
 	for _, product := range order.Products {
 		items = append(items, dto.Item{
 			SKUNumber:   fmt.Sprint(product.ProductId),
@@ -65,21 +368,20 @@ func (u *AddPaymentUseCaseImpl) Execute(command *commands.AddPaymentCommand) (st
 			Description: product.Description,
 			UnitPrice:   product.Price,
 			Quantity:    int(product.Quantity),
-			TotalAmount: float32(product.Quantity) * product.Price,
+			TotalAmount: product.Price.Mul(int64(product.Quantity)),
 		})
 	}
 
-	qrCodeResponse, err := u.mercadoPagoGateway.GenerateQRCode(context.Background(), dto.CreateQRCodeDTO{
-		ExternalReference: fmt.Sprintf("order-%d", order.ID),
-		Title:             "Fiap",
-		Description:       "Fiap",
-		NotificationURL:   os.Getenv("MERCADO_PAGO_WEBHOOK_CALLBACK_URL"),
-		TotalAmount:       order.TotalAmount,
-		Items:             items,
-	})
-	if err != nil {
-		return "", err
+	if difference := order.TotalAmount - money.Sum(lineTotals(order)...); difference != 0 {
+		items = append(items, chargeItem("adjustment", "Ajuste de arredondamento", difference))
 	}
+	return items
+}
 
-	return qrCodeResponse.QRData, nil
+func lineTotals(order *dto.OrderResponseDto) []money.Amount {
+	totals := make([]money.Amount, len(order.Products))
+	for i, product := range order.Products {
+		totals[i] = product.Price.Mul(int64(product.Quantity))
+	}
+	return totals
 }
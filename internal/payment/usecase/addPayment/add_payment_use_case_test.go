@@ -4,36 +4,92 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	addpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/addPayment"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
 	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
 	mockClients "github.com/abattassini/tc-fiap-payment/mocks/payment/infrastructure/clients"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
+// qrCodeGateway is a gateway whose charges expire, like the Mercado Pago one.
+type qrCodeGateway struct {
+	*mockGateways.MockPaymentGateway
+	*mockGateways.MockExpiringPaymentGateway
+}
+
 type AddPaymentUseCaseTestSuite struct {
 	suite.Suite
 	mockRepository  *mockRepositories.MockPaymentRepository
-	mockGateway     *mockGateways.MockMercadoPagoGateway
+	mockGateway     *mockGateways.MockPaymentGateway
 	mockOrderClient *mockClients.MockOrderClient
+	registry        *gateways.PaymentGatewayRegistry
 	useCase         addpayment.AddPaymentUseCase
 }
 
 func (suite *AddPaymentUseCaseTestSuite) SetupTest() {
 	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
-	suite.mockGateway = mockGateways.NewMockMercadoPagoGateway(suite.T())
+	suite.mockGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockGateway.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
 	suite.mockOrderClient = mockClients.NewMockOrderClient(suite.T())
-	suite.useCase = addpayment.NewAddPaymentUseCaseImpl(
-		suite.mockGateway,
+
+	expiring := mockGateways.NewMockExpiringPaymentGateway(suite.T())
+	expiring.EXPECT().ExpiresCharges().Return(true).Maybe()
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig(
+		[]gateways.PaymentGatewayRegistration{{PaymentType: entities.PaymentTypeQRCode, Gateway: qrCodeGateway{suite.mockGateway, expiring}}},
+		&gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode},
+	)
+	suite.Require().NoError(err)
+	suite.registry = registry
+
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(
+		suite.registry,
 		suite.mockOrderClient,
 		suite.mockRepository,
+		&addpayment.AddPaymentConfig{},
 	)
+	suite.Require().NoError(err)
+	suite.useCase = useCase
+}
+
+func newOrder(total string) *dto.OrderResponseDto {
+	amount := money.MustParse(total)
+	return &dto.OrderResponseDto{
+		ID:          1,
+		TotalAmount: amount,
+		Products: []*dto.OrderProductDto{
+			{ProductId: 1, Name: "Produto Teste", Price: amount, Quantity: 1},
+		},
+	}
+}
+
+func (suite *AddPaymentUseCaseTestSuite) expectOrder(order *dto.OrderResponseDto) {
+	suite.mockOrderClient.EXPECT().
+		GetOrder(order.ID).
+		Return(order, nil).
+		Once()
+}
+
+func (suite *AddPaymentUseCaseTestSuite) expectQRCode(qrData string) {
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Return(&gateways.Charge{QRData: qrData}, nil).
+		Once()
+}
+
+func (suite *AddPaymentUseCaseTestSuite) expectNoActivePayment(orderId uint) {
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(orderId).
+		Return(nil, nil).
+		Once()
 }
 
 func TestAddPaymentUseCaseTestSuite(t *testing.T) {
@@ -44,43 +100,39 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithValidData_ShouldGen
 	// GIVEN a valid payment command
 	command := &commands.AddPaymentCommand{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
 	savedPayment := &entities.Payment{
 		ID:      1,
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 		Status:  "pending",
 	}
 
 	order := &dto.OrderResponseDto{
 		ID:          1,
-		TotalAmount: 100.50,
+		TotalAmount: money.MustParse("100.50"),
 		Products: []*dto.OrderProductDto{
 			{
 				ProductId:   1,
 				Name:        "Produto Teste",
 				Description: "Descrição Teste",
 				Category:    1,
-				Price:       50.25,
+				Price:       money.MustParse("50.25"),
 				Quantity:    2,
 			},
 		},
 	}
 
-	qrCodeResponse := dto.QRCodeResponseDto{
-		QRData: "00020101021243650016COM.MERCADOLIBRE",
+	charge := &gateways.Charge{
+		QRData:          "00020101021243650016COM.MERCADOLIBRE",
+		ProviderOrderId: "in-store-order-1",
 	}
 
-	suite.mockRepository.EXPECT().
-		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
-			return p.OrderId == 1 && p.Total == 100.50 && p.Status == "pending"
-		})).
-		Return(savedPayment, nil).
-		Once()
+	suite.expectNoActivePayment(1)
 
 	suite.mockOrderClient.EXPECT().
 		GetOrder(uint(1)).
@@ -88,16 +140,31 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithValidData_ShouldGen
 		Once()
 
 	suite.mockGateway.EXPECT().
-		GenerateQRCode(mock.Anything, mock.MatchedBy(func(qr dto.CreateQRCodeDTO) bool {
-			return qr.ExternalReference == "order-1" && qr.TotalAmount == 100.50
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.ExternalReference == "order-1" && request.Total == money.New(money.MustParse("100.50"), money.BRL)
+		})).
+		Return(charge, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.OrderId == 1 &&
+				p.Total == money.MustParse("100.50") &&
+				p.Status == "pending" &&
+				p.Active &&
+				p.Type == entities.PaymentTypeQRCode &&
+				p.Provider == entities.PaymentProviderMercadoPago &&
+				p.ProviderOrderId == "in-store-order-1" &&
+				p.ExternalReference == "order-1" &&
+				p.QRData == "00020101021243650016COM.MERCADOLIBRE"
 		})).
-		Return(qrCodeResponse, nil).
+		Return(savedPayment, nil).
 		Once()
 
 	// WHEN adding payment
 	qrCode, err := suite.useCase.Execute(command)
 
-	// THEN QR code should be generated successfully
+	// THEN QR code should be generated and stored on the payment
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "00020101021243650016COM.MERCADOLIBRE", qrCode)
 	suite.mockRepository.AssertExpectations(suite.T())
@@ -109,18 +176,25 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithRepositoryError_Sho
 	// GIVEN a valid payment command
 	command := &commands.AddPaymentCommand{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
 	expectedError := errors.New("database error")
 
+	suite.expectNoActivePayment(1)
+
+	suite.expectOrder(newOrder("100.50"))
+	suite.expectQRCode("qr-data")
+
 	suite.mockRepository.EXPECT().
 		AddPayment(mock.Anything).
 		Return(nil, expectedError).
 		Once()
 
-	// WHEN adding payment with repository error
+	suite.expectNoActivePayment(1)
+
+	// WHEN storing the payment fails after the QR code was issued
 	qrCode, err := suite.useCase.Execute(command)
 
 	// THEN error should be returned
@@ -134,24 +208,13 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithOrderClientError_Sh
 	// GIVEN a valid payment command
 	command := &commands.AddPaymentCommand{
 		OrderId: 1,
-		Total:   100.50,
-		Type:    "QRCode",
-	}
-
-	savedPayment := &entities.Payment{
-		ID:      1,
-		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
-		Status:  "pending",
 	}
 
 	expectedError := errors.New("order service unavailable")
 
-	suite.mockRepository.EXPECT().
-		AddPayment(mock.Anything).
-		Return(savedPayment, nil).
-		Once()
+	suite.expectNoActivePayment(1)
 
 	suite.mockOrderClient.EXPECT().
 		GetOrder(uint(1)).
@@ -166,7 +229,7 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithOrderClientError_Sh
 	assert.Contains(suite.T(), err.Error(), "failed to get order from Order Service")
 	assert.Contains(suite.T(), err.Error(), expectedError.Error())
 	assert.Empty(suite.T(), qrCode)
-	suite.mockRepository.AssertExpectations(suite.T())
+	suite.mockRepository.AssertNotCalled(suite.T(), "AddPayment", mock.Anything)
 	suite.mockOrderClient.AssertExpectations(suite.T())
 }
 
@@ -174,28 +237,28 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithMercadoPagoError_Sh
 	// GIVEN a valid payment command
 	command := &commands.AddPaymentCommand{
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 	}
 
 	savedPayment := &entities.Payment{
 		ID:      1,
 		OrderId: 1,
-		Total:   100.50,
+		Total:   money.MustParse("100.50"),
 		Type:    "QRCode",
 		Status:  "pending",
 	}
 
 	order := &dto.OrderResponseDto{
 		ID:          1,
-		TotalAmount: 100.50,
+		TotalAmount: money.MustParse("100.50"),
 		Products: []*dto.OrderProductDto{
 			{
 				ProductId:   1,
 				Name:        "Produto Teste",
 				Description: "Descrição Teste",
 				Category:    1,
-				Price:       50.25,
+				Price:       money.MustParse("50.25"),
 				Quantity:    2,
 			},
 		},
@@ -203,10 +266,7 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithMercadoPagoError_Sh
 
 	expectedError := errors.New("mercado pago gateway error")
 
-	suite.mockRepository.EXPECT().
-		AddPayment(mock.Anything).
-		Return(savedPayment, nil).
-		Once()
+	suite.expectNoActivePayment(1)
 
 	suite.mockOrderClient.EXPECT().
 		GetOrder(uint(1)).
@@ -214,14 +274,26 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithMercadoPagoError_Sh
 		Once()
 
 	suite.mockGateway.EXPECT().
-		GenerateQRCode(context.Background(), mock.Anything).
-		Return(dto.QRCodeResponseDto{}, expectedError).
+		CreateCharge(context.Background(), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.OrderId == 1 &&
+				p.Status == entities.PaymentStatusFailed &&
+				!p.Active &&
+				p.QRData == "" &&
+				p.ExternalReference == "order-1" &&
+				p.FailureReason == "mercado pago gateway error"
+		})).
+		Return(savedPayment, nil).
 		Once()
 
 	// WHEN mercado pago fails
 	qrCode, err := suite.useCase.Execute(command)
 
-	// THEN error should be returned
+	// THEN error should be returned and only a failed attempt recorded
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), expectedError, err)
 	assert.Empty(suite.T(), qrCode)
@@ -229,3 +301,783 @@ func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithMercadoPagoError_Sh
 	suite.mockOrderClient.AssertExpectations(suite.T())
 	suite.mockGateway.AssertExpectations(suite.T())
 }
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithPendingPayment_ShouldReturnExistingQRCode() {
+	// GIVEN an order with a pending payment
+	command := commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false)
+
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{
+			ID:      1,
+			OrderId: 1,
+			Status:  entities.PaymentStatusPending,
+			Active:  true,
+			QRData:  "existing-qr-data",
+		}, nil).
+		Once()
+
+	// WHEN the totem asks for a payment again
+	qrCode, err := suite.useCase.Execute(command)
+
+	// THEN the existing QR code should be returned without creating a payment
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "existing-qr-data", qrCode)
+	suite.mockRepository.AssertNotCalled(suite.T(), "AddPayment", mock.Anything)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithRegenerate_ShouldCancelPendingPaymentAndCreateNewOne() {
+	// GIVEN an order with a pending payment
+	command := commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", true)
+
+	pending := &entities.Payment{
+		ID:      1,
+		OrderId: 1,
+		Status:  entities.PaymentStatusPending,
+		Active:  true,
+		QRData:  "old-qr-data",
+	}
+
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(pending, nil).
+		Once()
+
+	suite.expectOrder(newOrder("100.50"))
+	suite.expectQRCode("new-qr-data")
+
+	suite.mockRepository.EXPECT().
+		ReplacePayment(
+			mock.MatchedBy(func(p *entities.Payment) bool {
+				return p.ID == 1 && p.Status == entities.PaymentStatusCancelled && !p.Active
+			}),
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.PaymentId == 1 &&
+					change.FromStatus == entities.PaymentStatusPending &&
+					change.ToStatus == entities.PaymentStatusCancelled &&
+					change.Source == entities.PaymentStatusChangeSourceAPI
+			}),
+			mock.MatchedBy(func(p *entities.Payment) bool {
+				return p.Status == entities.PaymentStatusPending && p.Active && p.QRData == "new-qr-data"
+			})).
+		Return(&entities.Payment{ID: 2, OrderId: 1, Status: entities.PaymentStatusPending, Active: true}, nil).
+		Once()
+
+	// WHEN regenerating the payment
+	qrCode, err := suite.useCase.Execute(command)
+
+	// THEN the old payment should be cancelled and a new QR code issued
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "new-qr-data", qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithApprovedPayment_ShouldReturnConflict() {
+	// GIVEN an order that was already paid
+	for _, regenerate := range []bool{false, true} {
+		suite.mockRepository.EXPECT().
+			FindActivePaymentByOrderId(uint(1)).
+			Return(&entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusApproved, Active: true}, nil).
+			Once()
+
+		// WHEN asking for a new payment
+		qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", regenerate))
+
+		// THEN it should be rejected
+		assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+		assert.Empty(suite.T(), qrCode)
+	}
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithSplitPayment_ShouldReturnConflict() {
+	// GIVEN an order paid in split legs, with a pending leg
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 1, OrderId: 1, SplitId: "split", Leg: 1, Status: entities.PaymentStatusPending, Active: true, QRData: "leg-qr"}, nil).
+		Once()
+
+	// WHEN regenerating a single payment for it
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", true))
+
+	// THEN the legs should be kept
+	assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+	assert.Empty(suite.T(), qrCode)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithPendingPaymentWithoutQRCode_ShouldReturnConflict() {
+	// GIVEN a pending payment whose QR code was never stored
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusPending, Active: true}, nil).
+		Once()
+
+	// WHEN asking for a payment without regenerate
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN the caller should be told to regenerate
+	assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+	assert.Contains(suite.T(), err.Error(), "regenerate")
+	assert.Empty(suite.T(), qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithConcurrentCreation_ShouldReturnConflict() {
+	// GIVEN another request that creates the active payment first
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+	suite.expectQRCode("qr-data")
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.Anything).
+		Return(nil, errors.New("duplicate key value violates unique constraint \"idx_payment_active_order\"")).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 2, OrderId: 1, Status: entities.PaymentStatusPending, Active: true}, nil).
+		Once()
+
+	// WHEN inserting the payment
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN a conflict should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+	assert.Empty(suite.T(), qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) expectQRCodeItems(order *dto.OrderResponseDto, assertItems func(items []dto.Item)) {
+	suite.expectNoActivePayment(order.ID)
+	suite.expectOrder(order)
+
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, request gateways.ChargeRequest) {
+			assertItems(request.Items)
+		}).
+		Return(&gateways.Charge{QRData: "qr-data"}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.Anything).
+		Return(&entities.Payment{ID: 1, OrderId: order.ID, Status: entities.PaymentStatusPending, Active: true}, nil).
+		Once()
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_ShouldComputeItemTotalsWithoutRounding() {
+	// GIVEN an order whose line totals drift when computed with floats
+	order := &dto.OrderResponseDto{
+		ID:          1,
+		TotalAmount: money.MustParse("0.50"),
+		Products: []*dto.OrderProductDto{
+			{ProductId: 1, Price: money.MustParse("0.10"), Quantity: 3},
+			{ProductId: 2, Price: money.MustParse("0.20"), Quantity: 1},
+		},
+	}
+
+	suite.expectQRCodeItems(order, func(items []dto.Item) {
+		// THEN the item totals should be exact and add up to the order total
+		assert.Equal(suite.T(), money.MustParse("0.30"), items[0].TotalAmount)
+		assert.Equal(suite.T(), money.MustParse("0.20"), items[1].TotalAmount)
+		assert.Equal(suite.T(), money.MustParse("0.10"), items[0].UnitPrice)
+	})
+
+	// WHEN adding payment
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("0.50"), "QRCode", false))
+
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithRoundingWithinTolerance_ShouldAddAdjustmentLine() {
+	// GIVEN a one cent tolerance and an order whose items add up to one cent less than its total
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(
+		suite.registry,
+		suite.mockOrderClient,
+		suite.mockRepository,
+		&addpayment.AddPaymentConfig{AmountTolerance: money.MustParse("0.01")},
+	)
+	suite.Require().NoError(err)
+
+	order := &dto.OrderResponseDto{
+		ID:          1,
+		TotalAmount: money.MustParse("10.00"),
+		Products: []*dto.OrderProductDto{
+			{ProductId: 1, Price: money.MustParse("3.33"), Quantity: 1},
+			{ProductId: 2, Price: money.MustParse("3.33"), Quantity: 1},
+			{ProductId: 3, Price: money.MustParse("3.33"), Quantity: 1},
+		},
+	}
+
+	suite.expectQRCodeItems(order, func(items []dto.Item) {
+		// THEN the product lines should be kept and the difference charged on an adjustment line
+		var totals []money.Amount
+		for _, item := range items {
+			assert.Equal(suite.T(), item.UnitPrice.Mul(int64(item.Quantity)), item.TotalAmount)
+			totals = append(totals, item.TotalAmount)
+		}
+		assert.Equal(suite.T(), order.TotalAmount, money.Sum(totals...))
+		suite.Require().Len(items, 4)
+		for _, item := range items[:3] {
+			assert.Equal(suite.T(), money.MustParse("3.33"), item.TotalAmount)
+		}
+		assert.Equal(suite.T(), "adjustment", items[3].SKUNumber)
+		assert.Equal(suite.T(), money.MustParse("0.01"), items[3].TotalAmount)
+	})
+
+	// WHEN adding payment
+	_, err = useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("10.00"), "QRCode", false))
+
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithAmountMismatch_ShouldRejectWithoutCharging() {
+	// GIVEN a request whose amount differs from the Order Service total
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("99.90"))
+
+	// WHEN adding payment
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.00"), "QRCode", false))
+
+	// THEN the request should be rejected before anything is stored or charged
+	assert.ErrorIs(suite.T(), err, entities.ErrAmountMismatch)
+	var mismatch *entities.PaymentAmountMismatchError
+	assert.ErrorAs(suite.T(), err, &mismatch)
+	assert.Equal(suite.T(), money.MustParse("100.00"), mismatch.Requested)
+	assert.Equal(suite.T(), money.MustParse("99.90"), mismatch.OrderTotal)
+	assert.Contains(suite.T(), err.Error(), "requested 100.00, order total is 99.90")
+	assert.Empty(suite.T(), qrCode)
+	suite.mockRepository.AssertNotCalled(suite.T(), "AddPayment", mock.Anything)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithinTolerance_ShouldPersistOrderTotal() {
+	// GIVEN a five cent tolerance and a request that is off by three cents
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(
+		suite.registry,
+		suite.mockOrderClient,
+		suite.mockRepository,
+		&addpayment.AddPaymentConfig{AmountTolerance: money.MustParse("0.05")},
+	)
+	suite.Require().NoError(err)
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("99.90"))
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Total == money.MustParse("99.90") && p.Currency == money.BRL
+		})).
+		Return(&entities.Payment{ID: 1, OrderId: 1, Total: money.MustParse("99.90")}, nil).
+		Once()
+
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.Total.Amount == money.MustParse("99.90")
+		})).
+		Return(&gateways.Charge{QRData: "qr-data"}, nil).
+		Once()
+
+	// WHEN adding payment
+	qrCode, err := useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("99.93"), "QRCode", false))
+
+	// THEN the Order Service total should be stored and charged
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "qr-data", qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithItemsNotAddingUp_ShouldReject() {
+	// GIVEN an order whose items do not add up to its total
+	order := newOrder("50.00")
+	order.Products = append(order.Products, &dto.OrderProductDto{ProductId: 2, Price: money.MustParse("10.00"), Quantity: 2})
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(order)
+
+	// WHEN adding payment
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("50.00"), "QRCode", false))
+
+	// THEN the order should be rejected
+	assert.ErrorIs(suite.T(), err, entities.ErrAmountMismatch)
+	var mismatch *entities.OrderTotalMismatchError
+	assert.ErrorAs(suite.T(), err, &mismatch)
+	assert.Equal(suite.T(), money.MustParse("70.00"), mismatch.ItemsTotal)
+	suite.mockRepository.AssertNotCalled(suite.T(), "AddPayment", mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithRegenerateAndAmountMismatch_ShouldKeepPendingPayment() {
+	// GIVEN a pending payment and an invalid regenerate request
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusPending, Active: true, QRData: "old"}, nil).
+		Once()
+	suite.expectOrder(newOrder("99.90"))
+
+	// WHEN regenerating with the wrong amount
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("10.00"), "QRCode", true))
+
+	// THEN the pending payment should not be cancelled
+	assert.ErrorIs(suite.T(), err, entities.ErrAmountMismatch)
+	suite.mockRepository.AssertNotCalled(suite.T(), "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithOrderClientError_ShouldNotCallGateway() {
+	// GIVEN an unavailable Order Service
+	suite.expectNoActivePayment(1)
+
+	suite.mockOrderClient.EXPECT().
+		GetOrder(uint(1)).
+		Return(nil, errors.New("order service unavailable")).
+		Once()
+
+	// WHEN adding payment
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN nothing should be charged or stored
+	assert.Error(suite.T(), err)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+	suite.mockRepository.AssertNotCalled(suite.T(), "AddPayment", mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithGatewayAndRecordingErrors_ShouldReturnGatewayError() {
+	// GIVEN a gateway failure and a database that cannot record it either
+	gatewayError := errors.New("mercado pago gateway error")
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Return(nil, gatewayError).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.Anything).
+		Return(nil, errors.New("database error")).
+		Once()
+
+	// WHEN adding payment
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN the gateway error should be reported
+	assert.Equal(suite.T(), gatewayError, err)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithRegenerateAndGatewayError_ShouldKeepPendingPayment() {
+	// GIVEN a pending payment and a gateway that fails to issue the new QR code
+	pending := &entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusPending, Active: true, QRData: "old"}
+
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(pending, nil).
+		Once()
+	suite.expectOrder(newOrder("100.50"))
+
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Return(nil, errors.New("mercado pago gateway error")).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Status == entities.PaymentStatusFailed
+		})).
+		Return(&entities.Payment{ID: 2}, nil).
+		Once()
+
+	// WHEN regenerating
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", true))
+
+	// THEN the pending payment should stay active
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), entities.PaymentStatusPending, pending.Status)
+	suite.mockRepository.AssertNotCalled(suite.T(), "ReplacePayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithReplaceError_ShouldReturnError() {
+	// GIVEN a pending payment that cannot be replaced
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusPending, Active: true, QRData: "old"}, nil).
+		Once()
+	suite.expectOrder(newOrder("100.50"))
+	suite.expectQRCode("new-qr-data")
+
+	suite.mockRepository.EXPECT().
+		ReplacePayment(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	// The old payment is still the active one, so this is not a concurrent creation
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusPending, Active: true}, nil).
+		Once()
+
+	// WHEN regenerating
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", true))
+
+	// THEN the database error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Empty(suite.T(), qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithQRCodeTTL_ShouldSendAndStoreExpiration() {
+	// GIVEN a use case whose QR codes expire after 15 minutes
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(
+		suite.registry,
+		suite.mockOrderClient,
+		suite.mockRepository,
+		&addpayment.AddPaymentConfig{QRCodeTTL: 15 * time.Minute},
+	)
+	suite.Require().NoError(err)
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+
+	var sentExpiresAt *time.Time
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, request gateways.ChargeRequest) {
+			sentExpiresAt = request.ExpiresAt
+		}).
+		Return(&gateways.Charge{QRData: "qr-data"}, nil).
+		Once()
+
+	var stored *entities.Payment
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.Anything).
+		Run(func(p *entities.Payment) { stored = p }).
+		Return(&entities.Payment{ID: 1}, nil).
+		Once()
+
+	// WHEN adding payment
+	before := time.Now()
+	_, err = useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN the gateway and the payment should get the same expiration, 15 minutes ahead
+	assert.NoError(suite.T(), err)
+	suite.Require().NotNil(stored.ExpiresAt)
+	assert.WithinDuration(suite.T(), before.Add(15*time.Minute), *stored.ExpiresAt, time.Second)
+	suite.Require().NotNil(sentExpiresAt)
+	assert.Equal(suite.T(), *stored.ExpiresAt, *sentExpiresAt)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithQRCodeTTLAndCardType_ShouldNotExpire() {
+	// GIVEN a use case whose QR codes expire and a card gateway that does not enforce expiration
+	cardGateway := mockGateways.NewMockPaymentGateway(suite.T())
+	cardGateway.EXPECT().Name().Return(entities.PaymentProviderStripe).Maybe()
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockGateway},
+		{PaymentType: entities.PaymentTypeCard, Gateway: cardGateway},
+	}, &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode})
+	suite.Require().NoError(err)
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(
+		registry,
+		suite.mockOrderClient,
+		suite.mockRepository,
+		&addpayment.AddPaymentConfig{QRCodeTTL: 15 * time.Minute},
+	)
+	suite.Require().NoError(err)
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+	cardGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.ExpiresAt == nil
+		})).
+		Return(&gateways.Charge{ProviderPaymentId: "pi_1", ClientSecret: "pi_1_secret"}, nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool { return p.ExpiresAt == nil })).
+		Return(&entities.Payment{ID: 1}, nil).
+		Once()
+
+	// WHEN adding a card payment
+	command := commands.NewAddPaymentCommand(1, money.MustParse("100.50"), entities.PaymentTypeCard, false)
+	command.PaymentMethod = "pm_card_visa"
+	_, err = useCase.Execute(command)
+
+	// THEN no expiration should be sent or stored, so the sweeper never expires the payment
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithoutQRCodeTTL_ShouldNotExpire() {
+	// GIVEN expiration is disabled
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.ExpiresAt == nil
+		})).
+		Return(&gateways.Charge{QRData: "qr-data"}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool { return p.ExpiresAt == nil })).
+		Return(&entities.Payment{ID: 1}, nil).
+		Once()
+
+	// WHEN adding payment
+	_, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN no expiration should be sent or stored
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithExpiredPendingPayment_ShouldReplaceItAsExpired() {
+	// GIVEN a pending payment whose QR code expired before the sweeper ran
+	expiredAt := time.Now().Add(-time.Minute)
+	pending := &entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusPending, Active: true, QRData: "old", ExpiresAt: &expiredAt}
+
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(pending, nil).
+		Once()
+	suite.expectOrder(newOrder("100.50"))
+	suite.expectQRCode("new-qr-data")
+
+	suite.mockRepository.EXPECT().
+		ReplacePayment(
+			mock.MatchedBy(func(p *entities.Payment) bool { return p.Status == entities.PaymentStatusExpired }),
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.ToStatus == entities.PaymentStatusExpired &&
+					change.Source == entities.PaymentStatusChangeSourceReconciliation
+			}),
+			mock.Anything).
+		Return(&entities.Payment{ID: 2}, nil).
+		Once()
+
+	// WHEN adding payment without asking to regenerate
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "QRCode", false))
+
+	// THEN a new QR code should be issued instead of the stale one
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "new-qr-data", qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithUnsupportedType_ShouldRejectWithoutCharging() {
+	// GIVEN a payment type no gateway is registered for
+	command := commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "boleto", false)
+
+	// WHEN adding payment
+	qrCode, err := suite.useCase.Execute(command)
+
+	// THEN the request should be rejected before anything is looked up, stored or charged
+	assert.ErrorIs(suite.T(), err, entities.ErrUnsupportedPaymentType)
+	assert.Empty(suite.T(), qrCode)
+	suite.mockRepository.AssertNotCalled(suite.T(), "FindActivePaymentByOrderId", mock.Anything)
+	suite.mockOrderClient.AssertNotCalled(suite.T(), "GetOrder", mock.Anything)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithoutType_ShouldUseDefaultGateway() {
+	// GIVEN a request that does not name a payment type
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+	suite.expectQRCode("qr-data")
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Type == entities.PaymentTypeQRCode && p.Provider == entities.PaymentProviderMercadoPago
+		})).
+		Return(&entities.Payment{ID: 1}, nil).
+		Once()
+
+	// WHEN adding payment
+	qrCode, err := suite.useCase.Execute(commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "", false))
+
+	// THEN the default gateway should charge it
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "qr-data", qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithCardType_ShouldReturnClientSecret() {
+	// GIVEN a card gateway next to the QR code one
+	cardGateway := mockGateways.NewMockPaymentGateway(suite.T())
+	cardGateway.EXPECT().Name().Return(entities.PaymentProviderStripe).Maybe()
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockGateway},
+		{PaymentType: entities.PaymentTypeCard, Gateway: cardGateway},
+	}, &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode})
+	suite.Require().NoError(err)
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(registry, suite.mockOrderClient, suite.mockRepository, &addpayment.AddPaymentConfig{})
+	suite.Require().NoError(err)
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("100.50"))
+	cardGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.PaymentMethod == "pm_card_visa"
+		})).
+		Return(&gateways.Charge{ProviderPaymentId: "pi_1", ClientSecret: "pi_1_secret"}, nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Type == entities.PaymentTypeCard &&
+				p.Provider == entities.PaymentProviderStripe &&
+				p.ProviderPaymentId == "pi_1" &&
+				p.ClientSecret == "pi_1_secret" &&
+				p.QRData == ""
+		})).
+		Return(&entities.Payment{ID: 1}, nil).
+		Once()
+
+	command := commands.NewAddPaymentCommand(1, money.MustParse("100.50"), "card", false)
+	command.PaymentMethod = "pm_card_visa"
+
+	// WHEN adding payment
+	clientSecret, err := useCase.Execute(command)
+
+	// THEN the card gateway should charge it and the client secret should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pi_1_secret", clientSecret)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithServiceFeesAndTip_ShouldChargeThemAsItems() {
+	// GIVEN a 10% service fee, a fixed packaging fee and a 5.00 tip on a 50.00 order
+	useCase, err := addpayment.NewAddPaymentUseCaseImplWithConfig(
+		suite.registry,
+		suite.mockOrderClient,
+		suite.mockRepository,
+		&addpayment.AddPaymentConfig{ServiceFees: []addpayment.ServiceFee{
+			{Title: "Taxa de serviço", Percentage: money.MustParse("10")},
+			{Title: "Embalagem", Amount: money.MustParse("2.00")},
+		}},
+	)
+	suite.Require().NoError(err)
+
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("50.00"))
+
+	suite.mockGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, request gateways.ChargeRequest) {
+			// THEN the fees and the tip should be charged as lines of their own on top of the order
+			assert.Equal(suite.T(), money.MustParse("62.00"), request.Total.Amount)
+			suite.Require().Len(request.Items, 4)
+			assert.Equal(suite.T(), money.MustParse("50.00"), request.Items[0].TotalAmount)
+			assert.Equal(suite.T(), "Taxa de serviço", request.Items[1].Title)
+			assert.Equal(suite.T(), money.MustParse("5.00"), request.Items[1].TotalAmount)
+			assert.Equal(suite.T(), "Embalagem", request.Items[2].Title)
+			assert.Equal(suite.T(), money.MustParse("2.00"), request.Items[2].TotalAmount)
+			assert.Equal(suite.T(), "tip", request.Items[3].SKUNumber)
+			assert.Equal(suite.T(), money.MustParse("5.00"), request.Items[3].TotalAmount)
+		}).
+		Return(&gateways.Charge{QRData: "qr-data"}, nil).
+		Once()
+
+	// AND the payment should keep the tip and the fees apart from the order total
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Total == money.MustParse("62.00") &&
+				p.TipAmount == money.MustParse("5.00") &&
+				p.ServiceFeeAmount == money.MustParse("7.00")
+		})).
+		Return(&entities.Payment{ID: 1, OrderId: 1}, nil).
+		Once()
+
+	// WHEN adding payment with a tip
+	command := commands.NewAddPaymentCommand(1, money.MustParse("50.00"), "QRCode", false)
+	command.Tip = commands.Tip{Amount: money.MustParse("5.00")}
+	qrCode, err := useCase.Execute(command)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "qr-data", qrCode)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithTipPercentage_ShouldTakeItOfTheOrderTotal() {
+	// GIVEN a 12.5% tip on a 99.90 order
+	suite.expectQRCodeItems(newOrder("99.90"), func(items []dto.Item) {
+		// THEN the tip should be rounded to the cent
+		suite.Require().Len(items, 2)
+		assert.Equal(suite.T(), money.MustParse("12.49"), items[1].TotalAmount)
+	})
+
+	// WHEN adding payment
+	command := commands.NewAddPaymentCommand(1, money.MustParse("99.90"), "QRCode", false)
+	command.Tip = commands.Tip{Percentage: money.MustParse("12.5")}
+	_, err := suite.useCase.Execute(command)
+
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithInvalidTip_ShouldRejectWithoutCharging() {
+	// GIVEN a tip given both as an amount and as a percentage
+	suite.expectNoActivePayment(1)
+	suite.expectOrder(newOrder("50.00"))
+
+	// WHEN adding payment
+	command := commands.NewAddPaymentCommand(1, money.MustParse("50.00"), "QRCode", false)
+	command.Tip = commands.Tip{Amount: money.MustParse("5.00"), Percentage: money.MustParse("10")}
+	qrCode, err := suite.useCase.Execute(command)
+
+	// THEN the request should be rejected before anything is stored or charged
+	assert.ErrorIs(suite.T(), err, entities.ErrInvalidTip)
+	assert.Empty(suite.T(), qrCode)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddPaymentUseCaseTestSuite) Test_AddPayment_WithTipAboveOrderTotal_ShouldRejectWithoutCharging() {
+	for _, tip := range []commands.Tip{
+		{Amount: money.MustParse("50.01")},
+		{Percentage: money.MustParse("150")},
+	} {
+		// GIVEN an order of 50.00
+		suite.expectNoActivePayment(1)
+		suite.expectOrder(newOrder("50.00"))
+
+		// WHEN adding payment with a tip larger than the order
+		command := commands.NewAddPaymentCommand(1, money.MustParse("50.00"), "QRCode", false)
+		command.Tip = tip
+		qrCode, err := suite.useCase.Execute(command)
+
+		// THEN the request should be rejected before anything is stored or charged
+		assert.ErrorIs(suite.T(), err, entities.ErrInvalidTip)
+		assert.Contains(suite.T(), err.Error(), "exceeds the order total of 50.00")
+		assert.Empty(suite.T(), qrCode)
+	}
+	suite.mockGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func TestAddPaymentConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&addpayment.AddPaymentConfig{}).Validate())
+	assert.Error(t, (&addpayment.AddPaymentConfig{AmountTolerance: -1}).Validate())
+	assert.Error(t, (&addpayment.AddPaymentConfig{QRCodeTTL: -time.Minute}).Validate())
+	assert.Error(t, (&addpayment.AddPaymentConfig{ServiceFees: []addpayment.ServiceFee{{Amount: 100}}}).Validate())
+	assert.Error(t, (&addpayment.AddPaymentConfig{ServiceFees: []addpayment.ServiceFee{{Title: "Taxa", Amount: 100, Percentage: 1000}}}).Validate())
+}
+
+func TestNewAddPaymentUseCaseImpl_WithServiceFees_ShouldParseThem(t *testing.T) {
+	t.Setenv("PAYMENT_SERVICE_FEES", "Taxa de serviço:10%, Embalagem:2.00")
+
+	_, err := addpayment.NewAddPaymentUseCaseImpl(nil, nil, nil)
+
+	assert.NoError(t, err)
+}
+
+func TestNewAddPaymentUseCaseImpl_WithInvalidServiceFees_ShouldFail(t *testing.T) {
+	t.Setenv("PAYMENT_SERVICE_FEES", "Taxa de serviço")
+
+	_, err := addpayment.NewAddPaymentUseCaseImpl(nil, nil, nil)
+
+	assert.ErrorContains(t, err, "PAYMENT_SERVICE_FEES")
+}
+
+func TestNewAddPaymentUseCaseImpl_WithInvalidQRCodeTTL_ShouldFail(t *testing.T) {
+	t.Setenv("PAYMENT_QR_CODE_TTL", "forever")
+
+	_, err := addpayment.NewAddPaymentUseCaseImpl(nil, nil, nil)
+
+	assert.ErrorContains(t, err, "PAYMENT_QR_CODE_TTL")
+}
+
+func TestNewAddPaymentUseCaseImpl_WithInvalidTolerance_ShouldFail(t *testing.T) {
+	t.Setenv("PAYMENT_AMOUNT_TOLERANCE", "one cent")
+
+	_, err := addpayment.NewAddPaymentUseCaseImpl(nil, nil, nil)
+
+	assert.ErrorContains(t, err, "PAYMENT_AMOUNT_TOLERANCE")
+}
@@ -0,0 +1,10 @@
+package addpayment
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type AddSplitPaymentUseCase interface {
+	Execute(command *commands.AddSplitPaymentCommand) (*entities.SplitPayment, error)
+}
@@ -0,0 +1,284 @@
+package addpayment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/clients"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+var (
+	_ AddSplitPaymentUseCase = (*AddSplitPaymentUseCaseImpl)(nil)
+)
+
+type AddSplitPaymentUseCaseImpl struct {
+	config            *AddPaymentConfig
+	gatewayRegistry   *gateways.PaymentGatewayRegistry
+	orderClient       clients.OrderClient
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewAddSplitPaymentUseCaseImpl(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	orderClient clients.OrderClient,
+	paymentRepository repositories.PaymentRepository) (*AddSplitPaymentUseCaseImpl, error) {
+	config, err := newAddPaymentConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewAddSplitPaymentUseCaseImplWithConfig(gatewayRegistry, orderClient, paymentRepository, config)
+}
+
+func NewAddSplitPaymentUseCaseImplWithConfig(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	orderClient clients.OrderClient,
+	paymentRepository repositories.PaymentRepository,
+	config *AddPaymentConfig) (*AddSplitPaymentUseCaseImpl, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &AddSplitPaymentUseCaseImpl{
+		config:            config,
+		gatewayRegistry:   gatewayRegistry,
+		orderClient:       orderClient,
+		paymentRepository: paymentRepository,
+	}, nil
+}
+
+// splitLeg is a leg of the request with the gateway that charges it.
+type splitLeg struct {
+	commands.SplitPaymentLeg
+	Number      int
+	PaymentType string
+	Gateway     gateways.PaymentGateway
+}
+
+func (u *AddSplitPaymentUseCaseImpl) Execute(command *commands.AddSplitPaymentCommand) (*entities.SplitPayment, error) {
+	legs, err := u.legsFromCommand(command)
+	if err != nil {
+		return nil, err
+	}
+
+	activePayment, err := u.paymentRepository.FindActivePaymentByOrderId(command.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new split issues every leg; a live split of the same amounts only issues the legs that cannot
+	// be paid anymore, plus the pending ones when regenerating
+	split := &entities.SplitPayment{OrderId: command.OrderId, SplitId: entities.NewSplitId()}
+	toIssue := legs
+	var replaced []*entities.Payment
+	now := time.Now()
+
+	if activePayment != nil {
+		switch {
+		case activePayment.IsSplitLeg():
+			attempts, err := u.paymentRepository.ListSplitLegs(command.OrderId)
+			if err != nil {
+				return nil, err
+			}
+			split = entities.NewSplitPayment(attempts)
+			if !split.MatchesAmounts(legAmounts(legs)) {
+				return nil, fmt.Errorf("%w: order %d is already split into other amounts", entities.ErrActivePaymentExists, command.OrderId)
+			}
+
+			toIssue = nil
+			for i, leg := range split.Legs {
+				if !needsIssuing(leg, command.Regenerate, now) {
+					continue
+				}
+				toIssue = append(toIssue, legs[i])
+				if leg.Active {
+					replaced = append(replaced, leg)
+				}
+			}
+			if len(toIssue) == 0 {
+				// Hand out the legs of the split instead of orphaning them
+				return split, nil
+			}
+		case activePayment.Status != entities.PaymentStatusPending:
+			return nil, fmt.Errorf("%w: payment %d is %s", entities.ErrActivePaymentExists, activePayment.ID, activePayment.Status)
+		case !command.Regenerate && !activePayment.IsExpired(now):
+			return nil, fmt.Errorf("%w: payment %d is pending, retry with regenerate to split it", entities.ErrActivePaymentExists, activePayment.ID)
+		default:
+			replaced = append(replaced, activePayment)
+		}
+	}
+
+	// Get order details from Order Service
+	order, err := u.orderClient.GetOrder(command.OrderId)
+	if err != nil {
+		println("ERROR: Failed to get order from Order Service:", err.Error())
+		return nil, fmt.Errorf("failed to get order from Order Service: %w", err)
+	}
+
+	if err := u.config.validateAmounts(command.Total, order); err != nil {
+		return nil, err
+	}
+	ordered, err := u.config.chargeOrder(order, command.Tip)
+	if err != nil {
+		return nil, err
+	}
+	if legsTotal := money.Sum(legAmounts(legs)...); legsTotal != ordered.Total {
+		return nil, &entities.SplitAmountMismatchError{LegsTotal: legsTotal, Charged: ordered.Total}
+	}
+	// Each leg carries its share of the service fees and tip
+	serviceFees := money.Allocate(ordered.ServiceFees, legAmounts(legs))
+	tips := money.Allocate(ordered.Tip, legAmounts(legs))
+
+	issued := make([]*entities.Payment, 0, len(toIssue))
+	for _, leg := range toIssue {
+		chargeRequest := gateways.ChargeRequest{
+			ExternalReference: entities.LegExternalReference(order.ID, leg.Number),
+			Total:             money.New(leg.Amount, money.BRL),
+			Items:             []dto.Item{legItem(order.ID, leg.Number, leg.Amount)},
+			PaymentMethod:     leg.PaymentMethod,
+			ExpiresAt:         u.config.chargeExpiry(leg.Gateway, now),
+		}
+
+		charge, err := leg.Gateway.CreateCharge(context.Background(), chargeRequest)
+		if err != nil {
+			u.recordFailedLeg(command.OrderId, split.SplitId, leg, chargeRequest, err)
+			// The order is either split as a whole or not at all
+			u.cancelCharges(issued)
+			return nil, err
+		}
+
+		payment := entities.NewPaymentLeg(command.OrderId, split.SplitId, leg.Number, chargeRequest.Total, leg.PaymentType)
+		payment.ServiceFeeAmount = serviceFees[leg.Number-1]
+		payment.TipAmount = tips[leg.Number-1]
+		payment.Provider = leg.Gateway.Name()
+		payment.ProviderOrderId = charge.ProviderOrderId
+		payment.ProviderPaymentId = charge.ProviderPaymentId
+		payment.ExternalReference = chargeRequest.ExternalReference
+		payment.QRData = charge.QRData
+		payment.ClientSecret = charge.ClientSecret
+		payment.Txid = charge.Txid
+		payment.ExpiresAt = chargeRequest.ExpiresAt
+		issued = append(issued, payment)
+	}
+
+	changes := make([]*entities.PaymentStatusChange, 0, len(replaced))
+	for _, payment := range replaced {
+		change, err := closeReplacedPayment(payment)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	if err := u.paymentRepository.AddSplitPayment(replaced, changes, issued); err != nil {
+		u.cancelCharges(issued)
+		// A concurrent request may have paid or split the order first
+		concurrent, findErr := u.paymentRepository.FindActivePaymentByOrderId(command.OrderId)
+		if findErr == nil && concurrent != nil && (activePayment == nil || concurrent.ID != activePayment.ID) {
+			return nil, fmt.Errorf("%w: payment %d", entities.ErrActivePaymentExists, concurrent.ID)
+		}
+		println("ERROR: Failed to store split payment after creating its charges:", err.Error())
+		return nil, err
+	}
+
+	return withLegs(split, issued), nil
+}
+
+// legsFromCommand checks the requested legs and finds the gateway charging each of them.
+func (u *AddSplitPaymentUseCaseImpl) legsFromCommand(command *commands.AddSplitPaymentCommand) ([]splitLeg, error) {
+	if len(command.Legs) < 2 {
+		return nil, fmt.Errorf("%w: an order is split into at least two legs", entities.ErrInvalidSplitPayment)
+	}
+
+	legs := make([]splitLeg, len(command.Legs))
+	singleSlotProviders := make(map[string]bool)
+	for i, requested := range command.Legs {
+		if requested.Amount <= 0 {
+			return nil, fmt.Errorf("%w: leg %d must charge a positive amount", entities.ErrInvalidSplitPayment, i+1)
+		}
+
+		paymentType := u.gatewayRegistry.PaymentType(requested.Type)
+		gateway, err := u.gatewayRegistry.ForPaymentType(paymentType)
+		if err != nil {
+			return nil, err
+		}
+		if singleSlot, ok := gateway.(gateways.SingleSlotPaymentGateway); ok && singleSlot.SingleSlot() {
+			// A second charge would replace the first one, which could then never be paid
+			if singleSlotProviders[gateway.Name()] {
+				return nil, fmt.Errorf("%w: %s holds one charge at a time, so only one leg can be charged through it",
+					entities.ErrInvalidSplitPayment, gateway.Name())
+			}
+			singleSlotProviders[gateway.Name()] = true
+		}
+		legs[i] = splitLeg{SplitPaymentLeg: requested, Number: i + 1, PaymentType: paymentType, Gateway: gateway}
+	}
+	return legs, nil
+}
+
+// recordFailedLeg keeps a failed leg attempt for auditing, like recordFailedPayment does for single
+// payments.
+func (u *AddSplitPaymentUseCaseImpl) recordFailedLeg(orderId uint, splitId string, leg splitLeg, chargeRequest gateways.ChargeRequest, cause error) {
+	failed := entities.NewFailedPayment(orderId, chargeRequest.Total, leg.PaymentType, cause.Error())
+	failed.SplitId = splitId
+	failed.Leg = leg.Number
+	failed.Provider = leg.Gateway.Name()
+	failed.ExternalReference = chargeRequest.ExternalReference
+	if _, err := u.paymentRepository.AddPayment(failed); err != nil {
+		println("ERROR: Failed to record failed payment leg:", err.Error())
+	}
+}
+
+// cancelCharges cancels on their providers the charges created for a split payment that could not be
+// completed. It is best effort: a charge left behind can still be cancelled by the provider expiry.
+func (u *AddSplitPaymentUseCaseImpl) cancelCharges(payments []*entities.Payment) {
+	for _, payment := range payments {
+		gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider)
+		if !ok {
+			continue
+		}
+		if err := gateway.CancelCharge(context.Background(), payment); err != nil {
+			println("ERROR: Failed to cancel payment leg on", gateway.Name()+":", err.Error())
+		}
+	}
+}
+
+// needsIssuing reports whether a leg of a live split is charged again: when it can no longer be paid,
+// or when it is still pending and the caller asked to regenerate it.
+func needsIssuing(leg *entities.Payment, regenerate bool, now time.Time) bool {
+	switch leg.Status {
+	case entities.PaymentStatusDeclined, entities.PaymentStatusCancelled, entities.PaymentStatusExpired, entities.PaymentStatusFailed:
+		return true
+	case entities.PaymentStatusPending:
+		return regenerate || leg.IsExpired(now)
+	}
+	return false
+}
+
+// withLegs returns the split payment with the given legs in place of the attempts they replace.
+func withLegs(split *entities.SplitPayment, legs []*entities.Payment) *entities.SplitPayment {
+	for _, leg := range legs {
+		replaced := false
+		for i, current := range split.Legs {
+			if current.Leg == leg.Leg {
+				split.Legs[i], replaced = leg, true
+			}
+		}
+		if !replaced {
+			split.Legs = append(split.Legs, leg)
+		}
+	}
+	return split
+}
+
+func legAmounts(legs []splitLeg) []money.Amount {
+	amounts := make([]money.Amount, len(legs))
+	for i, leg := range legs {
+		amounts[i] = leg.Amount
+	}
+	return amounts
+}
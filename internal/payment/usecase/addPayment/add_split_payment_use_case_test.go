@@ -0,0 +1,446 @@
+package addpayment_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	addpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/addPayment"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	mockClients "github.com/abattassini/tc-fiap-payment/mocks/payment/infrastructure/clients"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AddSplitPaymentUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository  *mockRepositories.MockPaymentRepository
+	mockQRGateway   *mockGateways.MockPaymentGateway
+	mockCardGateway *mockGateways.MockPaymentGateway
+	mockOrderClient *mockClients.MockOrderClient
+	useCase         addpayment.AddSplitPaymentUseCase
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockQRGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockQRGateway.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
+	suite.mockCardGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockCardGateway.EXPECT().Name().Return(entities.PaymentProviderStripe).Maybe()
+	suite.mockOrderClient = mockClients.NewMockOrderClient(suite.T())
+
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig(
+		[]gateways.PaymentGatewayRegistration{
+			{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockQRGateway},
+			{PaymentType: entities.PaymentTypeCard, Gateway: suite.mockCardGateway},
+		},
+		&gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode},
+	)
+	suite.Require().NoError(err)
+
+	useCase, err := addpayment.NewAddSplitPaymentUseCaseImplWithConfig(
+		registry,
+		suite.mockOrderClient,
+		suite.mockRepository,
+		&addpayment.AddPaymentConfig{},
+	)
+	suite.Require().NoError(err)
+	suite.useCase = useCase
+}
+
+func TestAddSplitPaymentUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AddSplitPaymentUseCaseTestSuite))
+}
+
+func splitCommand(regenerate bool, amounts ...string) *commands.AddSplitPaymentCommand {
+	types := []string{entities.PaymentTypeQRCode, entities.PaymentTypeCard}
+	legs := make([]commands.SplitPaymentLeg, len(amounts))
+	for i, amount := range amounts {
+		legs[i] = commands.SplitPaymentLeg{Type: types[i%len(types)], Amount: money.MustParse(amount)}
+	}
+	return commands.NewAddSplitPaymentCommand(1, money.MustParse("100.00"), legs, regenerate)
+}
+
+func splitLeg(id uint, leg int, amount string, status entities.PaymentStatus) *entities.Payment {
+	return &entities.Payment{
+		ID:      id,
+		OrderId: 1,
+		SplitId: "split",
+		Leg:     leg,
+		Total:   money.MustParse(amount),
+		Status:  status,
+		Active:  status != entities.PaymentStatusDeclined,
+		QRData:  "leg-qr",
+	}
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) expectLiveSplit(legs ...*entities.Payment) {
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(legs[0], nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		ListSplitLegs(uint(1)).
+		Return(legs, nil).
+		Once()
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_ShouldChargeEachLegThroughItsGateway() {
+	// GIVEN an order of 100.00 without payments
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(nil, nil).Once()
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+
+	suite.mockQRGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.ExternalReference == "order-1-leg-1" &&
+				request.Total.Amount == money.MustParse("60.00") &&
+				len(request.Items) == 1 && request.Items[0].TotalAmount == money.MustParse("60.00")
+		})).
+		Return(&gateways.Charge{QRData: "leg-1-qr"}, nil).
+		Once()
+	suite.mockCardGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.ExternalReference == "order-1-leg-2" && request.Total.Amount == money.MustParse("40.00")
+		})).
+		Return(&gateways.Charge{ProviderPaymentId: "pi_1", ClientSecret: "pi_1_secret"}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		AddSplitPayment(
+			mock.MatchedBy(func(replaced []*entities.Payment) bool { return len(replaced) == 0 }),
+			mock.Anything,
+			mock.MatchedBy(func(legs []*entities.Payment) bool {
+				return len(legs) == 2 && legs[0].SplitId != "" && legs[0].SplitId == legs[1].SplitId &&
+					legs[0].Leg == 1 && legs[0].Provider == entities.PaymentProviderMercadoPago &&
+					legs[1].Leg == 2 && legs[1].Provider == entities.PaymentProviderStripe
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN splitting it into a QR code and a card leg
+	split, err := suite.useCase.Execute(splitCommand(false, "60.00", "40.00"))
+
+	// THEN both legs should be issued with their payment codes
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), split.Legs, 2)
+	assert.Equal(suite.T(), "leg-1-qr", split.Legs[0].PaymentCode())
+	assert.Equal(suite.T(), "pi_1_secret", split.Legs[1].PaymentCode())
+	assert.Equal(suite.T(), money.MustParse("100.00"), split.Total())
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithLegsNotAddingUp_ShouldRejectWithoutCharging() {
+	// GIVEN an order of 100.00
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(nil, nil).Once()
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+
+	// WHEN the legs only add up to 90.00
+	_, err := suite.useCase.Execute(splitCommand(false, "60.00", "30.00"))
+
+	// THEN the split should be rejected as an amount mismatch
+	assert.ErrorIs(suite.T(), err, entities.ErrAmountMismatch)
+	var mismatch *entities.SplitAmountMismatchError
+	assert.True(suite.T(), errors.As(err, &mismatch))
+	suite.mockQRGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithInvalidLegs_ShouldReject() {
+	for _, command := range []*commands.AddSplitPaymentCommand{
+		splitCommand(false, "100.00"),
+		splitCommand(false, "100.00", "0.00"),
+	} {
+		// WHEN splitting into a single leg or a leg without amount
+		_, err := suite.useCase.Execute(command)
+
+		// THEN it should be rejected before looking the order up
+		assert.ErrorIs(suite.T(), err, entities.ErrInvalidSplitPayment)
+	}
+	suite.mockRepository.AssertNotCalled(suite.T(), "FindActivePaymentByOrderId", mock.Anything)
+}
+
+// singleSlotGateway is a gateway holding one charge at a time, like the Mercado Pago point of sale.
+type singleSlotGateway struct {
+	*mockGateways.MockPaymentGateway
+}
+
+func (singleSlotGateway) SingleSlot() bool {
+	return true
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithTwoLegsOnSingleSlotGateway_ShouldReject() {
+	// GIVEN a QR code gateway showing one QR code at a time
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig(
+		[]gateways.PaymentGatewayRegistration{
+			{PaymentType: entities.PaymentTypeQRCode, Gateway: singleSlotGateway{suite.mockQRGateway}},
+			{PaymentType: entities.PaymentTypeCard, Gateway: suite.mockCardGateway},
+		},
+		&gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode},
+	)
+	suite.Require().NoError(err)
+	useCase, err := addpayment.NewAddSplitPaymentUseCaseImplWithConfig(registry, suite.mockOrderClient, suite.mockRepository, &addpayment.AddPaymentConfig{})
+	suite.Require().NoError(err)
+
+	// WHEN splitting the order into two QR code legs and a card leg
+	_, err = useCase.Execute(splitCommand(false, "40.00", "30.00", "30.00"))
+
+	// THEN it should be rejected, since the second QR code would replace the first
+	assert.ErrorIs(suite.T(), err, entities.ErrInvalidSplitPayment)
+	suite.mockQRGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithLiveSplit_ShouldReturnExistingLegs() {
+	// GIVEN an order split into a paid and a pending leg
+	suite.expectLiveSplit(
+		splitLeg(1, 1, "60.00", entities.PaymentStatusApproved),
+		splitLeg(2, 2, "40.00", entities.PaymentStatusPending),
+	)
+
+	// WHEN asking for the same split again
+	split, err := suite.useCase.Execute(splitCommand(false, "60.00", "40.00"))
+
+	// THEN the existing legs should be handed out
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(2), split.Legs[1].ID)
+	suite.mockOrderClient.AssertNotCalled(suite.T(), "GetOrder", mock.Anything)
+	suite.mockCardGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithDeclinedLeg_ShouldOnlyChargeThatLegAgain() {
+	// GIVEN an order split into a paid and a declined leg
+	suite.expectLiveSplit(
+		splitLeg(1, 1, "60.00", entities.PaymentStatusApproved),
+		splitLeg(2, 2, "40.00", entities.PaymentStatusDeclined),
+	)
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+	suite.mockCardGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.MatchedBy(func(request gateways.ChargeRequest) bool {
+			return request.ExternalReference == "order-1-leg-2"
+		})).
+		Return(&gateways.Charge{ClientSecret: "new-secret"}, nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		AddSplitPayment(
+			mock.MatchedBy(func(replaced []*entities.Payment) bool { return len(replaced) == 0 }),
+			mock.Anything,
+			mock.MatchedBy(func(legs []*entities.Payment) bool {
+				return len(legs) == 1 && legs[0].Leg == 2 && legs[0].SplitId == "split"
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN asking for the same split again
+	split, err := suite.useCase.Execute(splitCommand(false, "60.00", "40.00"))
+
+	// THEN only the declined leg should be charged again
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), split.Legs[0].ID)
+	assert.Equal(suite.T(), "new-secret", split.Legs[1].PaymentCode())
+	suite.mockQRGateway.AssertNotCalled(suite.T(), "CreateCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithRegenerate_ShouldReplacePendingLegs() {
+	// GIVEN an order split into a paid and a pending leg
+	suite.expectLiveSplit(
+		splitLeg(1, 1, "60.00", entities.PaymentStatusApproved),
+		splitLeg(2, 2, "40.00", entities.PaymentStatusPending),
+	)
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+	suite.mockCardGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Return(&gateways.Charge{ClientSecret: "new-secret"}, nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		AddSplitPayment(
+			mock.MatchedBy(func(replaced []*entities.Payment) bool {
+				return len(replaced) == 1 && replaced[0].ID == 2 && replaced[0].Status == entities.PaymentStatusCancelled
+			}),
+			mock.MatchedBy(func(changes []*entities.PaymentStatusChange) bool {
+				return len(changes) == 1 && changes[0].PaymentId == 2
+			}),
+			mock.MatchedBy(func(legs []*entities.Payment) bool { return len(legs) == 1 && legs[0].Leg == 2 })).
+		Return(nil).
+		Once()
+
+	// WHEN regenerating the split
+	_, err := suite.useCase.Execute(splitCommand(true, "60.00", "40.00"))
+
+	// THEN the pending leg should be replaced and the paid one kept
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithLiveSplitOfOtherAmounts_ShouldReturnConflict() {
+	// GIVEN an order split 60/40
+	suite.expectLiveSplit(
+		splitLeg(1, 1, "60.00", entities.PaymentStatusApproved),
+		splitLeg(2, 2, "40.00", entities.PaymentStatusPending),
+	)
+
+	// WHEN asking for a 50/50 split
+	_, err := suite.useCase.Execute(splitCommand(true, "50.00", "50.00"))
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithPendingSinglePayment_ShouldReplaceItOnRegenerate() {
+	pending := &entities.Payment{ID: 7, OrderId: 1, Status: entities.PaymentStatusPending, Active: true, QRData: "old"}
+
+	// GIVEN an order with a pending single payment
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(pending, nil).Once()
+
+	// WHEN splitting it without regenerate
+	_, err := suite.useCase.Execute(splitCommand(false, "60.00", "40.00"))
+
+	// THEN it should be rejected
+	assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+
+	// WHEN splitting it with regenerate
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(pending, nil).Once()
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+	suite.mockQRGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(&gateways.Charge{QRData: "qr"}, nil).Once()
+	suite.mockCardGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(&gateways.Charge{ClientSecret: "secret"}, nil).Once()
+	suite.mockRepository.EXPECT().
+		AddSplitPayment(
+			mock.MatchedBy(func(replaced []*entities.Payment) bool {
+				return len(replaced) == 1 && replaced[0].ID == 7 && !replaced[0].Active
+			}),
+			mock.Anything,
+			mock.MatchedBy(func(legs []*entities.Payment) bool { return len(legs) == 2 })).
+		Return(nil).
+		Once()
+
+	split, err := suite.useCase.Execute(splitCommand(true, "60.00", "40.00"))
+
+	// THEN the single payment should be replaced by the legs
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), split.Legs, 2)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithGatewayError_ShouldCancelIssuedLegs() {
+	// GIVEN a card provider that fails
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(nil, nil).Once()
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+	suite.mockQRGateway.EXPECT().
+		CreateCharge(mock.Anything, mock.Anything).
+		Return(&gateways.Charge{ProviderOrderId: "mp-1", QRData: "qr"}, nil).
+		Once()
+	providerErr := errors.New("card provider unavailable")
+	suite.mockCardGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(nil, providerErr).Once()
+
+	suite.mockRepository.EXPECT().
+		AddPayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Status == entities.PaymentStatusFailed && p.Leg == 2 && p.SplitId != ""
+		})).
+		Return(&entities.Payment{}, nil).
+		Once()
+	suite.mockQRGateway.EXPECT().
+		CancelCharge(mock.Anything, mock.MatchedBy(func(p *entities.Payment) bool { return p.ProviderOrderId == "mp-1" })).
+		Return(nil).
+		Once()
+
+	// WHEN splitting the order
+	_, err := suite.useCase.Execute(splitCommand(false, "60.00", "40.00"))
+
+	// THEN the QR code leg should be cancelled and no leg stored
+	assert.ErrorIs(suite.T(), err, providerErr)
+	suite.mockRepository.AssertNotCalled(suite.T(), "AddSplitPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithConcurrentPayment_ShouldCancelLegsAndReturnConflict() {
+	// GIVEN a payment of the whole order stored by a concurrent request once both legs were charged
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(nil, nil).Once()
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Once()
+	suite.mockQRGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(&gateways.Charge{ProviderOrderId: "mp-1"}, nil).Once()
+	suite.mockCardGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(&gateways.Charge{ProviderPaymentId: "pi_1"}, nil).Once()
+	suite.mockRepository.EXPECT().
+		AddSplitPayment(mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("duplicate key value violates unique constraint")).
+		Once()
+	suite.mockRepository.EXPECT().
+		FindActivePaymentByOrderId(uint(1)).
+		Return(&entities.Payment{ID: 9, OrderId: 1, Status: entities.PaymentStatusPending, Active: true}, nil).
+		Once()
+	suite.mockQRGateway.EXPECT().CancelCharge(mock.Anything, mock.Anything).Return(nil).Once()
+	suite.mockCardGateway.EXPECT().CancelCharge(mock.Anything, mock.Anything).Return(nil).Once()
+
+	// WHEN splitting the order
+	_, err := suite.useCase.Execute(splitCommand(false, "60.00", "40.00"))
+
+	// THEN the charged legs should be cancelled and a conflict returned
+	assert.ErrorIs(suite.T(), err, entities.ErrActivePaymentExists)
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_ShouldChargeEachLegAsSingleLine() {
+	// GIVEN an order with several products
+	order := &dto.OrderResponseDto{
+		ID:          1,
+		TotalAmount: money.MustParse("100.00"),
+		Products: []*dto.OrderProductDto{
+			{ProductId: 1, Name: "Lanche", Price: money.MustParse("70.00"), Quantity: 1},
+			{ProductId: 2, Name: "Bebida", Price: money.MustParse("15.00"), Quantity: 2},
+		},
+	}
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(nil, nil).Once()
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(order, nil).Once()
+
+	var legItems [][]dto.Item
+	for _, gateway := range []*mockGateways.MockPaymentGateway{suite.mockQRGateway, suite.mockCardGateway} {
+		gateway.EXPECT().
+			CreateCharge(mock.Anything, mock.Anything).
+			Run(func(_ context.Context, request gateways.ChargeRequest) { legItems = append(legItems, request.Items) }).
+			Return(&gateways.Charge{}, nil).
+			Once()
+	}
+	suite.mockRepository.EXPECT().AddSplitPayment(mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	// WHEN splitting it unevenly
+	_, err := suite.useCase.Execute(splitCommand(false, "33.33", "66.67"))
+
+	// THEN each leg should be charged as a single line of the leg amount
+	assert.NoError(suite.T(), err)
+	for i, expected := range []string{"33.33", "66.67"} {
+		suite.Require().Len(legItems[i], 1)
+		item := legItems[i][0]
+		assert.Equal(suite.T(), money.MustParse(expected), item.TotalAmount)
+		assert.Equal(suite.T(), item.UnitPrice.Mul(int64(item.Quantity)), item.TotalAmount)
+	}
+}
+
+func (suite *AddSplitPaymentUseCaseTestSuite) Test_AddSplitPayment_WithTip_ShouldShareItAcrossLegs() {
+	// GIVEN an order of 100.00 and a 10.00 tip
+	suite.mockRepository.EXPECT().FindActivePaymentByOrderId(uint(1)).Return(nil, nil).Times(2)
+	suite.mockOrderClient.EXPECT().GetOrder(uint(1)).Return(newOrder("100.00"), nil).Times(2)
+
+	suite.mockQRGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(&gateways.Charge{}, nil).Once()
+	suite.mockCardGateway.EXPECT().CreateCharge(mock.Anything, mock.Anything).Return(&gateways.Charge{}, nil).Once()
+
+	// THEN each leg should carry its share of the tip
+	suite.mockRepository.EXPECT().
+		AddSplitPayment(mock.Anything, mock.Anything, mock.MatchedBy(func(legs []*entities.Payment) bool {
+			return len(legs) == 2 &&
+				legs[0].TipAmount == money.MustParse("6.00") &&
+				legs[1].TipAmount == money.MustParse("4.00")
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN splitting it into legs that add up to the order total and the tip
+	command := splitCommand(false, "66.00", "44.00")
+	command.Tip = commands.Tip{Amount: money.MustParse("10.00")}
+	_, err := suite.useCase.Execute(command)
+	assert.NoError(suite.T(), err)
+
+	// AND legs adding up to the order total alone should be rejected
+	_, err = suite.useCase.Execute(func() *commands.AddSplitPaymentCommand {
+		command := splitCommand(false, "60.00", "40.00")
+		command.Tip = commands.Tip{Amount: money.MustParse("10.00")}
+		return command
+	}())
+	assert.ErrorIs(suite.T(), err, entities.ErrAmountMismatch)
+}
@@ -0,0 +1,10 @@
+package cancelpayment
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type CancelPaymentUseCase interface {
+	Execute(command *commands.CancelPaymentCommand) (*entities.Payment, error)
+}
@@ -0,0 +1,64 @@
+package cancelpayment
+
+import (
+	"context"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ CancelPaymentUseCase = (*CancelPaymentUseCaseImpl)(nil)
+)
+
+type CancelPaymentUseCaseImpl struct {
+	gatewayRegistry   *gateways.PaymentGatewayRegistry
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewCancelPaymentUseCaseImpl(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	paymentRepository repositories.PaymentRepository) *CancelPaymentUseCaseImpl {
+	return &CancelPaymentUseCaseImpl{
+		gatewayRegistry:   gatewayRegistry,
+		paymentRepository: paymentRepository,
+	}
+}
+
+func (u *CancelPaymentUseCaseImpl) Execute(command *commands.CancelPaymentCommand) (*entities.Payment, error) {
+	payment, err := u.paymentRepository.GetPaymentByOrderLeg(command.OrderId, command.Leg)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status == entities.PaymentStatusCancelled {
+		// Cancelling twice is harmless
+		return payment, nil
+	}
+
+	previousStatus := payment.Status
+	if err := payment.TransitionTo(entities.PaymentStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	change := entities.NewPaymentStatusChange(payment, previousStatus, entities.PaymentStatusChangeSourceAPI, "")
+	err = u.paymentRepository.CancelPayment(payment, change, func() error {
+		// If this fails the payment stays pending, since it can still be paid
+		gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider)
+		if !ok {
+			return nil
+		}
+		if err := gateway.CancelCharge(context.Background(), payment); err != nil {
+			println("ERROR: Failed to cancel payment on", gateway.Name()+":", err.Error())
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,239 @@
+package cancelpayment_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	cancelpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/cancelPayment"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type CancelPaymentUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockPaymentRepository
+	mockGateway    *mockGateways.MockPaymentGateway
+	useCase        cancelpayment.CancelPaymentUseCase
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockGateway.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
+
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig(
+		[]gateways.PaymentGatewayRegistration{{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockGateway}},
+		&gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode},
+	)
+	suite.Require().NoError(err)
+	suite.useCase = cancelpayment.NewCancelPaymentUseCaseImpl(registry, suite.mockRepository)
+}
+
+func TestCancelPaymentUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CancelPaymentUseCaseTestSuite))
+}
+
+// runCancelCharge stands for a repository that cancels the payment on the provider under its lock.
+func runCancelCharge(_ *entities.Payment, _ *entities.PaymentStatusChange, cancelCharge func() error) error {
+	return cancelCharge()
+}
+
+func newPendingPayment() *entities.Payment {
+	return &entities.Payment{
+		ID:       1,
+		OrderId:  1,
+		Status:   entities.PaymentStatusPending,
+		Active:   true,
+		Provider: entities.PaymentProviderMercadoPago,
+		QRData:   "qr-data",
+	}
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithPendingPayment_ShouldClearPOSAndCancel() {
+	// GIVEN a pending payment whose QR code is on the point of sale
+	payment := newPendingPayment()
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(payment, nil).
+		Once()
+
+	suite.mockGateway.EXPECT().
+		CancelCharge(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		CancelPayment(
+			payment,
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.FromStatus == entities.PaymentStatusPending &&
+					change.ToStatus == entities.PaymentStatusCancelled &&
+					change.Source == entities.PaymentStatusChangeSourceAPI
+			}),
+			mock.Anything).
+		RunAndReturn(runCancelCharge).
+		Once()
+
+	// WHEN cancelling
+	result, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN the payment should be cancelled and no longer active
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.PaymentStatusCancelled, result.Status)
+	assert.False(suite.T(), result.Active)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithGatewayError_ShouldKeepPaymentPending() {
+	// GIVEN a point of sale that cannot be cleared
+	expectedError := errors.New("mercado pago unavailable")
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(newPendingPayment(), nil).
+		Once()
+
+	suite.mockGateway.EXPECT().
+		CancelCharge(mock.Anything, mock.Anything).
+		Return(expectedError).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		CancelPayment(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(runCancelCharge).
+		Once()
+
+	// WHEN cancelling
+	result, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN the error should be returned and nothing stored
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithPaymentApprovedMeanwhile_ShouldReturnConflict() {
+	// GIVEN a pending payment that a webhook approves before the cancellation locks it
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(newPendingPayment(), nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		CancelPayment(mock.Anything, mock.Anything, mock.Anything).
+		Return(&entities.InvalidStatusTransitionError{From: entities.PaymentStatusApproved, To: entities.PaymentStatusCancelled}).
+		Once()
+
+	// WHEN cancelling
+	_, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN an invalid transition error should be returned without cancelling on the provider
+	assert.ErrorIs(suite.T(), err, entities.ErrInvalidStatusTransition)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CancelCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithApprovedPayment_ShouldReturnConflict() {
+	// GIVEN an approved payment
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(&entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusApproved, Active: true}, nil).
+		Once()
+
+	// WHEN cancelling
+	_, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN an invalid transition error should be returned without touching the point of sale
+	assert.ErrorIs(suite.T(), err, entities.ErrInvalidStatusTransition)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CancelCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithCancelledPayment_ShouldBeNoOp() {
+	// GIVEN an already cancelled payment
+	payment := &entities.Payment{ID: 1, OrderId: 1, Status: entities.PaymentStatusCancelled}
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(payment, nil).
+		Once()
+
+	// WHEN cancelling again
+	result, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN it should be returned unchanged
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), payment, result)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CancelCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithUnknownOrder_ShouldReturnNotFound() {
+	// GIVEN an order without payments
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(nil, gorm.ErrRecordNotFound).
+		Once()
+
+	// WHEN cancelling
+	_, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN a not found error should be returned
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a database that cannot store the cancellation
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(newPendingPayment(), nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		CancelPayment(mock.Anything, mock.Anything, mock.Anything).
+		Return(expectedError).
+		Once()
+
+	// WHEN cancelling
+	_, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithSplitPaymentWithoutLeg_ShouldRequireLeg() {
+	// GIVEN an order paid in split legs
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(nil, fmt.Errorf("%w: order 1", entities.ErrPaymentLegRequired)).
+		Once()
+
+	// WHEN cancelling without a leg
+	_, err := suite.useCase.Execute(commands.NewCancelPaymentCommand(1))
+
+	// THEN the leg should be asked for
+	assert.ErrorIs(suite.T(), err, entities.ErrPaymentLegRequired)
+	suite.mockGateway.AssertNotCalled(suite.T(), "CancelCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *CancelPaymentUseCaseTestSuite) Test_CancelPayment_WithUnknownLeg_ShouldReturnNotFound() {
+	// GIVEN an order split into two legs
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 3).
+		Return(nil, fmt.Errorf("%w: order 1 has no leg 3", entities.ErrPaymentNotFound)).
+		Once()
+
+	// WHEN cancelling a third leg
+	command := commands.NewCancelPaymentCommand(1)
+	command.Leg = 3
+	_, err := suite.useCase.Execute(command)
+
+	// THEN a not found error should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrPaymentNotFound)
+}
@@ -0,0 +1,10 @@
+package capturepayment
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type CapturePaymentUseCase interface {
+	Execute(command *commands.CapturePaymentCommand) (*entities.Payment, error)
+}
@@ -0,0 +1,80 @@
+package capturepayment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	updatePaymentUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/updatePayment"
+)
+
+var (
+	_ CapturePaymentUseCase = (*CapturePaymentUseCaseImpl)(nil)
+)
+
+type CapturePaymentUseCaseImpl struct {
+	gatewayRegistry      *gateways.PaymentGatewayRegistry
+	paymentRepository    repositories.PaymentRepository
+	updatePaymentUseCase updatePaymentUseCase.UpdatePaymentUseCase
+}
+
+func NewCapturePaymentUseCaseImpl(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	paymentRepository repositories.PaymentRepository,
+	updatePaymentUseCase updatePaymentUseCase.UpdatePaymentUseCase) *CapturePaymentUseCaseImpl {
+	return &CapturePaymentUseCaseImpl{
+		gatewayRegistry:      gatewayRegistry,
+		paymentRepository:    paymentRepository,
+		updatePaymentUseCase: updatePaymentUseCase,
+	}
+}
+
+func (u *CapturePaymentUseCaseImpl) Execute(command *commands.CapturePaymentCommand) (*entities.Payment, error) {
+	payment, err := u.paymentRepository.GetPaymentByOrderLeg(command.OrderId, command.Leg)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != entities.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("%w: payment %d is %s", entities.ErrCaptureNotAllowed, payment.ID, payment.Status)
+	}
+	gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: payment %d has no gateway for provider %q", entities.ErrCaptureNotAllowed, payment.ID, payment.Provider)
+	}
+	capturable, ok := gateway.(gateways.CapturablePaymentGateway)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not capture payments", entities.ErrCaptureNotAllowed, gateway.Name())
+	}
+
+	amount := command.Amount
+	if amount == 0 {
+		amount = payment.Total
+	}
+	if amount <= 0 || amount > payment.Total {
+		return nil, &entities.CaptureExceedsAuthorizedError{Requested: amount, Authorized: payment.Total}
+	}
+
+	if err := capturable.CaptureCharge(context.Background(), payment, amount); err != nil {
+		println("ERROR: Failed to capture payment on", gateway.Name()+":", err.Error())
+		return nil, err
+	}
+
+	// Approving the payment enqueues the order status update, so the order only moves to "Preparing"
+	// once the money is collected
+	updatePayment := commands.NewUpdatePaymentStatusCommand(
+		payment.OrderId,
+		entities.PaymentStatusApproved,
+		entities.PaymentStatusChangeSourceAPI,
+		"")
+	updatePayment.PaymentId = payment.ID
+	updatePayment.CapturedAmount = amount
+	if err := u.updatePaymentUseCase.Execute(updatePayment); err != nil {
+		return nil, err
+	}
+
+	return u.paymentRepository.GetPaymentById(payment.ID)
+}
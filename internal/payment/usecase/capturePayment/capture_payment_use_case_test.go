@@ -0,0 +1,216 @@
+package capturepayment_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	capturepayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/capturePayment"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	mockUpdatePayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/updatePayment"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// cardGateway is a gateway that captures its payments, like the Stripe one.
+type cardGateway struct {
+	*mockGateways.MockPaymentGateway
+	*mockGateways.MockCapturablePaymentGateway
+}
+
+type CapturePaymentUseCaseTestSuite struct {
+	suite.Suite
+	mockPaymentRepository    *mockRepositories.MockPaymentRepository
+	mockUpdatePaymentUseCase *mockUpdatePayment.MockUpdatePaymentUseCase
+	mockCaptureGateway       *mockGateways.MockCapturablePaymentGateway
+	useCase                  capturepayment.CapturePaymentUseCase
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) SetupTest() {
+	suite.mockPaymentRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockUpdatePaymentUseCase = mockUpdatePayment.NewMockUpdatePaymentUseCase(suite.T())
+	suite.mockCaptureGateway = mockGateways.NewMockCapturablePaymentGateway(suite.T())
+
+	card := cardGateway{mockGateways.NewMockPaymentGateway(suite.T()), suite.mockCaptureGateway}
+	card.MockPaymentGateway.EXPECT().Name().Return(entities.PaymentProviderStripe).Maybe()
+	qrCode := mockGateways.NewMockPaymentGateway(suite.T())
+	qrCode.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
+
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: qrCode},
+		{PaymentType: entities.PaymentTypeCard, Gateway: card},
+	}, &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode})
+	suite.Require().NoError(err)
+	suite.useCase = capturepayment.NewCapturePaymentUseCaseImpl(registry, suite.mockPaymentRepository, suite.mockUpdatePaymentUseCase)
+}
+
+func TestCapturePaymentUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CapturePaymentUseCaseTestSuite))
+}
+
+func newAuthorizedPayment() *entities.Payment {
+	return &entities.Payment{
+		ID:                1,
+		OrderId:           1,
+		Total:             money.MustParse("50.00"),
+		Currency:          money.BRL,
+		Type:              entities.PaymentTypeCard,
+		Status:            entities.PaymentStatusAuthorized,
+		Provider:          entities.PaymentProviderStripe,
+		ProviderPaymentId: "pi_1",
+	}
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) expectPayment(payment *entities.Payment) {
+	suite.mockPaymentRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(payment, nil).
+		Once()
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_WithoutAmount_ShouldCaptureEverythingAndApprove() {
+	// GIVEN an authorized card payment
+	payment := newAuthorizedPayment()
+	suite.expectPayment(payment)
+
+	suite.mockCaptureGateway.EXPECT().
+		CaptureCharge(mock.Anything, payment, money.MustParse("50.00")).
+		Return(nil).
+		Once()
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.UpdatePaymentStatusCommand) bool {
+			return command.PaymentId == 1 &&
+				command.Status == entities.PaymentStatusApproved &&
+				command.Source == entities.PaymentStatusChangeSourceAPI &&
+				command.CapturedAmount == money.MustParse("50.00")
+		})).
+		Return(nil).
+		Once()
+
+	captured := newAuthorizedPayment()
+	captured.Status = entities.PaymentStatusApproved
+	captured.CapturedTotal = money.MustParse("50.00")
+	suite.mockPaymentRepository.EXPECT().GetPaymentById(uint(1)).Return(captured, nil).Once()
+
+	// WHEN capturing it
+	result, err := suite.useCase.Execute(commands.NewCapturePaymentCommand(1, 0))
+
+	// THEN the whole authorization should be captured and the payment approved
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, result.Status)
+	assert.Equal(suite.T(), money.MustParse("50.00"), result.CapturedAmount())
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_WithPartialAmount_ShouldRecordCapturedAmount() {
+	// GIVEN an authorized card payment
+	payment := newAuthorizedPayment()
+	suite.expectPayment(payment)
+
+	suite.mockCaptureGateway.EXPECT().
+		CaptureCharge(mock.Anything, payment, money.MustParse("42.00")).
+		Return(nil).
+		Once()
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.UpdatePaymentStatusCommand) bool {
+			return command.CapturedAmount == money.MustParse("42.00")
+		})).
+		Return(nil).
+		Once()
+	suite.mockPaymentRepository.EXPECT().GetPaymentById(uint(1)).Return(payment, nil).Once()
+
+	// WHEN capturing part of it
+	_, err := suite.useCase.Execute(commands.NewCapturePaymentCommand(1, money.MustParse("42.00")))
+
+	// THEN only that part should be captured
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_ExceedingAuthorization_ShouldReturnError() {
+	// GIVEN an authorized card payment
+	suite.expectPayment(newAuthorizedPayment())
+
+	// WHEN capturing more than was authorized
+	_, err := suite.useCase.Execute(commands.NewCapturePaymentCommand(1, money.MustParse("50.01")))
+
+	// THEN error should be returned without calling the provider
+	assert.ErrorIs(suite.T(), err, entities.ErrCaptureExceedsAuthorized)
+	suite.mockCaptureGateway.AssertNotCalled(suite.T(), "CaptureCharge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_WithPendingPayment_ShouldReturnError() {
+	// GIVEN a card payment that was not authorized yet
+	payment := newAuthorizedPayment()
+	payment.Status = entities.PaymentStatusPending
+	suite.expectPayment(payment)
+
+	// WHEN capturing it
+	_, err := suite.useCase.Execute(commands.NewCapturePaymentCommand(1, 0))
+
+	// THEN error should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrCaptureNotAllowed)
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_WithGatewayWithoutCapture_ShouldReturnError() {
+	// GIVEN an authorized payment on a provider that does not capture
+	payment := newAuthorizedPayment()
+	payment.Provider = entities.PaymentProviderMercadoPago
+	suite.expectPayment(payment)
+
+	// WHEN capturing it
+	_, err := suite.useCase.Execute(commands.NewCapturePaymentCommand(1, 0))
+
+	// THEN error should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrCaptureNotAllowed)
+	assert.ErrorContains(suite.T(), err, entities.PaymentProviderMercadoPago)
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_WithGatewayError_ShouldNotApprove() {
+	// GIVEN an authorized payment the provider fails to capture
+	payment := newAuthorizedPayment()
+	suite.expectPayment(payment)
+	expectedError := errors.New("unexpected status: 400")
+	suite.mockCaptureGateway.EXPECT().
+		CaptureCharge(mock.Anything, payment, money.MustParse("50.00")).
+		Return(expectedError).
+		Once()
+
+	// WHEN capturing it
+	_, err := suite.useCase.Execute(commands.NewCapturePaymentCommand(1, 0))
+
+	// THEN error should be returned and the payment left authorized
+	assert.ErrorIs(suite.T(), err, expectedError)
+	suite.mockUpdatePaymentUseCase.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func (suite *CapturePaymentUseCaseTestSuite) Test_CapturePayment_WithLeg_ShouldCaptureThatLeg() {
+	// GIVEN an order split into a paid QR code leg and an authorized card leg
+	cardLeg := newAuthorizedPayment()
+	cardLeg.ID, cardLeg.SplitId, cardLeg.Leg = 2, "split", 2
+	suite.mockPaymentRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 2).
+		Return(cardLeg, nil).
+		Once()
+
+	suite.mockCaptureGateway.EXPECT().
+		CaptureCharge(mock.Anything, cardLeg, money.MustParse("50.00")).
+		Return(nil).
+		Once()
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(command *commands.UpdatePaymentStatusCommand) bool { return command.PaymentId == 2 })).
+		Return(nil).
+		Once()
+	suite.mockPaymentRepository.EXPECT().GetPaymentById(uint(2)).Return(cardLeg, nil).Once()
+
+	// WHEN capturing the card leg
+	command := commands.NewCapturePaymentCommand(1, 0)
+	command.Leg = 2
+	_, err := suite.useCase.Execute(command)
+
+	// THEN only that leg should be captured
+	assert.NoError(suite.T(), err)
+}
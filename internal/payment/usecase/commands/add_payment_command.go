@@ -1,15 +1,29 @@
 package commands
 
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
 type AddPaymentCommand struct {
 	OrderId uint
-	Total   float32
+	Total   money.Amount
 	Type    string
+	// Regenerate cancels the pending payment of the order, if any, and issues a new QR code.
+	Regenerate bool
+	// PaymentMethod is a tokenized card that confirms a card payment on creation.
+	PaymentMethod string
+	Tip           Tip
+}
+
+// Tip is an optional tip charged on top of the order: a fixed Amount, or a Percentage of the order total.
+type Tip struct {
+	Amount     money.Amount
+	Percentage money.Amount
 }
 
-func NewAddPaymentCommand(orderId uint, total float32, type_ string) *AddPaymentCommand {
+func NewAddPaymentCommand(orderId uint, total money.Amount, type_ string, regenerate bool) *AddPaymentCommand {
 	return &AddPaymentCommand{
-		OrderId: orderId,
-		Total:   total,
-		Type:    type_,
+		OrderId:    orderId,
+		Total:      total,
+		Type:       type_,
+		Regenerate: regenerate,
 	}
 }
@@ -0,0 +1,30 @@
+package commands
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+// SplitPaymentLeg is one of the payments an order is split into.
+type SplitPaymentLeg struct {
+	Type   string
+	Amount money.Amount
+	// PaymentMethod is a tokenized card that confirms a card leg on creation.
+	PaymentMethod string
+}
+
+type AddSplitPaymentCommand struct {
+	OrderId uint
+	Total   money.Amount
+	Legs    []SplitPaymentLeg
+	// Regenerate also issues the legs that are still pending again, instead of only the ones that
+	// failed or expired.
+	Regenerate bool
+	Tip        Tip
+}
+
+func NewAddSplitPaymentCommand(orderId uint, total money.Amount, legs []SplitPaymentLeg, regenerate bool) *AddSplitPaymentCommand {
+	return &AddSplitPaymentCommand{
+		OrderId:    orderId,
+		Total:      total,
+		Legs:       legs,
+		Regenerate: regenerate,
+	}
+}
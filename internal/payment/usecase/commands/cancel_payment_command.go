@@ -0,0 +1,13 @@
+package commands
+
+type CancelPaymentCommand struct {
+	OrderId uint
+	// Leg selects the leg of an order paid in split legs.
+	Leg int
+}
+
+func NewCancelPaymentCommand(orderId uint) *CancelPaymentCommand {
+	return &CancelPaymentCommand{
+		OrderId: orderId,
+	}
+}
@@ -0,0 +1,18 @@
+package commands
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type CapturePaymentCommand struct {
+	OrderId uint
+	// Leg selects the leg of an order paid in split legs.
+	Leg int
+	// Amount to collect; zero captures the whole authorization.
+	Amount money.Amount
+}
+
+func NewCapturePaymentCommand(orderId uint, amount money.Amount) *CapturePaymentCommand {
+	return &CapturePaymentCommand{
+		OrderId: orderId,
+		Amount:  amount,
+	}
+}
@@ -3,24 +3,27 @@ package commands_test
 import (
 	"testing"
 
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewAddPaymentCommand(t *testing.T) {
 	// GIVEN payment data
 	orderId := uint(1)
-	total := float32(100.50)
+	total := money.MustParse("100.50")
 	paymentType := "QRCode"
 
 	// WHEN creating command
-	cmd := commands.NewAddPaymentCommand(orderId, total, paymentType)
+	cmd := commands.NewAddPaymentCommand(orderId, total, paymentType, true)
 
 	// THEN command should be created correctly
 	assert.NotNil(t, cmd)
 	assert.Equal(t, orderId, cmd.OrderId)
 	assert.Equal(t, total, cmd.Total)
 	assert.Equal(t, paymentType, cmd.Type)
+	assert.True(t, cmd.Regenerate)
 }
 
 func TestNewGetPaymentCommand(t *testing.T) {
@@ -50,29 +53,34 @@ func TestNewGetPaymentStatusCommand(t *testing.T) {
 func TestNewUpdatePaymentStatusCommand(t *testing.T) {
 	// GIVEN order ID and status
 	orderId := uint(1)
-	status := "Approved"
+	status := entities.PaymentStatusApproved
 
 	// WHEN creating command
-	cmd := commands.NewUpdatePaymentStatusCommand(orderId, status)
+	cmd := commands.NewUpdatePaymentStatusCommand(orderId, status, entities.PaymentStatusChangeSourceWebhook, "987")
 
 	// THEN command should be created correctly
 	assert.NotNil(t, cmd)
 	assert.Equal(t, orderId, cmd.OrderId)
 	assert.Equal(t, status, cmd.Status)
+	assert.Equal(t, entities.PaymentStatusChangeSourceWebhook, cmd.Source)
+	assert.Equal(t, "987", cmd.NotificationId)
 }
 
 func TestHandleWebhookCommand(t *testing.T) {
 	// GIVEN webhook data
 	id := "123"
-	status := "Approved"
+	topic := "payment"
+	resource := "https://api.mercadopago.com/v1/payments/123"
 
 	// WHEN creating command
 	cmd := commands.HandleWebhookCommand{
-		Id:     id,
-		Status: status,
+		Id:       id,
+		Topic:    topic,
+		Resource: resource,
 	}
 
 	// THEN command should have correct values
 	assert.Equal(t, id, cmd.Id)
-	assert.Equal(t, status, cmd.Status)
+	assert.Equal(t, topic, cmd.Topic)
+	assert.Equal(t, resource, cmd.Resource)
 }
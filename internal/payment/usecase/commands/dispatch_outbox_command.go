@@ -0,0 +1,20 @@
+package commands
+
+import "time"
+
+// DispatchOutboxCommand carries the delivery policy of one outbox dispatch round.
+type DispatchOutboxCommand struct {
+	BatchSize   int
+	MaxAttempts uint
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func NewDispatchOutboxCommand(batchSize int, maxAttempts uint, baseBackoff, maxBackoff time.Duration) *DispatchOutboxCommand {
+	return &DispatchOutboxCommand{
+		BatchSize:   batchSize,
+		MaxAttempts: maxAttempts,
+		BaseBackoff: baseBackoff,
+		MaxBackoff:  maxBackoff,
+	}
+}
@@ -0,0 +1,12 @@
+package commands
+
+// ExpirePaymentsCommand carries the size of one expiry sweep.
+type ExpirePaymentsCommand struct {
+	BatchSize int
+}
+
+func NewExpirePaymentsCommand(batchSize int) *ExpirePaymentsCommand {
+	return &ExpirePaymentsCommand{
+		BatchSize: batchSize,
+	}
+}
@@ -0,0 +1,11 @@
+package commands
+
+type GetPaymentHistoryCommand struct {
+	OrderId uint
+}
+
+func NewGetPaymentHistoryCommand(orderId uint) *GetPaymentHistoryCommand {
+	return &GetPaymentHistoryCommand{
+		OrderId: orderId,
+	}
+}
@@ -0,0 +1,11 @@
+package commands
+
+type GetSplitPaymentCommand struct {
+	OrderId uint
+}
+
+func NewGetSplitPaymentCommand(orderId uint) *GetSplitPaymentCommand {
+	return &GetSplitPaymentCommand{
+		OrderId: orderId,
+	}
+}
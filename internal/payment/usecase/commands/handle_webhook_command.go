@@ -1,6 +1,15 @@
 package commands
 
+import "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+
 type HandleWebhookCommand struct {
-	Id     string
-	Status string
+	// Provider sent the notification; empty for Mercado Pago, whose notifications predate the others.
+	Provider string
+	Id       string
+	Topic    string
+	Resource string
+}
+
+func (c HandleWebhookCommand) IsFromMercadoPago() bool {
+	return c.Provider == "" || c.Provider == entities.PaymentProviderMercadoPago
 }
@@ -0,0 +1,15 @@
+package commands
+
+type ListDisputesCommand struct {
+	OrderId uint
+	Status  string
+	Limit   int
+}
+
+func NewListDisputesCommand(orderId uint, status string, limit int) *ListDisputesCommand {
+	return &ListDisputesCommand{
+		OrderId: orderId,
+		Status:  status,
+		Limit:   limit,
+	}
+}
@@ -0,0 +1,15 @@
+package commands
+
+type ListOutboxMessagesCommand struct {
+	OrderId uint
+	Status  string
+	Limit   int
+}
+
+func NewListOutboxMessagesCommand(orderId uint, status string, limit int) *ListOutboxMessagesCommand {
+	return &ListOutboxMessagesCommand{
+		OrderId: orderId,
+		Status:  status,
+		Limit:   limit,
+	}
+}
@@ -0,0 +1,11 @@
+package commands
+
+type ListPaymentAttemptsCommand struct {
+	OrderId uint
+}
+
+func NewListPaymentAttemptsCommand(orderId uint) *ListPaymentAttemptsCommand {
+	return &ListPaymentAttemptsCommand{
+		OrderId: orderId,
+	}
+}
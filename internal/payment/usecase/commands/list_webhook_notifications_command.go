@@ -0,0 +1,19 @@
+package commands
+
+type ListWebhookNotificationsCommand struct {
+	ProviderId string
+	Topic      string
+	Resource   string
+	Outcome    string
+	Limit      int
+}
+
+func NewListWebhookNotificationsCommand(providerId, topic, resource, outcome string, limit int) *ListWebhookNotificationsCommand {
+	return &ListWebhookNotificationsCommand{
+		ProviderId: providerId,
+		Topic:      topic,
+		Resource:   resource,
+		Outcome:    outcome,
+		Limit:      limit,
+	}
+}
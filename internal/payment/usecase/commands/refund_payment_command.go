@@ -0,0 +1,20 @@
+package commands
+
+import "github.com/abattassini/tc-fiap-payment/pkg/money"
+
+type RefundPaymentCommand struct {
+	OrderId uint
+	// Leg selects the leg of an order paid in split legs.
+	Leg int
+	// Amount to give back; zero refunds everything that was not refunded yet.
+	Amount money.Amount
+	Reason string
+}
+
+func NewRefundPaymentCommand(orderId uint, amount money.Amount, reason string) *RefundPaymentCommand {
+	return &RefundPaymentCommand{
+		OrderId: orderId,
+		Amount:  amount,
+		Reason:  reason,
+	}
+}
@@ -0,0 +1,11 @@
+package commands
+
+type RetryOutboxMessageCommand struct {
+	Id uint
+}
+
+func NewRetryOutboxMessageCommand(id uint) *RetryOutboxMessageCommand {
+	return &RetryOutboxMessageCommand{
+		Id: id,
+	}
+}
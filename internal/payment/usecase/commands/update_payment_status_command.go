@@ -1,13 +1,34 @@
 package commands
 
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
 type UpdatePaymentStatusCommand struct {
 	OrderId uint
-	Status  string
+	// PaymentId selects a specific payment attempt; when zero the effective payment of the order is updated.
+	PaymentId uint
+	// Leg selects the leg of an order paid in split legs, when no PaymentId is given.
+	Leg            int
+	Status         entities.PaymentStatus
+	Source         entities.PaymentStatusChangeSource
+	NotificationId string
+	// ProviderPaymentId is the provider's id of the payment, when the update comes from the provider.
+	ProviderPaymentId string
+	// CapturedAmount is the part of an authorization that was captured, when known.
+	CapturedAmount money.Amount
 }
 
-func NewUpdatePaymentStatusCommand(orderId uint, status string) *UpdatePaymentStatusCommand {
+func NewUpdatePaymentStatusCommand(
+	orderId uint,
+	status entities.PaymentStatus,
+	source entities.PaymentStatusChangeSource,
+	notificationId string) *UpdatePaymentStatusCommand {
 	return &UpdatePaymentStatusCommand{
-		OrderId: orderId,
-		Status:  status,
+		OrderId:        orderId,
+		Status:         status,
+		Source:         source,
+		NotificationId: notificationId,
 	}
 }
@@ -0,0 +1,16 @@
+package commands
+
+import "time"
+
+// VoidAuthorizationsCommand carries the age and size of one sweep of uncaptured authorizations.
+type VoidAuthorizationsCommand struct {
+	AuthorizedBefore time.Time
+	BatchSize        int
+}
+
+func NewVoidAuthorizationsCommand(authorizedBefore time.Time, batchSize int) *VoidAuthorizationsCommand {
+	return &VoidAuthorizationsCommand{
+		AuthorizedBefore: authorizedBefore,
+		BatchSize:        batchSize,
+	}
+}
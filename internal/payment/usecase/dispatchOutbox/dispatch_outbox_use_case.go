@@ -0,0 +1,14 @@
+package dispatchoutbox
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+
+// DispatchResult counts what happened to the messages handled in one dispatch round.
+type DispatchResult struct {
+	Delivered   int
+	Rescheduled int
+	Failed      int
+}
+
+type DispatchOutboxUseCase interface {
+	Execute(command *commands.DispatchOutboxCommand) (DispatchResult, error)
+}
@@ -0,0 +1,75 @@
+package dispatchoutbox
+
+import (
+	"errors"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/clients"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ DispatchOutboxUseCase = (*DispatchOutboxUseCaseImpl)(nil)
+)
+
+type DispatchOutboxUseCaseImpl struct {
+	outboxRepository repositories.OutboxRepository
+	orderClient      clients.OrderClient
+}
+
+func NewDispatchOutboxUseCaseImpl(
+	outboxRepository repositories.OutboxRepository,
+	orderClient clients.OrderClient) *DispatchOutboxUseCaseImpl {
+	return &DispatchOutboxUseCaseImpl{
+		outboxRepository: outboxRepository,
+		orderClient:      orderClient,
+	}
+}
+
+func (u *DispatchOutboxUseCaseImpl) Execute(command *commands.DispatchOutboxCommand) (DispatchResult, error) {
+	var result DispatchResult
+
+	messages, err := u.outboxRepository.ClaimDueOutboxMessages(time.Now(), command.BatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	var errs []error
+	for _, message := range messages {
+		if err := u.orderClient.UpdateOrderStatus(message.OrderId, message.OrderStatus); err != nil {
+			println("ERROR: Failed to update order status in Order Service:", err.Error())
+			message.MarkAttemptFailed(err, time.Now().Add(backoff(message.Attempts, command.BaseBackoff, command.MaxBackoff)), command.MaxAttempts)
+		} else {
+			message.MarkDelivered()
+		}
+
+		// A message whose state could not be saved is delivered again once its claim lapses, which the
+		// Order Service status update tolerates.
+		if err := u.outboxRepository.UpdateOutboxMessage(message); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		switch message.Status {
+		case entities.OutboxStatusDelivered:
+			result.Delivered++
+		case entities.OutboxStatusFailed:
+			result.Failed++
+		default:
+			result.Rescheduled++
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// backoff doubles the base delay for every previous attempt, capped at maxBackoff.
+func backoff(attempts uint, baseBackoff, maxBackoff time.Duration) time.Duration {
+	delay := baseBackoff
+	for i := uint(0); i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
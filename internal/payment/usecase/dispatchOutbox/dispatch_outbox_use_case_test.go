@@ -0,0 +1,176 @@
+package dispatchoutbox_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	dispatchoutbox "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/dispatchOutbox"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockClients "github.com/abattassini/tc-fiap-payment/mocks/payment/infrastructure/clients"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DispatchOutboxUseCaseTestSuite struct {
+	suite.Suite
+	mockOutboxRepository *mockRepositories.MockOutboxRepository
+	mockOrderClient      *mockClients.MockOrderClient
+	useCase              dispatchoutbox.DispatchOutboxUseCase
+	command              *commands.DispatchOutboxCommand
+}
+
+func (suite *DispatchOutboxUseCaseTestSuite) SetupTest() {
+	suite.mockOutboxRepository = mockRepositories.NewMockOutboxRepository(suite.T())
+	suite.mockOrderClient = mockClients.NewMockOrderClient(suite.T())
+	suite.useCase = dispatchoutbox.NewDispatchOutboxUseCaseImpl(suite.mockOutboxRepository, suite.mockOrderClient)
+	suite.command = commands.NewDispatchOutboxCommand(10, 3, time.Second, time.Minute)
+}
+
+func TestDispatchOutboxUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(DispatchOutboxUseCaseTestSuite))
+}
+
+func (suite *DispatchOutboxUseCaseTestSuite) Test_Dispatch_WithReachableOrderService_ShouldMarkDelivered() {
+	// GIVEN a due order status update
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+
+	suite.mockOutboxRepository.EXPECT().
+		ClaimDueOutboxMessages(mock.Anything, 10).
+		Return([]*entities.OutboxMessage{message}, nil).
+		Once()
+
+	suite.mockOrderClient.EXPECT().
+		UpdateOrderStatus(uint(1), entities.OrderStatusPreparing).
+		Return(nil).
+		Once()
+
+	suite.mockOutboxRepository.EXPECT().
+		UpdateOutboxMessage(message).
+		Return(nil).
+		Once()
+
+	// WHEN dispatching
+	result, err := suite.useCase.Execute(suite.command)
+
+	// THEN the message should be delivered
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), dispatchoutbox.DispatchResult{Delivered: 1}, result)
+	assert.Equal(suite.T(), entities.OutboxStatusDelivered, message.Status)
+}
+
+func (suite *DispatchOutboxUseCaseTestSuite) Test_Dispatch_WithOrderServiceDown_ShouldRescheduleWithBackoff() {
+	// GIVEN a message that already failed twice
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.Attempts = 1
+
+	suite.mockOutboxRepository.EXPECT().
+		ClaimDueOutboxMessages(mock.Anything, 10).
+		Return([]*entities.OutboxMessage{message}, nil).
+		Once()
+
+	suite.mockOrderClient.EXPECT().
+		UpdateOrderStatus(uint(1), entities.OrderStatusPreparing).
+		Return(errors.New("order service unavailable")).
+		Once()
+
+	suite.mockOutboxRepository.EXPECT().
+		UpdateOutboxMessage(message).
+		Return(nil).
+		Once()
+
+	before := time.Now()
+
+	// WHEN dispatching
+	result, err := suite.useCase.Execute(suite.command)
+
+	// THEN the message should be rescheduled with a doubled delay
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), dispatchoutbox.DispatchResult{Rescheduled: 1}, result)
+	assert.Equal(suite.T(), entities.OutboxStatusPending, message.Status)
+	assert.Equal(suite.T(), uint(2), message.Attempts)
+	assert.Equal(suite.T(), "order service unavailable", message.LastError)
+	assert.WithinDuration(suite.T(), before.Add(2*time.Second), message.NextAttemptAt, time.Second)
+}
+
+func (suite *DispatchOutboxUseCaseTestSuite) Test_Dispatch_WithExhaustedAttempts_ShouldMarkFailed() {
+	// GIVEN a message on its last attempt
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.Attempts = 2
+
+	suite.mockOutboxRepository.EXPECT().
+		ClaimDueOutboxMessages(mock.Anything, 10).
+		Return([]*entities.OutboxMessage{message}, nil).
+		Once()
+
+	suite.mockOrderClient.EXPECT().
+		UpdateOrderStatus(uint(1), entities.OrderStatusPreparing).
+		Return(errors.New("order service unavailable")).
+		Once()
+
+	suite.mockOutboxRepository.EXPECT().
+		UpdateOutboxMessage(message).
+		Return(nil).
+		Once()
+
+	// WHEN dispatching
+	result, err := suite.useCase.Execute(suite.command)
+
+	// THEN the message should be left failed for a manual retry
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), dispatchoutbox.DispatchResult{Failed: 1}, result)
+	assert.Equal(suite.T(), entities.OutboxStatusFailed, message.Status)
+}
+
+func (suite *DispatchOutboxUseCaseTestSuite) Test_Dispatch_WithUpdateError_ShouldContinueAndReturnError() {
+	// GIVEN two due messages, the first of which cannot be saved
+	first := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	second := entities.NewOrderStatusOutboxMessage(2, entities.OrderStatusPreparing)
+	expectedError := errors.New("database error")
+
+	suite.mockOutboxRepository.EXPECT().
+		ClaimDueOutboxMessages(mock.Anything, 10).
+		Return([]*entities.OutboxMessage{first, second}, nil).
+		Once()
+
+	suite.mockOrderClient.EXPECT().
+		UpdateOrderStatus(mock.Anything, entities.OrderStatusPreparing).
+		Return(nil).
+		Twice()
+
+	suite.mockOutboxRepository.EXPECT().
+		UpdateOutboxMessage(first).
+		Return(expectedError).
+		Once()
+
+	suite.mockOutboxRepository.EXPECT().
+		UpdateOutboxMessage(second).
+		Return(nil).
+		Once()
+
+	// WHEN dispatching
+	result, err := suite.useCase.Execute(suite.command)
+
+	// THEN the second message should still be delivered and the error reported
+	assert.ErrorIs(suite.T(), err, expectedError)
+	assert.Equal(suite.T(), dispatchoutbox.DispatchResult{Delivered: 1}, result)
+}
+
+func (suite *DispatchOutboxUseCaseTestSuite) Test_Dispatch_WithRepositoryError_ShouldReturnError() {
+	// GIVEN an outbox that cannot be read
+	expectedError := errors.New("database error")
+
+	suite.mockOutboxRepository.EXPECT().
+		ClaimDueOutboxMessages(mock.Anything, 10).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN dispatching
+	_, err := suite.useCase.Execute(suite.command)
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+}
@@ -0,0 +1,8 @@
+package expirepayments
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+
+type ExpirePaymentsUseCase interface {
+	// Execute expires overdue pending payments and returns how many were expired.
+	Execute(command *commands.ExpirePaymentsCommand) (int, error)
+}
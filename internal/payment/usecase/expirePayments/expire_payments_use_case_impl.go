@@ -0,0 +1,70 @@
+package expirepayments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ ExpirePaymentsUseCase = (*ExpirePaymentsUseCaseImpl)(nil)
+)
+
+type ExpirePaymentsUseCaseImpl struct {
+	gatewayRegistry   *gateways.PaymentGatewayRegistry
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewExpirePaymentsUseCaseImpl(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	paymentRepository repositories.PaymentRepository) *ExpirePaymentsUseCaseImpl {
+	return &ExpirePaymentsUseCaseImpl{
+		gatewayRegistry:   gatewayRegistry,
+		paymentRepository: paymentRepository,
+	}
+}
+
+func (u *ExpirePaymentsUseCaseImpl) Execute(command *commands.ExpirePaymentsCommand) (int, error) {
+	payments, err := u.paymentRepository.ClaimExpiredPayments(time.Now(), command.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, payment := range payments {
+		if err := u.expire(payment); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+func (u *ExpirePaymentsUseCaseImpl) expire(payment *entities.Payment) error {
+	previousStatus := payment.Status
+	if err := payment.TransitionTo(entities.PaymentStatusExpired); err != nil {
+		return err
+	}
+
+	// Cancel the charge on the provider first, so that it can no longer be paid once the order is
+	// cancelled: if this fails the payment stays pending and the next sweep tries again
+	if gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider); ok {
+		if err := gateway.CancelCharge(context.Background(), payment); err != nil {
+			println("ERROR: Failed to cancel expired payment on", gateway.Name()+":", err.Error())
+			return err
+		}
+	}
+
+	// The order was not paid in time; the outbox dispatcher tells the Order Service
+	change := entities.NewPaymentStatusChange(payment, previousStatus, entities.PaymentStatusChangeSourceReconciliation, "")
+	message := entities.NewOrderStatusOutboxMessage(payment.OrderId, entities.OrderStatusCancelled)
+	return u.paymentRepository.UpdatePaymentStatus(payment, change, message)
+}
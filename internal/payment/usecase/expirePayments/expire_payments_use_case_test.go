@@ -0,0 +1,139 @@
+package expirepayments_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	expirepayments "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/expirePayments"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExpirePaymentsUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockPaymentRepository
+	mockGateway    *mockGateways.MockPaymentGateway
+	useCase        expirepayments.ExpirePaymentsUseCase
+}
+
+func (suite *ExpirePaymentsUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockGateway.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
+
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockGateway},
+	}, &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode})
+	suite.Require().NoError(err)
+	suite.useCase = expirepayments.NewExpirePaymentsUseCaseImpl(registry, suite.mockRepository)
+}
+
+func TestExpirePaymentsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ExpirePaymentsUseCaseTestSuite))
+}
+
+func (suite *ExpirePaymentsUseCaseTestSuite) Test_ExpirePayments_WithOverduePayment_ShouldExpireAndNotifyOrderService() {
+	// GIVEN an overdue pending payment
+	payment := &entities.Payment{ID: 1, OrderId: 10, Status: entities.PaymentStatusPending, Active: true, Provider: entities.PaymentProviderMercadoPago}
+
+	suite.mockRepository.EXPECT().
+		ClaimExpiredPayments(mock.Anything, 50).
+		Return([]*entities.Payment{payment}, nil).
+		Once()
+	suite.mockGateway.EXPECT().CancelCharge(mock.Anything, payment).Return(nil).Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(
+			payment,
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.FromStatus == entities.PaymentStatusPending &&
+					change.ToStatus == entities.PaymentStatusExpired &&
+					change.Source == entities.PaymentStatusChangeSourceReconciliation
+			}),
+			mock.MatchedBy(func(message *entities.OutboxMessage) bool {
+				return message.OrderId == 10 && message.OrderStatus == entities.OrderStatusCancelled
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN sweeping
+	expired, err := suite.useCase.Execute(commands.NewExpirePaymentsCommand(50))
+
+	// THEN the charge should be cancelled and the payment expired and no longer active
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, expired)
+	assert.Equal(suite.T(), entities.PaymentStatusExpired, payment.Status)
+	assert.False(suite.T(), payment.Active)
+}
+
+func (suite *ExpirePaymentsUseCaseTestSuite) Test_ExpirePayments_WithUpdateError_ShouldContinueAndReturnError() {
+	// GIVEN two overdue payments, the first of which cannot be saved
+	first := &entities.Payment{ID: 1, OrderId: 10, Status: entities.PaymentStatusPending, Active: true}
+	second := &entities.Payment{ID: 2, OrderId: 11, Status: entities.PaymentStatusPending, Active: true}
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		ClaimExpiredPayments(mock.Anything, 50).
+		Return([]*entities.Payment{first, second}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(first, mock.Anything, mock.Anything).
+		Return(expectedError).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(second, mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN sweeping
+	expired, err := suite.useCase.Execute(commands.NewExpirePaymentsCommand(50))
+
+	// THEN the second payment should still be expired
+	assert.ErrorIs(suite.T(), err, expectedError)
+	assert.Equal(suite.T(), 1, expired)
+}
+
+func (suite *ExpirePaymentsUseCaseTestSuite) Test_ExpirePayments_WithCancelError_ShouldKeepPaymentPending() {
+	// GIVEN an overdue payment whose charge cannot be cancelled on the provider
+	payment := &entities.Payment{ID: 1, OrderId: 10, Status: entities.PaymentStatusPending, Active: true, Provider: entities.PaymentProviderMercadoPago}
+	expectedError := errors.New("provider unavailable")
+
+	suite.mockRepository.EXPECT().
+		ClaimExpiredPayments(mock.Anything, 50).
+		Return([]*entities.Payment{payment}, nil).
+		Once()
+	suite.mockGateway.EXPECT().CancelCharge(mock.Anything, payment).Return(expectedError).Once()
+
+	// WHEN sweeping
+	expired, err := suite.useCase.Execute(commands.NewExpirePaymentsCommand(50))
+
+	// THEN the payment should not be expired nor the order cancelled, so that the next sweep retries
+	assert.ErrorIs(suite.T(), err, expectedError)
+	assert.Zero(suite.T(), expired)
+	suite.mockRepository.AssertNotCalled(suite.T(), "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ExpirePaymentsUseCaseTestSuite) Test_ExpirePayments_WithListError_ShouldReturnError() {
+	// GIVEN a database that cannot be queried
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		ClaimExpiredPayments(mock.Anything, 50).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN sweeping
+	expired, err := suite.useCase.Execute(commands.NewExpirePaymentsCommand(50))
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Zero(suite.T(), expired)
+}
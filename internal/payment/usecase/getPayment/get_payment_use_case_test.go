@@ -9,6 +9,7 @@ import (
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	getpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPayment"
 	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -37,7 +38,7 @@ func (suite *GetPaymentUseCaseTestSuite) Test_GetPayment_WithExistingPayment_Sho
 		ID:        1,
 		CreatedAt: time.Now(),
 		OrderId:   orderId,
-		Total:     100.50,
+		Total:     money.MustParse("100.50"),
 		Type:      "credit_card",
 		Status:    "pending",
 	}
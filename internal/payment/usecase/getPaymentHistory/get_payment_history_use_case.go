@@ -0,0 +1,10 @@
+package getpaymenthistory
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type GetPaymentHistoryUseCase interface {
+	Execute(command *commands.GetPaymentHistoryCommand) ([]*entities.PaymentStatusChange, error)
+}
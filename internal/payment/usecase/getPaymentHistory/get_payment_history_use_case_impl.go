@@ -0,0 +1,23 @@
+package getpaymenthistory
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ GetPaymentHistoryUseCase = (*GetPaymentHistoryUseCaseImpl)(nil)
+)
+
+type GetPaymentHistoryUseCaseImpl struct {
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewGetPaymentHistoryUseCaseImpl(paymentRepository repositories.PaymentRepository) *GetPaymentHistoryUseCaseImpl {
+	return &GetPaymentHistoryUseCaseImpl{paymentRepository: paymentRepository}
+}
+
+func (u *GetPaymentHistoryUseCaseImpl) Execute(command *commands.GetPaymentHistoryCommand) ([]*entities.PaymentStatusChange, error) {
+	return u.paymentRepository.ListPaymentStatusHistory(command.OrderId)
+}
@@ -0,0 +1,72 @@
+package getpaymenthistory_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	getpaymenthistory "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getPaymentHistory"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetPaymentHistoryUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockPaymentRepository
+	useCase        getpaymenthistory.GetPaymentHistoryUseCase
+}
+
+func (suite *GetPaymentHistoryUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.useCase = getpaymenthistory.NewGetPaymentHistoryUseCaseImpl(suite.mockRepository)
+}
+
+func TestGetPaymentHistoryUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(GetPaymentHistoryUseCaseTestSuite))
+}
+
+func (suite *GetPaymentHistoryUseCaseTestSuite) Test_GetPaymentHistory_ShouldReturnStatusChanges() {
+	// GIVEN an order whose payment was approved by a webhook
+	expected := []*entities.PaymentStatusChange{
+		{
+			ID:             1,
+			PaymentId:      1,
+			OrderId:        1,
+			FromStatus:     entities.PaymentStatusPending,
+			ToStatus:       entities.PaymentStatusApproved,
+			Source:         entities.PaymentStatusChangeSourceWebhook,
+			NotificationId: "987",
+		},
+	}
+
+	suite.mockRepository.EXPECT().
+		ListPaymentStatusHistory(uint(1)).
+		Return(expected, nil).
+		Once()
+
+	// WHEN getting the history
+	result, err := suite.useCase.Execute(commands.NewGetPaymentHistoryCommand(1))
+
+	// THEN the status changes should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *GetPaymentHistoryUseCaseTestSuite) Test_GetPaymentHistory_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a failing repository
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		ListPaymentStatusHistory(uint(1)).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN getting the history
+	result, err := suite.useCase.Execute(commands.NewGetPaymentHistoryCommand(1))
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
@@ -1,6 +1,7 @@
 package getpaymentstatus
 
 import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 )
@@ -22,6 +23,14 @@ func (u *GetPaymentStatusUseCaseImpl) Execute(command *commands.GetPaymentStatus
 	if err != nil {
 		return "", err
 	}
+	if !payment.IsSplitLeg() {
+		return string(payment.Status), nil
+	}
 
-	return payment.Status, nil
+	// An order paid in split legs has the status of its legs together
+	attempts, err := u.paymentRepository.ListSplitLegs(command.OrderId)
+	if err != nil {
+		return "", err
+	}
+	return string(entities.NewSplitPayment(attempts).Status()), nil
 }
@@ -73,3 +73,19 @@ func (suite *GetPaymentStatusUseCaseTestSuite) Test_GetPaymentStatus_WithNonExis
 	assert.Equal(suite.T(), expectedError, err)
 	suite.mockRepository.AssertExpectations(suite.T())
 }
+
+func (suite *GetPaymentStatusUseCaseTestSuite) Test_GetPaymentStatus_WithSplitPayment_ShouldReturnStatusOfAllLegs() {
+	// GIVEN an order split into a paid and a pending leg
+	paid := &entities.Payment{ID: 1, OrderId: 1, SplitId: "split", Leg: 1, Status: entities.PaymentStatusApproved, Active: true}
+	pending := &entities.Payment{ID: 2, OrderId: 1, SplitId: "split", Leg: 2, Status: entities.PaymentStatusPending, Active: true}
+
+	suite.mockRepository.EXPECT().GetPaymentByOrderId(uint(1)).Return(paid, nil).Once()
+	suite.mockRepository.EXPECT().ListSplitLegs(uint(1)).Return([]*entities.Payment{paid, pending}, nil).Once()
+
+	// WHEN the payment status is requested
+	status, err := suite.useCase.Execute(commands.NewGetPaymentStatusCommand(1))
+
+	// THEN the order should still be waiting for its second leg
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), string(entities.PaymentStatusPending), status)
+}
@@ -0,0 +1,10 @@
+package getsplitpayment
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type GetSplitPaymentUseCase interface {
+	Execute(command *commands.GetSplitPaymentCommand) (*entities.SplitPayment, error)
+}
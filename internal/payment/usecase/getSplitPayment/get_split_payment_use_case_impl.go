@@ -0,0 +1,33 @@
+package getsplitpayment
+
+import (
+	"fmt"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ GetSplitPaymentUseCase = (*GetSplitPaymentUseCaseImpl)(nil)
+)
+
+type GetSplitPaymentUseCaseImpl struct {
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewGetSplitPaymentUseCaseImpl(paymentRepository repositories.PaymentRepository) *GetSplitPaymentUseCaseImpl {
+	return &GetSplitPaymentUseCaseImpl{paymentRepository: paymentRepository}
+}
+
+// Execute returns the latest split payment of the order, with the current attempt of each leg.
+func (u *GetSplitPaymentUseCaseImpl) Execute(command *commands.GetSplitPaymentCommand) (*entities.SplitPayment, error) {
+	attempts, err := u.paymentRepository.ListSplitLegs(command.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if len(attempts) == 0 {
+		return nil, fmt.Errorf("%w: order %d was not split", entities.ErrPaymentNotFound, command.OrderId)
+	}
+	return entities.NewSplitPayment(attempts), nil
+}
@@ -0,0 +1,60 @@
+package getsplitpayment_test
+
+import (
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	getsplitpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/getSplitPayment"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetSplitPaymentUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockPaymentRepository
+	useCase        getsplitpayment.GetSplitPaymentUseCase
+}
+
+func (suite *GetSplitPaymentUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.useCase = getsplitpayment.NewGetSplitPaymentUseCaseImpl(suite.mockRepository)
+}
+
+func TestGetSplitPaymentUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(GetSplitPaymentUseCaseTestSuite))
+}
+
+func (suite *GetSplitPaymentUseCaseTestSuite) Test_GetSplitPayment_ShouldReturnCurrentAttemptOfEachLeg() {
+	// GIVEN an order split in two legs, the second one charged twice
+	suite.mockRepository.EXPECT().
+		ListSplitLegs(uint(1)).
+		Return([]*entities.Payment{
+			{ID: 1, OrderId: 1, SplitId: "split", Leg: 1, Total: money.MustParse("60.00"), Status: entities.PaymentStatusApproved, Active: true},
+			{ID: 2, OrderId: 1, SplitId: "split", Leg: 2, Total: money.MustParse("40.00"), Status: entities.PaymentStatusDeclined},
+			{ID: 3, OrderId: 1, SplitId: "split", Leg: 2, Total: money.MustParse("40.00"), Status: entities.PaymentStatusPending, Active: true},
+		}, nil).
+		Once()
+
+	// WHEN getting the split payment
+	split, err := suite.useCase.Execute(commands.NewGetSplitPaymentCommand(1))
+
+	// THEN it should hold the active attempt of each leg
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), split.Legs, 2)
+	assert.Equal(suite.T(), uint(3), split.Legs[1].ID)
+	assert.Equal(suite.T(), entities.PaymentStatusPending, split.Status())
+}
+
+func (suite *GetSplitPaymentUseCaseTestSuite) Test_GetSplitPayment_WithOrderNotSplit_ShouldReturnNotFound() {
+	// GIVEN an order that was never split
+	suite.mockRepository.EXPECT().ListSplitLegs(uint(1)).Return(nil, nil).Once()
+
+	// WHEN getting the split payment
+	_, err := suite.useCase.Execute(commands.NewGetSplitPaymentCommand(1))
+
+	// THEN a not found error should be returned
+	assert.ErrorIs(suite.T(), err, entities.ErrPaymentNotFound)
+}
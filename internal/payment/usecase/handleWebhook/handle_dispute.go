@@ -0,0 +1,180 @@
+package handlewebhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+// providerDispute is the state of a chargeback or claim as reported by Mercado Pago.
+type providerDispute struct {
+	disputeType entities.DisputeType
+	id          string
+	paymentId   string
+	amount      money.Amount
+	reason      string
+	closed      bool
+	outcome     entities.DisputeOutcome
+}
+
+// disputeTypeFromTopic recognises chargeback and claim notifications, whose topics differ between
+// IPN ("chargebacks", "claim") and webhooks ("topic_chargebacks_wh", "topic_claims_integration_wh").
+func disputeTypeFromTopic(topic string) (entities.DisputeType, bool) {
+	switch topic {
+	case "chargebacks", "chargeback", "topic_chargebacks_wh":
+		return entities.DisputeTypeChargeback, true
+	case "claim", "claims", "topic_claims_integration_wh":
+		return entities.DisputeTypeClaim, true
+	default:
+		return "", false
+	}
+}
+
+// processDispute opens a dispute on the notified payment, moving it to disputed, and closes it with
+// its outcome once Mercado Pago resolves it. The order service is not notified either way.
+func (u *HandleWebhookUseCaseImpl) processDispute(command commands.HandleWebhookCommand, disputeType entities.DisputeType) (entities.WebhookOutcome, error) {
+	providerDispute, err := u.fetchProviderDispute(command, disputeType)
+	if err != nil {
+		return "", err
+	}
+	if providerDispute == nil {
+		// Claims about shipments or orders do not concern our payments
+		return entities.WebhookOutcomeIgnored, nil
+	}
+
+	payment, err := u.paymentRepository.FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, providerDispute.paymentId)
+	if err != nil {
+		return "", err
+	}
+	if payment == nil {
+		return "", fmt.Errorf("%w: provider payment %q", entities.ErrPaymentNotFound, providerDispute.paymentId)
+	}
+
+	dispute, err := u.disputeRepository.FindDispute(entities.PaymentProviderMercadoPago, disputeType, providerDispute.id)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	previousStatus := payment.Status
+	switch {
+	case dispute == nil:
+		amount := providerDispute.amount
+		if amount == 0 {
+			amount = payment.CapturedAmount()
+		}
+		dispute = entities.NewDispute(payment, disputeType, providerDispute.id, amount, providerDispute.reason, now)
+		if payment.Status == entities.PaymentStatusDisputed {
+			// Opened while another dispute is open, whose recorded status is the one to restore
+			open, err := u.disputeRepository.ListDisputes(repositories.DisputeFilter{
+				PaymentId: payment.ID,
+				Status:    entities.DisputeStatusOpen,
+				Limit:     1,
+			})
+			if err != nil {
+				return "", err
+			}
+			if len(open) > 0 {
+				dispute.PaymentStatus = open[0].PaymentStatus
+			}
+		}
+		if payment.Status.CanTransitionTo(entities.PaymentStatusDisputed) {
+			payment.TransitionTo(entities.PaymentStatusDisputed)
+		}
+	case !dispute.IsOpen():
+		// Nothing changed since the last notification
+		return entities.WebhookOutcomeIgnored, nil
+	case !providerDispute.closed:
+		// Still open, the resolution is yet to be notified
+		return entities.WebhookOutcomeOpen, nil
+	}
+
+	if providerDispute.closed {
+		dispute.Close(providerDispute.outcome, now)
+		if payment.Status == entities.PaymentStatusDisputed {
+			if err := payment.TransitionTo(dispute.ResolvedPaymentStatus()); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	outcome := entities.WebhookOutcomeProcessed
+	if dispute.IsOpen() {
+		outcome = entities.WebhookOutcomeOpen
+	}
+
+	if payment.Status == previousStatus {
+		return outcome, u.disputeRepository.SaveDispute(dispute, nil, nil)
+	}
+
+	change := entities.NewPaymentStatusChange(payment, previousStatus, entities.PaymentStatusChangeSourceWebhook, command.Id)
+	if err := u.disputeRepository.SaveDispute(dispute, payment, change); err != nil {
+		return "", err
+	}
+	return outcome, nil
+}
+
+// fetchProviderDispute asks Mercado Pago for the notified chargeback or claim. It returns nil for
+// claims that are not about a payment.
+func (u *HandleWebhookUseCaseImpl) fetchProviderDispute(command commands.HandleWebhookCommand, disputeType entities.DisputeType) (*providerDispute, error) {
+	resourceId := resourceIdFromCommand(command)
+	if resourceId == "" {
+		return nil, fmt.Errorf("webhook notification has no resource id")
+	}
+
+	if disputeType == entities.DisputeTypeChargeback {
+		chargeback, err := u.mercadoPagoGateway.GetChargeback(context.Background(), resourceId)
+		if err != nil {
+			return nil, err
+		}
+		if len(chargeback.Payments) == 0 {
+			return nil, fmt.Errorf("chargeback %s has no payment", chargeback.Id)
+		}
+
+		dispute := &providerDispute{
+			disputeType: disputeType,
+			id:          chargeback.Id,
+			paymentId:   strconv.FormatInt(chargeback.Payments[0], 10),
+			amount:      chargeback.Amount,
+			reason:      chargeback.ReasonCode,
+			closed:      chargeback.CoverageApplied != nil,
+		}
+		if dispute.closed {
+			dispute.outcome = entities.DisputeOutcomeLost
+			if *chargeback.CoverageApplied {
+				dispute.outcome = entities.DisputeOutcomeWon
+			}
+		}
+		return dispute, nil
+	}
+
+	claim, err := u.mercadoPagoGateway.GetClaim(context.Background(), resourceId)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Resource != "payment" {
+		return nil, nil
+	}
+
+	dispute := &providerDispute{
+		disputeType: disputeType,
+		id:          strconv.FormatInt(claim.Id, 10),
+		paymentId:   strconv.FormatInt(claim.ResourceId, 10),
+		reason:      claim.ReasonId,
+		closed:      claim.Status == "closed",
+	}
+	if dispute.closed {
+		dispute.outcome = entities.DisputeOutcomeWon
+		if claim.Resolution != nil && slices.Contains(claim.Resolution.Benefited, "complainant") {
+			dispute.outcome = entities.DisputeOutcomeLost
+		}
+	}
+	return dispute, nil
+}
@@ -0,0 +1,363 @@
+package handlewebhook_test
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPaidPayment() *entities.Payment {
+	return &entities.Payment{
+		ID:                7,
+		OrderId:           1,
+		Total:             money.MustParse("50.00"),
+		Currency:          money.BRL,
+		Status:            entities.PaymentStatusApproved,
+		Provider:          entities.PaymentProviderMercadoPago,
+		ProviderPaymentId: "987",
+	}
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) expectPaymentByProviderPaymentId(payment *entities.Payment) {
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, payment.ProviderPaymentId).
+		Return(payment, nil).
+		Once()
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithNewChargeback_ShouldOpenDisputeAndDisputePayment() {
+	// GIVEN a chargeback notification for a paid payment
+	command := commands.HandleWebhookCommand{
+		Id:       "55",
+		Topic:    "chargebacks",
+		Resource: "cb-1",
+	}
+	payment := newPaidPayment()
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeOpen)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetChargeback(mock.Anything, "cb-1").
+		Return(dto.MercadoPagoChargebackResponseDto{Id: "cb-1", Payments: []int64{987}, Amount: money.MustParse("50.00"), ReasonCode: "fraud"}, nil).
+		Once()
+
+	suite.expectPaymentByProviderPaymentId(payment)
+
+	suite.mockDisputeRepository.EXPECT().
+		FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeChargeback, "cb-1").
+		Return(nil, nil).
+		Once()
+
+	suite.mockDisputeRepository.EXPECT().
+		SaveDispute(
+			mock.MatchedBy(func(dispute *entities.Dispute) bool {
+				return dispute.IsOpen() &&
+					dispute.PaymentId == 7 &&
+					dispute.Amount == money.MustParse("50.00") &&
+					dispute.Reason == "fraud" &&
+					dispute.PaymentStatus == entities.PaymentStatusApproved
+			}),
+			payment,
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.FromStatus == entities.PaymentStatusApproved &&
+					change.ToStatus == entities.PaymentStatusDisputed &&
+					change.Source == entities.PaymentStatusChangeSourceWebhook &&
+					change.NotificationId == "55"
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the payment should be disputed without touching the order
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.PaymentStatusDisputed, payment.Status)
+	suite.mockUpdatePaymentUseCase.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithSecondDispute_ShouldCarryOverPreviousStatus() {
+	// GIVEN a claim opened on a payment already disputed by a chargeback opened while it was approved
+	command := commands.HandleWebhookCommand{
+		Id:       "60",
+		Topic:    "claim",
+		Resource: "42",
+	}
+	payment := newPaidPayment()
+	payment.Status = entities.PaymentStatusDisputed
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeOpen)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetClaim(mock.Anything, "42").
+		Return(dto.MercadoPagoClaimResponseDto{Id: 42, Resource: "payment", ResourceId: 987, Status: "opened"}, nil).
+		Once()
+
+	suite.expectPaymentByProviderPaymentId(payment)
+
+	suite.mockDisputeRepository.EXPECT().
+		FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeClaim, "42").
+		Return(nil, nil).
+		Once()
+
+	suite.mockDisputeRepository.EXPECT().
+		ListDisputes(repositories.DisputeFilter{PaymentId: 7, Status: entities.DisputeStatusOpen, Limit: 1}).
+		Return([]*entities.Dispute{{ID: 3, PaymentId: 7, Status: entities.DisputeStatusOpen, PaymentStatus: entities.PaymentStatusApproved}}, nil).
+		Once()
+
+	suite.mockDisputeRepository.EXPECT().
+		SaveDispute(
+			mock.MatchedBy(func(dispute *entities.Dispute) bool {
+				return dispute.IsOpen() && dispute.PaymentStatus == entities.PaymentStatusApproved
+			}),
+			(*entities.Payment)(nil),
+			(*entities.PaymentStatusChange)(nil)).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the claim should restore the status the payment had before the chargeback
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.PaymentStatusDisputed, payment.Status)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithLostChargeback_ShouldCloseDisputeAndRefundPayment() {
+	// GIVEN a chargeback that Mercado Pago did not cover
+	command := commands.HandleWebhookCommand{
+		Id:       "56",
+		Topic:    "topic_chargebacks_wh",
+		Resource: "cb-1",
+	}
+	payment := newPaidPayment()
+	payment.Status = entities.PaymentStatusDisputed
+	dispute := &entities.Dispute{ID: 3, PaymentId: 7, Status: entities.DisputeStatusOpen, PaymentStatus: entities.PaymentStatusApproved}
+	coverageApplied := false
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetChargeback(mock.Anything, "cb-1").
+		Return(dto.MercadoPagoChargebackResponseDto{Id: "cb-1", Payments: []int64{987}, CoverageApplied: &coverageApplied}, nil).
+		Once()
+
+	suite.expectPaymentByProviderPaymentId(payment)
+
+	suite.mockDisputeRepository.EXPECT().
+		FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeChargeback, "cb-1").
+		Return(dispute, nil).
+		Once()
+
+	suite.mockDisputeRepository.EXPECT().
+		SaveDispute(dispute, payment, mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+			return change.FromStatus == entities.PaymentStatusDisputed && change.ToStatus == entities.PaymentStatusRefunded
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the dispute should be closed as lost and the payment refunded
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.DisputeStatusClosed, dispute.Status)
+	assert.Equal(suite.T(), entities.DisputeOutcomeLost, dispute.Outcome)
+	assert.NotNil(suite.T(), dispute.ClosedAt)
+	assert.Equal(suite.T(), entities.PaymentStatusRefunded, payment.Status)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithClaimWonBySeller_ShouldRestorePaymentStatus() {
+	// GIVEN a claim closed in favour of the seller on a partially refunded payment
+	command := commands.HandleWebhookCommand{
+		Id:       "57",
+		Topic:    "claim",
+		Resource: "https://api.mercadopago.com/post-purchase/v1/claims/42",
+	}
+	payment := newPaidPayment()
+	payment.Status = entities.PaymentStatusDisputed
+	dispute := &entities.Dispute{ID: 3, PaymentId: 7, Status: entities.DisputeStatusOpen, PaymentStatus: entities.PaymentStatusPartiallyRefunded}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetClaim(mock.Anything, "42").
+		Return(dto.MercadoPagoClaimResponseDto{
+			Id:         42,
+			Resource:   "payment",
+			ResourceId: 987,
+			Status:     "closed",
+			Resolution: &dto.MercadoPagoClaimResolutionDto{Benefited: []string{"respondent"}},
+		}, nil).
+		Once()
+
+	suite.expectPaymentByProviderPaymentId(payment)
+
+	suite.mockDisputeRepository.EXPECT().
+		FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeClaim, "42").
+		Return(dispute, nil).
+		Once()
+
+	suite.mockDisputeRepository.EXPECT().
+		SaveDispute(dispute, payment, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the dispute should be won and the payment back to its previous status
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.DisputeOutcomeWon, dispute.Outcome)
+	assert.Equal(suite.T(), entities.PaymentStatusPartiallyRefunded, payment.Status)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithOpenDisputeStillUnderAnalysis_ShouldKeepNotificationOpen() {
+	// GIVEN a repeated notification for a chargeback that is still open
+	command := commands.HandleWebhookCommand{
+		Id:       "58",
+		Topic:    "chargebacks",
+		Resource: "cb-1",
+	}
+	payment := newPaidPayment()
+	payment.Status = entities.PaymentStatusDisputed
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeOpen)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetChargeback(mock.Anything, "cb-1").
+		Return(dto.MercadoPagoChargebackResponseDto{Id: "cb-1", Payments: []int64{987}}, nil).
+		Once()
+
+	suite.expectPaymentByProviderPaymentId(payment)
+
+	suite.mockDisputeRepository.EXPECT().
+		FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeChargeback, "cb-1").
+		Return(&entities.Dispute{ID: 3, Status: entities.DisputeStatusOpen}, nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN nothing should be saved
+	assert.NoError(suite.T(), err)
+	suite.mockDisputeRepository.AssertNotCalled(suite.T(), "SaveDispute", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithClaimAboutShipment_ShouldIgnore() {
+	// GIVEN a claim that is not about a payment
+	command := commands.HandleWebhookCommand{
+		Id:       "59",
+		Topic:    "claim",
+		Resource: "43",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeIgnored)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetClaim(mock.Anything, "43").
+		Return(dto.MercadoPagoClaimResponseDto{Id: 43, Resource: "shipment", ResourceId: 1}, nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN no payment should be looked up
+	assert.NoError(suite.T(), err)
+	suite.mockPaymentRepository.AssertNotCalled(suite.T(), "FindPaymentByProviderPaymentId", mock.Anything, mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithPaymentUpdateForDisputedPayment_ShouldIgnore() {
+	// GIVEN a late payment notification for a payment under dispute
+	command := commands.HandleWebhookCommand{
+		Topic:    "payment",
+		Resource: "987",
+	}
+	payment := newPaidPayment()
+	payment.Status = entities.PaymentStatusDisputed
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeIgnored)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByProviderPaymentId(payment)
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the disputed status should be kept
+	assert.NoError(suite.T(), err)
+	suite.mockUpdatePaymentUseCase.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithChargebackOpenedThenClosed_ShouldCloseDispute() {
+	// GIVEN the notifications of a chargeback, which Mercado Pago sends with the same id, topic and resource
+	// when it is opened and when it is resolved
+	command := commands.HandleWebhookCommand{
+		Id:       "cb-1",
+		Topic:    "chargebacks",
+		Resource: "cb-1",
+	}
+	payment := newPaidPayment()
+	coverageApplied := true
+
+	notification := suite.expectNewNotification(command)
+	suite.mockInboxRepository.EXPECT().
+		FindWebhookNotification(command.Id, command.Topic, command.Resource).
+		Return(notification, nil).
+		Once()
+	suite.mockInboxRepository.EXPECT().UpdateWebhookNotification(notification).Return(nil).Times(2)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetChargeback(mock.Anything, "cb-1").
+		Return(dto.MercadoPagoChargebackResponseDto{Id: "cb-1", Payments: []int64{987}, Amount: money.MustParse("50.00")}, nil).
+		Once()
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetChargeback(mock.Anything, "cb-1").
+		Return(dto.MercadoPagoChargebackResponseDto{Id: "cb-1", Payments: []int64{987}, CoverageApplied: &coverageApplied}, nil).
+		Once()
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, "987").
+		Return(payment, nil).
+		Times(2)
+
+	var dispute *entities.Dispute
+	suite.mockDisputeRepository.EXPECT().
+		FindDispute(entities.PaymentProviderMercadoPago, entities.DisputeTypeChargeback, "cb-1").
+		RunAndReturn(func(string, entities.DisputeType, string) (*entities.Dispute, error) { return dispute, nil }).
+		Times(2)
+	suite.mockDisputeRepository.EXPECT().
+		SaveDispute(mock.Anything, payment, mock.Anything).
+		Run(func(saved *entities.Dispute, _ *entities.Payment, _ *entities.PaymentStatusChange) { dispute = saved }).
+		Return(nil).
+		Times(2)
+
+	// WHEN handling the opening and then the resolution
+	assert.NoError(suite.T(), suite.useCase.Execute(command))
+	assert.Equal(suite.T(), entities.PaymentStatusDisputed, payment.Status)
+	assert.Equal(suite.T(), entities.WebhookOutcomeOpen, notification.Outcome)
+	err := suite.useCase.Execute(command)
+
+	// THEN the dispute should be closed as won and the payment restored
+	assert.NoError(suite.T(), err)
+	suite.Require().NotNil(dispute)
+	assert.Equal(suite.T(), entities.DisputeStatusClosed, dispute.Status)
+	assert.Equal(suite.T(), entities.DisputeOutcomeWon, dispute.Outcome)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, payment.Status)
+	assert.Equal(suite.T(), uint(2), notification.Deliveries)
+	assert.Equal(suite.T(), entities.WebhookOutcomeProcessed, notification.Outcome)
+}
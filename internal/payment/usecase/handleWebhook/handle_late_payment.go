@@ -0,0 +1,85 @@
+package handlewebhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+)
+
+// giveBackLatePayment returns what the provider collected for a payment that was cancelled or expired
+// here first, e.g. a QR code paid while it was being cancelled. An authorization is voided; an approved
+// payment is refunded in full, with the refund recorded against it. The payment keeps its status, and a
+// failure fails the notification so that the redelivery of the provider tries again.
+func (u *HandleWebhookUseCaseImpl) giveBackLatePayment(payment *entities.Payment, providerPayment *providerPayment) (entities.WebhookOutcome, error) {
+	gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider)
+	if !ok {
+		return "", fmt.Errorf("%w: payment %d was paid after it was %s, and provider %q has no gateway to give it back",
+			entities.ErrRefundNotAllowed, payment.ID, payment.Status, payment.Provider)
+	}
+	println("WARN: Payment", payment.ID, "was paid on", gateway.Name(), "after it was", string(payment.Status)+", giving it back")
+
+	if payment.ProviderPaymentId == "" && providerPayment.paymentId != "" {
+		// The provider needs it to void or refund the payment
+		payment.ProviderPaymentId = providerPayment.paymentId
+		if err := u.paymentRepository.UpdatePayment(payment); err != nil {
+			return "", err
+		}
+	}
+
+	if providerPayment.status == entities.PaymentStatusAuthorized {
+		if err := gateway.CancelCharge(context.Background(), payment); err != nil {
+			return "", err
+		}
+		return entities.WebhookOutcomeProcessed, nil
+	}
+
+	refunds, err := u.refundRepository.ListRefundsByPaymentId(payment.ID)
+	if err != nil {
+		return "", err
+	}
+
+	refund := entities.UnsettledRefund(refunds)
+	if refund == nil {
+		if entities.RefundedAmount(refunds) > 0 {
+			// An earlier delivery already gave it back
+			return entities.WebhookOutcomeProcessed, nil
+		}
+
+		amount := providerPayment.capturedAmount
+		if amount == 0 {
+			amount = payment.CapturedAmount()
+		}
+		refund, err = u.refundRepository.AddRefund(entities.NewRefund(payment, amount, "paid after the payment was "+string(payment.Status)), amount)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	result, err := gateway.RefundCharge(context.Background(), payment, refund.Amount, refund.IdempotencyKey())
+	if err != nil {
+		if errors.Is(err, gateways.ErrProviderRejected) {
+			refund.Reject(err.Error())
+			if updateErr := u.refundRepository.UpdateRefund(refund); updateErr != nil {
+				println("ERROR: Failed to record rejected refund:", updateErr.Error())
+			}
+		}
+		return "", err
+	}
+
+	refund.ProviderRefundId = result.ProviderRefundId
+	refund.Status = result.Status
+	if refund.Status == entities.RefundStatusRejected {
+		refund.Reject("rejected by " + gateway.Name())
+		if err := u.refundRepository.UpdateRefund(refund); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: refund %d of late payment %d", gateways.ErrProviderRejected, refund.ID, payment.ID)
+	}
+	if err := u.refundRepository.UpdateRefund(refund); err != nil {
+		return "", err
+	}
+	return entities.WebhookOutcomeProcessed, nil
+}
@@ -0,0 +1,173 @@
+package handlewebhook_test
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCancelledPayment() *entities.Payment {
+	return &entities.Payment{
+		ID:                10,
+		OrderId:           1,
+		Total:             money.MustParse("50.00"),
+		Currency:          money.BRL,
+		Status:            entities.PaymentStatusCancelled,
+		Provider:          entities.PaymentProviderMercadoPago,
+		ExternalReference: "order-1",
+	}
+}
+
+// expectLatePaymentRefund sets up the full refund of a payment approved after it was closed.
+func (suite *HandleWebhookUseCaseTestSuite) expectLatePaymentRefund(payment *entities.Payment) {
+	suite.mockPaymentRepository.EXPECT().
+		UpdatePayment(mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.ID == payment.ID && p.ProviderPaymentId == "987" && p.Status == entities.PaymentStatusCancelled
+		})).
+		Return(nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		ListRefundsByPaymentId(payment.ID).
+		Return(nil, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		AddRefund(
+			mock.MatchedBy(func(refund *entities.Refund) bool {
+				return refund.PaymentId == payment.ID && refund.Amount == money.MustParse("50.00")
+			}),
+			money.MustParse("50.00")).
+		RunAndReturn(func(refund *entities.Refund, _ money.Amount) (*entities.Refund, error) {
+			refund.ID = 3
+			return refund, nil
+		}).
+		Once()
+
+	suite.mockQRCodeGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("50.00"), "refund-3").
+		Return(&gateways.RefundResult{ProviderRefundId: "r-1", Status: entities.RefundStatusApproved}, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		UpdateRefund(mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.ID == 3 && refund.ProviderRefundId == "r-1"
+		})).
+		Return(nil).
+		Once()
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithApprovalOfCancelledPayment_ShouldRefundIt() {
+	// GIVEN a QR code paid after its payment was cancelled
+	command := commands.HandleWebhookCommand{
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
+	}
+	payment := newCancelledPayment()
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", payment)
+	suite.expectLatePaymentRefund(payment)
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the money should be given back and the payment stay cancelled
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.PaymentStatusCancelled, payment.Status)
+	suite.mockUpdatePaymentUseCase.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithApprovalRacingCancellation_ShouldRefundIt() {
+	// GIVEN a QR code paid while its payment was being cancelled
+	command := commands.HandleWebhookCommand{
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
+	}
+	pending := newCancelledPayment()
+	pending.Status = entities.PaymentStatusPending
+	cancelled := newCancelledPayment()
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", pending)
+
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.Anything).
+		Return(&entities.InvalidStatusTransitionError{From: entities.PaymentStatusCancelled, To: entities.PaymentStatusApproved}).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		GetPaymentById(uint(10)).
+		Return(cancelled, nil).
+		Once()
+
+	suite.expectLatePaymentRefund(cancelled)
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the approval should be given back instead of failing the notification
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithAuthorizationOfCancelledPayment_ShouldVoidIt() {
+	// GIVEN a card authorized after its payment was cancelled
+	command := commands.HandleWebhookCommand{
+		Provider: entities.PaymentProviderStripe,
+		Id:       "evt_1",
+		Topic:    "payment_intent.amount_capturable_updated",
+		Resource: "pi_1",
+	}
+	payment := &entities.Payment{
+		ID:                10,
+		OrderId:           1,
+		Status:            entities.PaymentStatusCancelled,
+		Provider:          entities.PaymentProviderStripe,
+		ProviderPaymentId: "pi_1",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockCardGateway.EXPECT().
+		GetChargeStatus(mock.Anything, "pi_1").
+		Return(&gateways.ChargeStatus{ProviderPaymentId: "pi_1", ExternalReference: "order-1", Status: entities.PaymentStatusAuthorized}, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderStripe, "pi_1").
+		Return(payment, nil).
+		Once()
+
+	suite.mockCardGateway.EXPECT().
+		CancelCharge(mock.Anything, payment).
+		Return(nil).
+		Once()
+
+	// WHEN handling the event
+	err := suite.useCase.Execute(command)
+
+	// THEN the hold should be released without refunding
+	assert.NoError(suite.T(), err)
+	suite.mockRefundRepository.AssertNotCalled(suite.T(), "AddRefund", mock.Anything, mock.Anything)
+}
@@ -1,12 +1,20 @@
 package handlewebhook
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/clients"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	updatePaymentUseCase "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/updatePayment"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 )
 
 var (
@@ -14,44 +22,280 @@ var (
 )
 
 type HandleWebhookUseCaseImpl struct {
-	updatePaymentUseCase updatePaymentUseCase.UpdatePaymentUseCase
-	orderClient          clients.OrderClient
+	updatePaymentUseCase          updatePaymentUseCase.UpdatePaymentUseCase
+	mercadoPagoGateway            gateways.MercadoPagoGateway
+	paymentRepository             repositories.PaymentRepository
+	webhookNotificationRepository repositories.WebhookNotificationRepository
+	disputeRepository             repositories.DisputeRepository
+	refundRepository              repositories.RefundRepository
+	gatewayRegistry               *gateways.PaymentGatewayRegistry
 }
 
 func NewHandleWebhookUseCaseImpl(
 	updatePaymentUseCase updatePaymentUseCase.UpdatePaymentUseCase,
-	orderClient clients.OrderClient) *HandleWebhookUseCaseImpl {
+	mercadoPagoGateway gateways.MercadoPagoGateway,
+	paymentRepository repositories.PaymentRepository,
+	webhookNotificationRepository repositories.WebhookNotificationRepository,
+	disputeRepository repositories.DisputeRepository,
+	refundRepository repositories.RefundRepository,
+	gatewayRegistry *gateways.PaymentGatewayRegistry) *HandleWebhookUseCaseImpl {
 	return &HandleWebhookUseCaseImpl{
-		updatePaymentUseCase: updatePaymentUseCase,
-		orderClient:          orderClient,
+		updatePaymentUseCase:          updatePaymentUseCase,
+		mercadoPagoGateway:            mercadoPagoGateway,
+		paymentRepository:             paymentRepository,
+		webhookNotificationRepository: webhookNotificationRepository,
+		disputeRepository:             disputeRepository,
+		refundRepository:              refundRepository,
+		gatewayRegistry:               gatewayRegistry,
 	}
 }
 
 func (u *HandleWebhookUseCaseImpl) Execute(command commands.HandleWebhookCommand) error {
-	orderId, err := strconv.ParseUint(command.Id, 10, 32)
+	notification, err := u.receiveNotification(command)
 	if err != nil {
 		return err
 	}
 
-	updatePayment := commands.UpdatePaymentStatusCommand{
-		OrderId: uint(orderId),
-		Status:  command.Status,
+	if notification.IsProcessed() {
+		// Duplicate delivery of a notification that was already applied
+		return nil
 	}
 
-	err = u.updatePaymentUseCase.Execute(&updatePayment)
+	outcome, err := u.process(command)
+	notification.Complete(outcome, err)
+
+	if updateErr := u.webhookNotificationRepository.UpdateWebhookNotification(notification); updateErr != nil && err == nil {
+		return updateErr
+	}
+	return err
+}
+
+// receiveNotification records the delivery in the inbox, reusing the row of earlier deliveries.
+func (u *HandleWebhookUseCaseImpl) receiveNotification(command commands.HandleWebhookCommand) (*entities.WebhookNotification, error) {
+	notification, err := u.webhookNotificationRepository.FindWebhookNotification(command.Id, command.Topic, command.Resource)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if notification != nil {
+		notification.Deliveries++
+		return notification, nil
+	}
+
+	return u.webhookNotificationRepository.AddWebhookNotification(&entities.WebhookNotification{
+		ProviderId: command.Id,
+		Topic:      command.Topic,
+		Resource:   command.Resource,
+		ReceivedAt: time.Now(),
+		Deliveries: 1,
+		Outcome:    entities.WebhookOutcomeReceived,
+	})
+}
+
+func (u *HandleWebhookUseCaseImpl) process(command commands.HandleWebhookCommand) (entities.WebhookOutcome, error) {
+	if !command.IsFromMercadoPago() {
+		return u.processGatewayNotification(command)
+	}
+
+	if disputeType, ok := disputeTypeFromTopic(command.Topic); ok {
+		return u.processDispute(command, disputeType)
+	}
+
+	providerPayment, err := u.fetchProviderStatus(command)
+	if err != nil {
+		return "", err
+	}
+
+	return u.applyProviderStatus(command, providerPayment)
+}
+
+// processGatewayNotification handles the notifications of the providers other than Mercado Pago,
+// whose status is read through their PaymentGateway.
+func (u *HandleWebhookUseCaseImpl) processGatewayNotification(command commands.HandleWebhookCommand) (entities.WebhookOutcome, error) {
+	if command.Resource == "" {
+		// The event is not about a payment, e.g. a customer or payout event
+		return entities.WebhookOutcomeIgnored, nil
+	}
+
+	providerPayment, err := u.fetchGatewayStatus(command)
+	if err != nil {
+		return "", err
+	}
+
+	return u.applyProviderStatus(command, providerPayment)
+}
+
+func (u *HandleWebhookUseCaseImpl) applyProviderStatus(command commands.HandleWebhookCommand, providerPayment *providerPayment) (entities.WebhookOutcome, error) {
+	if providerPayment.status == entities.PaymentStatusPending {
+		// Nothing has been settled on the provider side yet
+		return entities.WebhookOutcomeIgnored, nil
+	}
+
+	payment, err := u.findPayment(providerPayment)
+	if err != nil {
+		return "", err
+	}
+
+	if payment.Status == entities.PaymentStatusDisputed {
+		// The dispute notifications decide how a disputed payment ends
+		return entities.WebhookOutcomeIgnored, nil
+	}
+	if payment.Status.IsPaidAfterClosing(providerPayment.status) {
+		return u.giveBackLatePayment(payment, providerPayment)
+	}
+
+	updatePayment := commands.NewUpdatePaymentStatusCommand(
+		payment.OrderId,
+		providerPayment.status,
+		entities.PaymentStatusChangeSourceWebhook,
+		command.Id)
+	updatePayment.PaymentId = payment.ID
+	updatePayment.ProviderPaymentId = providerPayment.paymentId
+	updatePayment.CapturedAmount = providerPayment.capturedAmount
+
+	// Approved payments enqueue the order status update in the same transaction
+	err = u.updatePaymentUseCase.Execute(updatePayment)
+
+	var transitionErr *entities.InvalidStatusTransitionError
+	if errors.As(err, &transitionErr) && transitionErr.From.IsPaidAfterClosing(providerPayment.status) {
+		// Cancelled or expired since it was read
+		payment, err = u.paymentRepository.GetPaymentById(payment.ID)
+		if err != nil {
+			return "", err
+		}
+		return u.giveBackLatePayment(payment, providerPayment)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return entities.WebhookOutcomeProcessed, nil
+}
+
+// findPayment resolves the notified payment by its provider payment id, falling back to the
+// external reference for payments the provider has not reported on before.
+func (u *HandleWebhookUseCaseImpl) findPayment(providerPayment *providerPayment) (*entities.Payment, error) {
+	if providerPayment.paymentId != "" {
+		payment, err := u.paymentRepository.FindPaymentByProviderPaymentId(providerPayment.provider, providerPayment.paymentId)
+		if err != nil || payment != nil {
+			return payment, err
+		}
+	}
+
+	if providerPayment.externalReference != "" {
+		payment, err := u.paymentRepository.FindPaymentByExternalReference(providerPayment.externalReference)
+		if err != nil || payment != nil {
+			return payment, err
+		}
 	}
 
-	if command.Status == "Approved" {
-		// Update order status to "Preparing" (status=2) when payment is approved
-		err = u.orderClient.UpdateOrderStatus(uint(orderId), 2)
+	return nil, fmt.Errorf("%w: provider payment %q, external reference %q",
+		entities.ErrPaymentNotFound, providerPayment.paymentId, providerPayment.externalReference)
+}
+
+// providerPayment is the state of a payment as reported by its provider.
+type providerPayment struct {
+	provider          string
+	externalReference string
+	paymentId         string
+	status            entities.PaymentStatus
+	capturedAmount    money.Amount
+}
+
+// fetchProviderStatus asks Mercado Pago for the notified resource instead of trusting the notification body.
+func (u *HandleWebhookUseCaseImpl) fetchProviderStatus(command commands.HandleWebhookCommand) (*providerPayment, error) {
+	resourceId := resourceIdFromCommand(command)
+	if resourceId == "" {
+		return nil, fmt.Errorf("webhook notification has no resource id")
+	}
+
+	if isMerchantOrderNotification(command) {
+		merchantOrder, err := u.mercadoPagoGateway.GetMerchantOrder(context.Background(), resourceId)
 		if err != nil {
-			println("ERROR: Failed to update order status in Order Service:", err.Error())
-			return fmt.Errorf("failed to update order status in Order Service: %w", err)
+			return nil, err
 		}
-		println("Successfully updated order", orderId, "status to 'Preparing' (status=2)")
+		return &providerPayment{
+			provider:          entities.PaymentProviderMercadoPago,
+			externalReference: merchantOrder.ExternalReference,
+			paymentId:         paymentIdFromMerchantOrder(merchantOrder),
+			status:            statusFromMerchantOrder(merchantOrder.OrderStatus),
+		}, nil
 	}
 
-	return nil
+	payment, err := u.mercadoPagoGateway.GetPayment(context.Background(), resourceId)
+	if err != nil {
+		return nil, err
+	}
+	return &providerPayment{
+		provider:          entities.PaymentProviderMercadoPago,
+		externalReference: payment.ExternalReference,
+		paymentId:         strconv.FormatInt(payment.Id, 10),
+		status:            gateways.MercadoPagoPaymentStatus(payment),
+	}, nil
+}
+
+// fetchGatewayStatus asks the gateway of the notifying provider for the payment named by the
+// notification, which only carries its id.
+func (u *HandleWebhookUseCaseImpl) fetchGatewayStatus(command commands.HandleWebhookCommand) (*providerPayment, error) {
+	gateway, ok := u.gatewayRegistry.ForProvider(command.Provider)
+	if !ok {
+		return nil, fmt.Errorf("no gateway is enabled for provider %q", command.Provider)
+	}
+
+	status, err := gateway.GetChargeStatus(context.Background(), command.Resource)
+	if err != nil {
+		return nil, err
+	}
+	return &providerPayment{
+		provider:          command.Provider,
+		externalReference: status.ExternalReference,
+		paymentId:         status.ProviderPaymentId,
+		status:            status.Status,
+		capturedAmount:    status.CapturedAmount,
+	}, nil
+}
+
+// paymentIdFromMerchantOrder picks the approved payment of the merchant order, or its latest one
+// when none was approved.
+func paymentIdFromMerchantOrder(merchantOrder dto.MercadoPagoMerchantOrderResponseDto) string {
+	var latest *dto.MercadoPagoPaymentResponseDto
+	for _, payment := range merchantOrder.Payments {
+		if payment == nil {
+			continue
+		}
+		if payment.Status == "approved" {
+			return strconv.FormatInt(payment.Id, 10)
+		}
+		latest = payment
+	}
+
+	if latest == nil {
+		return ""
+	}
+	return strconv.FormatInt(latest.Id, 10)
+}
+
+func isMerchantOrderNotification(command commands.HandleWebhookCommand) bool {
+	return command.Topic == "merchant_order" || strings.Contains(command.Resource, "/merchant_orders/")
+}
+
+// resourceIdFromCommand extracts the provider id from the notification, which is sent either as
+// a bare id or as the resource URL (e.g. https://api.mercadolibre.com/merchant_orders/123).
+func resourceIdFromCommand(command commands.HandleWebhookCommand) string {
+	resource := strings.TrimRight(command.Resource, "/")
+	if resource == "" {
+		return command.Id
+	}
+	return resource[strings.LastIndex(resource, "/")+1:]
+}
+
+func statusFromMerchantOrder(orderStatus string) entities.PaymentStatus {
+	switch orderStatus {
+	case "paid":
+		return entities.PaymentStatusApproved
+	case "expired":
+		return entities.PaymentStatusExpired
+	default:
+		return entities.PaymentStatusPending
+	}
 }
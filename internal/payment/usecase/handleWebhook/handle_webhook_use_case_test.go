@@ -4,9 +4,13 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	handlewebhook "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/handleWebhook"
-	mockClients "github.com/abattassini/tc-fiap-payment/mocks/payment/infrastructure/clients"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
 	mockUpdatePayment "github.com/abattassini/tc-fiap-payment/mocks/payment/usecase/updatePayment"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,61 +20,154 @@ import (
 type HandleWebhookUseCaseTestSuite struct {
 	suite.Suite
 	mockUpdatePaymentUseCase *mockUpdatePayment.MockUpdatePaymentUseCase
-	mockOrderClient          *mockClients.MockOrderClient
+	mockMercadoPagoGateway   *mockGateways.MockMercadoPagoGateway
+	mockPaymentRepository    *mockRepositories.MockPaymentRepository
+	mockInboxRepository      *mockRepositories.MockWebhookNotificationRepository
+	mockDisputeRepository    *mockRepositories.MockDisputeRepository
+	mockRefundRepository     *mockRepositories.MockRefundRepository
+	mockCardGateway          *mockGateways.MockPaymentGateway
+	mockQRCodeGateway        *mockGateways.MockPaymentGateway
 	useCase                  handlewebhook.HandleWebhookUseCase
 }
 
 func (suite *HandleWebhookUseCaseTestSuite) SetupTest() {
 	suite.mockUpdatePaymentUseCase = mockUpdatePayment.NewMockUpdatePaymentUseCase(suite.T())
-	suite.mockOrderClient = mockClients.NewMockOrderClient(suite.T())
+	suite.mockMercadoPagoGateway = mockGateways.NewMockMercadoPagoGateway(suite.T())
+	suite.mockPaymentRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockInboxRepository = mockRepositories.NewMockWebhookNotificationRepository(suite.T())
+	suite.mockDisputeRepository = mockRepositories.NewMockDisputeRepository(suite.T())
+	suite.mockRefundRepository = mockRepositories.NewMockRefundRepository(suite.T())
+	suite.mockCardGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockCardGateway.EXPECT().Name().Return(entities.PaymentProviderStripe).Maybe()
+	suite.mockQRCodeGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockQRCodeGateway.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockQRCodeGateway},
+		{PaymentType: entities.PaymentTypeCard, Gateway: suite.mockCardGateway},
+	}, &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode})
+	suite.Require().NoError(err)
 	suite.useCase = handlewebhook.NewHandleWebhookUseCaseImpl(
 		suite.mockUpdatePaymentUseCase,
-		suite.mockOrderClient,
+		suite.mockMercadoPagoGateway,
+		suite.mockPaymentRepository,
+		suite.mockInboxRepository,
+		suite.mockDisputeRepository,
+		suite.mockRefundRepository,
+		registry,
 	)
 }
 
+// expectNewNotification sets up the inbox for a first delivery and returns the recorded notification.
+func (suite *HandleWebhookUseCaseTestSuite) expectNewNotification(command commands.HandleWebhookCommand) *entities.WebhookNotification {
+	notification := &entities.WebhookNotification{
+		ID:         1,
+		ProviderId: command.Id,
+		Topic:      command.Topic,
+		Resource:   command.Resource,
+		Deliveries: 1,
+		Outcome:    entities.WebhookOutcomeReceived,
+	}
+
+	suite.mockInboxRepository.EXPECT().
+		FindWebhookNotification(command.Id, command.Topic, command.Resource).
+		Return(nil, nil).
+		Once()
+
+	suite.mockInboxRepository.EXPECT().
+		AddWebhookNotification(mock.MatchedBy(func(n *entities.WebhookNotification) bool {
+			return n.ProviderId == command.Id && n.Outcome == entities.WebhookOutcomeReceived
+		})).
+		Return(notification, nil).
+		Once()
+
+	return notification
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) expectOutcome(outcome entities.WebhookOutcome) {
+	suite.mockInboxRepository.EXPECT().
+		UpdateWebhookNotification(mock.MatchedBy(func(n *entities.WebhookNotification) bool {
+			return n.Outcome == outcome && n.ProcessedAt != nil
+		})).
+		Return(nil).
+		Once()
+}
+
+// expectPaymentByExternalReference sets up a payment the provider has not reported on before, so it
+// is only found through its external reference.
+func (suite *HandleWebhookUseCaseTestSuite) expectPaymentByExternalReference(providerPaymentId string, payment *entities.Payment) {
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, providerPaymentId).
+		Return(nil, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByExternalReference(payment.ExternalReference).
+		Return(payment, nil).
+		Once()
+}
+
 func TestHandleWebhookUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(HandleWebhookUseCaseTestSuite))
 }
 
-func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithApprovedPayment_ShouldUpdateOrderStatus() {
-	// GIVEN an approved payment webhook
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithApprovedPayment_ShouldApprovePayment() {
+	// GIVEN a payment notification that Mercado Pago reports as approved
 	command := commands.HandleWebhookCommand{
-		Id:     "1",
-		Status: "Approved",
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
 	}
 
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", &entities.Payment{ID: 10, OrderId: 1, ExternalReference: "order-1"})
+
 	suite.mockUpdatePaymentUseCase.EXPECT().
 		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
-			return cmd.OrderId == 1 && cmd.Status == "Approved"
+			return cmd.OrderId == 1 &&
+				cmd.Status == entities.PaymentStatusApproved &&
+				cmd.Source == entities.PaymentStatusChangeSourceWebhook &&
+				cmd.NotificationId == "987" &&
+				cmd.PaymentId == 10 &&
+				cmd.ProviderPaymentId == "987"
 		})).
 		Return(nil).
 		Once()
 
-	suite.mockOrderClient.EXPECT().
-		UpdateOrderStatus(uint(1), int(2)).
-		Return(nil).
-		Once()
-
 	// WHEN handling webhook
 	err := suite.useCase.Execute(command)
 
-	// THEN payment and order should be updated
+	// THEN the payment should be approved
 	assert.NoError(suite.T(), err)
 	suite.mockUpdatePaymentUseCase.AssertExpectations(suite.T())
-	suite.mockOrderClient.AssertExpectations(suite.T())
 }
 
-func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithDeclinedPayment_ShouldOnlyUpdatePayment() {
-	// GIVEN a declined payment webhook
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithRejectedPayment_ShouldOnlyUpdatePayment() {
+	// GIVEN a payment notification that Mercado Pago reports as rejected
 	command := commands.HandleWebhookCommand{
-		Id:     "1",
-		Status: "Declined",
+		Topic:    "payment",
+		Resource: "https://api.mercadopago.com/v1/payments/987",
 	}
 
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "rejected", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", &entities.Payment{ID: 10, OrderId: 1, ExternalReference: "order-1"})
+
 	suite.mockUpdatePaymentUseCase.EXPECT().
 		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
-			return cmd.OrderId == 1 && cmd.Status == "Declined"
+			return cmd.OrderId == 1 && cmd.Status == entities.PaymentStatusDeclined
 		})).
 		Return(nil).
 		Once()
@@ -83,29 +180,259 @@ func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithDeclinedPayme
 	suite.mockUpdatePaymentUseCase.AssertExpectations(suite.T())
 }
 
-func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithInvalidOrderId_ShouldReturnError() {
-	// GIVEN a webhook with invalid order ID
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithPartiallyRefundedPayment_ShouldPartiallyRefund() {
+	// GIVEN a payment that Mercado Pago keeps approved after a partial refund
+	command := commands.HandleWebhookCommand{
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", StatusDetail: "partially_refunded", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", &entities.Payment{ID: 10, OrderId: 1, ExternalReference: "order-1"})
+
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
+			return cmd.OrderId == 1 && cmd.Status == entities.PaymentStatusPartiallyRefunded
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the payment should be partially refunded
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithPaidMerchantOrder_ShouldApprovePayment() {
+	// GIVEN a merchant order notification
+	command := commands.HandleWebhookCommand{
+		Topic:    "merchant_order",
+		Resource: "https://api.mercadolibre.com/merchant_orders/555",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetMerchantOrder(mock.Anything, "555").
+		Return(dto.MercadoPagoMerchantOrderResponseDto{
+			Id:                555,
+			OrderStatus:       "paid",
+			ExternalReference: "order-3",
+			Payments: []*dto.MercadoPagoPaymentResponseDto{
+				{Id: 700, Status: "rejected"},
+				{Id: 701, Status: "approved"},
+			},
+		}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("701", &entities.Payment{ID: 30, OrderId: 3, ExternalReference: "order-3"})
+
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
+			return cmd.OrderId == 3 &&
+				cmd.PaymentId == 30 &&
+				cmd.Status == entities.PaymentStatusApproved &&
+				cmd.ProviderPaymentId == "701"
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the payment should be approved
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithPendingProviderStatus_ShouldNotUpdatePayment() {
+	// GIVEN a notification for a payment still in process
 	command := commands.HandleWebhookCommand{
-		Id:     "invalid",
-		Status: "Approved",
+		Topic:    "payment",
+		Resource: "987",
 	}
 
-	// WHEN handling webhook with invalid ID
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeIgnored)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "in_process", ExternalReference: "order-1"}, nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN nothing should be updated
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithGatewayError_ShouldReturnError() {
+	// GIVEN a notification that cannot be confirmed with Mercado Pago
+	command := commands.HandleWebhookCommand{
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeFailed)
+
+	expectedError := errors.New("payment not found")
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{}, expectedError).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN error should be returned and nothing updated
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), expectedError, err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithoutResourceId_ShouldReturnError() {
+	// GIVEN a notification without id or resource
+	command := commands.HandleWebhookCommand{
+		Topic: "payment",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeFailed)
+
+	// WHEN handling webhook
 	err := suite.useCase.Execute(command)
 
 	// THEN error should be returned
 	assert.Error(suite.T(), err)
 }
 
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithUnknownPayment_ShouldReturnNotFound() {
+	// GIVEN a provider payment that does not belong to one of our payments
+	command := commands.HandleWebhookCommand{
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeFailed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "invalid"}, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, "987").
+		Return(nil, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByExternalReference("invalid").
+		Return(nil, nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN a not found error should be returned and nothing updated
+	assert.ErrorIs(suite.T(), err, entities.ErrPaymentNotFound)
+	suite.mockUpdatePaymentUseCase.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithKnownProviderPaymentId_ShouldUpdateThatPayment() {
+	// GIVEN a refund of a payment that was approved on an earlier attempt of the order
+	command := commands.HandleWebhookCommand{
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "refunded", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, "987").
+		Return(&entities.Payment{ID: 7, OrderId: 1, ExternalReference: "order-1", ProviderPaymentId: "987"}, nil).
+		Once()
+
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
+			return cmd.PaymentId == 7 && cmd.OrderId == 1 && cmd.Status == entities.PaymentStatusRefunded
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN the payment with that provider id should be updated, without an external reference lookup
+	assert.NoError(suite.T(), err)
+	suite.mockPaymentRepository.AssertNotCalled(suite.T(), "FindPaymentByExternalReference", mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithPaymentLookupError_ShouldReturnError() {
+	// GIVEN a database that cannot be queried
+	command := commands.HandleWebhookCommand{
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeFailed)
+
+	expectedError := errors.New("database error")
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderMercadoPago, "987").
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN handling webhook
+	err := suite.useCase.Execute(command)
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+}
+
 func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithUpdatePaymentError_ShouldReturnError() {
 	// GIVEN an approved payment webhook
 	command := commands.HandleWebhookCommand{
-		Id:     "1",
-		Status: "Approved",
+		Topic:    "payment",
+		Resource: "987",
 	}
 
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeFailed)
+
 	expectedError := errors.New("payment update failed")
 
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "approved", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", &entities.Payment{ID: 10, OrderId: 1, ExternalReference: "order-1"})
+
 	suite.mockUpdatePaymentUseCase.EXPECT().
 		Execute(mock.Anything).
 		Return(expectedError).
@@ -120,32 +447,181 @@ func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithUpdatePayment
 	suite.mockUpdatePaymentUseCase.AssertExpectations(suite.T())
 }
 
-func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithOrderClientError_ShouldReturnError() {
-	// GIVEN an approved payment webhook
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithAlreadyProcessedNotification_ShouldSkipSideEffects() {
+	// GIVEN a retry of a notification that was already applied
 	command := commands.HandleWebhookCommand{
-		Id:     "1",
-		Status: "Approved",
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	suite.mockInboxRepository.EXPECT().
+		FindWebhookNotification("987", "payment", "987").
+		Return(&entities.WebhookNotification{
+			ID:         1,
+			ProviderId: "987",
+			Topic:      "payment",
+			Resource:   "987",
+			Deliveries: 1,
+			Outcome:    entities.WebhookOutcomeProcessed,
+		}, nil).
+		Once()
+
+	// WHEN handling the duplicate delivery
+	err := suite.useCase.Execute(command)
+
+	// THEN it should be acknowledged without touching the provider or the payment
+	assert.NoError(suite.T(), err)
+	suite.mockMercadoPagoGateway.AssertNotCalled(suite.T(), "GetPayment", mock.Anything, mock.Anything)
+	suite.mockMercadoPagoGateway.AssertNotCalled(suite.T(), "GetMerchantOrder", mock.Anything, mock.Anything)
+	suite.mockPaymentRepository.AssertNotCalled(suite.T(), "FindPaymentByProviderPaymentId", mock.Anything, mock.Anything)
+	suite.mockPaymentRepository.AssertNotCalled(suite.T(), "FindPaymentByExternalReference", mock.Anything)
+	suite.mockInboxRepository.AssertNotCalled(suite.T(), "UpdateWebhookNotification", mock.Anything)
+	suite.mockUpdatePaymentUseCase.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithPreviouslyFailedNotification_ShouldProcessAgain() {
+	// GIVEN a redelivery of a notification whose processing failed
+	command := commands.HandleWebhookCommand{
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	notification := &entities.WebhookNotification{
+		ID:         1,
+		ProviderId: "987",
+		Topic:      "payment",
+		Resource:   "987",
+		Deliveries: 1,
+		Outcome:    entities.WebhookOutcomeFailed,
+		Error:      "order service unavailable",
 	}
 
-	expectedError := errors.New("order service unavailable")
+	suite.mockInboxRepository.EXPECT().
+		FindWebhookNotification("987", "payment", "987").
+		Return(notification, nil).
+		Once()
+
+	suite.mockMercadoPagoGateway.EXPECT().
+		GetPayment(mock.Anything, "987").
+		Return(dto.MercadoPagoPaymentResponseDto{Id: 987, Status: "rejected", ExternalReference: "order-1"}, nil).
+		Once()
+
+	suite.expectPaymentByExternalReference("987", &entities.Payment{ID: 10, OrderId: 1, ExternalReference: "order-1"})
 
 	suite.mockUpdatePaymentUseCase.EXPECT().
 		Execute(mock.Anything).
 		Return(nil).
 		Once()
 
-	suite.mockOrderClient.EXPECT().
-		UpdateOrderStatus(uint(1), int(2)).
-		Return(expectedError).
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	// WHEN handling the redelivery
+	err := suite.useCase.Execute(command)
+
+	// THEN it should be processed again and the inbox row updated
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(2), notification.Deliveries)
+	assert.Empty(suite.T(), notification.Error)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithInboxError_ShouldReturnError() {
+	// GIVEN an inbox that cannot be read
+	command := commands.HandleWebhookCommand{
+		Id:       "987",
+		Topic:    "payment",
+		Resource: "987",
+	}
+
+	expectedError := errors.New("database error")
+
+	suite.mockInboxRepository.EXPECT().
+		FindWebhookNotification("987", "payment", "987").
+		Return(nil, expectedError).
 		Once()
 
-	// WHEN order update fails
+	// WHEN handling webhook
 	err := suite.useCase.Execute(command)
 
 	// THEN error should be returned
-	assert.Error(suite.T(), err)
-	assert.Contains(suite.T(), err.Error(), "failed to update order status in Order Service")
-	assert.Contains(suite.T(), err.Error(), expectedError.Error())
-	suite.mockUpdatePaymentUseCase.AssertExpectations(suite.T())
-	suite.mockOrderClient.AssertExpectations(suite.T())
+	assert.Equal(suite.T(), expectedError, err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithStripeEvent_ShouldUpdatePaymentFromGateway() {
+	// GIVEN a Stripe event about a payment intent the gateway reports as declined
+	command := commands.HandleWebhookCommand{
+		Provider: entities.PaymentProviderStripe,
+		Id:       "evt_1",
+		Topic:    "payment_intent.payment_failed",
+		Resource: "pi_1",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeProcessed)
+
+	suite.mockCardGateway.EXPECT().
+		GetChargeStatus(mock.Anything, "pi_1").
+		Return(&gateways.ChargeStatus{ProviderPaymentId: "pi_1", ExternalReference: "order-1", Status: entities.PaymentStatusDeclined}, nil).
+		Once()
+
+	suite.mockPaymentRepository.EXPECT().
+		FindPaymentByProviderPaymentId(entities.PaymentProviderStripe, "pi_1").
+		Return(&entities.Payment{ID: 10, OrderId: 1, Status: entities.PaymentStatusPending}, nil).
+		Once()
+
+	suite.mockUpdatePaymentUseCase.EXPECT().
+		Execute(mock.MatchedBy(func(cmd *commands.UpdatePaymentStatusCommand) bool {
+			return cmd.OrderId == 1 &&
+				cmd.Status == entities.PaymentStatusDeclined &&
+				cmd.NotificationId == "evt_1" &&
+				cmd.PaymentId == 10 &&
+				cmd.ProviderPaymentId == "pi_1"
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN handling the event
+	err := suite.useCase.Execute(command)
+
+	// THEN the payment should be updated without asking Mercado Pago
+	assert.NoError(suite.T(), err)
+	suite.mockMercadoPagoGateway.AssertNotCalled(suite.T(), "GetPayment", mock.Anything, mock.Anything)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithStripeEventWithoutPaymentIntent_ShouldIgnore() {
+	// GIVEN a Stripe event that is not about a payment
+	command := commands.HandleWebhookCommand{
+		Provider: entities.PaymentProviderStripe,
+		Id:       "evt_2",
+		Topic:    "customer.created",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeIgnored)
+
+	// WHEN handling the event
+	err := suite.useCase.Execute(command)
+
+	// THEN it should be recorded as ignored
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *HandleWebhookUseCaseTestSuite) Test_HandleWebhook_WithUnknownProvider_ShouldFail() {
+	// GIVEN an event from a provider no gateway is enabled for
+	command := commands.HandleWebhookCommand{
+		Provider: "adyen",
+		Id:       "evt_3",
+		Topic:    "payment",
+		Resource: "psp_1",
+	}
+
+	suite.expectNewNotification(command)
+	suite.expectOutcome(entities.WebhookOutcomeFailed)
+
+	// WHEN handling the event
+	err := suite.useCase.Execute(command)
+
+	// THEN it should fail
+	assert.ErrorContains(suite.T(), err, "adyen")
 }
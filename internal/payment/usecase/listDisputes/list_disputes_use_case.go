@@ -0,0 +1,10 @@
+package listdisputes
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type ListDisputesUseCase interface {
+	Execute(command *commands.ListDisputesCommand) ([]*entities.Dispute, error)
+}
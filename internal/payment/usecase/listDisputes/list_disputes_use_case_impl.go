@@ -0,0 +1,27 @@
+package listdisputes
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ ListDisputesUseCase = (*ListDisputesUseCaseImpl)(nil)
+)
+
+type ListDisputesUseCaseImpl struct {
+	disputeRepository repositories.DisputeRepository
+}
+
+func NewListDisputesUseCaseImpl(disputeRepository repositories.DisputeRepository) *ListDisputesUseCaseImpl {
+	return &ListDisputesUseCaseImpl{disputeRepository: disputeRepository}
+}
+
+func (u *ListDisputesUseCaseImpl) Execute(command *commands.ListDisputesCommand) ([]*entities.Dispute, error) {
+	return u.disputeRepository.ListDisputes(repositories.DisputeFilter{
+		OrderId: command.OrderId,
+		Status:  entities.DisputeStatus(command.Status),
+		Limit:   command.Limit,
+	})
+}
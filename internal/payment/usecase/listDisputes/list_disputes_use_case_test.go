@@ -0,0 +1,67 @@
+package listdisputes_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	listdisputes "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listDisputes"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListDisputesUseCaseTestSuite struct {
+	suite.Suite
+	mockDisputeRepository *mockRepositories.MockDisputeRepository
+	useCase               listdisputes.ListDisputesUseCase
+}
+
+func (suite *ListDisputesUseCaseTestSuite) SetupTest() {
+	suite.mockDisputeRepository = mockRepositories.NewMockDisputeRepository(suite.T())
+	suite.useCase = listdisputes.NewListDisputesUseCaseImpl(suite.mockDisputeRepository)
+}
+
+func TestListDisputesUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ListDisputesUseCaseTestSuite))
+}
+
+func (suite *ListDisputesUseCaseTestSuite) Test_ListDisputes_WithFilter_ShouldQueryRepository() {
+	// GIVEN a filter for open disputes of an order
+	expected := []*entities.Dispute{{ID: 1, OrderId: 1, Status: entities.DisputeStatusOpen}}
+
+	suite.mockDisputeRepository.EXPECT().
+		ListDisputes(repositories.DisputeFilter{
+			OrderId: 1,
+			Status:  entities.DisputeStatusOpen,
+			Limit:   10,
+		}).
+		Return(expected, nil).
+		Once()
+
+	// WHEN listing disputes
+	result, err := suite.useCase.Execute(commands.NewListDisputesCommand(1, "open", 10))
+
+	// THEN the matching disputes should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *ListDisputesUseCaseTestSuite) Test_ListDisputes_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a failing repository
+	expectedError := errors.New("database error")
+
+	suite.mockDisputeRepository.EXPECT().
+		ListDisputes(repositories.DisputeFilter{}).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN listing disputes
+	result, err := suite.useCase.Execute(commands.NewListDisputesCommand(0, "", 0))
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
@@ -0,0 +1,10 @@
+package listoutboxmessages
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type ListOutboxMessagesUseCase interface {
+	Execute(command *commands.ListOutboxMessagesCommand) ([]*entities.OutboxMessage, error)
+}
@@ -0,0 +1,27 @@
+package listoutboxmessages
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ ListOutboxMessagesUseCase = (*ListOutboxMessagesUseCaseImpl)(nil)
+)
+
+type ListOutboxMessagesUseCaseImpl struct {
+	outboxRepository repositories.OutboxRepository
+}
+
+func NewListOutboxMessagesUseCaseImpl(outboxRepository repositories.OutboxRepository) *ListOutboxMessagesUseCaseImpl {
+	return &ListOutboxMessagesUseCaseImpl{outboxRepository: outboxRepository}
+}
+
+func (u *ListOutboxMessagesUseCaseImpl) Execute(command *commands.ListOutboxMessagesCommand) ([]*entities.OutboxMessage, error) {
+	return u.outboxRepository.ListOutboxMessages(repositories.OutboxMessageFilter{
+		OrderId: command.OrderId,
+		Status:  entities.OutboxStatus(command.Status),
+		Limit:   command.Limit,
+	})
+}
@@ -0,0 +1,67 @@
+package listoutboxmessages_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	listoutboxmessages "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listOutboxMessages"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListOutboxMessagesUseCaseTestSuite struct {
+	suite.Suite
+	mockOutboxRepository *mockRepositories.MockOutboxRepository
+	useCase              listoutboxmessages.ListOutboxMessagesUseCase
+}
+
+func (suite *ListOutboxMessagesUseCaseTestSuite) SetupTest() {
+	suite.mockOutboxRepository = mockRepositories.NewMockOutboxRepository(suite.T())
+	suite.useCase = listoutboxmessages.NewListOutboxMessagesUseCaseImpl(suite.mockOutboxRepository)
+}
+
+func TestListOutboxMessagesUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ListOutboxMessagesUseCaseTestSuite))
+}
+
+func (suite *ListOutboxMessagesUseCaseTestSuite) Test_ListOutboxMessages_WithFilter_ShouldQueryRepository() {
+	// GIVEN a filter for failed messages of an order
+	expected := []*entities.OutboxMessage{entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)}
+
+	suite.mockOutboxRepository.EXPECT().
+		ListOutboxMessages(repositories.OutboxMessageFilter{
+			OrderId: 1,
+			Status:  entities.OutboxStatusFailed,
+			Limit:   10,
+		}).
+		Return(expected, nil).
+		Once()
+
+	// WHEN listing messages
+	result, err := suite.useCase.Execute(commands.NewListOutboxMessagesCommand(1, "failed", 10))
+
+	// THEN the matching messages should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *ListOutboxMessagesUseCaseTestSuite) Test_ListOutboxMessages_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a failing repository
+	expectedError := errors.New("database error")
+
+	suite.mockOutboxRepository.EXPECT().
+		ListOutboxMessages(repositories.OutboxMessageFilter{}).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN listing messages
+	result, err := suite.useCase.Execute(&commands.ListOutboxMessagesCommand{})
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
@@ -0,0 +1,10 @@
+package listpaymentattempts
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type ListPaymentAttemptsUseCase interface {
+	Execute(command *commands.ListPaymentAttemptsCommand) ([]*entities.Payment, error)
+}
@@ -0,0 +1,23 @@
+package listpaymentattempts
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ ListPaymentAttemptsUseCase = (*ListPaymentAttemptsUseCaseImpl)(nil)
+)
+
+type ListPaymentAttemptsUseCaseImpl struct {
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewListPaymentAttemptsUseCaseImpl(paymentRepository repositories.PaymentRepository) *ListPaymentAttemptsUseCaseImpl {
+	return &ListPaymentAttemptsUseCaseImpl{paymentRepository: paymentRepository}
+}
+
+func (u *ListPaymentAttemptsUseCaseImpl) Execute(command *commands.ListPaymentAttemptsCommand) ([]*entities.Payment, error) {
+	return u.paymentRepository.ListPaymentsByOrderId(command.OrderId)
+}
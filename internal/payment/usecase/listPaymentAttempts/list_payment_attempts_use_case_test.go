@@ -0,0 +1,65 @@
+package listpaymentattempts_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	listpaymentattempts "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listPaymentAttempts"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListPaymentAttemptsUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockPaymentRepository
+	useCase        listpaymentattempts.ListPaymentAttemptsUseCase
+}
+
+func (suite *ListPaymentAttemptsUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.useCase = listpaymentattempts.NewListPaymentAttemptsUseCaseImpl(suite.mockRepository)
+}
+
+func TestListPaymentAttemptsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ListPaymentAttemptsUseCaseTestSuite))
+}
+
+func (suite *ListPaymentAttemptsUseCaseTestSuite) Test_ListPaymentAttempts_ShouldReturnEveryAttempt() {
+	// GIVEN an order that was declined and then paid
+	expected := []*entities.Payment{
+		{ID: 1, OrderId: 1, Status: entities.PaymentStatusDeclined},
+		{ID: 2, OrderId: 1, Status: entities.PaymentStatusApproved, Active: true},
+	}
+
+	suite.mockRepository.EXPECT().
+		ListPaymentsByOrderId(uint(1)).
+		Return(expected, nil).
+		Once()
+
+	// WHEN listing its attempts
+	result, err := suite.useCase.Execute(commands.NewListPaymentAttemptsCommand(1))
+
+	// THEN both attempts should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *ListPaymentAttemptsUseCaseTestSuite) Test_ListPaymentAttempts_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a failing repository
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		ListPaymentsByOrderId(uint(1)).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN listing attempts
+	result, err := suite.useCase.Execute(commands.NewListPaymentAttemptsCommand(1))
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
@@ -0,0 +1,10 @@
+package listwebhooknotifications
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type ListWebhookNotificationsUseCase interface {
+	Execute(command *commands.ListWebhookNotificationsCommand) ([]*entities.WebhookNotification, error)
+}
@@ -0,0 +1,29 @@
+package listwebhooknotifications
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ ListWebhookNotificationsUseCase = (*ListWebhookNotificationsUseCaseImpl)(nil)
+)
+
+type ListWebhookNotificationsUseCaseImpl struct {
+	webhookNotificationRepository repositories.WebhookNotificationRepository
+}
+
+func NewListWebhookNotificationsUseCaseImpl(webhookNotificationRepository repositories.WebhookNotificationRepository) *ListWebhookNotificationsUseCaseImpl {
+	return &ListWebhookNotificationsUseCaseImpl{webhookNotificationRepository: webhookNotificationRepository}
+}
+
+func (u *ListWebhookNotificationsUseCaseImpl) Execute(command *commands.ListWebhookNotificationsCommand) ([]*entities.WebhookNotification, error) {
+	return u.webhookNotificationRepository.ListWebhookNotifications(repositories.WebhookNotificationFilter{
+		ProviderId: command.ProviderId,
+		Topic:      command.Topic,
+		Resource:   command.Resource,
+		Outcome:    entities.WebhookOutcome(command.Outcome),
+		Limit:      command.Limit,
+	})
+}
@@ -0,0 +1,71 @@
+package listwebhooknotifications_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	listwebhooknotifications "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/listWebhookNotifications"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListWebhookNotificationsUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockWebhookNotificationRepository
+	useCase        listwebhooknotifications.ListWebhookNotificationsUseCase
+}
+
+func (suite *ListWebhookNotificationsUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockWebhookNotificationRepository(suite.T())
+	suite.useCase = listwebhooknotifications.NewListWebhookNotificationsUseCaseImpl(suite.mockRepository)
+}
+
+func TestListWebhookNotificationsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ListWebhookNotificationsUseCaseTestSuite))
+}
+
+func (suite *ListWebhookNotificationsUseCaseTestSuite) Test_ListWebhookNotifications_WithFilter_ShouldQueryRepository() {
+	// GIVEN a filter for failed notifications of a resource
+	command := commands.NewListWebhookNotificationsCommand("", "payment", "987", "failed", 10)
+	expected := []*entities.WebhookNotification{
+		{ID: 1, ProviderId: "987", Topic: "payment", Resource: "987", Outcome: entities.WebhookOutcomeFailed},
+	}
+
+	suite.mockRepository.EXPECT().
+		ListWebhookNotifications(repositories.WebhookNotificationFilter{
+			Topic:    "payment",
+			Resource: "987",
+			Outcome:  entities.WebhookOutcomeFailed,
+			Limit:    10,
+		}).
+		Return(expected, nil).
+		Once()
+
+	// WHEN listing notifications
+	result, err := suite.useCase.Execute(command)
+
+	// THEN the matching notifications should be returned
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, result)
+}
+
+func (suite *ListWebhookNotificationsUseCaseTestSuite) Test_ListWebhookNotifications_WithRepositoryError_ShouldReturnError() {
+	// GIVEN a failing repository
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		ListWebhookNotifications(repositories.WebhookNotificationFilter{}).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN listing notifications
+	result, err := suite.useCase.Execute(&commands.ListWebhookNotificationsCommand{})
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
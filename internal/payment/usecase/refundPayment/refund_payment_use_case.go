@@ -0,0 +1,10 @@
+package refundpayment
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type RefundPaymentUseCase interface {
+	Execute(command *commands.RefundPaymentCommand) (*entities.Refund, error)
+}
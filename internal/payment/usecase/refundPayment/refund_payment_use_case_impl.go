@@ -0,0 +1,115 @@
+package refundpayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ RefundPaymentUseCase = (*RefundPaymentUseCaseImpl)(nil)
+)
+
+type RefundPaymentUseCaseImpl struct {
+	gatewayRegistry   *gateways.PaymentGatewayRegistry
+	paymentRepository repositories.PaymentRepository
+	refundRepository  repositories.RefundRepository
+}
+
+func NewRefundPaymentUseCaseImpl(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	paymentRepository repositories.PaymentRepository,
+	refundRepository repositories.RefundRepository) *RefundPaymentUseCaseImpl {
+	return &RefundPaymentUseCaseImpl{
+		gatewayRegistry:   gatewayRegistry,
+		paymentRepository: paymentRepository,
+		refundRepository:  refundRepository,
+	}
+}
+
+func (u *RefundPaymentUseCaseImpl) Execute(command *commands.RefundPaymentCommand) (*entities.Refund, error) {
+	payment, err := u.paymentRepository.GetPaymentByOrderLeg(command.OrderId, command.Leg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payment.Status.IsRefundable() {
+		return nil, fmt.Errorf("%w: payment %d is %s", entities.ErrRefundNotAllowed, payment.ID, payment.Status)
+	}
+	if payment.ProviderPaymentId == "" {
+		return nil, fmt.Errorf("%w: payment %d has no provider payment id", entities.ErrRefundNotAllowed, payment.ID)
+	}
+	gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: payment %d has no gateway for provider %q", entities.ErrRefundNotAllowed, payment.ID, payment.Provider)
+	}
+
+	refunds, err := u.refundRepository.ListRefundsByPaymentId(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if unsettled := entities.UnsettledRefund(refunds); unsettled != nil {
+		// A retry carries out the refund the provider has not answered for, with the same idempotency key,
+		// instead of refunding again
+		if command.Amount != 0 && command.Amount != unsettled.Amount {
+			return nil, fmt.Errorf("%w: refund %d of %s is awaiting the provider, retry it first",
+				entities.ErrRefundNotAllowed, unsettled.ID, unsettled.Amount)
+		}
+		return u.refund(gateway, payment, unsettled)
+	}
+
+	captured := payment.CapturedAmount()
+	refundable := captured - entities.RefundedAmount(refunds)
+	amount := command.Amount
+	if amount == 0 {
+		amount = refundable
+	}
+	if amount <= 0 || amount > refundable {
+		return nil, &entities.RefundExceedsCapturedError{Requested: amount, Refundable: refundable}
+	}
+
+	// The refund is stored before calling the provider, checked again under a lock on the payment, so that
+	// concurrent requests account for it
+	refund, err := u.refundRepository.AddRefund(entities.NewRefund(payment, amount, command.Reason), captured)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.refund(gateway, payment, refund)
+}
+
+// refund carries out the stored refund on the provider and records the outcome, which also moves the
+// payment to refunded or partially refunded.
+func (u *RefundPaymentUseCaseImpl) refund(gateway gateways.PaymentGateway, payment *entities.Payment, refund *entities.Refund) (*entities.Refund, error) {
+	result, err := gateway.RefundCharge(context.Background(), payment, refund.Amount, refund.IdempotencyKey())
+	if err != nil {
+		println("ERROR: Failed to refund payment on", gateway.Name()+":", err.Error())
+		if !errors.Is(err, gateways.ErrProviderRejected) {
+			// The provider may have refunded before failing, e.g. on a timeout, so the refund stays pending
+			// and keeps its amount until a retry settles it
+			return nil, err
+		}
+		refund.Reject(err.Error())
+		if updateErr := u.refundRepository.UpdateRefund(refund); updateErr != nil {
+			println("ERROR: Failed to record rejected refund:", updateErr.Error())
+		}
+		return nil, err
+	}
+
+	refund.ProviderRefundId = result.ProviderRefundId
+	refund.Status = result.Status
+	if refund.Status == entities.RefundStatusRejected {
+		refund.Reject("rejected by " + gateway.Name())
+	}
+	if err := u.refundRepository.UpdateRefund(refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
@@ -0,0 +1,303 @@
+package refundpayment_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	refundpayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/refundPayment"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefundPaymentUseCaseTestSuite struct {
+	suite.Suite
+	mockPaymentRepository *mockRepositories.MockPaymentRepository
+	mockRefundRepository  *mockRepositories.MockRefundRepository
+	mockGateway           *mockGateways.MockPaymentGateway
+	useCase               refundpayment.RefundPaymentUseCase
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) SetupTest() {
+	suite.mockPaymentRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockRefundRepository = mockRepositories.NewMockRefundRepository(suite.T())
+	suite.mockGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockGateway.EXPECT().Name().Return(entities.PaymentProviderMercadoPago).Maybe()
+
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig(
+		[]gateways.PaymentGatewayRegistration{{PaymentType: entities.PaymentTypeQRCode, Gateway: suite.mockGateway}},
+		&gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeQRCode},
+	)
+	suite.Require().NoError(err)
+	suite.useCase = refundpayment.NewRefundPaymentUseCaseImpl(registry, suite.mockPaymentRepository, suite.mockRefundRepository)
+}
+
+func TestRefundPaymentUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundPaymentUseCaseTestSuite))
+}
+
+func newApprovedPayment() *entities.Payment {
+	return &entities.Payment{
+		ID:                1,
+		OrderId:           1,
+		Total:             money.MustParse("50.00"),
+		Currency:          money.BRL,
+		Status:            entities.PaymentStatusApproved,
+		Provider:          entities.PaymentProviderMercadoPago,
+		ProviderPaymentId: "123456",
+	}
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) expectPayment(payment *entities.Payment, refunds []*entities.Refund) {
+	suite.mockPaymentRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(payment, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		ListRefundsByPaymentId(payment.ID).
+		Return(refunds, nil).
+		Once()
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) expectAddRefund(amount money.Amount) {
+	suite.mockRefundRepository.EXPECT().
+		AddRefund(mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Amount == amount && refund.Status == entities.RefundStatusPending
+		}), money.MustParse("50.00")).
+		RunAndReturn(func(refund *entities.Refund, _ money.Amount) (*entities.Refund, error) {
+			refund.ID = 7
+			return refund, nil
+		}).
+		Once()
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithoutAmount_ShouldRefundEverything() {
+	// GIVEN an approved payment without refunds
+	payment := newApprovedPayment()
+	suite.expectPayment(payment, nil)
+	suite.expectAddRefund(money.MustParse("50.00"))
+
+	suite.mockGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("50.00"), "refund-7").
+		Return(&gateways.RefundResult{ProviderRefundId: "555", Status: entities.RefundStatusApproved}, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		UpdateRefund(
+			mock.MatchedBy(func(refund *entities.Refund) bool {
+				return refund.Status == entities.RefundStatusApproved && refund.ProviderRefundId == "555"
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN refunding without an amount
+	refund, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, "customer gave up"))
+
+	// THEN the whole payment should be refunded
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("50.00"), refund.Amount)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithPartialAmount_ShouldPartiallyRefund() {
+	// GIVEN an approved payment without refunds
+	payment := newApprovedPayment()
+	suite.expectPayment(payment, nil)
+	suite.expectAddRefund(money.MustParse("10.00"))
+
+	suite.mockGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("10.00"), "refund-7").
+		Return(&gateways.RefundResult{ProviderRefundId: "555", Status: entities.RefundStatusApproved}, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		UpdateRefund(mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Amount == money.MustParse("10.00") && refund.Status == entities.RefundStatusApproved
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN refunding part of the amount
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, money.MustParse("10.00"), ""))
+
+	// THEN the refund of part of the payment should be settled
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithRemainingAmount_ShouldCompleteRefund() {
+	// GIVEN a partially refunded payment with a rejected refund that does not count
+	payment := newApprovedPayment()
+	payment.Status = entities.PaymentStatusPartiallyRefunded
+	refunds := []*entities.Refund{
+		{ID: 1, Amount: money.MustParse("10.00"), Status: entities.RefundStatusApproved},
+		{ID: 2, Amount: money.MustParse("40.00"), Status: entities.RefundStatusRejected},
+	}
+	suite.expectPayment(payment, refunds)
+	suite.expectAddRefund(money.MustParse("40.00"))
+
+	suite.mockGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("40.00"), "refund-7").
+		Return(&gateways.RefundResult{ProviderRefundId: "556", Status: entities.RefundStatusApproved}, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		UpdateRefund(mock.MatchedBy(func(refund *entities.Refund) bool {
+			return refund.Amount == money.MustParse("40.00") && refund.Status == entities.RefundStatusApproved
+		})).
+		Return(nil).
+		Once()
+
+	// WHEN refunding the rest
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, ""))
+
+	// THEN the refund of the rest should be settled
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_ExceedingCapturedAmount_ShouldReturnError() {
+	// GIVEN a payment with 10.00 already refunded
+	payment := newApprovedPayment()
+	payment.Status = entities.PaymentStatusPartiallyRefunded
+	suite.expectPayment(payment, []*entities.Refund{
+		{ID: 1, Amount: money.MustParse("10.00"), Status: entities.RefundStatusApproved},
+	})
+
+	// WHEN refunding more than what is left
+	refund, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, money.MustParse("40.01"), ""))
+
+	// THEN the refund should be rejected without calling the provider
+	assert.ErrorIs(suite.T(), err, entities.ErrRefundExceedsCaptured)
+	assert.ErrorContains(suite.T(), err, "refundable 40.00")
+	assert.Nil(suite.T(), refund)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithPendingPayment_ShouldReturnError() {
+	// GIVEN a payment that was never approved
+	payment := newApprovedPayment()
+	payment.Status = entities.PaymentStatusPending
+
+	suite.mockPaymentRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(payment, nil).
+		Once()
+
+	// WHEN refunding
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, ""))
+
+	// THEN the refund should not be allowed
+	assert.ErrorIs(suite.T(), err, entities.ErrRefundNotAllowed)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithGatewayError_ShouldRecordRejectedRefund() {
+	// GIVEN Mercado Pago turning the refund down
+	payment := newApprovedPayment()
+	suite.expectPayment(payment, nil)
+	suite.expectAddRefund(money.MustParse("50.00"))
+	expectedError := fmt.Errorf("failed to refund payment 123456, %w", gateways.ErrProviderRejected)
+
+	suite.mockGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("50.00"), "refund-7").
+		Return(nil, expectedError).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		UpdateRefund(
+			mock.MatchedBy(func(refund *entities.Refund) bool {
+				return refund.Status == entities.RefundStatusRejected && refund.FailureReason == expectedError.Error()
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN refunding
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, ""))
+
+	// THEN the error should be returned and the payment left approved
+	assert.ErrorIs(suite.T(), err, expectedError)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, payment.Status)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithTimeout_ShouldKeepRefundPending() {
+	// GIVEN Mercado Pago timing out, which does not say whether it refunded
+	payment := newApprovedPayment()
+	suite.expectPayment(payment, nil)
+	suite.expectAddRefund(money.MustParse("50.00"))
+	expectedError := errors.New("HTTP request failed: context deadline exceeded")
+
+	suite.mockGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("50.00"), "refund-7").
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN refunding
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, ""))
+
+	// THEN the refund should stay pending, holding its amount
+	assert.ErrorIs(suite.T(), err, expectedError)
+	suite.mockRefundRepository.AssertNotCalled(suite.T(), "UpdateRefund", mock.Anything)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, payment.Status)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithUnsettledRefund_ShouldRetryItWithSameKey() {
+	// GIVEN a refund left pending by a timeout
+	payment := newApprovedPayment()
+	unsettled := &entities.Refund{ID: 7, PaymentId: 1, Amount: money.MustParse("50.00"), Status: entities.RefundStatusPending}
+	suite.expectPayment(payment, []*entities.Refund{unsettled})
+
+	suite.mockGateway.EXPECT().
+		RefundCharge(mock.Anything, payment, money.MustParse("50.00"), "refund-7").
+		Return(&gateways.RefundResult{ProviderRefundId: "555", Status: entities.RefundStatusApproved}, nil).
+		Once()
+
+	suite.mockRefundRepository.EXPECT().
+		UpdateRefund(unsettled).
+		Return(nil).
+		Once()
+
+	// WHEN the refund is retried
+	refund, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, ""))
+
+	// THEN the same refund should be carried out instead of a new one
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), unsettled, refund)
+	assert.Equal(suite.T(), entities.RefundStatusApproved, refund.Status)
+	suite.mockRefundRepository.AssertNotCalled(suite.T(), "AddRefund", mock.Anything, mock.Anything)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithUnsettledRefundOfOtherAmount_ShouldReturnError() {
+	// GIVEN a refund of 50.00 left pending by a timeout
+	payment := newApprovedPayment()
+	unsettled := &entities.Refund{ID: 7, PaymentId: 1, Amount: money.MustParse("50.00"), Status: entities.RefundStatusPending}
+	suite.expectPayment(payment, []*entities.Refund{unsettled})
+
+	// WHEN asking for another amount
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, money.MustParse("10.00"), ""))
+
+	// THEN it should be refused without calling the provider
+	assert.ErrorIs(suite.T(), err, entities.ErrRefundNotAllowed)
+	suite.mockGateway.AssertNotCalled(suite.T(), "RefundCharge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RefundPaymentUseCaseTestSuite) Test_RefundPayment_WithUnknownProvider_ShouldReturnError() {
+	// GIVEN an approved payment created on a provider that is no longer registered
+	payment := newApprovedPayment()
+	payment.Provider = "legacy"
+
+	suite.mockPaymentRepository.EXPECT().
+		GetPaymentByOrderLeg(uint(1), 0).
+		Return(payment, nil).
+		Once()
+
+	// WHEN refunding
+	_, err := suite.useCase.Execute(commands.NewRefundPaymentCommand(1, 0, ""))
+
+	// THEN the refund should not be allowed nor recorded
+	assert.ErrorIs(suite.T(), err, entities.ErrRefundNotAllowed)
+	suite.mockRefundRepository.AssertNotCalled(suite.T(), "AddRefund", mock.Anything, mock.Anything)
+}
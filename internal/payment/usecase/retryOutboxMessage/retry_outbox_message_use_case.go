@@ -0,0 +1,10 @@
+package retryoutboxmessage
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+type RetryOutboxMessageUseCase interface {
+	Execute(command *commands.RetryOutboxMessageCommand) (*entities.OutboxMessage, error)
+}
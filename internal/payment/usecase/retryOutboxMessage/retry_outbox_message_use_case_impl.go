@@ -0,0 +1,35 @@
+package retryoutboxmessage
+
+import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ RetryOutboxMessageUseCase = (*RetryOutboxMessageUseCaseImpl)(nil)
+)
+
+type RetryOutboxMessageUseCaseImpl struct {
+	outboxRepository repositories.OutboxRepository
+}
+
+func NewRetryOutboxMessageUseCaseImpl(outboxRepository repositories.OutboxRepository) *RetryOutboxMessageUseCaseImpl {
+	return &RetryOutboxMessageUseCaseImpl{outboxRepository: outboxRepository}
+}
+
+func (u *RetryOutboxMessageUseCaseImpl) Execute(command *commands.RetryOutboxMessageCommand) (*entities.OutboxMessage, error) {
+	message, err := u.outboxRepository.GetOutboxMessage(command.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := message.Retry(); err != nil {
+		return nil, err
+	}
+
+	if err := u.outboxRepository.UpdateOutboxMessage(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,89 @@
+package retryoutboxmessage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	retryoutboxmessage "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/retryOutboxMessage"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetryOutboxMessageUseCaseTestSuite struct {
+	suite.Suite
+	mockOutboxRepository *mockRepositories.MockOutboxRepository
+	useCase              retryoutboxmessage.RetryOutboxMessageUseCase
+}
+
+func (suite *RetryOutboxMessageUseCaseTestSuite) SetupTest() {
+	suite.mockOutboxRepository = mockRepositories.NewMockOutboxRepository(suite.T())
+	suite.useCase = retryoutboxmessage.NewRetryOutboxMessageUseCaseImpl(suite.mockOutboxRepository)
+}
+
+func TestRetryOutboxMessageUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryOutboxMessageUseCaseTestSuite))
+}
+
+func (suite *RetryOutboxMessageUseCaseTestSuite) Test_Retry_WithFailedMessage_ShouldRescheduleIt() {
+	// GIVEN a message that exhausted its attempts
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.ID = 7
+	message.MarkAttemptFailed(errors.New("order service unavailable"), time.Now(), 1)
+
+	suite.mockOutboxRepository.EXPECT().
+		GetOutboxMessage(uint(7)).
+		Return(message, nil).
+		Once()
+
+	suite.mockOutboxRepository.EXPECT().
+		UpdateOutboxMessage(message).
+		Return(nil).
+		Once()
+
+	// WHEN retrying it
+	result, err := suite.useCase.Execute(commands.NewRetryOutboxMessageCommand(7))
+
+	// THEN it should be pending again
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entities.OutboxStatusPending, result.Status)
+	assert.Zero(suite.T(), result.Attempts)
+}
+
+func (suite *RetryOutboxMessageUseCaseTestSuite) Test_Retry_WithDeliveredMessage_ShouldReturnError() {
+	// GIVEN a delivered message
+	message := entities.NewOrderStatusOutboxMessage(1, entities.OrderStatusPreparing)
+	message.MarkDelivered()
+
+	suite.mockOutboxRepository.EXPECT().
+		GetOutboxMessage(uint(7)).
+		Return(message, nil).
+		Once()
+
+	// WHEN retrying it
+	result, err := suite.useCase.Execute(commands.NewRetryOutboxMessageCommand(7))
+
+	// THEN it should be rejected without saving
+	assert.ErrorIs(suite.T(), err, entities.ErrOutboxMessageDelivered)
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *RetryOutboxMessageUseCaseTestSuite) Test_Retry_WithUnknownMessage_ShouldReturnError() {
+	// GIVEN an unknown message id
+	expectedError := errors.New("record not found")
+
+	suite.mockOutboxRepository.EXPECT().
+		GetOutboxMessage(uint(7)).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN retrying it
+	result, err := suite.useCase.Execute(commands.NewRetryOutboxMessageCommand(7))
+
+	// THEN error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Nil(suite.T(), result)
+}
@@ -1,6 +1,7 @@
 package updatepayment
 
 import (
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 )
@@ -18,11 +19,44 @@ func NewUpdatePaymentUseCaseImpl(paymentRepository repositories.PaymentRepositor
 }
 
 func (u *UpdatePaymentUseCaseImpl) Execute(command *commands.UpdatePaymentStatusCommand) error {
-	payment, err := u.paymentRepository.GetPaymentByOrderId(command.OrderId)
+	payment, err := u.getPayment(command)
 	if err != nil {
 		return err
 	}
 
-	payment.Status = command.Status
-	return u.paymentRepository.UpdatePayment(payment)
+	previousStatus := payment.Status
+	if err := payment.TransitionTo(command.Status); err != nil {
+		return err
+	}
+
+	if command.ProviderPaymentId != "" {
+		payment.ProviderPaymentId = command.ProviderPaymentId
+	}
+	if command.CapturedAmount > 0 {
+		// Also recorded when the capture and its notification race and the status is already approved
+		payment.CapturedTotal = command.CapturedAmount
+	}
+
+	if payment.Status == previousStatus {
+		return u.paymentRepository.UpdatePayment(payment)
+	}
+
+	change := entities.NewPaymentStatusChange(payment, previousStatus, command.Source, command.NotificationId)
+
+	var message *entities.OutboxMessage
+	if payment.Status == entities.PaymentStatusApproved {
+		// The order moves to "Preparing" once paid, which for card payments means captured rather than
+		// authorized; the outbox dispatcher delivers it to the Order Service
+		message = entities.NewOrderStatusOutboxMessage(payment.OrderId, entities.OrderStatusPreparing)
+	}
+	return u.paymentRepository.UpdatePaymentStatus(payment, change, message)
+}
+
+// getPayment finds the payment attempt given by id, otherwise the effective payment of the order or the
+// given leg of an order paid in split legs.
+func (u *UpdatePaymentUseCaseImpl) getPayment(command *commands.UpdatePaymentStatusCommand) (*entities.Payment, error) {
+	if command.PaymentId != 0 {
+		return u.paymentRepository.GetPaymentById(command.PaymentId)
+	}
+	return u.paymentRepository.GetPaymentByOrderLeg(command.OrderId, command.Leg)
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
 	updatepayment "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/updatePayment"
 	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -30,42 +32,56 @@ func TestUpdatePaymentUseCaseTestSuite(t *testing.T) {
 func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithExistingPayment_ShouldUpdate() {
 	// GIVEN an existing payment
 	orderId := uint(1)
-	command := commands.NewUpdatePaymentStatusCommand(orderId, "Approved")
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusApproved, entities.PaymentStatusChangeSourceWebhook, "123456789")
 
 	payment := &entities.Payment{
 		ID:      1,
 		OrderId: orderId,
-		Status:  "pending",
+		Status:  entities.PaymentStatusPending,
 	}
 
 	suite.mockRepository.EXPECT().
-		GetPaymentByOrderId(orderId).
+		GetPaymentByOrderLeg(orderId, 0).
 		Return(payment, nil).
 		Once()
 
 	suite.mockRepository.EXPECT().
-		UpdatePayment(payment).
+		UpdatePaymentStatus(
+			payment,
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.PaymentId == 1 &&
+					change.OrderId == orderId &&
+					change.FromStatus == entities.PaymentStatusPending &&
+					change.ToStatus == entities.PaymentStatusApproved &&
+					change.Source == entities.PaymentStatusChangeSourceWebhook &&
+					change.NotificationId == "123456789"
+			}),
+			mock.MatchedBy(func(message *entities.OutboxMessage) bool {
+				return message.OrderId == orderId &&
+					message.OrderStatus == entities.OrderStatusPreparing &&
+					message.Status == entities.OutboxStatusPending
+			})).
 		Return(nil).
 		Once()
 
 	// WHEN updating the payment status
 	err := suite.useCase.Execute(command)
 
-	// THEN the operation should complete without errors
+	// THEN the payment should be approved and the order update enqueued
 	assert.NoError(suite.T(), err)
-	assert.Equal(suite.T(), "Approved", payment.Status)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, payment.Status)
 	suite.mockRepository.AssertExpectations(suite.T())
 }
 
 func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithNonExistentPayment_ShouldReturnError() {
 	// GIVEN a non-existent payment
 	orderId := uint(999)
-	command := commands.NewUpdatePaymentStatusCommand(orderId, "Approved")
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusApproved, entities.PaymentStatusChangeSourceWebhook, "123456789")
 
 	expectedError := errors.New("payment not found")
 
 	suite.mockRepository.EXPECT().
-		GetPaymentByOrderId(orderId).
+		GetPaymentByOrderLeg(orderId, 0).
 		Return(nil, expectedError).
 		Once()
 
@@ -81,23 +97,29 @@ func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithNonExis
 func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithRepositoryFailure_ShouldReturnError() {
 	// GIVEN an existing payment
 	orderId := uint(1)
-	command := commands.NewUpdatePaymentStatusCommand(orderId, "Declined")
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusDeclined, entities.PaymentStatusChangeSourceWebhook, "123456789")
 
 	payment := &entities.Payment{
 		ID:      1,
 		OrderId: orderId,
-		Status:  "pending",
+		Status:  entities.PaymentStatusPending,
 	}
 
 	expectedError := errors.New("database error")
 
 	suite.mockRepository.EXPECT().
-		GetPaymentByOrderId(orderId).
+		GetPaymentByOrderLeg(orderId, 0).
 		Return(payment, nil).
 		Once()
 
 	suite.mockRepository.EXPECT().
-		UpdatePayment(payment).
+		UpdatePaymentStatus(
+			payment,
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.FromStatus == entities.PaymentStatusPending &&
+					change.ToStatus == entities.PaymentStatusDeclined
+			}),
+			(*entities.OutboxMessage)(nil)).
 		Return(expectedError).
 		Once()
 
@@ -109,3 +131,174 @@ func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithReposit
 	assert.Equal(suite.T(), expectedError, err)
 	suite.mockRepository.AssertExpectations(suite.T())
 }
+
+func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithInvalidTransition_ShouldReturnConflictError() {
+	// GIVEN an already approved payment
+	orderId := uint(1)
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusDeclined, entities.PaymentStatusChangeSourceWebhook, "123456789")
+
+	payment := &entities.Payment{
+		ID:      1,
+		OrderId: orderId,
+		Status:  entities.PaymentStatusApproved,
+	}
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(orderId, 0).
+		Return(payment, nil).
+		Once()
+
+	// WHEN a late declined status arrives
+	err := suite.useCase.Execute(command)
+
+	// THEN the transition should be rejected and the payment left untouched
+	assert.Error(suite.T(), err)
+	assert.ErrorIs(suite.T(), err, entities.ErrInvalidStatusTransition)
+	assert.Equal(suite.T(), entities.PaymentStatusApproved, payment.Status)
+	suite.mockRepository.AssertExpectations(suite.T())
+}
+
+func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithAlreadyApprovedPayment_ShouldNotEnqueueAgain() {
+	// GIVEN a payment that is already approved
+	orderId := uint(1)
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusApproved, entities.PaymentStatusChangeSourceWebhook, "123456789")
+
+	payment := &entities.Payment{
+		ID:      1,
+		OrderId: orderId,
+		Status:  entities.PaymentStatusApproved,
+	}
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(orderId, 0).
+		Return(payment, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePayment(payment).
+		Return(nil).
+		Once()
+
+	// WHEN the approval is applied again
+	err := suite.useCase.Execute(command)
+
+	// THEN no status change or second order status update should be recorded
+	assert.NoError(suite.T(), err)
+	suite.mockRepository.AssertNotCalled(suite.T(), "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithProviderPaymentId_ShouldStoreIt() {
+	// GIVEN a webhook update that carries the provider payment id
+	orderId := uint(1)
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusDeclined, entities.PaymentStatusChangeSourceWebhook, "123456789")
+	command.ProviderPaymentId = "987"
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(orderId, 0).
+		Return(&entities.Payment{ID: 1, OrderId: orderId, Status: entities.PaymentStatusPending}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(
+			mock.MatchedBy(func(p *entities.Payment) bool {
+				return p.Status == entities.PaymentStatusDeclined && p.ProviderPaymentId == "987"
+			}),
+			mock.Anything,
+			(*entities.OutboxMessage)(nil)).
+		Return(nil).
+		Once()
+
+	// WHEN updating the payment
+	err := suite.useCase.Execute(command)
+
+	// THEN the provider payment id should be stored with the status change
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithCapturedAmount_ShouldApproveAndMovePreparing() {
+	// GIVEN a card authorization captured in part
+	orderId := uint(1)
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusApproved, entities.PaymentStatusChangeSourceAPI, "")
+	command.CapturedAmount = money.MustParse("42.00")
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(orderId, 0).
+		Return(&entities.Payment{ID: 1, OrderId: orderId, Total: money.MustParse("50.00"), Status: entities.PaymentStatusAuthorized}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(
+			mock.MatchedBy(func(p *entities.Payment) bool {
+				return p.Status == entities.PaymentStatusApproved && p.CapturedAmount() == money.MustParse("42.00")
+			}),
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.FromStatus == entities.PaymentStatusAuthorized
+			}),
+			mock.MatchedBy(func(message *entities.OutboxMessage) bool {
+				return message.OrderStatus == entities.OrderStatusPreparing
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN updating the payment
+	err := suite.useCase.Execute(command)
+
+	// THEN the captured amount should be stored and the order moved to preparing
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithPaymentId_ShouldUpdateThatAttempt() {
+	// GIVEN an update for a specific payment attempt
+	command := commands.NewUpdatePaymentStatusCommand(1, entities.PaymentStatusDeclined, entities.PaymentStatusChangeSourceWebhook, "123456789")
+	command.PaymentId = 7
+
+	suite.mockRepository.EXPECT().
+		GetPaymentById(uint(7)).
+		Return(&entities.Payment{ID: 7, OrderId: 1, Status: entities.PaymentStatusPending}, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(
+			mock.MatchedBy(func(p *entities.Payment) bool { return p.ID == 7 }),
+			mock.Anything,
+			(*entities.OutboxMessage)(nil)).
+		Return(nil).
+		Once()
+
+	// WHEN updating the payment
+	err := suite.useCase.Execute(command)
+
+	// THEN that attempt should be updated instead of the effective payment of the order
+	assert.NoError(suite.T(), err)
+	suite.mockRepository.AssertNotCalled(suite.T(), "GetPaymentByOrderLeg", mock.Anything, mock.Anything)
+}
+
+func (suite *UpdatePaymentUseCaseTestSuite) Test_UpdatePaymentStatus_WithOutboxFailure_ShouldReturnError() {
+	// GIVEN a pending payment
+	orderId := uint(1)
+	command := commands.NewUpdatePaymentStatusCommand(orderId, entities.PaymentStatusApproved, entities.PaymentStatusChangeSourceWebhook, "123456789")
+
+	payment := &entities.Payment{
+		ID:      1,
+		OrderId: orderId,
+		Status:  entities.PaymentStatusPending,
+	}
+
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		GetPaymentByOrderLeg(orderId, 0).
+		Return(payment, nil).
+		Once()
+
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(payment, mock.Anything, mock.Anything).
+		Return(expectedError).
+		Once()
+
+	// WHEN the transaction fails
+	err := suite.useCase.Execute(command)
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+}
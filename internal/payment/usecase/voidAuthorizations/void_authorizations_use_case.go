@@ -0,0 +1,8 @@
+package voidauthorizations
+
+import "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+
+type VoidAuthorizationsUseCase interface {
+	// Execute voids authorizations that were not captured in time and returns how many were voided.
+	Execute(command *commands.VoidAuthorizationsCommand) (int, error)
+}
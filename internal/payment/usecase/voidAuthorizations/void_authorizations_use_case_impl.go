@@ -0,0 +1,73 @@
+package voidauthorizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/repositories"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+)
+
+var (
+	_ VoidAuthorizationsUseCase = (*VoidAuthorizationsUseCaseImpl)(nil)
+)
+
+type VoidAuthorizationsUseCaseImpl struct {
+	gatewayRegistry   *gateways.PaymentGatewayRegistry
+	paymentRepository repositories.PaymentRepository
+}
+
+func NewVoidAuthorizationsUseCaseImpl(
+	gatewayRegistry *gateways.PaymentGatewayRegistry,
+	paymentRepository repositories.PaymentRepository) *VoidAuthorizationsUseCaseImpl {
+	return &VoidAuthorizationsUseCaseImpl{
+		gatewayRegistry:   gatewayRegistry,
+		paymentRepository: paymentRepository,
+	}
+}
+
+func (u *VoidAuthorizationsUseCaseImpl) Execute(command *commands.VoidAuthorizationsCommand) (int, error) {
+	payments, err := u.paymentRepository.ClaimStaleAuthorizedPayments(time.Now(), command.AuthorizedBefore, command.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	voided := 0
+	var errs []error
+	for _, payment := range payments {
+		if err := u.void(payment); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		voided++
+	}
+
+	return voided, errors.Join(errs...)
+}
+
+func (u *VoidAuthorizationsUseCaseImpl) void(payment *entities.Payment) error {
+	previousStatus := payment.Status
+	if err := payment.TransitionTo(entities.PaymentStatusCancelled); err != nil {
+		return err
+	}
+
+	// Release the hold on the customer's card first: if this fails the payment stays authorized and
+	// the next sweep tries again
+	gateway, ok := u.gatewayRegistry.ForProvider(payment.Provider)
+	if !ok {
+		return fmt.Errorf("payment %d has no gateway for provider %q", payment.ID, payment.Provider)
+	}
+	if err := gateway.CancelCharge(context.Background(), payment); err != nil {
+		println("ERROR: Failed to void authorization on", gateway.Name()+":", err.Error())
+		return err
+	}
+
+	// The order was never paid; the outbox dispatcher tells the Order Service
+	change := entities.NewPaymentStatusChange(payment, previousStatus, entities.PaymentStatusChangeSourceReconciliation, "")
+	message := entities.NewOrderStatusOutboxMessage(payment.OrderId, entities.OrderStatusCancelled)
+	return u.paymentRepository.UpdatePaymentStatus(payment, change, message)
+}
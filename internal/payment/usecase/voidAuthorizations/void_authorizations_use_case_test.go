@@ -0,0 +1,138 @@
+package voidauthorizations_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/gateways"
+	"github.com/abattassini/tc-fiap-payment/internal/payment/usecase/commands"
+	voidauthorizations "github.com/abattassini/tc-fiap-payment/internal/payment/usecase/voidAuthorizations"
+	mockRepositories "github.com/abattassini/tc-fiap-payment/mocks/payment/domain/repositories"
+	mockGateways "github.com/abattassini/tc-fiap-payment/mocks/payment/gateways"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type VoidAuthorizationsUseCaseTestSuite struct {
+	suite.Suite
+	mockRepository *mockRepositories.MockPaymentRepository
+	mockGateway    *mockGateways.MockPaymentGateway
+	useCase        voidauthorizations.VoidAuthorizationsUseCase
+}
+
+func (suite *VoidAuthorizationsUseCaseTestSuite) SetupTest() {
+	suite.mockRepository = mockRepositories.NewMockPaymentRepository(suite.T())
+	suite.mockGateway = mockGateways.NewMockPaymentGateway(suite.T())
+	suite.mockGateway.EXPECT().Name().Return(entities.PaymentProviderStripe).Maybe()
+
+	registry, err := gateways.NewPaymentGatewayRegistryWithConfig([]gateways.PaymentGatewayRegistration{
+		{PaymentType: entities.PaymentTypeCard, Gateway: suite.mockGateway},
+	}, &gateways.PaymentGatewayRegistryConfig{DefaultPaymentType: entities.PaymentTypeCard})
+	suite.Require().NoError(err)
+	suite.useCase = voidauthorizations.NewVoidAuthorizationsUseCaseImpl(registry, suite.mockRepository)
+}
+
+func TestVoidAuthorizationsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(VoidAuthorizationsUseCaseTestSuite))
+}
+
+func newAuthorizedPayment(id uint) *entities.Payment {
+	return &entities.Payment{
+		ID:                id,
+		OrderId:           10 + id,
+		Type:              entities.PaymentTypeCard,
+		Status:            entities.PaymentStatusAuthorized,
+		Provider:          entities.PaymentProviderStripe,
+		ProviderPaymentId: "pi_1",
+		Active:            true,
+	}
+}
+
+func (suite *VoidAuthorizationsUseCaseTestSuite) Test_VoidAuthorizations_WithStaleAuthorization_ShouldVoidAndNotifyOrderService() {
+	// GIVEN an authorization that was never captured
+	authorizedBefore := time.Now().Add(-48 * time.Hour)
+	payment := newAuthorizedPayment(1)
+
+	suite.mockRepository.EXPECT().
+		ClaimStaleAuthorizedPayments(mock.Anything, authorizedBefore, 50).
+		Return([]*entities.Payment{payment}, nil).
+		Once()
+	suite.mockGateway.EXPECT().
+		CancelCharge(mock.Anything, payment).
+		Return(nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(
+			payment,
+			mock.MatchedBy(func(change *entities.PaymentStatusChange) bool {
+				return change.FromStatus == entities.PaymentStatusAuthorized &&
+					change.ToStatus == entities.PaymentStatusCancelled &&
+					change.Source == entities.PaymentStatusChangeSourceReconciliation
+			}),
+			mock.MatchedBy(func(message *entities.OutboxMessage) bool {
+				return message.OrderId == 11 && message.OrderStatus == entities.OrderStatusCancelled
+			})).
+		Return(nil).
+		Once()
+
+	// WHEN sweeping
+	voided, err := suite.useCase.Execute(commands.NewVoidAuthorizationsCommand(authorizedBefore, 50))
+
+	// THEN the hold should be released and the payment cancelled
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, voided)
+	assert.Equal(suite.T(), entities.PaymentStatusCancelled, payment.Status)
+	assert.False(suite.T(), payment.Active)
+}
+
+func (suite *VoidAuthorizationsUseCaseTestSuite) Test_VoidAuthorizations_WithGatewayError_ShouldKeepPaymentAndContinue() {
+	// GIVEN two stale authorizations, the first of which the provider fails to void
+	first := newAuthorizedPayment(1)
+	second := newAuthorizedPayment(2)
+	expectedError := errors.New("unexpected status: 500")
+
+	suite.mockRepository.EXPECT().
+		ClaimStaleAuthorizedPayments(mock.Anything, mock.Anything, 50).
+		Return([]*entities.Payment{first, second}, nil).
+		Once()
+	suite.mockGateway.EXPECT().
+		CancelCharge(mock.Anything, first).
+		Return(expectedError).
+		Once()
+	suite.mockGateway.EXPECT().
+		CancelCharge(mock.Anything, second).
+		Return(nil).
+		Once()
+	suite.mockRepository.EXPECT().
+		UpdatePaymentStatus(second, mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	// WHEN sweeping
+	voided, err := suite.useCase.Execute(commands.NewVoidAuthorizationsCommand(time.Now(), 50))
+
+	// THEN the second payment should still be voided
+	assert.ErrorIs(suite.T(), err, expectedError)
+	assert.Equal(suite.T(), 1, voided)
+	suite.mockRepository.AssertNotCalled(suite.T(), "UpdatePaymentStatus", first, mock.Anything, mock.Anything)
+}
+
+func (suite *VoidAuthorizationsUseCaseTestSuite) Test_VoidAuthorizations_WithListError_ShouldReturnError() {
+	// GIVEN a database that cannot be queried
+	expectedError := errors.New("database error")
+
+	suite.mockRepository.EXPECT().
+		ClaimStaleAuthorizedPayments(mock.Anything, mock.Anything, 50).
+		Return(nil, expectedError).
+		Once()
+
+	// WHEN sweeping
+	voided, err := suite.useCase.Execute(commands.NewVoidAuthorizationsCommand(time.Now(), 50))
+
+	// THEN the error should be returned
+	assert.Equal(suite.T(), expectedError, err)
+	assert.Zero(suite.T(), voided)
+}
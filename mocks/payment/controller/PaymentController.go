@@ -4,6 +4,7 @@ package mocks
 
 import (
 	dto "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -20,6 +21,125 @@ func (_m *MockPaymentController) EXPECT() *MockPaymentController_Expecter {
 	return &MockPaymentController_Expecter{mock: &_m.Mock}
 }
 
+// CancelPayment provides a mock function with given fields: orderId, leg
+func (_m *MockPaymentController) CancelPayment(orderId uint, leg int) (*dto.GetPaymentResponseDto, error) {
+	ret := _m.Called(orderId, leg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelPayment")
+	}
+
+	var r0 *dto.GetPaymentResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int) (*dto.GetPaymentResponseDto, error)); ok {
+		return rf(orderId, leg)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int) *dto.GetPaymentResponseDto); ok {
+		r0 = rf(orderId, leg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetPaymentResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int) error); ok {
+		r1 = rf(orderId, leg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentController_CancelPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelPayment'
+type MockPaymentController_CancelPayment_Call struct {
+	*mock.Call
+}
+
+// CancelPayment is a helper method to define mock.On call
+//   - orderId uint
+//   - leg int
+func (_e *MockPaymentController_Expecter) CancelPayment(orderId interface{}, leg interface{}) *MockPaymentController_CancelPayment_Call {
+	return &MockPaymentController_CancelPayment_Call{Call: _e.mock.On("CancelPayment", orderId, leg)}
+}
+
+func (_c *MockPaymentController_CancelPayment_Call) Run(run func(orderId uint, leg int)) *MockPaymentController_CancelPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockPaymentController_CancelPayment_Call) Return(_a0 *dto.GetPaymentResponseDto, _a1 error) *MockPaymentController_CancelPayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentController_CancelPayment_Call) RunAndReturn(run func(uint, int) (*dto.GetPaymentResponseDto, error)) *MockPaymentController_CancelPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CapturePayment provides a mock function with given fields: orderId, leg, captureRequest
+func (_m *MockPaymentController) CapturePayment(orderId uint, leg int, captureRequest *dto.CapturePaymentRequestDto) (*dto.GetPaymentResponseDto, error) {
+	ret := _m.Called(orderId, leg, captureRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CapturePayment")
+	}
+
+	var r0 *dto.GetPaymentResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, *dto.CapturePaymentRequestDto) (*dto.GetPaymentResponseDto, error)); ok {
+		return rf(orderId, leg, captureRequest)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, *dto.CapturePaymentRequestDto) *dto.GetPaymentResponseDto); ok {
+		r0 = rf(orderId, leg, captureRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetPaymentResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, *dto.CapturePaymentRequestDto) error); ok {
+		r1 = rf(orderId, leg, captureRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentController_CapturePayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CapturePayment'
+type MockPaymentController_CapturePayment_Call struct {
+	*mock.Call
+}
+
+// CapturePayment is a helper method to define mock.On call
+//   - orderId uint
+//   - leg int
+//   - captureRequest *dto.CapturePaymentRequestDto
+func (_e *MockPaymentController_Expecter) CapturePayment(orderId interface{}, leg interface{}, captureRequest interface{}) *MockPaymentController_CapturePayment_Call {
+	return &MockPaymentController_CapturePayment_Call{Call: _e.mock.On("CapturePayment", orderId, leg, captureRequest)}
+}
+
+func (_c *MockPaymentController_CapturePayment_Call) Run(run func(orderId uint, leg int, captureRequest *dto.CapturePaymentRequestDto)) *MockPaymentController_CapturePayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(int), args[2].(*dto.CapturePaymentRequestDto))
+	})
+	return _c
+}
+
+func (_c *MockPaymentController_CapturePayment_Call) Return(_a0 *dto.GetPaymentResponseDto, _a1 error) *MockPaymentController_CapturePayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentController_CapturePayment_Call) RunAndReturn(run func(uint, int, *dto.CapturePaymentRequestDto) (*dto.GetPaymentResponseDto, error)) *MockPaymentController_CapturePayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreatePayment provides a mock function with given fields: addPaymentRequest
 func (_m *MockPaymentController) CreatePayment(addPaymentRequest *dto.AddPaymentRequestDto) (string, error) {
 	ret := _m.Called(addPaymentRequest)
@@ -76,6 +196,122 @@ func (_c *MockPaymentController_CreatePayment_Call) RunAndReturn(run func(*dto.A
 	return _c
 }
 
+// CreateSplitPayment provides a mock function with given fields: addPaymentRequest
+func (_m *MockPaymentController) CreateSplitPayment(addPaymentRequest *dto.AddPaymentRequestDto) ([]*dto.PaymentLegCodeResponseDto, error) {
+	ret := _m.Called(addPaymentRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSplitPayment")
+	}
+
+	var r0 []*dto.PaymentLegCodeResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*dto.AddPaymentRequestDto) ([]*dto.PaymentLegCodeResponseDto, error)); ok {
+		return rf(addPaymentRequest)
+	}
+	if rf, ok := ret.Get(0).(func(*dto.AddPaymentRequestDto) []*dto.PaymentLegCodeResponseDto); ok {
+		r0 = rf(addPaymentRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.PaymentLegCodeResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*dto.AddPaymentRequestDto) error); ok {
+		r1 = rf(addPaymentRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentController_CreateSplitPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSplitPayment'
+type MockPaymentController_CreateSplitPayment_Call struct {
+	*mock.Call
+}
+
+// CreateSplitPayment is a helper method to define mock.On call
+//   - addPaymentRequest *dto.AddPaymentRequestDto
+func (_e *MockPaymentController_Expecter) CreateSplitPayment(addPaymentRequest interface{}) *MockPaymentController_CreateSplitPayment_Call {
+	return &MockPaymentController_CreateSplitPayment_Call{Call: _e.mock.On("CreateSplitPayment", addPaymentRequest)}
+}
+
+func (_c *MockPaymentController_CreateSplitPayment_Call) Run(run func(addPaymentRequest *dto.AddPaymentRequestDto)) *MockPaymentController_CreateSplitPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*dto.AddPaymentRequestDto))
+	})
+	return _c
+}
+
+func (_c *MockPaymentController_CreateSplitPayment_Call) Return(_a0 []*dto.PaymentLegCodeResponseDto, _a1 error) *MockPaymentController_CreateSplitPayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentController_CreateSplitPayment_Call) RunAndReturn(run func(*dto.AddPaymentRequestDto) ([]*dto.PaymentLegCodeResponseDto, error)) *MockPaymentController_CreateSplitPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPaymentAttemptsByOrderId provides a mock function with given fields: orderId
+func (_m *MockPaymentController) GetPaymentAttemptsByOrderId(orderId uint) ([]*dto.PaymentAttemptResponseDto, error) {
+	ret := _m.Called(orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentAttemptsByOrderId")
+	}
+
+	var r0 []*dto.PaymentAttemptResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*dto.PaymentAttemptResponseDto, error)); ok {
+		return rf(orderId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*dto.PaymentAttemptResponseDto); ok {
+		r0 = rf(orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.PaymentAttemptResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentController_GetPaymentAttemptsByOrderId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentAttemptsByOrderId'
+type MockPaymentController_GetPaymentAttemptsByOrderId_Call struct {
+	*mock.Call
+}
+
+// GetPaymentAttemptsByOrderId is a helper method to define mock.On call
+//   - orderId uint
+func (_e *MockPaymentController_Expecter) GetPaymentAttemptsByOrderId(orderId interface{}) *MockPaymentController_GetPaymentAttemptsByOrderId_Call {
+	return &MockPaymentController_GetPaymentAttemptsByOrderId_Call{Call: _e.mock.On("GetPaymentAttemptsByOrderId", orderId)}
+}
+
+func (_c *MockPaymentController_GetPaymentAttemptsByOrderId_Call) Run(run func(orderId uint)) *MockPaymentController_GetPaymentAttemptsByOrderId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentController_GetPaymentAttemptsByOrderId_Call) Return(_a0 []*dto.PaymentAttemptResponseDto, _a1 error) *MockPaymentController_GetPaymentAttemptsByOrderId_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentController_GetPaymentAttemptsByOrderId_Call) RunAndReturn(run func(uint) ([]*dto.PaymentAttemptResponseDto, error)) *MockPaymentController_GetPaymentAttemptsByOrderId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPaymentByOrderId provides a mock function with given fields: orderId
 func (_m *MockPaymentController) GetPaymentByOrderId(orderId uint) (*dto.GetPaymentResponseDto, error) {
 	ret := _m.Called(orderId)
@@ -134,6 +370,64 @@ func (_c *MockPaymentController_GetPaymentByOrderId_Call) RunAndReturn(run func(
 	return _c
 }
 
+// GetPaymentHistoryByOrderId provides a mock function with given fields: orderId
+func (_m *MockPaymentController) GetPaymentHistoryByOrderId(orderId uint) ([]*dto.PaymentStatusChangeResponseDto, error) {
+	ret := _m.Called(orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentHistoryByOrderId")
+	}
+
+	var r0 []*dto.PaymentStatusChangeResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*dto.PaymentStatusChangeResponseDto, error)); ok {
+		return rf(orderId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*dto.PaymentStatusChangeResponseDto); ok {
+		r0 = rf(orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.PaymentStatusChangeResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentController_GetPaymentHistoryByOrderId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentHistoryByOrderId'
+type MockPaymentController_GetPaymentHistoryByOrderId_Call struct {
+	*mock.Call
+}
+
+// GetPaymentHistoryByOrderId is a helper method to define mock.On call
+//   - orderId uint
+func (_e *MockPaymentController_Expecter) GetPaymentHistoryByOrderId(orderId interface{}) *MockPaymentController_GetPaymentHistoryByOrderId_Call {
+	return &MockPaymentController_GetPaymentHistoryByOrderId_Call{Call: _e.mock.On("GetPaymentHistoryByOrderId", orderId)}
+}
+
+func (_c *MockPaymentController_GetPaymentHistoryByOrderId_Call) Run(run func(orderId uint)) *MockPaymentController_GetPaymentHistoryByOrderId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentController_GetPaymentHistoryByOrderId_Call) Return(_a0 []*dto.PaymentStatusChangeResponseDto, _a1 error) *MockPaymentController_GetPaymentHistoryByOrderId_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentController_GetPaymentHistoryByOrderId_Call) RunAndReturn(run func(uint) ([]*dto.PaymentStatusChangeResponseDto, error)) *MockPaymentController_GetPaymentHistoryByOrderId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPaymentStatusByOrderId provides a mock function with given fields: orderId
 func (_m *MockPaymentController) GetPaymentStatusByOrderId(orderId uint) (string, error) {
 	ret := _m.Called(orderId)
@@ -190,6 +484,66 @@ func (_c *MockPaymentController_GetPaymentStatusByOrderId_Call) RunAndReturn(run
 	return _c
 }
 
+// RefundPayment provides a mock function with given fields: orderId, leg, refundRequest
+func (_m *MockPaymentController) RefundPayment(orderId uint, leg int, refundRequest *dto.RefundPaymentRequestDto) (*dto.RefundResponseDto, error) {
+	ret := _m.Called(orderId, leg, refundRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefundPayment")
+	}
+
+	var r0 *dto.RefundResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, *dto.RefundPaymentRequestDto) (*dto.RefundResponseDto, error)); ok {
+		return rf(orderId, leg, refundRequest)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, *dto.RefundPaymentRequestDto) *dto.RefundResponseDto); ok {
+		r0 = rf(orderId, leg, refundRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.RefundResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, *dto.RefundPaymentRequestDto) error); ok {
+		r1 = rf(orderId, leg, refundRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentController_RefundPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefundPayment'
+type MockPaymentController_RefundPayment_Call struct {
+	*mock.Call
+}
+
+// RefundPayment is a helper method to define mock.On call
+//   - orderId uint
+//   - leg int
+//   - refundRequest *dto.RefundPaymentRequestDto
+func (_e *MockPaymentController_Expecter) RefundPayment(orderId interface{}, leg interface{}, refundRequest interface{}) *MockPaymentController_RefundPayment_Call {
+	return &MockPaymentController_RefundPayment_Call{Call: _e.mock.On("RefundPayment", orderId, leg, refundRequest)}
+}
+
+func (_c *MockPaymentController_RefundPayment_Call) Run(run func(orderId uint, leg int, refundRequest *dto.RefundPaymentRequestDto)) *MockPaymentController_RefundPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(int), args[2].(*dto.RefundPaymentRequestDto))
+	})
+	return _c
+}
+
+func (_c *MockPaymentController_RefundPayment_Call) Return(_a0 *dto.RefundResponseDto, _a1 error) *MockPaymentController_RefundPayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentController_RefundPayment_Call) RunAndReturn(run func(uint, int, *dto.RefundPaymentRequestDto) (*dto.RefundResponseDto, error)) *MockPaymentController_RefundPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdatePaymentStatus provides a mock function with given fields: orderId, status
 func (_m *MockPaymentController) UpdatePaymentStatus(orderId uint, status string) error {
 	ret := _m.Called(orderId, status)
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	dto "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -20,6 +21,98 @@ func (_m *MockPaymentWebhookController) EXPECT() *MockPaymentWebhookController_E
 	return &MockPaymentWebhookController_Expecter{mock: &_m.Mock}
 }
 
+// HandlePixNotification provides a mock function with given fields: pixNotification
+func (_m *MockPaymentWebhookController) HandlePixNotification(pixNotification *dto.PixWebhookDto) error {
+	ret := _m.Called(pixNotification)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandlePixNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dto.PixWebhookDto) error); ok {
+		r0 = rf(pixNotification)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPaymentWebhookController_HandlePixNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HandlePixNotification'
+type MockPaymentWebhookController_HandlePixNotification_Call struct {
+	*mock.Call
+}
+
+// HandlePixNotification is a helper method to define mock.On call
+//   - pixNotification *dto.PixWebhookDto
+func (_e *MockPaymentWebhookController_Expecter) HandlePixNotification(pixNotification interface{}) *MockPaymentWebhookController_HandlePixNotification_Call {
+	return &MockPaymentWebhookController_HandlePixNotification_Call{Call: _e.mock.On("HandlePixNotification", pixNotification)}
+}
+
+func (_c *MockPaymentWebhookController_HandlePixNotification_Call) Run(run func(pixNotification *dto.PixWebhookDto)) *MockPaymentWebhookController_HandlePixNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*dto.PixWebhookDto))
+	})
+	return _c
+}
+
+func (_c *MockPaymentWebhookController_HandlePixNotification_Call) Return(_a0 error) *MockPaymentWebhookController_HandlePixNotification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentWebhookController_HandlePixNotification_Call) RunAndReturn(run func(*dto.PixWebhookDto) error) *MockPaymentWebhookController_HandlePixNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HandleStripeEvent provides a mock function with given fields: stripeEvent
+func (_m *MockPaymentWebhookController) HandleStripeEvent(stripeEvent *dto.StripeEventDto) error {
+	ret := _m.Called(stripeEvent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleStripeEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dto.StripeEventDto) error); ok {
+		r0 = rf(stripeEvent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPaymentWebhookController_HandleStripeEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HandleStripeEvent'
+type MockPaymentWebhookController_HandleStripeEvent_Call struct {
+	*mock.Call
+}
+
+// HandleStripeEvent is a helper method to define mock.On call
+//   - stripeEvent *dto.StripeEventDto
+func (_e *MockPaymentWebhookController_Expecter) HandleStripeEvent(stripeEvent interface{}) *MockPaymentWebhookController_HandleStripeEvent_Call {
+	return &MockPaymentWebhookController_HandleStripeEvent_Call{Call: _e.mock.On("HandleStripeEvent", stripeEvent)}
+}
+
+func (_c *MockPaymentWebhookController_HandleStripeEvent_Call) Run(run func(stripeEvent *dto.StripeEventDto)) *MockPaymentWebhookController_HandleStripeEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*dto.StripeEventDto))
+	})
+	return _c
+}
+
+func (_c *MockPaymentWebhookController_HandleStripeEvent_Call) Return(_a0 error) *MockPaymentWebhookController_HandleStripeEvent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentWebhookController_HandleStripeEvent_Call) RunAndReturn(run func(*dto.StripeEventDto) error) *MockPaymentWebhookController_HandleStripeEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // HandleWebhook provides a mock function with given fields: mercadoPagoWebhookRequest
 func (_m *MockPaymentWebhookController) HandleWebhook(mercadoPagoWebhookRequest *dto.MercadoPagoWebhookNotificationRequestDTO) error {
 	ret := _m.Called(mercadoPagoWebhookRequest)
@@ -66,6 +159,64 @@ func (_c *MockPaymentWebhookController_HandleWebhook_Call) RunAndReturn(run func
 	return _c
 }
 
+// ListNotifications provides a mock function with given fields: listRequest
+func (_m *MockPaymentWebhookController) ListNotifications(listRequest *dto.ListWebhookNotificationsRequestDto) ([]*dto.WebhookNotificationResponseDto, error) {
+	ret := _m.Called(listRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNotifications")
+	}
+
+	var r0 []*dto.WebhookNotificationResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*dto.ListWebhookNotificationsRequestDto) ([]*dto.WebhookNotificationResponseDto, error)); ok {
+		return rf(listRequest)
+	}
+	if rf, ok := ret.Get(0).(func(*dto.ListWebhookNotificationsRequestDto) []*dto.WebhookNotificationResponseDto); ok {
+		r0 = rf(listRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.WebhookNotificationResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*dto.ListWebhookNotificationsRequestDto) error); ok {
+		r1 = rf(listRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentWebhookController_ListNotifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNotifications'
+type MockPaymentWebhookController_ListNotifications_Call struct {
+	*mock.Call
+}
+
+// ListNotifications is a helper method to define mock.On call
+//   - listRequest *dto.ListWebhookNotificationsRequestDto
+func (_e *MockPaymentWebhookController_Expecter) ListNotifications(listRequest interface{}) *MockPaymentWebhookController_ListNotifications_Call {
+	return &MockPaymentWebhookController_ListNotifications_Call{Call: _e.mock.On("ListNotifications", listRequest)}
+}
+
+func (_c *MockPaymentWebhookController_ListNotifications_Call) Run(run func(listRequest *dto.ListWebhookNotificationsRequestDto)) *MockPaymentWebhookController_ListNotifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*dto.ListWebhookNotificationsRequestDto))
+	})
+	return _c
+}
+
+func (_c *MockPaymentWebhookController_ListNotifications_Call) Return(_a0 []*dto.WebhookNotificationResponseDto, _a1 error) *MockPaymentWebhookController_ListNotifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentWebhookController_ListNotifications_Call) RunAndReturn(run func(*dto.ListWebhookNotificationsRequestDto) ([]*dto.WebhookNotificationResponseDto, error)) *MockPaymentWebhookController_ListNotifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockPaymentWebhookController creates a new instance of MockPaymentWebhookController. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockPaymentWebhookController(t interface {
@@ -4,7 +4,10 @@ package mocks
 
 import (
 	entities "github.com/abattassini/tc-fiap-payment/internal/payment/domain/entities"
+
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockPaymentRepository is an autogenerated mock type for the PaymentRepository type
@@ -78,12 +81,227 @@ func (_c *MockPaymentRepository_AddPayment_Call) RunAndReturn(run func(*entities
 	return _c
 }
 
-// GetPaymentByOrderId provides a mock function with given fields: orderId
-func (_m *MockPaymentRepository) GetPaymentByOrderId(orderId uint) (*entities.Payment, error) {
+// AddSplitPayment provides a mock function with given fields: replaced, changes, legs
+func (_m *MockPaymentRepository) AddSplitPayment(replaced []*entities.Payment, changes []*entities.PaymentStatusChange, legs []*entities.Payment) error {
+	ret := _m.Called(replaced, changes, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSplitPayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entities.Payment, []*entities.PaymentStatusChange, []*entities.Payment) error); ok {
+		r0 = rf(replaced, changes, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPaymentRepository_AddSplitPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSplitPayment'
+type MockPaymentRepository_AddSplitPayment_Call struct {
+	*mock.Call
+}
+
+// AddSplitPayment is a helper method to define mock.On call
+//   - replaced []*entities.Payment
+//   - changes []*entities.PaymentStatusChange
+//   - legs []*entities.Payment
+func (_e *MockPaymentRepository_Expecter) AddSplitPayment(replaced interface{}, changes interface{}, legs interface{}) *MockPaymentRepository_AddSplitPayment_Call {
+	return &MockPaymentRepository_AddSplitPayment_Call{Call: _e.mock.On("AddSplitPayment", replaced, changes, legs)}
+}
+
+func (_c *MockPaymentRepository_AddSplitPayment_Call) Run(run func(replaced []*entities.Payment, changes []*entities.PaymentStatusChange, legs []*entities.Payment)) *MockPaymentRepository_AddSplitPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.Payment), args[1].([]*entities.PaymentStatusChange), args[2].([]*entities.Payment))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_AddSplitPayment_Call) Return(_a0 error) *MockPaymentRepository_AddSplitPayment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentRepository_AddSplitPayment_Call) RunAndReturn(run func([]*entities.Payment, []*entities.PaymentStatusChange, []*entities.Payment) error) *MockPaymentRepository_AddSplitPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelPayment provides a mock function with given fields: payment, change, cancelCharge
+func (_m *MockPaymentRepository) CancelPayment(payment *entities.Payment, change *entities.PaymentStatusChange, cancelCharge func() error) error {
+	ret := _m.Called(payment, change, cancelCharge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelPayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Payment, *entities.PaymentStatusChange, func() error) error); ok {
+		r0 = rf(payment, change, cancelCharge)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPaymentRepository_CancelPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelPayment'
+type MockPaymentRepository_CancelPayment_Call struct {
+	*mock.Call
+}
+
+// CancelPayment is a helper method to define mock.On call
+//   - payment *entities.Payment
+//   - change *entities.PaymentStatusChange
+//   - cancelCharge func() error
+func (_e *MockPaymentRepository_Expecter) CancelPayment(payment interface{}, change interface{}, cancelCharge interface{}) *MockPaymentRepository_CancelPayment_Call {
+	return &MockPaymentRepository_CancelPayment_Call{Call: _e.mock.On("CancelPayment", payment, change, cancelCharge)}
+}
+
+func (_c *MockPaymentRepository_CancelPayment_Call) Run(run func(payment *entities.Payment, change *entities.PaymentStatusChange, cancelCharge func() error)) *MockPaymentRepository_CancelPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Payment), args[1].(*entities.PaymentStatusChange), args[2].(func() error))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_CancelPayment_Call) Return(_a0 error) *MockPaymentRepository_CancelPayment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentRepository_CancelPayment_Call) RunAndReturn(run func(*entities.Payment, *entities.PaymentStatusChange, func() error) error) *MockPaymentRepository_CancelPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimExpiredPayments provides a mock function with given fields: now, limit
+func (_m *MockPaymentRepository) ClaimExpiredPayments(now time.Time, limit int) ([]*entities.Payment, error) {
+	ret := _m.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimExpiredPayments")
+	}
+
+	var r0 []*entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]*entities.Payment, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []*entities.Payment); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_ClaimExpiredPayments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimExpiredPayments'
+type MockPaymentRepository_ClaimExpiredPayments_Call struct {
+	*mock.Call
+}
+
+// ClaimExpiredPayments is a helper method to define mock.On call
+//   - now time.Time
+//   - limit int
+func (_e *MockPaymentRepository_Expecter) ClaimExpiredPayments(now interface{}, limit interface{}) *MockPaymentRepository_ClaimExpiredPayments_Call {
+	return &MockPaymentRepository_ClaimExpiredPayments_Call{Call: _e.mock.On("ClaimExpiredPayments", now, limit)}
+}
+
+func (_c *MockPaymentRepository_ClaimExpiredPayments_Call) Run(run func(now time.Time, limit int)) *MockPaymentRepository_ClaimExpiredPayments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_ClaimExpiredPayments_Call) Return(_a0 []*entities.Payment, _a1 error) *MockPaymentRepository_ClaimExpiredPayments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_ClaimExpiredPayments_Call) RunAndReturn(run func(time.Time, int) ([]*entities.Payment, error)) *MockPaymentRepository_ClaimExpiredPayments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimStaleAuthorizedPayments provides a mock function with given fields: now, authorizedBefore, limit
+func (_m *MockPaymentRepository) ClaimStaleAuthorizedPayments(now time.Time, authorizedBefore time.Time, limit int) ([]*entities.Payment, error) {
+	ret := _m.Called(now, authorizedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimStaleAuthorizedPayments")
+	}
+
+	var r0 []*entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int) ([]*entities.Payment, error)); ok {
+		return rf(now, authorizedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int) []*entities.Payment); ok {
+		r0 = rf(now, authorizedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, int) error); ok {
+		r1 = rf(now, authorizedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_ClaimStaleAuthorizedPayments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimStaleAuthorizedPayments'
+type MockPaymentRepository_ClaimStaleAuthorizedPayments_Call struct {
+	*mock.Call
+}
+
+// ClaimStaleAuthorizedPayments is a helper method to define mock.On call
+//   - now time.Time
+//   - authorizedBefore time.Time
+//   - limit int
+func (_e *MockPaymentRepository_Expecter) ClaimStaleAuthorizedPayments(now interface{}, authorizedBefore interface{}, limit interface{}) *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call {
+	return &MockPaymentRepository_ClaimStaleAuthorizedPayments_Call{Call: _e.mock.On("ClaimStaleAuthorizedPayments", now, authorizedBefore, limit)}
+}
+
+func (_c *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call) Run(run func(now time.Time, authorizedBefore time.Time, limit int)) *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call) Return(_a0 []*entities.Payment, _a1 error) *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call) RunAndReturn(run func(time.Time, time.Time, int) ([]*entities.Payment, error)) *MockPaymentRepository_ClaimStaleAuthorizedPayments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindActivePaymentByOrderId provides a mock function with given fields: orderId
+func (_m *MockPaymentRepository) FindActivePaymentByOrderId(orderId uint) (*entities.Payment, error) {
 	ret := _m.Called(orderId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPaymentByOrderId")
+		panic("no return value specified for FindActivePaymentByOrderId")
 	}
 
 	var r0 *entities.Payment
@@ -108,76 +326,650 @@ func (_m *MockPaymentRepository) GetPaymentByOrderId(orderId uint) (*entities.Pa
 	return r0, r1
 }
 
-// MockPaymentRepository_GetPaymentByOrderId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentByOrderId'
-type MockPaymentRepository_GetPaymentByOrderId_Call struct {
+// MockPaymentRepository_FindActivePaymentByOrderId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindActivePaymentByOrderId'
+type MockPaymentRepository_FindActivePaymentByOrderId_Call struct {
 	*mock.Call
 }
 
-// GetPaymentByOrderId is a helper method to define mock.On call
+// FindActivePaymentByOrderId is a helper method to define mock.On call
 //   - orderId uint
-func (_e *MockPaymentRepository_Expecter) GetPaymentByOrderId(orderId interface{}) *MockPaymentRepository_GetPaymentByOrderId_Call {
-	return &MockPaymentRepository_GetPaymentByOrderId_Call{Call: _e.mock.On("GetPaymentByOrderId", orderId)}
+func (_e *MockPaymentRepository_Expecter) FindActivePaymentByOrderId(orderId interface{}) *MockPaymentRepository_FindActivePaymentByOrderId_Call {
+	return &MockPaymentRepository_FindActivePaymentByOrderId_Call{Call: _e.mock.On("FindActivePaymentByOrderId", orderId)}
 }
 
-func (_c *MockPaymentRepository_GetPaymentByOrderId_Call) Run(run func(orderId uint)) *MockPaymentRepository_GetPaymentByOrderId_Call {
+func (_c *MockPaymentRepository_FindActivePaymentByOrderId_Call) Run(run func(orderId uint)) *MockPaymentRepository_FindActivePaymentByOrderId_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(uint))
 	})
 	return _c
 }
 
-func (_c *MockPaymentRepository_GetPaymentByOrderId_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_GetPaymentByOrderId_Call {
+func (_c *MockPaymentRepository_FindActivePaymentByOrderId_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_FindActivePaymentByOrderId_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockPaymentRepository_GetPaymentByOrderId_Call) RunAndReturn(run func(uint) (*entities.Payment, error)) *MockPaymentRepository_GetPaymentByOrderId_Call {
+func (_c *MockPaymentRepository_FindActivePaymentByOrderId_Call) RunAndReturn(run func(uint) (*entities.Payment, error)) *MockPaymentRepository_FindActivePaymentByOrderId_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdatePayment provides a mock function with given fields: payment
-func (_m *MockPaymentRepository) UpdatePayment(payment *entities.Payment) error {
-	ret := _m.Called(payment)
+// FindPaymentByExternalReference provides a mock function with given fields: externalReference
+func (_m *MockPaymentRepository) FindPaymentByExternalReference(externalReference string) (*entities.Payment, error) {
+	ret := _m.Called(externalReference)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdatePayment")
+		panic("no return value specified for FindPaymentByExternalReference")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*entities.Payment) error); ok {
-		r0 = rf(payment)
+	var r0 *entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entities.Payment, error)); ok {
+		return rf(externalReference)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entities.Payment); ok {
+		r0 = rf(externalReference)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Payment)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(externalReference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockPaymentRepository_UpdatePayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePayment'
-type MockPaymentRepository_UpdatePayment_Call struct {
+// MockPaymentRepository_FindPaymentByExternalReference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPaymentByExternalReference'
+type MockPaymentRepository_FindPaymentByExternalReference_Call struct {
 	*mock.Call
 }
 
-// UpdatePayment is a helper method to define mock.On call
-//   - payment *entities.Payment
-func (_e *MockPaymentRepository_Expecter) UpdatePayment(payment interface{}) *MockPaymentRepository_UpdatePayment_Call {
-	return &MockPaymentRepository_UpdatePayment_Call{Call: _e.mock.On("UpdatePayment", payment)}
+// FindPaymentByExternalReference is a helper method to define mock.On call
+//   - externalReference string
+func (_e *MockPaymentRepository_Expecter) FindPaymentByExternalReference(externalReference interface{}) *MockPaymentRepository_FindPaymentByExternalReference_Call {
+	return &MockPaymentRepository_FindPaymentByExternalReference_Call{Call: _e.mock.On("FindPaymentByExternalReference", externalReference)}
 }
 
-func (_c *MockPaymentRepository_UpdatePayment_Call) Run(run func(payment *entities.Payment)) *MockPaymentRepository_UpdatePayment_Call {
+func (_c *MockPaymentRepository_FindPaymentByExternalReference_Call) Run(run func(externalReference string)) *MockPaymentRepository_FindPaymentByExternalReference_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*entities.Payment))
+		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockPaymentRepository_UpdatePayment_Call) Return(_a0 error) *MockPaymentRepository_UpdatePayment_Call {
-	_c.Call.Return(_a0)
+func (_c *MockPaymentRepository_FindPaymentByExternalReference_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_FindPaymentByExternalReference_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockPaymentRepository_UpdatePayment_Call) RunAndReturn(run func(*entities.Payment) error) *MockPaymentRepository_UpdatePayment_Call {
+func (_c *MockPaymentRepository_FindPaymentByExternalReference_Call) RunAndReturn(run func(string) (*entities.Payment, error)) *MockPaymentRepository_FindPaymentByExternalReference_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPaymentByProviderPaymentId provides a mock function with given fields: provider, providerPaymentId
+func (_m *MockPaymentRepository) FindPaymentByProviderPaymentId(provider string, providerPaymentId string) (*entities.Payment, error) {
+	ret := _m.Called(provider, providerPaymentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPaymentByProviderPaymentId")
+	}
+
+	var r0 *entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*entities.Payment, error)); ok {
+		return rf(provider, providerPaymentId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *entities.Payment); ok {
+		r0 = rf(provider, providerPaymentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(provider, providerPaymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_FindPaymentByProviderPaymentId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPaymentByProviderPaymentId'
+type MockPaymentRepository_FindPaymentByProviderPaymentId_Call struct {
+	*mock.Call
+}
+
+// FindPaymentByProviderPaymentId is a helper method to define mock.On call
+//   - provider string
+//   - providerPaymentId string
+func (_e *MockPaymentRepository_Expecter) FindPaymentByProviderPaymentId(provider interface{}, providerPaymentId interface{}) *MockPaymentRepository_FindPaymentByProviderPaymentId_Call {
+	return &MockPaymentRepository_FindPaymentByProviderPaymentId_Call{Call: _e.mock.On("FindPaymentByProviderPaymentId", provider, providerPaymentId)}
+}
+
+func (_c *MockPaymentRepository_FindPaymentByProviderPaymentId_Call) Run(run func(provider string, providerPaymentId string)) *MockPaymentRepository_FindPaymentByProviderPaymentId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_FindPaymentByProviderPaymentId_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_FindPaymentByProviderPaymentId_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_FindPaymentByProviderPaymentId_Call) RunAndReturn(run func(string, string) (*entities.Payment, error)) *MockPaymentRepository_FindPaymentByProviderPaymentId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPaymentById provides a mock function with given fields: id
+func (_m *MockPaymentRepository) GetPaymentById(id uint) (*entities.Payment, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentById")
+	}
+
+	var r0 *entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entities.Payment, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entities.Payment); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_GetPaymentById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentById'
+type MockPaymentRepository_GetPaymentById_Call struct {
+	*mock.Call
+}
+
+// GetPaymentById is a helper method to define mock.On call
+//   - id uint
+func (_e *MockPaymentRepository_Expecter) GetPaymentById(id interface{}) *MockPaymentRepository_GetPaymentById_Call {
+	return &MockPaymentRepository_GetPaymentById_Call{Call: _e.mock.On("GetPaymentById", id)}
+}
+
+func (_c *MockPaymentRepository_GetPaymentById_Call) Run(run func(id uint)) *MockPaymentRepository_GetPaymentById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_GetPaymentById_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_GetPaymentById_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_GetPaymentById_Call) RunAndReturn(run func(uint) (*entities.Payment, error)) *MockPaymentRepository_GetPaymentById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPaymentByOrderId provides a mock function with given fields: orderId
+func (_m *MockPaymentRepository) GetPaymentByOrderId(orderId uint) (*entities.Payment, error) {
+	ret := _m.Called(orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentByOrderId")
+	}
+
+	var r0 *entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entities.Payment, error)); ok {
+		return rf(orderId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entities.Payment); ok {
+		r0 = rf(orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_GetPaymentByOrderId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentByOrderId'
+type MockPaymentRepository_GetPaymentByOrderId_Call struct {
+	*mock.Call
+}
+
+// GetPaymentByOrderId is a helper method to define mock.On call
+//   - orderId uint
+func (_e *MockPaymentRepository_Expecter) GetPaymentByOrderId(orderId interface{}) *MockPaymentRepository_GetPaymentByOrderId_Call {
+	return &MockPaymentRepository_GetPaymentByOrderId_Call{Call: _e.mock.On("GetPaymentByOrderId", orderId)}
+}
+
+func (_c *MockPaymentRepository_GetPaymentByOrderId_Call) Run(run func(orderId uint)) *MockPaymentRepository_GetPaymentByOrderId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_GetPaymentByOrderId_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_GetPaymentByOrderId_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_GetPaymentByOrderId_Call) RunAndReturn(run func(uint) (*entities.Payment, error)) *MockPaymentRepository_GetPaymentByOrderId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPaymentByOrderLeg provides a mock function with given fields: orderId, leg
+func (_m *MockPaymentRepository) GetPaymentByOrderLeg(orderId uint, leg int) (*entities.Payment, error) {
+	ret := _m.Called(orderId, leg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentByOrderLeg")
+	}
+
+	var r0 *entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int) (*entities.Payment, error)); ok {
+		return rf(orderId, leg)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int) *entities.Payment); ok {
+		r0 = rf(orderId, leg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int) error); ok {
+		r1 = rf(orderId, leg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_GetPaymentByOrderLeg_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPaymentByOrderLeg'
+type MockPaymentRepository_GetPaymentByOrderLeg_Call struct {
+	*mock.Call
+}
+
+// GetPaymentByOrderLeg is a helper method to define mock.On call
+//   - orderId uint
+//   - leg int
+func (_e *MockPaymentRepository_Expecter) GetPaymentByOrderLeg(orderId interface{}, leg interface{}) *MockPaymentRepository_GetPaymentByOrderLeg_Call {
+	return &MockPaymentRepository_GetPaymentByOrderLeg_Call{Call: _e.mock.On("GetPaymentByOrderLeg", orderId, leg)}
+}
+
+func (_c *MockPaymentRepository_GetPaymentByOrderLeg_Call) Run(run func(orderId uint, leg int)) *MockPaymentRepository_GetPaymentByOrderLeg_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_GetPaymentByOrderLeg_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_GetPaymentByOrderLeg_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_GetPaymentByOrderLeg_Call) RunAndReturn(run func(uint, int) (*entities.Payment, error)) *MockPaymentRepository_GetPaymentByOrderLeg_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPaymentStatusHistory provides a mock function with given fields: orderId
+func (_m *MockPaymentRepository) ListPaymentStatusHistory(orderId uint) ([]*entities.PaymentStatusChange, error) {
+	ret := _m.Called(orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPaymentStatusHistory")
+	}
+
+	var r0 []*entities.PaymentStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*entities.PaymentStatusChange, error)); ok {
+		return rf(orderId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*entities.PaymentStatusChange); ok {
+		r0 = rf(orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.PaymentStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_ListPaymentStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPaymentStatusHistory'
+type MockPaymentRepository_ListPaymentStatusHistory_Call struct {
+	*mock.Call
+}
+
+// ListPaymentStatusHistory is a helper method to define mock.On call
+//   - orderId uint
+func (_e *MockPaymentRepository_Expecter) ListPaymentStatusHistory(orderId interface{}) *MockPaymentRepository_ListPaymentStatusHistory_Call {
+	return &MockPaymentRepository_ListPaymentStatusHistory_Call{Call: _e.mock.On("ListPaymentStatusHistory", orderId)}
+}
+
+func (_c *MockPaymentRepository_ListPaymentStatusHistory_Call) Run(run func(orderId uint)) *MockPaymentRepository_ListPaymentStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_ListPaymentStatusHistory_Call) Return(_a0 []*entities.PaymentStatusChange, _a1 error) *MockPaymentRepository_ListPaymentStatusHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_ListPaymentStatusHistory_Call) RunAndReturn(run func(uint) ([]*entities.PaymentStatusChange, error)) *MockPaymentRepository_ListPaymentStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPaymentsByOrderId provides a mock function with given fields: orderId
+func (_m *MockPaymentRepository) ListPaymentsByOrderId(orderId uint) ([]*entities.Payment, error) {
+	ret := _m.Called(orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPaymentsByOrderId")
+	}
+
+	var r0 []*entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*entities.Payment, error)); ok {
+		return rf(orderId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*entities.Payment); ok {
+		r0 = rf(orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_ListPaymentsByOrderId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPaymentsByOrderId'
+type MockPaymentRepository_ListPaymentsByOrderId_Call struct {
+	*mock.Call
+}
+
+// ListPaymentsByOrderId is a helper method to define mock.On call
+//   - orderId uint
+func (_e *MockPaymentRepository_Expecter) ListPaymentsByOrderId(orderId interface{}) *MockPaymentRepository_ListPaymentsByOrderId_Call {
+	return &MockPaymentRepository_ListPaymentsByOrderId_Call{Call: _e.mock.On("ListPaymentsByOrderId", orderId)}
+}
+
+func (_c *MockPaymentRepository_ListPaymentsByOrderId_Call) Run(run func(orderId uint)) *MockPaymentRepository_ListPaymentsByOrderId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_ListPaymentsByOrderId_Call) Return(_a0 []*entities.Payment, _a1 error) *MockPaymentRepository_ListPaymentsByOrderId_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_ListPaymentsByOrderId_Call) RunAndReturn(run func(uint) ([]*entities.Payment, error)) *MockPaymentRepository_ListPaymentsByOrderId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSplitLegs provides a mock function with given fields: orderId
+func (_m *MockPaymentRepository) ListSplitLegs(orderId uint) ([]*entities.Payment, error) {
+	ret := _m.Called(orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSplitLegs")
+	}
+
+	var r0 []*entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*entities.Payment, error)); ok {
+		return rf(orderId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*entities.Payment); ok {
+		r0 = rf(orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_ListSplitLegs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSplitLegs'
+type MockPaymentRepository_ListSplitLegs_Call struct {
+	*mock.Call
+}
+
+// ListSplitLegs is a helper method to define mock.On call
+//   - orderId uint
+func (_e *MockPaymentRepository_Expecter) ListSplitLegs(orderId interface{}) *MockPaymentRepository_ListSplitLegs_Call {
+	return &MockPaymentRepository_ListSplitLegs_Call{Call: _e.mock.On("ListSplitLegs", orderId)}
+}
+
+func (_c *MockPaymentRepository_ListSplitLegs_Call) Run(run func(orderId uint)) *MockPaymentRepository_ListSplitLegs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_ListSplitLegs_Call) Return(_a0 []*entities.Payment, _a1 error) *MockPaymentRepository_ListSplitLegs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_ListSplitLegs_Call) RunAndReturn(run func(uint) ([]*entities.Payment, error)) *MockPaymentRepository_ListSplitLegs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplacePayment provides a mock function with given fields: replaced, change, payment
+func (_m *MockPaymentRepository) ReplacePayment(replaced *entities.Payment, change *entities.PaymentStatusChange, payment *entities.Payment) (*entities.Payment, error) {
+	ret := _m.Called(replaced, change, payment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplacePayment")
+	}
+
+	var r0 *entities.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*entities.Payment, *entities.PaymentStatusChange, *entities.Payment) (*entities.Payment, error)); ok {
+		return rf(replaced, change, payment)
+	}
+	if rf, ok := ret.Get(0).(func(*entities.Payment, *entities.PaymentStatusChange, *entities.Payment) *entities.Payment); ok {
+		r0 = rf(replaced, change, payment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*entities.Payment, *entities.PaymentStatusChange, *entities.Payment) error); ok {
+		r1 = rf(replaced, change, payment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPaymentRepository_ReplacePayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplacePayment'
+type MockPaymentRepository_ReplacePayment_Call struct {
+	*mock.Call
+}
+
+// ReplacePayment is a helper method to define mock.On call
+//   - replaced *entities.Payment
+//   - change *entities.PaymentStatusChange
+//   - payment *entities.Payment
+func (_e *MockPaymentRepository_Expecter) ReplacePayment(replaced interface{}, change interface{}, payment interface{}) *MockPaymentRepository_ReplacePayment_Call {
+	return &MockPaymentRepository_ReplacePayment_Call{Call: _e.mock.On("ReplacePayment", replaced, change, payment)}
+}
+
+func (_c *MockPaymentRepository_ReplacePayment_Call) Run(run func(replaced *entities.Payment, change *entities.PaymentStatusChange, payment *entities.Payment)) *MockPaymentRepository_ReplacePayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Payment), args[1].(*entities.PaymentStatusChange), args[2].(*entities.Payment))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_ReplacePayment_Call) Return(_a0 *entities.Payment, _a1 error) *MockPaymentRepository_ReplacePayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPaymentRepository_ReplacePayment_Call) RunAndReturn(run func(*entities.Payment, *entities.PaymentStatusChange, *entities.Payment) (*entities.Payment, error)) *MockPaymentRepository_ReplacePayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePayment provides a mock function with given fields: payment
+func (_m *MockPaymentRepository) UpdatePayment(payment *entities.Payment) error {
+	ret := _m.Called(payment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Payment) error); ok {
+		r0 = rf(payment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPaymentRepository_UpdatePayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePayment'
+type MockPaymentRepository_UpdatePayment_Call struct {
+	*mock.Call
+}
+
+// UpdatePayment is a helper method to define mock.On call
+//   - payment *entities.Payment
+func (_e *MockPaymentRepository_Expecter) UpdatePayment(payment interface{}) *MockPaymentRepository_UpdatePayment_Call {
+	return &MockPaymentRepository_UpdatePayment_Call{Call: _e.mock.On("UpdatePayment", payment)}
+}
+
+func (_c *MockPaymentRepository_UpdatePayment_Call) Run(run func(payment *entities.Payment)) *MockPaymentRepository_UpdatePayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Payment))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_UpdatePayment_Call) Return(_a0 error) *MockPaymentRepository_UpdatePayment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentRepository_UpdatePayment_Call) RunAndReturn(run func(*entities.Payment) error) *MockPaymentRepository_UpdatePayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePaymentStatus provides a mock function with given fields: payment, change, message
+func (_m *MockPaymentRepository) UpdatePaymentStatus(payment *entities.Payment, change *entities.PaymentStatusChange, message *entities.OutboxMessage) error {
+	ret := _m.Called(payment, change, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePaymentStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Payment, *entities.PaymentStatusChange, *entities.OutboxMessage) error); ok {
+		r0 = rf(payment, change, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPaymentRepository_UpdatePaymentStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePaymentStatus'
+type MockPaymentRepository_UpdatePaymentStatus_Call struct {
+	*mock.Call
+}
+
+// UpdatePaymentStatus is a helper method to define mock.On call
+//   - payment *entities.Payment
+//   - change *entities.PaymentStatusChange
+//   - message *entities.OutboxMessage
+func (_e *MockPaymentRepository_Expecter) UpdatePaymentStatus(payment interface{}, change interface{}, message interface{}) *MockPaymentRepository_UpdatePaymentStatus_Call {
+	return &MockPaymentRepository_UpdatePaymentStatus_Call{Call: _e.mock.On("UpdatePaymentStatus", payment, change, message)}
+}
+
+func (_c *MockPaymentRepository_UpdatePaymentStatus_Call) Run(run func(payment *entities.Payment, change *entities.PaymentStatusChange, message *entities.OutboxMessage)) *MockPaymentRepository_UpdatePaymentStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Payment), args[1].(*entities.PaymentStatusChange), args[2].(*entities.OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockPaymentRepository_UpdatePaymentStatus_Call) Return(_a0 error) *MockPaymentRepository_UpdatePaymentStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentRepository_UpdatePaymentStatus_Call) RunAndReturn(run func(*entities.Payment, *entities.PaymentStatusChange, *entities.OutboxMessage) error) *MockPaymentRepository_UpdatePaymentStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	dto "github.com/abattassini/tc-fiap-payment/internal/payment/infrastructure/api/dto"
+	money "github.com/abattassini/tc-fiap-payment/pkg/money"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -23,6 +24,52 @@ func (_m *MockMercadoPagoGateway) EXPECT() *MockMercadoPagoGateway_Expecter {
 	return &MockMercadoPagoGateway_Expecter{mock: &_m.Mock}
 }
 
+// DeleteInStoreOrder provides a mock function with given fields: ctx
+func (_m *MockMercadoPagoGateway) DeleteInStoreOrder(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteInStoreOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMercadoPagoGateway_DeleteInStoreOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteInStoreOrder'
+type MockMercadoPagoGateway_DeleteInStoreOrder_Call struct {
+	*mock.Call
+}
+
+// DeleteInStoreOrder is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockMercadoPagoGateway_Expecter) DeleteInStoreOrder(ctx interface{}) *MockMercadoPagoGateway_DeleteInStoreOrder_Call {
+	return &MockMercadoPagoGateway_DeleteInStoreOrder_Call{Call: _e.mock.On("DeleteInStoreOrder", ctx)}
+}
+
+func (_c *MockMercadoPagoGateway_DeleteInStoreOrder_Call) Run(run func(ctx context.Context)) *MockMercadoPagoGateway_DeleteInStoreOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_DeleteInStoreOrder_Call) Return(_a0 error) *MockMercadoPagoGateway_DeleteInStoreOrder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_DeleteInStoreOrder_Call) RunAndReturn(run func(context.Context) error) *MockMercadoPagoGateway_DeleteInStoreOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GenerateQRCode provides a mock function with given fields: ctx, request
 func (_m *MockMercadoPagoGateway) GenerateQRCode(ctx context.Context, request dto.CreateQRCodeDTO) (dto.QRCodeResponseDto, error) {
 	ret := _m.Called(ctx, request)
@@ -80,6 +127,351 @@ func (_c *MockMercadoPagoGateway_GenerateQRCode_Call) RunAndReturn(run func(cont
 	return _c
 }
 
+// GetChargeback provides a mock function with given fields: ctx, chargebackId
+func (_m *MockMercadoPagoGateway) GetChargeback(ctx context.Context, chargebackId string) (dto.MercadoPagoChargebackResponseDto, error) {
+	ret := _m.Called(ctx, chargebackId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChargeback")
+	}
+
+	var r0 dto.MercadoPagoChargebackResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.MercadoPagoChargebackResponseDto, error)); ok {
+		return rf(ctx, chargebackId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.MercadoPagoChargebackResponseDto); ok {
+		r0 = rf(ctx, chargebackId)
+	} else {
+		r0 = ret.Get(0).(dto.MercadoPagoChargebackResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, chargebackId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMercadoPagoGateway_GetChargeback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetChargeback'
+type MockMercadoPagoGateway_GetChargeback_Call struct {
+	*mock.Call
+}
+
+// GetChargeback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chargebackId string
+func (_e *MockMercadoPagoGateway_Expecter) GetChargeback(ctx interface{}, chargebackId interface{}) *MockMercadoPagoGateway_GetChargeback_Call {
+	return &MockMercadoPagoGateway_GetChargeback_Call{Call: _e.mock.On("GetChargeback", ctx, chargebackId)}
+}
+
+func (_c *MockMercadoPagoGateway_GetChargeback_Call) Run(run func(ctx context.Context, chargebackId string)) *MockMercadoPagoGateway_GetChargeback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetChargeback_Call) Return(_a0 dto.MercadoPagoChargebackResponseDto, _a1 error) *MockMercadoPagoGateway_GetChargeback_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetChargeback_Call) RunAndReturn(run func(context.Context, string) (dto.MercadoPagoChargebackResponseDto, error)) *MockMercadoPagoGateway_GetChargeback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetClaim provides a mock function with given fields: ctx, claimId
+func (_m *MockMercadoPagoGateway) GetClaim(ctx context.Context, claimId string) (dto.MercadoPagoClaimResponseDto, error) {
+	ret := _m.Called(ctx, claimId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClaim")
+	}
+
+	var r0 dto.MercadoPagoClaimResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.MercadoPagoClaimResponseDto, error)); ok {
+		return rf(ctx, claimId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.MercadoPagoClaimResponseDto); ok {
+		r0 = rf(ctx, claimId)
+	} else {
+		r0 = ret.Get(0).(dto.MercadoPagoClaimResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, claimId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMercadoPagoGateway_GetClaim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetClaim'
+type MockMercadoPagoGateway_GetClaim_Call struct {
+	*mock.Call
+}
+
+// GetClaim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claimId string
+func (_e *MockMercadoPagoGateway_Expecter) GetClaim(ctx interface{}, claimId interface{}) *MockMercadoPagoGateway_GetClaim_Call {
+	return &MockMercadoPagoGateway_GetClaim_Call{Call: _e.mock.On("GetClaim", ctx, claimId)}
+}
+
+func (_c *MockMercadoPagoGateway_GetClaim_Call) Run(run func(ctx context.Context, claimId string)) *MockMercadoPagoGateway_GetClaim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetClaim_Call) Return(_a0 dto.MercadoPagoClaimResponseDto, _a1 error) *MockMercadoPagoGateway_GetClaim_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetClaim_Call) RunAndReturn(run func(context.Context, string) (dto.MercadoPagoClaimResponseDto, error)) *MockMercadoPagoGateway_GetClaim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInStoreOrder provides a mock function with given fields: ctx
+func (_m *MockMercadoPagoGateway) GetInStoreOrder(ctx context.Context) (*dto.MercadoPagoInStoreOrderResponseDto, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInStoreOrder")
+	}
+
+	var r0 *dto.MercadoPagoInStoreOrderResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*dto.MercadoPagoInStoreOrderResponseDto, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *dto.MercadoPagoInStoreOrderResponseDto); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.MercadoPagoInStoreOrderResponseDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMercadoPagoGateway_GetInStoreOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInStoreOrder'
+type MockMercadoPagoGateway_GetInStoreOrder_Call struct {
+	*mock.Call
+}
+
+// GetInStoreOrder is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockMercadoPagoGateway_Expecter) GetInStoreOrder(ctx interface{}) *MockMercadoPagoGateway_GetInStoreOrder_Call {
+	return &MockMercadoPagoGateway_GetInStoreOrder_Call{Call: _e.mock.On("GetInStoreOrder", ctx)}
+}
+
+func (_c *MockMercadoPagoGateway_GetInStoreOrder_Call) Run(run func(ctx context.Context)) *MockMercadoPagoGateway_GetInStoreOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetInStoreOrder_Call) Return(_a0 *dto.MercadoPagoInStoreOrderResponseDto, _a1 error) *MockMercadoPagoGateway_GetInStoreOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetInStoreOrder_Call) RunAndReturn(run func(context.Context) (*dto.MercadoPagoInStoreOrderResponseDto, error)) *MockMercadoPagoGateway_GetInStoreOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMerchantOrder provides a mock function with given fields: ctx, merchantOrderId
+func (_m *MockMercadoPagoGateway) GetMerchantOrder(ctx context.Context, merchantOrderId string) (dto.MercadoPagoMerchantOrderResponseDto, error) {
+	ret := _m.Called(ctx, merchantOrderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMerchantOrder")
+	}
+
+	var r0 dto.MercadoPagoMerchantOrderResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.MercadoPagoMerchantOrderResponseDto, error)); ok {
+		return rf(ctx, merchantOrderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.MercadoPagoMerchantOrderResponseDto); ok {
+		r0 = rf(ctx, merchantOrderId)
+	} else {
+		r0 = ret.Get(0).(dto.MercadoPagoMerchantOrderResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantOrderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMercadoPagoGateway_GetMerchantOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMerchantOrder'
+type MockMercadoPagoGateway_GetMerchantOrder_Call struct {
+	*mock.Call
+}
+
+// GetMerchantOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantOrderId string
+func (_e *MockMercadoPagoGateway_Expecter) GetMerchantOrder(ctx interface{}, merchantOrderId interface{}) *MockMercadoPagoGateway_GetMerchantOrder_Call {
+	return &MockMercadoPagoGateway_GetMerchantOrder_Call{Call: _e.mock.On("GetMerchantOrder", ctx, merchantOrderId)}
+}
+
+func (_c *MockMercadoPagoGateway_GetMerchantOrder_Call) Run(run func(ctx context.Context, merchantOrderId string)) *MockMercadoPagoGateway_GetMerchantOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetMerchantOrder_Call) Return(_a0 dto.MercadoPagoMerchantOrderResponseDto, _a1 error) *MockMercadoPagoGateway_GetMerchantOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetMerchantOrder_Call) RunAndReturn(run func(context.Context, string) (dto.MercadoPagoMerchantOrderResponseDto, error)) *MockMercadoPagoGateway_GetMerchantOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPayment provides a mock function with given fields: ctx, paymentId
+func (_m *MockMercadoPagoGateway) GetPayment(ctx context.Context, paymentId string) (dto.MercadoPagoPaymentResponseDto, error) {
+	ret := _m.Called(ctx, paymentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayment")
+	}
+
+	var r0 dto.MercadoPagoPaymentResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.MercadoPagoPaymentResponseDto, error)); ok {
+		return rf(ctx, paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.MercadoPagoPaymentResponseDto); ok {
+		r0 = rf(ctx, paymentId)
+	} else {
+		r0 = ret.Get(0).(dto.MercadoPagoPaymentResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMercadoPagoGateway_GetPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPayment'
+type MockMercadoPagoGateway_GetPayment_Call struct {
+	*mock.Call
+}
+
+// GetPayment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - paymentId string
+func (_e *MockMercadoPagoGateway_Expecter) GetPayment(ctx interface{}, paymentId interface{}) *MockMercadoPagoGateway_GetPayment_Call {
+	return &MockMercadoPagoGateway_GetPayment_Call{Call: _e.mock.On("GetPayment", ctx, paymentId)}
+}
+
+func (_c *MockMercadoPagoGateway_GetPayment_Call) Run(run func(ctx context.Context, paymentId string)) *MockMercadoPagoGateway_GetPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetPayment_Call) Return(_a0 dto.MercadoPagoPaymentResponseDto, _a1 error) *MockMercadoPagoGateway_GetPayment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_GetPayment_Call) RunAndReturn(run func(context.Context, string) (dto.MercadoPagoPaymentResponseDto, error)) *MockMercadoPagoGateway_GetPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refund provides a mock function with given fields: ctx, paymentId, amount, idempotencyKey
+func (_m *MockMercadoPagoGateway) Refund(ctx context.Context, paymentId string, amount money.Amount, idempotencyKey string) (dto.MercadoPagoRefundResponseDto, error) {
+	ret := _m.Called(ctx, paymentId, amount, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 dto.MercadoPagoRefundResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, money.Amount, string) (dto.MercadoPagoRefundResponseDto, error)); ok {
+		return rf(ctx, paymentId, amount, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, money.Amount, string) dto.MercadoPagoRefundResponseDto); ok {
+		r0 = rf(ctx, paymentId, amount, idempotencyKey)
+	} else {
+		r0 = ret.Get(0).(dto.MercadoPagoRefundResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, money.Amount, string) error); ok {
+		r1 = rf(ctx, paymentId, amount, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMercadoPagoGateway_Refund_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refund'
+type MockMercadoPagoGateway_Refund_Call struct {
+	*mock.Call
+}
+
+// Refund is a helper method to define mock.On call
+//   - ctx context.Context
+//   - paymentId string
+//   - amount money.Amount
+//   - idempotencyKey string
+func (_e *MockMercadoPagoGateway_Expecter) Refund(ctx interface{}, paymentId interface{}, amount interface{}, idempotencyKey interface{}) *MockMercadoPagoGateway_Refund_Call {
+	return &MockMercadoPagoGateway_Refund_Call{Call: _e.mock.On("Refund", ctx, paymentId, amount, idempotencyKey)}
+}
+
+func (_c *MockMercadoPagoGateway_Refund_Call) Run(run func(ctx context.Context, paymentId string, amount money.Amount, idempotencyKey string)) *MockMercadoPagoGateway_Refund_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(money.Amount), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_Refund_Call) Return(_a0 dto.MercadoPagoRefundResponseDto, _a1 error) *MockMercadoPagoGateway_Refund_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMercadoPagoGateway_Refund_Call) RunAndReturn(run func(context.Context, string, money.Amount, string) (dto.MercadoPagoRefundResponseDto, error)) *MockMercadoPagoGateway_Refund_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockMercadoPagoGateway creates a new instance of MockMercadoPagoGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockMercadoPagoGateway(t interface {
@@ -70,6 +70,438 @@ func (_c *MockPaymentPresenter_Present_Call) RunAndReturn(run func(*entities.Pay
 	return _c
 }
 
+// PresentDisputes provides a mock function with given fields: disputes
+func (_m *MockPaymentPresenter) PresentDisputes(disputes []*entities.Dispute) []*dto.DisputeResponseDto {
+	ret := _m.Called(disputes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentDisputes")
+	}
+
+	var r0 []*dto.DisputeResponseDto
+	if rf, ok := ret.Get(0).(func([]*entities.Dispute) []*dto.DisputeResponseDto); ok {
+		r0 = rf(disputes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.DisputeResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentDisputes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentDisputes'
+type MockPaymentPresenter_PresentDisputes_Call struct {
+	*mock.Call
+}
+
+// PresentDisputes is a helper method to define mock.On call
+//   - disputes []*entities.Dispute
+func (_e *MockPaymentPresenter_Expecter) PresentDisputes(disputes interface{}) *MockPaymentPresenter_PresentDisputes_Call {
+	return &MockPaymentPresenter_PresentDisputes_Call{Call: _e.mock.On("PresentDisputes", disputes)}
+}
+
+func (_c *MockPaymentPresenter_PresentDisputes_Call) Run(run func(disputes []*entities.Dispute)) *MockPaymentPresenter_PresentDisputes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.Dispute))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentDisputes_Call) Return(_a0 []*dto.DisputeResponseDto) *MockPaymentPresenter_PresentDisputes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentDisputes_Call) RunAndReturn(run func([]*entities.Dispute) []*dto.DisputeResponseDto) *MockPaymentPresenter_PresentDisputes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentOutboxMessage provides a mock function with given fields: message
+func (_m *MockPaymentPresenter) PresentOutboxMessage(message *entities.OutboxMessage) *dto.OutboxMessageResponseDto {
+	ret := _m.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentOutboxMessage")
+	}
+
+	var r0 *dto.OutboxMessageResponseDto
+	if rf, ok := ret.Get(0).(func(*entities.OutboxMessage) *dto.OutboxMessageResponseDto); ok {
+		r0 = rf(message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.OutboxMessageResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentOutboxMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentOutboxMessage'
+type MockPaymentPresenter_PresentOutboxMessage_Call struct {
+	*mock.Call
+}
+
+// PresentOutboxMessage is a helper method to define mock.On call
+//   - message *entities.OutboxMessage
+func (_e *MockPaymentPresenter_Expecter) PresentOutboxMessage(message interface{}) *MockPaymentPresenter_PresentOutboxMessage_Call {
+	return &MockPaymentPresenter_PresentOutboxMessage_Call{Call: _e.mock.On("PresentOutboxMessage", message)}
+}
+
+func (_c *MockPaymentPresenter_PresentOutboxMessage_Call) Run(run func(message *entities.OutboxMessage)) *MockPaymentPresenter_PresentOutboxMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentOutboxMessage_Call) Return(_a0 *dto.OutboxMessageResponseDto) *MockPaymentPresenter_PresentOutboxMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentOutboxMessage_Call) RunAndReturn(run func(*entities.OutboxMessage) *dto.OutboxMessageResponseDto) *MockPaymentPresenter_PresentOutboxMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentOutboxMessages provides a mock function with given fields: messages
+func (_m *MockPaymentPresenter) PresentOutboxMessages(messages []*entities.OutboxMessage) []*dto.OutboxMessageResponseDto {
+	ret := _m.Called(messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentOutboxMessages")
+	}
+
+	var r0 []*dto.OutboxMessageResponseDto
+	if rf, ok := ret.Get(0).(func([]*entities.OutboxMessage) []*dto.OutboxMessageResponseDto); ok {
+		r0 = rf(messages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.OutboxMessageResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentOutboxMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentOutboxMessages'
+type MockPaymentPresenter_PresentOutboxMessages_Call struct {
+	*mock.Call
+}
+
+// PresentOutboxMessages is a helper method to define mock.On call
+//   - messages []*entities.OutboxMessage
+func (_e *MockPaymentPresenter_Expecter) PresentOutboxMessages(messages interface{}) *MockPaymentPresenter_PresentOutboxMessages_Call {
+	return &MockPaymentPresenter_PresentOutboxMessages_Call{Call: _e.mock.On("PresentOutboxMessages", messages)}
+}
+
+func (_c *MockPaymentPresenter_PresentOutboxMessages_Call) Run(run func(messages []*entities.OutboxMessage)) *MockPaymentPresenter_PresentOutboxMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentOutboxMessages_Call) Return(_a0 []*dto.OutboxMessageResponseDto) *MockPaymentPresenter_PresentOutboxMessages_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentOutboxMessages_Call) RunAndReturn(run func([]*entities.OutboxMessage) []*dto.OutboxMessageResponseDto) *MockPaymentPresenter_PresentOutboxMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentPaymentAttempts provides a mock function with given fields: payments
+func (_m *MockPaymentPresenter) PresentPaymentAttempts(payments []*entities.Payment) []*dto.PaymentAttemptResponseDto {
+	ret := _m.Called(payments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentPaymentAttempts")
+	}
+
+	var r0 []*dto.PaymentAttemptResponseDto
+	if rf, ok := ret.Get(0).(func([]*entities.Payment) []*dto.PaymentAttemptResponseDto); ok {
+		r0 = rf(payments)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.PaymentAttemptResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentPaymentAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentPaymentAttempts'
+type MockPaymentPresenter_PresentPaymentAttempts_Call struct {
+	*mock.Call
+}
+
+// PresentPaymentAttempts is a helper method to define mock.On call
+//   - payments []*entities.Payment
+func (_e *MockPaymentPresenter_Expecter) PresentPaymentAttempts(payments interface{}) *MockPaymentPresenter_PresentPaymentAttempts_Call {
+	return &MockPaymentPresenter_PresentPaymentAttempts_Call{Call: _e.mock.On("PresentPaymentAttempts", payments)}
+}
+
+func (_c *MockPaymentPresenter_PresentPaymentAttempts_Call) Run(run func(payments []*entities.Payment)) *MockPaymentPresenter_PresentPaymentAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.Payment))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentPaymentAttempts_Call) Return(_a0 []*dto.PaymentAttemptResponseDto) *MockPaymentPresenter_PresentPaymentAttempts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentPaymentAttempts_Call) RunAndReturn(run func([]*entities.Payment) []*dto.PaymentAttemptResponseDto) *MockPaymentPresenter_PresentPaymentAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentPaymentStatusHistory provides a mock function with given fields: changes
+func (_m *MockPaymentPresenter) PresentPaymentStatusHistory(changes []*entities.PaymentStatusChange) []*dto.PaymentStatusChangeResponseDto {
+	ret := _m.Called(changes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentPaymentStatusHistory")
+	}
+
+	var r0 []*dto.PaymentStatusChangeResponseDto
+	if rf, ok := ret.Get(0).(func([]*entities.PaymentStatusChange) []*dto.PaymentStatusChangeResponseDto); ok {
+		r0 = rf(changes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.PaymentStatusChangeResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentPaymentStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentPaymentStatusHistory'
+type MockPaymentPresenter_PresentPaymentStatusHistory_Call struct {
+	*mock.Call
+}
+
+// PresentPaymentStatusHistory is a helper method to define mock.On call
+//   - changes []*entities.PaymentStatusChange
+func (_e *MockPaymentPresenter_Expecter) PresentPaymentStatusHistory(changes interface{}) *MockPaymentPresenter_PresentPaymentStatusHistory_Call {
+	return &MockPaymentPresenter_PresentPaymentStatusHistory_Call{Call: _e.mock.On("PresentPaymentStatusHistory", changes)}
+}
+
+func (_c *MockPaymentPresenter_PresentPaymentStatusHistory_Call) Run(run func(changes []*entities.PaymentStatusChange)) *MockPaymentPresenter_PresentPaymentStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.PaymentStatusChange))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentPaymentStatusHistory_Call) Return(_a0 []*dto.PaymentStatusChangeResponseDto) *MockPaymentPresenter_PresentPaymentStatusHistory_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentPaymentStatusHistory_Call) RunAndReturn(run func([]*entities.PaymentStatusChange) []*dto.PaymentStatusChangeResponseDto) *MockPaymentPresenter_PresentPaymentStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentRefund provides a mock function with given fields: refund
+func (_m *MockPaymentPresenter) PresentRefund(refund *entities.Refund) *dto.RefundResponseDto {
+	ret := _m.Called(refund)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentRefund")
+	}
+
+	var r0 *dto.RefundResponseDto
+	if rf, ok := ret.Get(0).(func(*entities.Refund) *dto.RefundResponseDto); ok {
+		r0 = rf(refund)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.RefundResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentRefund_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentRefund'
+type MockPaymentPresenter_PresentRefund_Call struct {
+	*mock.Call
+}
+
+// PresentRefund is a helper method to define mock.On call
+//   - refund *entities.Refund
+func (_e *MockPaymentPresenter_Expecter) PresentRefund(refund interface{}) *MockPaymentPresenter_PresentRefund_Call {
+	return &MockPaymentPresenter_PresentRefund_Call{Call: _e.mock.On("PresentRefund", refund)}
+}
+
+func (_c *MockPaymentPresenter_PresentRefund_Call) Run(run func(refund *entities.Refund)) *MockPaymentPresenter_PresentRefund_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Refund))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentRefund_Call) Return(_a0 *dto.RefundResponseDto) *MockPaymentPresenter_PresentRefund_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentRefund_Call) RunAndReturn(run func(*entities.Refund) *dto.RefundResponseDto) *MockPaymentPresenter_PresentRefund_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentSplitPayment provides a mock function with given fields: split
+func (_m *MockPaymentPresenter) PresentSplitPayment(split *entities.SplitPayment) *dto.GetPaymentResponseDto {
+	ret := _m.Called(split)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentSplitPayment")
+	}
+
+	var r0 *dto.GetPaymentResponseDto
+	if rf, ok := ret.Get(0).(func(*entities.SplitPayment) *dto.GetPaymentResponseDto); ok {
+		r0 = rf(split)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetPaymentResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentSplitPayment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentSplitPayment'
+type MockPaymentPresenter_PresentSplitPayment_Call struct {
+	*mock.Call
+}
+
+// PresentSplitPayment is a helper method to define mock.On call
+//   - split *entities.SplitPayment
+func (_e *MockPaymentPresenter_Expecter) PresentSplitPayment(split interface{}) *MockPaymentPresenter_PresentSplitPayment_Call {
+	return &MockPaymentPresenter_PresentSplitPayment_Call{Call: _e.mock.On("PresentSplitPayment", split)}
+}
+
+func (_c *MockPaymentPresenter_PresentSplitPayment_Call) Run(run func(split *entities.SplitPayment)) *MockPaymentPresenter_PresentSplitPayment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.SplitPayment))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentSplitPayment_Call) Return(_a0 *dto.GetPaymentResponseDto) *MockPaymentPresenter_PresentSplitPayment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentSplitPayment_Call) RunAndReturn(run func(*entities.SplitPayment) *dto.GetPaymentResponseDto) *MockPaymentPresenter_PresentSplitPayment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentSplitPaymentCodes provides a mock function with given fields: split
+func (_m *MockPaymentPresenter) PresentSplitPaymentCodes(split *entities.SplitPayment) []*dto.PaymentLegCodeResponseDto {
+	ret := _m.Called(split)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentSplitPaymentCodes")
+	}
+
+	var r0 []*dto.PaymentLegCodeResponseDto
+	if rf, ok := ret.Get(0).(func(*entities.SplitPayment) []*dto.PaymentLegCodeResponseDto); ok {
+		r0 = rf(split)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.PaymentLegCodeResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentSplitPaymentCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentSplitPaymentCodes'
+type MockPaymentPresenter_PresentSplitPaymentCodes_Call struct {
+	*mock.Call
+}
+
+// PresentSplitPaymentCodes is a helper method to define mock.On call
+//   - split *entities.SplitPayment
+func (_e *MockPaymentPresenter_Expecter) PresentSplitPaymentCodes(split interface{}) *MockPaymentPresenter_PresentSplitPaymentCodes_Call {
+	return &MockPaymentPresenter_PresentSplitPaymentCodes_Call{Call: _e.mock.On("PresentSplitPaymentCodes", split)}
+}
+
+func (_c *MockPaymentPresenter_PresentSplitPaymentCodes_Call) Run(run func(split *entities.SplitPayment)) *MockPaymentPresenter_PresentSplitPaymentCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.SplitPayment))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentSplitPaymentCodes_Call) Return(_a0 []*dto.PaymentLegCodeResponseDto) *MockPaymentPresenter_PresentSplitPaymentCodes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentSplitPaymentCodes_Call) RunAndReturn(run func(*entities.SplitPayment) []*dto.PaymentLegCodeResponseDto) *MockPaymentPresenter_PresentSplitPaymentCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PresentWebhookNotifications provides a mock function with given fields: notifications
+func (_m *MockPaymentPresenter) PresentWebhookNotifications(notifications []*entities.WebhookNotification) []*dto.WebhookNotificationResponseDto {
+	ret := _m.Called(notifications)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresentWebhookNotifications")
+	}
+
+	var r0 []*dto.WebhookNotificationResponseDto
+	if rf, ok := ret.Get(0).(func([]*entities.WebhookNotification) []*dto.WebhookNotificationResponseDto); ok {
+		r0 = rf(notifications)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.WebhookNotificationResponseDto)
+		}
+	}
+
+	return r0
+}
+
+// MockPaymentPresenter_PresentWebhookNotifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PresentWebhookNotifications'
+type MockPaymentPresenter_PresentWebhookNotifications_Call struct {
+	*mock.Call
+}
+
+// PresentWebhookNotifications is a helper method to define mock.On call
+//   - notifications []*entities.WebhookNotification
+func (_e *MockPaymentPresenter_Expecter) PresentWebhookNotifications(notifications interface{}) *MockPaymentPresenter_PresentWebhookNotifications_Call {
+	return &MockPaymentPresenter_PresentWebhookNotifications_Call{Call: _e.mock.On("PresentWebhookNotifications", notifications)}
+}
+
+func (_c *MockPaymentPresenter_PresentWebhookNotifications_Call) Run(run func(notifications []*entities.WebhookNotification)) *MockPaymentPresenter_PresentWebhookNotifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.WebhookNotification))
+	})
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentWebhookNotifications_Call) Return(_a0 []*dto.WebhookNotificationResponseDto) *MockPaymentPresenter_PresentWebhookNotifications_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPaymentPresenter_PresentWebhookNotifications_Call) RunAndReturn(run func([]*entities.WebhookNotification) []*dto.WebhookNotificationResponseDto) *MockPaymentPresenter_PresentWebhookNotifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockPaymentPresenter creates a new instance of MockPaymentPresenter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockPaymentPresenter(t interface {
@@ -0,0 +1,210 @@
+// Package money represents monetary amounts as integer minor units so that totals can be added,
+// multiplied and split without floating point rounding errors.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code. Every supported currency has two decimal places.
+type Currency string
+
+const (
+	BRL Currency = "BRL"
+)
+
+const minorUnitsPerUnit = 100
+
+// Amount is a monetary amount in minor units (cents). It encodes to JSON as a decimal number with
+// two decimal places and is stored as a decimal string, so it maps to numeric database columns.
+type Amount int64
+
+// Money is an amount in a given currency.
+type Money struct {
+	Amount   Amount   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func New(amount Amount, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Currency, m.Amount)
+}
+
+func FromMinorUnits(minorUnits int64) Amount {
+	return Amount(minorUnits)
+}
+
+// Parse reads a decimal amount such as "99.90" exactly. Digits beyond the cents are rounded half
+// away from zero.
+func Parse(value string) (Amount, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return 0, fmt.Errorf("invalid monetary amount %q", value)
+	}
+
+	minorUnits := rat.Mul(rat, big.NewRat(minorUnitsPerUnit, 1))
+	quotient, remainder := new(big.Int).QuoRem(minorUnits.Num(), minorUnits.Denom(), new(big.Int))
+	// Round half away from zero: compare twice the remainder against the denominator
+	if new(big.Int).Abs(new(big.Int).Lsh(remainder, 1)).Cmp(minorUnits.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(remainder.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return 0, fmt.Errorf("monetary amount %q is out of range", value)
+	}
+	return Amount(quotient.Int64()), nil
+}
+
+// MustParse is like Parse but panics on invalid input. It is meant for constants and tests.
+func MustParse(value string) Amount {
+	amount, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func (a Amount) MinorUnits() int64 {
+	return int64(a)
+}
+
+func (a Amount) Mul(quantity int64) Amount {
+	return a * Amount(quantity)
+}
+
+// Percent returns percentage percent of the amount, rounded half away from zero to the cent. The
+// percentage is read like an amount, so MustParse("12.5") is 12.5%.
+func (a Amount) Percent(percentage Amount) Amount {
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(percentage)))
+	hundredPercent := big.NewInt(100 * minorUnitsPerUnit)
+	quotient, remainder := new(big.Int).QuoRem(product, hundredPercent, new(big.Int))
+	if new(big.Int).Abs(new(big.Int).Lsh(remainder, 1)).Cmp(hundredPercent) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(remainder.Sign())))
+	}
+	return Amount(quotient.Int64())
+}
+
+func (a Amount) String() string {
+	sign := ""
+	minorUnits := int64(a)
+	if minorUnits < 0 {
+		sign = "-"
+		minorUnits = -minorUnits
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minorUnits/minorUnitsPerUnit, minorUnits%minorUnitsPerUnit)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and decimal strings.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (a *Amount) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// Allocate splits total into parts proportional to weights, using the largest remainder method so
+// that the parts always add up to total exactly. Zero weights split the total evenly.
+func Allocate(total Amount, weights []Amount) []Amount {
+	parts := make([]Amount, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	weightSum := big.NewInt(int64(Sum(weights...)))
+	if weightSum.Sign() == 0 {
+		weights = make([]Amount, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		weightSum = big.NewInt(int64(len(weights)))
+	}
+
+	type remainder struct {
+		index int
+		value *big.Int
+	}
+	remainders := make([]remainder, len(weights))
+	allocated := Amount(0)
+	for i, weight := range weights {
+		share := new(big.Int).Mul(big.NewInt(int64(total)), big.NewInt(int64(weight)))
+		quotient, rest := new(big.Int).QuoRem(share, weightSum, new(big.Int))
+		parts[i] = Amount(quotient.Int64())
+		allocated += parts[i]
+		remainders[i] = remainder{index: i, value: rest.Abs(rest)}
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value.Cmp(remainders[j].value) > 0
+	})
+
+	step := Amount(1)
+	if total < allocated {
+		step = -1
+	}
+	for i := 0; allocated != total; i++ {
+		parts[remainders[i%len(remainders)].index] += step
+		allocated += step
+	}
+	return parts
+}
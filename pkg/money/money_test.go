@@ -0,0 +1,151 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected money.Amount
+	}{
+		{"99.90", 9990},
+		{"99.9", 9990},
+		{"100", 10000},
+		{"0.1", 10},
+		{"-12.34", -1234},
+		{"0.005", 1},
+		{"0.004", 0},
+		{"-0.005", -1},
+		{"1e2", 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// WHEN parsing the decimal amount
+			amount, err := money.Parse(tt.value)
+
+			// THEN it should be converted to cents exactly
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestParse_WithInvalidValue_ShouldReturnError(t *testing.T) {
+	_, err := money.Parse("ten")
+	assert.Error(t, err)
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "100.50", money.Amount(10050).String())
+	assert.Equal(t, "0.05", money.Amount(5).String())
+	assert.Equal(t, "-1.20", money.Amount(-120).String())
+}
+
+func TestAmount_JSON_ShouldRoundTripAsDecimalNumber(t *testing.T) {
+	// GIVEN a payload with amounts as numbers and strings
+	var payload struct {
+		Total money.Amount `json:"total"`
+		Price money.Amount `json:"price"`
+	}
+
+	// WHEN decoding it
+	err := json.Unmarshal([]byte(`{"total": 0.3, "price": "19.99"}`), &payload)
+
+	// THEN the amounts should be exact
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(30), payload.Total)
+	assert.Equal(t, money.Amount(1999), payload.Price)
+
+	// AND encoding should produce decimal numbers
+	encoded, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"total": 0.30, "price": 19.99}`, string(encoded))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected money.Amount
+	}{
+		{"string", "100.50", 10050},
+		{"bytes", []byte("7.25"), 725},
+		{"float", 0.1 + 0.2, 30},
+		{"integer", int64(3), 300},
+		{"null", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var amount money.Amount
+			assert.NoError(t, amount.Scan(tt.src))
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestAmount_Value_ShouldBeDecimalString(t *testing.T) {
+	value, err := money.Amount(10050).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "100.50", value)
+}
+
+func TestAmount_Mul(t *testing.T) {
+	// 3 x 0.10 is exactly 0.30, unlike with float32
+	assert.Equal(t, money.Amount(30), money.MustParse("0.10").Mul(3))
+}
+
+func TestAmount_Percent(t *testing.T) {
+	tests := []struct {
+		amount, percentage, expected string
+	}{
+		{"100.00", "10", "10.00"},
+		{"99.90", "10", "9.99"},
+		{"33.35", "10", "3.34"},
+		{"10.00", "12.5", "1.25"},
+		{"0.05", "10", "0.01"},
+		{"-33.35", "10", "-3.34"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, money.MustParse(tt.expected), money.MustParse(tt.amount).Percent(money.MustParse(tt.percentage)), tt)
+	}
+}
+
+func TestAllocate_ShouldAlwaysAddUpToTotal(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    money.Amount
+		weights  []money.Amount
+		expected []money.Amount
+	}{
+		{"exact", 300, []money.Amount{100, 200}, []money.Amount{100, 200}},
+		{"thirds", 100, []money.Amount{1, 1, 1}, []money.Amount{34, 33, 33}},
+		{"discount", 900, []money.Amount{333, 333, 334}, []money.Amount{300, 300, 300}},
+		{"surcharge", 1001, []money.Amount{500, 500}, []money.Amount{501, 500}},
+		{"zero weights", 5, []money.Amount{0, 0}, []money.Amount{3, 2}},
+		{"no weights", 5, nil, []money.Amount{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN allocating the total
+			parts := money.Allocate(tt.total, tt.weights)
+
+			// THEN the parts should match and add up to the total
+			assert.Equal(t, tt.expected, parts)
+			if len(parts) > 0 {
+				assert.Equal(t, tt.total, money.Sum(parts...))
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "BRL 99.90", money.New(9990, money.BRL).String())
+}
@@ -0,0 +1,271 @@
+// Package pix builds and reads BR Codes, the EMV QR code payloads of PIX payments defined by the
+// Banco Central do Brasil. The same string is rendered as the QR code and handed to the customer as
+// the "copy and paste" code.
+package pix
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+)
+
+var ErrInvalidBRCode = errors.New("invalid BR Code")
+
+// Field ids of the EMV payload, see the BR Code manual of the Banco Central do Brasil.
+const (
+	idPayloadFormatIndicator     = "00"
+	idPointOfInitiationMethod    = "01"
+	idMerchantAccountInformation = "26"
+	idMerchantCategoryCode       = "52"
+	idTransactionCurrency        = "53"
+	idTransactionAmount          = "54"
+	idCountryCode                = "58"
+	idMerchantName               = "59"
+	idMerchantCity               = "60"
+	idAdditionalDataField        = "62"
+	idCRC16                      = "63"
+
+	// Sub-fields of the merchant account information
+	idGUI         = "00"
+	idKey         = "01"
+	idDescription = "02"
+	idURL         = "25"
+
+	// Sub-field of the additional data field
+	idTxid = "05"
+)
+
+const (
+	gui = "br.gov.bcb.pix"
+	// singleUse marks a dynamic BR Code, which may only be paid once.
+	singleUse = "12"
+	// noTxid is the txid of BR Codes whose charge is identified by the payload URL.
+	noTxid = "***"
+
+	maxMerchantNameLength = 25
+	maxMerchantCityLength = 15
+	maxStaticTxidLength   = 25
+	maxFieldLength        = 99
+)
+
+var (
+	txidPattern = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+	accents = strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+		"é", "e", "è", "e", "ê", "e", "ë", "e",
+		"í", "i", "ì", "i", "î", "i", "ï", "i",
+		"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+		"ú", "u", "ù", "u", "û", "u", "ü", "u",
+		"ç", "c", "ñ", "n",
+		"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+		"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+		"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+		"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+		"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+		"Ç", "C", "Ñ", "N",
+	)
+)
+
+// Payload is the content of a BR Code. A static BR Code carries the PIX key of the receiver; a
+// dynamic one carries the URL of a charge (cobrança) created at the PSP, which holds the key, the
+// amount and the txid.
+type Payload struct {
+	// Key is the PIX key of a static BR Code.
+	Key string
+	// URL is the location of the charge of a dynamic BR Code, without the scheme.
+	URL string
+	// Description is shown to the payer; only used by static BR Codes.
+	Description  string
+	MerchantName string
+	MerchantCity string
+	// Amount is the amount to pay; zero lets the payer choose it.
+	Amount money.Amount
+	// Txid identifies the payment of a static BR Code; dynamic BR Codes always use "***".
+	Txid string
+}
+
+// IsDynamic reports whether the payload points to a charge at the PSP.
+func (p Payload) IsDynamic() bool {
+	return p.URL != ""
+}
+
+// Encode renders the payload as a BR Code, ending with its CRC16. Merchant name and city are
+// stripped of accents and truncated to the lengths allowed by the specification.
+func (p Payload) Encode() (string, error) {
+	if (p.Key == "") == (p.URL == "") {
+		return "", fmt.Errorf("%w: exactly one of key and URL must be set", ErrInvalidBRCode)
+	}
+	if p.Amount < 0 {
+		return "", fmt.Errorf("%w: amount must not be negative", ErrInvalidBRCode)
+	}
+
+	name := normalize(p.MerchantName, maxMerchantNameLength)
+	city := normalize(p.MerchantCity, maxMerchantCityLength)
+	if name == "" || city == "" {
+		return "", fmt.Errorf("%w: merchant name and city must be set", ErrInvalidBRCode)
+	}
+
+	txid := noTxid
+	if !p.IsDynamic() && p.Txid != "" {
+		if len(p.Txid) > maxStaticTxidLength || !txidPattern.MatchString(p.Txid) {
+			return "", fmt.Errorf("%w: txid must have up to %d letters and digits", ErrInvalidBRCode, maxStaticTxidLength)
+		}
+		txid = p.Txid
+	}
+
+	accountInformation := field(idGUI, gui)
+	if p.IsDynamic() {
+		accountInformation += field(idURL, strings.TrimPrefix(strings.TrimPrefix(p.URL, "https://"), "http://"))
+	} else {
+		accountInformation += field(idKey, p.Key)
+		if p.Description != "" {
+			accountInformation += field(idDescription, p.Description)
+		}
+	}
+	if len(accountInformation) > maxFieldLength {
+		return "", fmt.Errorf("%w: key, description or URL too long", ErrInvalidBRCode)
+	}
+
+	var code strings.Builder
+	code.WriteString(field(idPayloadFormatIndicator, "01"))
+	if p.IsDynamic() {
+		code.WriteString(field(idPointOfInitiationMethod, singleUse))
+	}
+	code.WriteString(field(idMerchantAccountInformation, accountInformation))
+	code.WriteString(field(idMerchantCategoryCode, "0000"))
+	code.WriteString(field(idTransactionCurrency, "986"))
+	if p.Amount > 0 {
+		code.WriteString(field(idTransactionAmount, p.Amount.String()))
+	}
+	code.WriteString(field(idCountryCode, "BR"))
+	code.WriteString(field(idMerchantName, name))
+	code.WriteString(field(idMerchantCity, city))
+	code.WriteString(field(idAdditionalDataField, field(idTxid, txid)))
+	code.WriteString(idCRC16 + "04")
+
+	return code.String() + checksum(code.String()), nil
+}
+
+// Decode reads a BR Code, checking its CRC16. The txid of dynamic BR Codes is left empty.
+func Decode(code string) (Payload, error) {
+	if len(code) < 8 || code[len(code)-8:len(code)-4] != idCRC16+"04" {
+		return Payload{}, fmt.Errorf("%w: missing CRC16", ErrInvalidBRCode)
+	}
+	if expected := checksum(code[:len(code)-4]); !strings.EqualFold(code[len(code)-4:], expected) {
+		return Payload{}, fmt.Errorf("%w: CRC16 mismatch, expected %s", ErrInvalidBRCode, expected)
+	}
+
+	fields, err := parseFields(code[:len(code)-8])
+	if err != nil {
+		return Payload{}, err
+	}
+	if fields[idPayloadFormatIndicator] != "01" {
+		return Payload{}, fmt.Errorf("%w: unsupported payload format", ErrInvalidBRCode)
+	}
+
+	accountInformation, err := parseFields(fields[idMerchantAccountInformation])
+	if err != nil {
+		return Payload{}, err
+	}
+	if !strings.EqualFold(accountInformation[idGUI], gui) {
+		return Payload{}, fmt.Errorf("%w: not a PIX payload", ErrInvalidBRCode)
+	}
+
+	additionalData, err := parseFields(fields[idAdditionalDataField])
+	if err != nil {
+		return Payload{}, err
+	}
+
+	payload := Payload{
+		Key:          accountInformation[idKey],
+		URL:          accountInformation[idURL],
+		Description:  accountInformation[idDescription],
+		MerchantName: fields[idMerchantName],
+		MerchantCity: fields[idMerchantCity],
+	}
+	if txid := additionalData[idTxid]; txid != noTxid {
+		payload.Txid = txid
+	}
+	if value := fields[idTransactionAmount]; value != "" {
+		if payload.Amount, err = money.Parse(value); err != nil {
+			return Payload{}, fmt.Errorf("%w: %v", ErrInvalidBRCode, err)
+		}
+	}
+	return payload, nil
+}
+
+// NewTxid returns a random txid of 32 letters and digits, within the 26 to 35 characters the PSPs
+// require to create a charge.
+func NewTxid() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate PIX txid: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+func parseFields(data string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("%w: truncated field", ErrInvalidBRCode)
+		}
+		length, err := strconv.Atoi(data[2:4])
+		if err != nil || len(data) < 4+length {
+			return nil, fmt.Errorf("%w: malformed field %s", ErrInvalidBRCode, data[:2])
+		}
+		fields[data[:2]] = data[4 : 4+length]
+		data = data[4+length:]
+	}
+	return fields, nil
+}
+
+// normalize keeps the printable ASCII characters of value, replacing accented letters by their
+// base letter, and truncates it to maxLength.
+func normalize(value string, maxLength int) string {
+	var normalized strings.Builder
+	for _, r := range accents.Replace(value) {
+		if r >= ' ' && r <= '~' {
+			normalized.WriteRune(r)
+		}
+	}
+
+	result := strings.TrimSpace(normalized.String())
+	if len(result) > maxLength {
+		result = strings.TrimSpace(result[:maxLength])
+	}
+	return result
+}
+
+// checksum is the CRC16 of the BR Code: CRC-16/CCITT-FALSE (polynomial 0x1021, initial value
+// 0xFFFF) rendered as four uppercase hex digits.
+func checksum(data string) string {
+	return fmt.Sprintf("%04X", CRC16([]byte(data)))
+}
+
+// CRC16 computes the CRC-16/CCITT-FALSE of data.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,141 @@
+package pix_test
+
+import (
+	"testing"
+
+	"github.com/abattassini/tc-fiap-payment/pkg/money"
+	"github.com/abattassini/tc-fiap-payment/pkg/pix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualExample is the static BR Code given as example in the BR Code manual of the Banco Central do Brasil.
+const manualExample = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	// WHEN computing the CRC of the standard check input
+	crc := pix.CRC16([]byte("123456789"))
+
+	// THEN it should be the CRC-16/CCITT-FALSE check value
+	assert.Equal(t, uint16(0x29B1), crc)
+}
+
+func TestEncode_StaticPayload_ShouldMatchManualExample(t *testing.T) {
+	// GIVEN the payload of the manual example
+	payload := pix.Payload{
+		Key:          "123e4567-e12b-12d1-a456-426655440000",
+		MerchantName: "Fulano de Tal",
+		MerchantCity: "BRASILIA",
+	}
+
+	// WHEN encoding it
+	code, err := payload.Encode()
+
+	// THEN it should be byte for byte the BR Code of the manual
+	require.NoError(t, err)
+	assert.Equal(t, manualExample, code)
+}
+
+func TestEncode_DynamicPayload_ShouldRoundTrip(t *testing.T) {
+	// GIVEN a dynamic payload with accented merchant data
+	payload := pix.Payload{
+		URL:          "https://pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25",
+		MerchantName: "Lanchonete São João da Praça Central",
+		MerchantCity: "São Paulo",
+		Amount:       money.MustParse("50.90"),
+		Txid:         "ignoredForDynamicCodes",
+	}
+
+	// WHEN encoding and decoding it
+	code, err := payload.Encode()
+	require.NoError(t, err)
+	decoded, err := pix.Decode(code)
+
+	// THEN it should be a single use code pointing to the charge, without scheme nor txid
+	require.NoError(t, err)
+	assert.Contains(t, code, "010212")
+	assert.Contains(t, code, "62070503***")
+	assert.True(t, decoded.IsDynamic())
+	assert.Equal(t, "pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25", decoded.URL)
+	assert.Equal(t, money.MustParse("50.90"), decoded.Amount)
+	assert.Empty(t, decoded.Txid)
+
+	// AND the merchant data should be plain ASCII within the allowed lengths
+	assert.Equal(t, "Lanchonete Sao Joao da Pr", decoded.MerchantName)
+	assert.Equal(t, "Sao Paulo", decoded.MerchantCity)
+}
+
+func TestEncode_StaticPayloadWithTxid_ShouldRoundTrip(t *testing.T) {
+	// GIVEN a static payload with amount, description and txid
+	payload := pix.Payload{
+		Key:          "pix@example.com",
+		Description:  "Pedido 42",
+		MerchantName: "Fiap Lanches",
+		MerchantCity: "Sao Paulo",
+		Amount:       money.MustParse("12.00"),
+		Txid:         "order42",
+	}
+
+	// WHEN encoding and decoding it
+	code, err := payload.Encode()
+	require.NoError(t, err)
+	decoded, err := pix.Decode(code)
+
+	// THEN every field should be kept
+	require.NoError(t, err)
+	assert.Equal(t, payload, decoded)
+}
+
+func TestEncode_InvalidPayload_ShouldFail(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload pix.Payload
+	}{
+		{"no key nor URL", pix.Payload{MerchantName: "Fiap", MerchantCity: "Sao Paulo"}},
+		{"key and URL", pix.Payload{Key: "pix@example.com", URL: "pix.example.com/qr/1", MerchantName: "Fiap", MerchantCity: "Sao Paulo"}},
+		{"no merchant name", pix.Payload{Key: "pix@example.com", MerchantCity: "Sao Paulo"}},
+		{"negative amount", pix.Payload{Key: "pix@example.com", MerchantName: "Fiap", MerchantCity: "Sao Paulo", Amount: -1}},
+		{"txid with symbols", pix.Payload{Key: "pix@example.com", MerchantName: "Fiap", MerchantCity: "Sao Paulo", Txid: "order-42"}},
+		{"txid too long", pix.Payload{Key: "pix@example.com", MerchantName: "Fiap", MerchantCity: "Sao Paulo", Txid: "abcdefghijklmnopqrstuvwxyz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN encoding the payload
+			_, err := tt.payload.Encode()
+
+			// THEN it should be rejected
+			assert.ErrorIs(t, err, pix.ErrInvalidBRCode)
+		})
+	}
+}
+
+func TestDecode_WithWrongCRC_ShouldFail(t *testing.T) {
+	// GIVEN the manual example with a tampered merchant name
+	tampered := "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tel6008BRASILIA62070503***63041D3D"
+
+	// WHEN decoding it
+	_, err := pix.Decode(tampered)
+
+	// THEN the CRC should not match
+	assert.ErrorIs(t, err, pix.ErrInvalidBRCode)
+	assert.ErrorContains(t, err, "CRC16 mismatch")
+}
+
+func TestDecode_WithTruncatedCode_ShouldFail(t *testing.T) {
+	// WHEN decoding a code without its CRC
+	_, err := pix.Decode(manualExample[:40])
+
+	// THEN it should be rejected
+	assert.ErrorIs(t, err, pix.ErrInvalidBRCode)
+}
+
+func TestNewTxid(t *testing.T) {
+	// WHEN generating two txids
+	first, second := pix.NewTxid(), pix.NewTxid()
+
+	// THEN they should be distinct and accepted by the PSPs
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, 32)
+	assert.Regexp(t, `^[a-zA-Z0-9]{26,35}$`, first)
+}
@@ -41,8 +41,75 @@ func newDB(dsn string) *gorm.DB {
 }
 
 func migrate(db *gorm.DB) {
+	backfillActive := !db.Migrator().HasColumn(&paymentEntities.Payment{}, "active")
+	backfillReferences := !db.Migrator().HasColumn(&paymentEntities.Payment{}, "external_reference")
+
+	// The single active payment per order became one per leg of the order, which split payments need;
+	// idx_payment_active_order_slot still keeps an order from being paid at once and in legs
+	if db.Migrator().HasIndex(&paymentEntities.Payment{}, "idx_payment_active_order") {
+		if err := db.Migrator().DropIndex(&paymentEntities.Payment{}, "idx_payment_active_order"); err != nil {
+			log.Fatalf("Failed to drop the active payment index: %v", err)
+		}
+	}
+
 	if err := db.AutoMigrate(
-		&paymentEntities.Payment{}); err != nil {
+		&paymentEntities.Payment{},
+		&paymentEntities.PaymentStatusChange{},
+		&paymentEntities.Refund{},
+		&paymentEntities.Dispute{},
+		&paymentEntities.WebhookNotification{},
+		&paymentEntities.OutboxMessage{},
+		&paymentEntities.IdempotencyKey{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
+
+	// Statuses used to be stored capitalized ("Approved", "Declined"); normalize them to the typed values.
+	if err := db.Model(&paymentEntities.Payment{}).
+		Where("status <> LOWER(status)").
+		Update("status", gorm.Expr("LOWER(status)")).Error; err != nil {
+		log.Fatalf("Failed to normalize payment statuses: %v", err)
+	}
+
+	if backfillActive {
+		markActivePayments(db)
+	}
+
+	if backfillReferences {
+		setProviderReferences(db)
+	}
+}
+
+// setProviderReferences fills in the references of payments created before they were stored. Every
+// one of them was a Mercado Pago QR order referenced as "order-<id>"; the in-store order id is lost.
+func setProviderReferences(db *gorm.DB) {
+	if err := db.Model(&paymentEntities.Payment{}).
+		Where("external_reference IS NULL OR external_reference = ''").
+		Updates(map[string]any{
+			"provider":           paymentEntities.PaymentProviderMercadoPago,
+			"external_reference": gorm.Expr("CONCAT('order-', order_id)"),
+		}).Error; err != nil {
+		log.Fatalf("Failed to backfill payment provider references: %v", err)
+	}
+}
+
+// markActivePayments flags the latest non-terminal payment of each order as active. Older duplicates
+// created before the one-active-payment rule stay inactive, since their QR codes were already replaced.
+func markActivePayments(db *gorm.DB) {
+	var activeStatuses []paymentEntities.PaymentStatus
+	for _, status := range paymentEntities.PaymentStatuses() {
+		if !status.IsTerminal() {
+			activeStatuses = append(activeStatuses, status)
+		}
+	}
+
+	latest := db.Model(&paymentEntities.Payment{}).
+		Select("MAX(id)").
+		Where("status IN ?", activeStatuses).
+		Group("order_id")
+
+	if err := db.Model(&paymentEntities.Payment{}).
+		Where("id IN (?)", latest).
+		Update("active", true).Error; err != nil {
+		log.Fatalf("Failed to mark active payments: %v", err)
+	}
 }